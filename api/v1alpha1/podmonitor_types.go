@@ -32,10 +32,38 @@ type PodMonitorSpec struct {
 	Foo string `json:"foo,omitempty"`
 }
 
-// PodMonitorStatus defines the observed state of PodMonitor.
+// PodMonitorStatus defines the observed state of PodMonitor. It summarizes
+// the monitoring of the PodMonitor's namespace.
 type PodMonitorStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// MonitoredPodCount is the number of pods observed in the namespace.
+	// +optional
+	MonitoredPodCount int32 `json:"monitoredPodCount,omitempty"`
+
+	// MonitoredSecretCount is the number of secrets checked in the namespace.
+	// +optional
+	MonitoredSecretCount int32 `json:"monitoredSecretCount,omitempty"`
+
+	// CertificatesExpiringSoon is the number of certificates in the namespace
+	// that expire within the warning threshold.
+	// +optional
+	CertificatesExpiringSoon int32 `json:"certificatesExpiringSoon,omitempty"`
+
+	// CertificatesExpired is the number of expired certificates in the
+	// namespace.
+	// +optional
+	CertificatesExpired int32 `json:"certificatesExpired,omitempty"`
+
+	// LastReconcileTime is when the operator last changed this status. It is
+	// not refreshed while the summary stays the same.
+	// +optional
+	LastReconcileTime metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// Conditions describe the state of the monitoring: Ready and
+	// CertificatesValid.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultPodMonitorPolicyName is the name of the policy that provides the
+// fallback values for namespaces without a more specific policy.
+const DefaultPodMonitorPolicyName = "default"
+
+// PodMonitorPolicySpec defines the monitoring settings for a set of namespaces.
+// Unset fields fall back to the "default" policy, then to built-in defaults.
+type PodMonitorPolicySpec struct {
+	// Namespaces this policy applies to. A policy listing a namespace takes
+	// precedence over the "default" policy. Ignored for the "default" policy.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// RestartAlertThreshold is the container restart count from which a
+	// RestartThresholdExceeded Warning event is emitted. 0 disables the event.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RestartAlertThreshold *int32 `json:"restartAlertThreshold,omitempty"`
+
+	// CertWarningDays is the number of days before expiry from which a
+	// certificate is reported as expiring soon.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CertWarningDays *int32 `json:"certWarningDays,omitempty"`
+
+	// CertCriticalDays is the number of days before expiry from which a
+	// certificate is reported as critical.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CertCriticalDays *int32 `json:"certCriticalDays,omitempty"`
+
+	// ExcludedContainers are container names whose restarts are ignored.
+	// +optional
+	ExcludedContainers []string `json:"excludedContainers,omitempty"`
+
+	// MonitorInitContainers enables restart detection for init containers.
+	// +optional
+	MonitorInitContainers *bool `json:"monitorInitContainers,omitempty"`
+
+	// Silences are time windows during which no Kubernetes Events or event
+	// stream notifications are emitted for the namespaces of the policy, e.g.
+	// during planned maintenance. Metrics are still recorded.
+	// +optional
+	Silences []SilenceWindow `json:"silences,omitempty"`
+}
+
+// SilenceWindow is a time window during which notifications are suppressed.
+type SilenceWindow struct {
+	// Start is when the silence begins.
+	Start metav1.Time `json:"start"`
+
+	// End is when the silence ends. It stops applying on its own, without
+	// any change to the policy.
+	End metav1.Time `json:"end"`
+
+	// Reason documents the silence, e.g. a maintenance ticket.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// PodMonitorPolicyStatus defines the observed state of PodMonitorPolicy.
+type PodMonitorPolicyStatus struct {
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+
+// PodMonitorPolicy is the Schema for the podmonitorpolicies API.
+type PodMonitorPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PodMonitorPolicySpec   `json:"spec,omitempty"`
+	Status PodMonitorPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PodMonitorPolicyList contains a list of PodMonitorPolicy.
+type PodMonitorPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PodMonitorPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PodMonitorPolicy{}, &PodMonitorPolicyList{})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RestartBudgetExceededCondition is the condition type reporting whether the
+// budget is exceeded.
+const RestartBudgetExceededCondition = "BudgetExceeded"
+
+// PodRestartBudgetSpec defines a soft limit on the share of a Deployment's
+// pods that may restart within a time window.
+type PodRestartBudgetSpec struct {
+	// TargetDeployment is the name of the Deployment whose pods are counted.
+	// +kubebuilder:validation:MinLength=1
+	TargetDeployment string `json:"targetDeployment"`
+
+	// Namespace of the Deployment. It must be empty or equal to the
+	// namespace of the budget: a budget cannot count the pods of other
+	// namespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// MaxRestartPercentage is the percentage of pods, between 0 and 100, that
+	// may have restarted within Window before the budget is exceeded, e.g. 5
+	// or "2.5".
+	MaxRestartPercentage resource.Quantity `json:"maxRestartPercentage"`
+
+	// Window is how far back restarts are counted. Defaults to one hour.
+	// +optional
+	Window metav1.Duration `json:"window,omitempty"`
+}
+
+// PodRestartBudgetStatus defines the observed state of PodRestartBudget.
+type PodRestartBudgetStatus struct {
+	// Pods is the number of pods of the Deployment.
+	// +optional
+	Pods int32 `json:"pods,omitempty"`
+
+	// RestartedPods is the number of pods with a container restart within
+	// the window.
+	// +optional
+	RestartedPods int32 `json:"restartedPods,omitempty"`
+
+	// Conditions hold the BudgetExceeded condition.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Deployment",type=string,JSONPath=`.spec.targetDeployment`
+// +kubebuilder:printcolumn:name="Restarted",type=integer,JSONPath=`.status.restartedPods`
+// +kubebuilder:printcolumn:name="Pods",type=integer,JSONPath=`.status.pods`
+// +kubebuilder:printcolumn:name="Exceeded",type=string,JSONPath=`.status.conditions[?(@.type=="BudgetExceeded")].status`
+
+// PodRestartBudget is the Schema for the podrestartbudgets API.
+type PodRestartBudget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PodRestartBudgetSpec   `json:"spec,omitempty"`
+	Status PodRestartBudgetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PodRestartBudgetList contains a list of PodRestartBudget.
+type PodRestartBudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PodRestartBudget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PodRestartBudget{}, &PodRestartBudgetList{})
+}
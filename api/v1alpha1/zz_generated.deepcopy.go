@@ -21,6 +21,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,7 +31,7 @@ func (in *PodMonitor) DeepCopyInto(out *PodMonitor) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitor.
@@ -83,6 +84,132 @@ func (in *PodMonitorList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMonitorPolicy) DeepCopyInto(out *PodMonitorPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitorPolicy.
+func (in *PodMonitorPolicy) DeepCopy() *PodMonitorPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMonitorPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodMonitorPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMonitorPolicyList) DeepCopyInto(out *PodMonitorPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PodMonitorPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitorPolicyList.
+func (in *PodMonitorPolicyList) DeepCopy() *PodMonitorPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMonitorPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodMonitorPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMonitorPolicySpec) DeepCopyInto(out *PodMonitorPolicySpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartAlertThreshold != nil {
+		in, out := &in.RestartAlertThreshold, &out.RestartAlertThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CertWarningDays != nil {
+		in, out := &in.CertWarningDays, &out.CertWarningDays
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CertCriticalDays != nil {
+		in, out := &in.CertCriticalDays, &out.CertCriticalDays
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExcludedContainers != nil {
+		in, out := &in.ExcludedContainers, &out.ExcludedContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorInitContainers != nil {
+		in, out := &in.MonitorInitContainers, &out.MonitorInitContainers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Silences != nil {
+		in, out := &in.Silences, &out.Silences
+		*out = make([]SilenceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitorPolicySpec.
+func (in *PodMonitorPolicySpec) DeepCopy() *PodMonitorPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMonitorPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodMonitorPolicyStatus) DeepCopyInto(out *PodMonitorPolicyStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitorPolicyStatus.
+func (in *PodMonitorPolicyStatus) DeepCopy() *PodMonitorPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PodMonitorPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodMonitorSpec) DeepCopyInto(out *PodMonitorSpec) {
 	*out = *in
@@ -101,6 +228,14 @@ func (in *PodMonitorSpec) DeepCopy() *PodMonitorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodMonitorStatus) DeepCopyInto(out *PodMonitorStatus) {
 	*out = *in
+	in.LastReconcileTime.DeepCopyInto(&out.LastReconcileTime)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMonitorStatus.
@@ -112,3 +247,118 @@ func (in *PodMonitorStatus) DeepCopy() *PodMonitorStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRestartBudget) DeepCopyInto(out *PodRestartBudget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRestartBudget.
+func (in *PodRestartBudget) DeepCopy() *PodRestartBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRestartBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodRestartBudget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRestartBudgetList) DeepCopyInto(out *PodRestartBudgetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PodRestartBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRestartBudgetList.
+func (in *PodRestartBudgetList) DeepCopy() *PodRestartBudgetList {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRestartBudgetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PodRestartBudgetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRestartBudgetSpec) DeepCopyInto(out *PodRestartBudgetSpec) {
+	*out = *in
+	out.MaxRestartPercentage = in.MaxRestartPercentage.DeepCopy()
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRestartBudgetSpec.
+func (in *PodRestartBudgetSpec) DeepCopy() *PodRestartBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRestartBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodRestartBudgetStatus) DeepCopyInto(out *PodRestartBudgetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodRestartBudgetStatus.
+func (in *PodRestartBudgetStatus) DeepCopy() *PodRestartBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PodRestartBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SilenceWindow) DeepCopyInto(out *SilenceWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SilenceWindow.
+func (in *SilenceWindow) DeepCopy() *SilenceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SilenceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
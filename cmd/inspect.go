@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+)
+
+// inspectCommand is the subcommand printing the certificates of a secret or
+// file as the operator parses them.
+const inspectCommand = "inspect"
+
+// runInspect implements "inspect --secret namespace/name" and
+// "inspect --file path" and returns the exit code.
+func runInspect(args []string, out io.Writer) int {
+	fs := flag.NewFlagSet(inspectCommand, flag.ContinueOnError)
+	secretRef := fs.String("secret", "", "The secret to inspect, as namespace/name. Uses the current kubeconfig.")
+	file := fs.String("file", "", "The PEM or DER file to inspect.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*secretRef == "") == (*file == "") {
+		fmt.Fprintln(fs.Output(), "exactly one of --secret or --file is required")
+		fs.Usage()
+		return 2
+	}
+
+	entries, err := inspectSources(*secretRef, *file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	found := false
+	now := time.Now()
+	for _, entry := range entries {
+		certs, err := certparse.ParseAll(entry.data)
+		if errors.Is(err, certparse.ErrNotCertificateData) && *secretRef != "" {
+			// Secret 中的私钥、密码等非证书数据直接跳过
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(out, "%s: %v\n", entry.name, err)
+		}
+		for i, cert := range certs {
+			found = true
+			printCertSummary(out, fmt.Sprintf("%s [%d]", entry.name, i), certparse.Summarize(cert), now)
+		}
+	}
+	if !found {
+		fmt.Fprintln(os.Stderr, "no certificates found")
+		return 1
+	}
+	return 0
+}
+
+// inspectEntry is one value to parse: a secret key or a file.
+type inspectEntry struct {
+	name string
+	data []byte
+}
+
+// inspectSources reads the secret or the file to inspect. Secret keys are
+// returned in sorted order.
+func inspectSources(secretRef, file string) ([]inspectEntry, error) {
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return []inspectEntry{{name: file, data: data}}, nil
+	}
+
+	namespace, name, ok := strings.Cut(secretRef, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("--secret must be namespace/name, got %q", secretRef)
+	}
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	entries := make([]inspectEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, inspectEntry{name: key, data: secret.Data[key]})
+	}
+	return entries, nil
+}
+
+// printCertSummary writes a certificate summary as an indented block.
+func printCertSummary(out io.Writer, title string, s certparse.CertSummary, now time.Time) {
+	fmt.Fprintln(out, title)
+	fmt.Fprintf(out, "  Subject:     %s\n", s.Subject)
+	fmt.Fprintf(out, "  Issuer:      %s\n", s.Issuer)
+	fmt.Fprintf(out, "  Not before:  %s\n", s.NotBefore.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "  Not after:   %s (%.1f days left)\n", s.NotAfter.UTC().Format(time.RFC3339),
+		s.NotAfter.Sub(now).Hours()/24)
+	fmt.Fprintf(out, "  CA:          %t\n", s.IsCA)
+	if len(s.SANs) > 0 {
+		fmt.Fprintf(out, "  SANs:        %s\n", strings.Join(s.SANs, ", "))
+	}
+	fmt.Fprintf(out, "  Fingerprint: %s (SHA-256)\n", s.Fingerprint)
+}
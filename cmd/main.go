@@ -21,22 +21,29 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
 	"github.com/Deraiven/pod-monitor-operator/internal/controller"
 	// +kubebuilder:scaffold:imports
 )
@@ -49,23 +56,89 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
-	//utilruntime.Must(monitorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(monitorv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(monitoringv1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == inspectCommand {
+		os.Exit(runInspect(os.Args[2:], os.Stdout))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
+	var metricsAuth controller.MetricsAuthOptions
 	var enableHTTP2 bool
+	var drainCorrelationWindow time.Duration
+	var maxConcurrentReconciles int
+	var requireAllPermissions bool
+	var strictRBAC bool
+	var suppressPlannedRestartEvents bool
+	var validateCertificateHostnames bool
+	var exposeContainerInfo bool
+	var secretSizeWarnThreshold int64
+	var stateAPIAddr string
+	var grpcAddr string
+	var createServiceMonitor bool
+	var serviceMonitorNamespace, serviceMonitorService, serviceMonitorPort string
+	var historySize, historyPerContainer int
+	var restartWindow time.Duration
+	var failureReasonWindow time.Duration
+	var simulateRestarts bool
+	var includeSucceededPods bool
+	var annotateSecrets bool
+	var imagePullStuckThreshold time.Duration
+	var terminationWarnThreshold time.Duration
+	var repeatedExitCodeEventThreshold int
+	var watchPodDisruptionBudgets bool
+	var watchNodes bool
+	var watchProbeEvents bool
+	var livenessCorrelationWindow time.Duration
+	var enableNodeWatch bool
+	var enableCSRWatch bool
+	var patchWorkloadConditions bool
+	var slackWebhookURL string
+	var workloadConditionCoolDown time.Duration
+	var csrPendingAlertThreshold time.Duration
+	var enableRestartBudgets bool
+	var apiErrorThreshold int
+	var apiBackoffCoolOff time.Duration
+	var secretRecheckJitter float64
+	var secretStartupSpread time.Duration
+	var secretWatchConfig string
+	var useMetricsAPI bool
+	var cpuThrottlingThreshold float64
+	var linkerdMode bool
+	var linkerdNamespace string
+	var linkerdRotationWindow time.Duration
+	var restartVelocityAlpha float64
+	var watchEtcdCerts bool
+	var etcdSecretNames string
+	var kubeadmMode bool
+	var autoDiscoverCerts bool
+	var autoDiscoverNamespaces string
+	var autoDiscoverMax int
+	var seriesConsistencyInterval time.Duration
+	var trustedCACommonNames string
+	var includePodUID bool
+	var maxSecretKeySize int64
+	var maxPEMBlocksPerKey int
+	var simulateRate float64
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&stateAPIAddr, "state-api-bind-address", "0", "The address the state API (e.g. /report) binds to. "+
+		"Use :8082 to enable it, or leave as 0 to disable the state API.")
+	flag.StringVar(&grpcAddr, "grpc-addr", "0", "The address the gRPC event stream (WatchEvents) binds to. "+
+		"Use :9090 to enable it, or leave as 0 to disable it. Served over TLS with the metrics server certificate "+
+		"when --metrics-cert-path is set.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -76,10 +149,166 @@ func main() {
 	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
 	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
 		"The directory that contains the metrics server certificate.")
+	flag.StringVar(&metricsCertPath, "metrics-cert-dir", "",
+		"Alias of --metrics-cert-path. Mount the metrics TLS secret here; rotated certificates are reloaded.")
+	flag.StringVar(&metricsAuth.Mode, "metrics-auth-mode", controller.MetricsAuthModeRBAC,
+		"Authentication of the secure metrics endpoint: \"rbac\" (TokenReview and SubjectAccessReview), "+
+			"\"token\" (static bearer token from --metrics-auth-token-file) or \"none\". "+
+			"Rejected scrapes are counted in pod_monitor_metrics_auth_failures_total.")
+	flag.StringVar(&metricsAuth.TokenFile, "metrics-auth-token-file", "",
+		"File holding the bearer token accepted with --metrics-auth-mode=token.")
+	flag.StringVar(&metricsAuth.Resource, "metrics-auth-resource", "",
+		"With --metrics-auth-mode=rbac, authorize scrapes as \"get\" on this resource, "+
+			"as resource[.group][/subresource] (e.g. services/proxy), instead of the non-resource URL /metrics.")
+	flag.StringVar(&metricsAuth.Namespace, "metrics-auth-namespace", "",
+		"Namespace of --metrics-auth-resource.")
+	flag.StringVar(&metricsAuth.Name, "metrics-auth-resource-name", "",
+		"Name of --metrics-auth-resource.")
 	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&requireAllPermissions, "require-all-permissions", false,
+		"If set, exit at startup when the RBAC self-check finds a required permission missing.")
+	flag.BoolVar(&strictRBAC, "strict-rbac", false,
+		"If set, exit at startup when a configured feature lacks RBAC permissions instead of disabling it.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 4,
+		"Number of pods and secrets reconciled concurrently.")
+	flag.DurationVar(&drainCorrelationWindow, "drain-correlation-window", 10*time.Minute,
+		"How long after a node cordon/drain a container restart on that node is labeled as planned.")
+	flag.BoolVar(&suppressPlannedRestartEvents, "suppress-planned-restart-events", false,
+		"If set, no Warning event is emitted for restarts that happen during a planned node drain.")
+	flag.BoolVar(&validateCertificateHostnames, "validate-certificate-hostnames", false,
+		"If set, TLS secrets referenced by Ingresses are checked to cover every Ingress host.")
+	flag.BoolVar(&exposeContainerInfo, "expose-container-info", false,
+		"If set, export pod_monitor_container_info with the image of every running container. "+
+			"Adds one series per running container.")
+	flag.Int64Var(&secretSizeWarnThreshold, "secret-size-warn-threshold", 500*1024,
+		"Total secret data size in bytes above which a Warning event is emitted on the secret, once each time "+
+			"its size crosses the threshold. Set to 0 to disable.")
+	flag.IntVar(&historySize, "history-size", 1000,
+		"Number of recent container terminations kept in memory and served on /api/v1/restarts/history.")
+	flag.IntVar(&historyPerContainer, "history-per-container", 50,
+		"Maximum number of terminations kept in the history per container.")
+	flag.DurationVar(&restartWindow, "restart-window", time.Hour,
+		"Length of the sliding window of pod_monitor_restarts_last_window.")
+	flag.DurationVar(&failureReasonWindow, "failure-reason-window", 24*time.Hour,
+		"Length of the sliding window of pod_monitor_workload_distinct_failure_reasons.")
+	flag.Int64Var(&maxSecretKeySize, "max-secret-key-size", 1<<20,
+		"Secret values larger than this many bytes are not parsed for certificates.")
+	flag.IntVar(&maxPEMBlocksPerKey, "max-pem-blocks-per-key", 100,
+		"Maximum number of PEM blocks decoded from a single secret value.")
+	flag.BoolVar(&watchEtcdCerts, "watch-etcd-certs", false,
+		"If set, the etcd certificate secrets in kube-system are checked and labeled source=\"etcd\".")
+	flag.StringVar(&etcdSecretNames, "etcd-secret-names", strings.Join(controller.DefaultEtcdSecretNames, ","),
+		"Comma-separated names of the etcd certificate secrets in kube-system.")
+	flag.BoolVar(&kubeadmMode, "kubeadm-mode", false,
+		"If set, the control-plane certificates published by kubeadm (kubeadm-certs secret, cluster-info and "+
+			"extension-apiserver-authentication ConfigMaps) are checked and labeled source=\"kubeadm\".")
+	flag.DurationVar(&seriesConsistencyInterval, "series-consistency-interval", time.Minute,
+		"How often the leader compares its metric series with its internal state and exports "+
+			"pod_monitor_series_consistency_drift. 0 disables the check.")
+	flag.BoolVar(&includePodUID, "include-pod-uid", false,
+		"If set, add a pod_uid label to the restart and termination metrics and reset the restart state of a pod "+
+			"recreated with the same name, e.g. a StatefulSet pod.")
+	flag.StringVar(&trustedCACommonNames, "trusted-ca-common-names", "",
+		"Comma-separated common names of trusted issuing CAs. When set, certificates issued by any other CA are "+
+			"reported in pod_monitor_certificate_issued_by_unknown_ca.")
+	flag.BoolVar(&autoDiscoverCerts, "auto-discover-certs", false,
+		"If set, check the .crt, .pem and .cer keys of secrets without a known certificate key and monitor those "+
+			"that parse as certificates. Secrets can opt out with pod-monitor.deraiven.io/auto-discover: \"false\".")
+	flag.StringVar(&autoDiscoverNamespaces, "auto-discover-namespaces", "",
+		"With --auto-discover-certs, comma-separated namespaces to discover certificates in. Defaults to all.")
+	flag.IntVar(&autoDiscoverMax, "auto-discover-max", 500,
+		"With --auto-discover-certs, the maximum number of auto-discovered secrets to monitor. Further secrets are "+
+			"counted in pod_monitor_auto_discover_exceeded_total.")
+	flag.Float64Var(&restartVelocityAlpha, "restart-velocity-alpha", 0.2,
+		"Smoothing factor in (0, 1] of pod_monitor_container_restart_velocity, per minute. Higher values react "+
+			"faster and decay faster once restarts stop.")
+	flag.DurationVar(&imagePullStuckThreshold, "image-pull-stuck-threshold", 10*time.Minute,
+		"How long a container may fail to pull its image before a Warning event is emitted.")
+	flag.DurationVar(&terminationWarnThreshold, "termination-warn-threshold", time.Minute,
+		"How long a pod may be terminating before it is exported in pod_monitor_pod_termination_duration_seconds.")
+	flag.IntVar(&repeatedExitCodeEventThreshold, "repeated-exit-code-event-threshold", 5,
+		"Number of consecutive terminations with the same non-zero exit code at which a Warning event is emitted. "+
+			"Set to 0 to disable.")
+	flag.BoolVar(&watchNodes, "watch-nodes", false,
+		"If set, export pod_monitor_node_ready and label restarts on nodes that were NotReady within "+
+			"--drain-correlation-window with node_ready_at_restart=\"false\".")
+	flag.BoolVar(&watchProbeEvents, "watch-probe-events", false,
+		"If set, watch the kubelet events of failed liveness probes and label restarts with cause=\"liveness_kill\" "+
+			"when they follow one within --liveness-correlation-window, cause=\"crash\" otherwise.")
+	flag.DurationVar(&livenessCorrelationWindow, "liveness-correlation-window", 2*time.Minute,
+		"How long after a failed liveness probe a restart of the container is attributed to it.")
+	flag.BoolVar(&enableNodeWatch, "enable-node-watch", false,
+		"If set, export pod_monitor_node_condition_status for the Ready and pressure conditions of nodes.")
+	flag.BoolVar(&enableCSRWatch, "enable-csr-watch", false,
+		"If set, watch CertificateSigningRequests and export pod_monitor_csr_pending_duration_seconds "+
+			"for those awaiting approval.")
+	flag.DurationVar(&csrPendingAlertThreshold, "csr-pending-alert-threshold", 5*time.Minute,
+		"How long a CertificateSigningRequest may wait for approval before a Warning event is emitted on it.")
+	flag.BoolVar(&patchWorkloadConditions, "patch-workload-conditions", false,
+		"If set, set the PodMonitorCrashLooping condition on the Deployment or StatefulSet of pods whose "+
+			"containers reach the restart alert threshold.")
+	flag.DurationVar(&workloadConditionCoolDown, "workload-condition-cool-down", 30*time.Minute,
+		"How long a workload must go without reaching the restart alert threshold before its "+
+			"PodMonitorCrashLooping condition is set back to False.")
+	flag.StringVar(&slackWebhookURL, "slack-webhook-url", "",
+		"If set, post a message to this Slack Incoming Webhook when a certificate enters the critical "+
+			"expiry window, at most once every 4 hours per certificate.")
+	flag.BoolVar(&enableRestartBudgets, "enable-restart-budgets", false,
+		"If set, evaluate PodRestartBudgets and export pod_monitor_restart_budget_exceeded. "+
+			"Requires the PodRestartBudget CRD.")
+	flag.BoolVar(&watchPodDisruptionBudgets, "watch-pod-disruption-budgets", false,
+		"If set, watch PodDisruptionBudgets and export pod_monitor_pod_disruption_budget_at_capacity.")
+	flag.IntVar(&apiErrorThreshold, "api-error-threshold", 20,
+		"Number of consecutive API server errors after which pod reconciles are paused.")
+	flag.DurationVar(&apiBackoffCoolOff, "api-backoff-cool-off", 30*time.Second,
+		"How long pod reconciles are paused after too many consecutive API server errors.")
+	flag.Float64Var(&secretRecheckJitter, "secret-recheck-jitter", 0.1,
+		"Fraction by which the hourly certificate recheck of each secret is randomly lengthened or shortened, "+
+			"so that secrets do not all reconcile at once.")
+	flag.DurationVar(&secretStartupSpread, "secret-startup-spread", time.Hour,
+		"Period over which the first checks of the secrets that exist at startup are randomly spread, "+
+			"so that they do not all reconcile at once. Certificate metrics of a secret appear once it is checked. "+
+			"Set to 0 to check all secrets immediately.")
+	flag.StringVar(&secretWatchConfig, "secret-watch-config", "",
+		"Path of a YAML file listing the secrets to monitor, as secrets (namespace and name) and selectors "+
+			"(labelSelector, optionally limited to a namespace). The file is reread every 30 seconds. "+
+			"Secrets named by other options are always monitored. If unset, all secrets are monitored.")
+	flag.BoolVar(&useMetricsAPI, "use-metrics-api", false,
+		"If set, query metrics.k8s.io on OOMKilled terminations and export pod_monitor_container_oom_working_set_bytes. "+
+			"Requires metrics-server.")
+	flag.Float64Var(&cpuThrottlingThreshold, "cpu-throttling-threshold", 0.95,
+		"With --use-metrics-api, label a restart suspected_cause=\"cpu_throttling\" when the container's recent CPU "+
+			"usage is at least this fraction of its CPU limit. This is a hint, not a verdict.")
+	flag.BoolVar(&linkerdMode, "linkerd-mode", false,
+		"If set, track linkerd-proxy sidecar restarts by proxy version and count the restarts that follow a "+
+			"rotation of the Linkerd identity issuer certificate.")
+	flag.StringVar(&linkerdNamespace, "linkerd-namespace", "linkerd",
+		"The namespace of the Linkerd control plane and its linkerd-identity-issuer secret.")
+	flag.DurationVar(&linkerdRotationWindow, "linkerd-rotation-window", 10*time.Minute,
+		"With --linkerd-mode, how long after an issuer rotation a linkerd-proxy restart is counted as following it.")
+	flag.BoolVar(&annotateSecrets, "annotate-secrets", false,
+		"If set, monitored secrets are annotated with pod-monitor.deraiven.io/last-checked, not-after and days-remaining. "+
+			"Each change is a write to the secret (at most one per secret per day) and is sent to all its watchers.")
+	flag.BoolVar(&includeSucceededPods, "include-succeeded-pods", false,
+		"If set, Succeeded pods are counted in pod_monitor_pods_by_phase.")
+	flag.BoolVar(&createServiceMonitor, "create-service-monitor", false,
+		"If set, create or update a Prometheus Operator ServiceMonitor for the metrics service. "+
+			"Skipped when the ServiceMonitor CRD is not installed.")
+	flag.StringVar(&serviceMonitorNamespace, "service-monitor-namespace", "",
+		"Namespace of the metrics service. Defaults to the namespace the operator runs in.")
+	flag.StringVar(&serviceMonitorService, "service-monitor-service",
+		"pod-monitor-operator-controller-manager-metrics-service",
+		"Name of the metrics service the ServiceMonitor selects. The ServiceMonitor gets the same name.")
+	flag.StringVar(&serviceMonitorPort, "service-monitor-port", "https",
+		"Name of the metrics port of the metrics service.")
+	flag.BoolVar(&simulateRestarts, "simulate-restarts", false,
+		"If set, periodically inject synthetic terminations for sim-pod-* containers into the metrics, "+
+			"labeled simulated=\"true\". Intended for dashboard testing only.")
+	flag.Float64Var(&simulateRate, "simulate-rate", 1,
+		"Number of simulated terminations per minute when --simulate-restarts is set.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -145,9 +374,19 @@ func main() {
 	if secureMetrics {
 		// FilterProvider is used to protect the metrics endpoint with authn/authz.
 		// These configurations ensure that only authorized users and service accounts
-		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'. More info:
-		// https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.4/pkg/metrics/filters#WithAuthenticationAndAuthorization
-		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+		// can access the metrics endpoint. The RBAC are configured in 'config/rbac/kustomization.yaml'.
+		// Unlike filters.WithAuthenticationAndAuthorization it counts rejected scrapes and supports
+		// a static token and resource attributes, see --metrics-auth-mode.
+		filterProvider, err := controller.NewMetricsAuthFilterProvider(metricsAuth)
+		if err != nil {
+			setupLog.Error(err, "invalid metrics authentication settings")
+			os.Exit(1)
+		}
+		if filterProvider != nil {
+			metricsServerOptions.FilterProvider = filterProvider
+		} else {
+			setupLog.Info("Serving the metrics endpoint without authentication", "metrics-auth-mode", metricsAuth.Mode)
+		}
 	}
 
 	// If the certificate is not specified, controller-runtime will automatically
@@ -177,8 +416,20 @@ func main() {
 		})
 	}
 
+	// 只有 leader 导出 pod_monitor_* 指标，避免同时抓取两个副本时出现冲突的值
+	metrics.Registry = controller.LeaderGatedRegistry(metrics.Registry)
+
+	// 监听探针事件时只缓存 Unhealthy 事件，而不是集群中的所有事件
+	var cacheOptions cache.Options
+	if watchProbeEvents {
+		cacheOptions.ByObject = map[client.Object]cache.ByObject{
+			&corev1.Event{}: {Field: controller.ProbeEventsFieldSelector()},
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -201,15 +452,210 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controller.PodMonitorReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	ctx := ctrl.SetupSignalHandler()
+
+	// 启动前自检 RBAC 权限，缺失时 reconcile 会静默失败
+	missing, err := controller.CheckPermissions(ctx, mgr.GetClient(), controller.RequiredPermissions())
+	if err != nil {
+		setupLog.Error(err, "unable to complete the RBAC self-check")
+	}
+	for _, permission := range missing {
+		setupLog.Error(nil, "Missing RBAC permission", "permission", permission.String())
+	}
+	if len(missing) > 0 && requireAllPermissions {
+		setupLog.Error(nil, "Exiting because of missing RBAC permissions (--require-all-permissions)")
+		os.Exit(1)
+	}
+
+	reconciler := &controller.PodMonitorReconciler{
+		Client:                         mgr.GetClient(),
+		Scheme:                         mgr.GetScheme(),
+		Recorder:                       mgr.GetEventRecorderFor("podmonitor"),
+		MaxConcurrentReconciles:        maxConcurrentReconciles,
+		DrainCorrelationWindow:         drainCorrelationWindow,
+		SuppressPlannedRestartEvents:   suppressPlannedRestartEvents,
+		ValidateCertificateHostnames:   validateCertificateHostnames,
+		ExposeContainerInfo:            exposeContainerInfo,
+		SecretSizeWarnThreshold:        secretSizeWarnThreshold,
+		HistorySize:                    historySize,
+		HistoryPerContainer:            historyPerContainer,
+		RestartWindow:                  restartWindow,
+		FailureReasonWindow:            failureReasonWindow,
+		IncludeSucceededPods:           includeSucceededPods,
+		AnnotateSecrets:                annotateSecrets,
+		ImagePullStuckThreshold:        imagePullStuckThreshold,
+		TerminationWarnThreshold:       terminationWarnThreshold,
+		RestartVelocityAlpha:           restartVelocityAlpha,
+		WatchEtcdCerts:                 watchEtcdCerts,
+		EtcdSecretNames:                splitList(etcdSecretNames),
+		KubeadmMode:                    kubeadmMode,
+		AutoDiscoverCerts:              autoDiscoverCerts,
+		AutoDiscoverNamespaces:         splitList(autoDiscoverNamespaces),
+		AutoDiscoverMax:                autoDiscoverMax,
+		TrustedCACommonNames:           splitList(trustedCACommonNames),
+		IncludePodUID:                  includePodUID,
+		WatchFilter:                    controller.DefaultWatchFilter{},
+		MaxSecretKeySize:               maxSecretKeySize,
+		MaxPEMBlocksPerKey:             maxPEMBlocksPerKey,
+		RepeatedExitCodeEventThreshold: repeatedExitCodeEventThreshold,
+		WatchPodDisruptionBudgets:      watchPodDisruptionBudgets,
+		WatchNodes:                     watchNodes,
+		WatchProbeEvents:               watchProbeEvents,
+		LivenessCorrelationWindow:      livenessCorrelationWindow,
+		EnableNodeWatch:                enableNodeWatch,
+		EnableCSRWatch:                 enableCSRWatch,
+		CSRPendingAlertThreshold:       csrPendingAlertThreshold,
+		PatchWorkloadConditions:        patchWorkloadConditions,
+		WorkloadConditionCoolDown:      workloadConditionCoolDown,
+		APIErrorThreshold:              apiErrorThreshold,
+		APIBackoffCoolOff:              apiBackoffCoolOff,
+		SecretRecheckJitter:            secretRecheckJitter,
+		SecretStartupSpread:            secretStartupSpread,
+		SecretWatchConfigFile:          secretWatchConfig,
+		UseMetricsAPI:                  useMetricsAPI,
+		CPUThrottlingThreshold:         cpuThrottlingThreshold,
+		LinkerdMode:                    linkerdMode,
+		LinkerdNamespace:               linkerdNamespace,
+		LinkerdRotationWindow:          linkerdRotationWindow,
+	}
+
+	if slackWebhookURL != "" {
+		reconciler.SlackNotifier = controller.NewSlackNotifier(slackWebhookURL)
+	}
+
+	// 按功能探测权限：缺少权限的功能被关闭，而不是在运行时反复报 Forbidden
+	features := reconciler.Features()
+	if createServiceMonitor {
+		features = append(features, controller.Feature{
+			Name: controller.FeatureServiceMonitor,
+			Permissions: []controller.Permission{
+				{Group: "monitoring.coreos.com", Resource: "servicemonitors", Verb: "get"},
+				{Group: "monitoring.coreos.com", Resource: "servicemonitors", Verb: "create"},
+				{Group: "monitoring.coreos.com", Resource: "servicemonitors", Verb: "update"},
+				{Resource: "services", Verb: "get"},
+			},
+		})
+	}
+	if enableRestartBudgets {
+		features = append(features, controller.Feature{
+			Name: controller.FeatureRestartBudgets,
+			Permissions: []controller.Permission{
+				{Group: monitorv1alpha1.GroupVersion.Group, Resource: "podrestartbudgets", Verb: "list"},
+				{Group: monitorv1alpha1.GroupVersion.Group, Resource: "podrestartbudgets", Verb: "watch"},
+				{Group: monitorv1alpha1.GroupVersion.Group, Resource: "podrestartbudgets/status", Verb: "update"},
+			},
+		})
+	}
+	disabled, err := controller.ProbeFeatures(ctx, mgr.GetClient(), features)
+	if err != nil {
+		// 无法探测时保持配置不变，由 reconcile 自行报错
+		setupLog.Error(err, "unable to probe the RBAC permissions of features")
+	}
+	for _, feature := range features {
+		missing, ok := disabled[feature.Name]
+		if !ok {
+			continue
+		}
+		permissions := make([]string, 0, len(missing))
+		for _, permission := range missing {
+			permissions = append(permissions, permission.String())
+		}
+		if strictRBAC {
+			setupLog.Error(nil, "Feature lacks RBAC permissions", "feature", feature.Name,
+				"missing", permissions)
+			continue
+		}
+		setupLog.Info("Disabling feature because of missing RBAC permissions", "feature", feature.Name,
+			"missing", permissions)
+		reconciler.DisableFeature(feature.Name)
+		switch feature.Name {
+		case controller.FeatureServiceMonitor:
+			createServiceMonitor = false
+		case controller.FeatureRestartBudgets:
+			enableRestartBudgets = false
+		}
+	}
+	if len(disabled) > 0 && strictRBAC {
+		setupLog.Error(nil, "Exiting because of features without RBAC permissions (--strict-rbac)")
+		os.Exit(1)
+	}
+
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PodMonitor")
 		os.Exit(1)
 	}
+	if enableRestartBudgets {
+		if err = (&controller.PodRestartBudgetReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PodRestartBudget")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
+	// 上报关闭缺少权限的功能之后的实际配置
+	buildFeatures := reconciler.BuildInfoFeatures()
+	buildFeatures[controller.FeatureLeaderElect] = enableLeaderElection
+	buildFeatures[controller.FeatureSimulateRestarts] = simulateRestarts
+	controller.SetBuildInfo(buildFeatures)
+
+	if err := mgr.Add(controller.NewLeaderTracker()); err != nil {
+		setupLog.Error(err, "unable to add leader tracker to manager")
+		os.Exit(1)
+	}
+
+	if seriesConsistencyInterval > 0 {
+		if err := mgr.Add(controller.NewSeriesConsistencyChecker(metrics.Registry,
+			seriesConsistencyInterval)); err != nil {
+			setupLog.Error(err, "unable to add series consistency checker to manager")
+			os.Exit(1)
+		}
+	}
+
+	if stateAPIAddr != "0" {
+		setupLog.Info("Adding state API server to manager", "addr", stateAPIAddr)
+		if err := mgr.Add(controller.NewStateServer(stateAPIAddr)); err != nil {
+			setupLog.Error(err, "unable to add state API server to manager")
+			os.Exit(1)
+		}
+	}
+
+	if grpcAddr != "0" {
+		// 与 metrics server 共用证书；未提供证书时使用明文
+		var grpcTLSConfig *tls.Config
+		if metricsCertWatcher != nil {
+			grpcTLSConfig = &tls.Config{
+				MinVersion:     tls.VersionTLS12,
+				GetCertificate: metricsCertWatcher.GetCertificate,
+			}
+		} else {
+			setupLog.Info("No metrics certificate configured, serving the gRPC event stream without TLS")
+		}
+		setupLog.Info("Adding gRPC event server to manager", "addr", grpcAddr)
+		if err := mgr.Add(controller.NewEventServer(grpcAddr, grpcTLSConfig)); err != nil {
+			setupLog.Error(err, "unable to add gRPC event server to manager")
+			os.Exit(1)
+		}
+	}
+
+	if createServiceMonitor {
+		setupLog.Info("Adding ServiceMonitor installer to manager", "service", serviceMonitorService)
+		if err := mgr.Add(controller.NewServiceMonitorInstaller(mgr, serviceMonitorNamespace, serviceMonitorService,
+			serviceMonitorPort, secureMetrics)); err != nil {
+			setupLog.Error(err, "unable to add ServiceMonitor installer to manager")
+			os.Exit(1)
+		}
+	}
+
+	if simulateRestarts {
+		setupLog.Info("Adding restart simulator to manager", "rate", simulateRate)
+		if err := mgr.Add(controller.NewRestartSimulator(simulateRate)); err != nil {
+			setupLog.Error(err, "unable to add restart simulator to manager")
+			os.Exit(1)
+		}
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -236,8 +682,19 @@ func main() {
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command event-stream-client prints the events streamed by the operator's
+// gRPC event server (--grpc-addr).
+//
+//	go run ./examples/event-stream-client --addr localhost:9090 --namespaces default,payments --insecure
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+)
+
+func main() {
+	var addr, namespaces, caFile string
+	var plaintext bool
+	flag.StringVar(&addr, "addr", "localhost:9090", "Address of the operator's gRPC event server.")
+	flag.StringVar(&namespaces, "namespaces", "", "Comma-separated namespaces to watch. Empty watches all.")
+	flag.StringVar(&caFile, "ca-file", "", "CA bundle used to verify the server certificate. "+
+		"Defaults to the system roots.")
+	flag.BoolVar(&plaintext, "insecure", false, "Connect without TLS.")
+	flag.Parse()
+
+	creds, err := transportCredentials(caFile, plaintext)
+	if err != nil {
+		log.Fatal(err)
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	req := &eventsv1.WatchEventsRequest{}
+	if namespaces != "" {
+		req.Namespaces = strings.Split(namespaces, ",")
+	}
+	stream, err := eventsv1.NewEventServiceClient(conn).WatchEvents(ctx, req)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(describe(event))
+	}
+}
+
+func transportCredentials(caFile string, plaintext bool) (credentials.TransportCredentials, error) {
+	if plaintext {
+		return insecure.NewCredentials(), nil
+	}
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+	}
+	return credentials.NewTLS(config), nil
+}
+
+func describe(event *eventsv1.Event) string {
+	prefix := fmt.Sprintf("%s %s", event.GetTime().AsTime().Format("2006-01-02T15:04:05Z07:00"), event.GetNamespace())
+	switch p := event.GetPayload().(type) {
+	case *eventsv1.Event_ContainerRestart:
+		r := p.ContainerRestart
+		return fmt.Sprintf("%s restart %s/%s count=%d reason=%s exit=%d planned=%t during_rollout=%s",
+			prefix, r.GetPod(), r.GetContainer(), r.GetRestartCount(), r.GetReason(), r.GetExitCode(),
+			r.GetPlanned(), r.GetDuringRollout())
+	case *eventsv1.Event_RestartThresholdCrossed:
+		t := p.RestartThresholdCrossed
+		return fmt.Sprintf("%s threshold %s/%s count=%d threshold=%d",
+			prefix, t.GetPod(), t.GetContainer(), t.GetRestartCount(), t.GetThreshold())
+	case *eventsv1.Event_CertificateWarning:
+		w := p.CertificateWarning
+		return fmt.Sprintf("%s certificate %s expires in %d days (%s)",
+			prefix, w.GetSecret(), w.GetDaysUntilExpiration(), w.GetSeverity())
+	case *eventsv1.Event_CertificateRotated:
+		r := p.CertificateRotated
+		return fmt.Sprintf("%s certificate %s/%s rotated, now expires %s",
+			prefix, r.GetSecret(), r.GetCertType(), r.GetNotAfter().AsTime().Format("2006-01-02"))
+	default:
+		return prefix + " unknown event"
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// apiErrorRequeueBase is the base delay before retrying after a throttled
+	// or timed out API request; it is jittered by up to 100%.
+	apiErrorRequeueBase = 5 * time.Second
+	// defaultAPIErrorThreshold is the number of consecutive API errors after
+	// which pod reconciles are paused.
+	defaultAPIErrorThreshold = 20
+	// defaultAPIBackoffCoolOff is how long pod reconciles are paused.
+	defaultAPIBackoffCoolOff = 30 * time.Second
+)
+
+var (
+	// 连续 API 错误过多时暂停 Pod reconcile，值为 1 表示处于暂停期
+	apiserverBackoffActive = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_apiserver_backoff_active",
+			Help: "1 while pod reconciles are paused after too many consecutive API server errors, else 0",
+		},
+	)
+)
+
+func init() {
+	registerMetrics(apiserverBackoffActive)
+}
+
+// isRetriableAPIError reports whether err means the API server is throttling
+// or overloaded, in which case retrying immediately only adds load.
+func isRetriableAPIError(err error) bool {
+	return apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// apiErrorRequeueAfter returns a jittered delay before retrying after err,
+// honoring the Retry-After the API server suggested, if any.
+func apiErrorRequeueAfter(err error) time.Duration {
+	base := apiErrorRequeueBase
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+		base = time.Duration(seconds) * time.Second
+	}
+	return wait.Jitter(base, 1.0)
+}
+
+// apiCircuitBreaker opens after more than threshold consecutive API errors
+// and stays open for the cool-off period. A nil breaker never opens.
+type apiCircuitBreaker struct {
+	mu          sync.Mutex
+	threshold   int
+	coolOff     time.Duration
+	consecutive int
+	openUntil   time.Time
+}
+
+func newAPICircuitBreaker(threshold int, coolOff time.Duration) *apiCircuitBreaker {
+	if threshold <= 0 {
+		threshold = defaultAPIErrorThreshold
+	}
+	if coolOff <= 0 {
+		coolOff = defaultAPIBackoffCoolOff
+	}
+	return &apiCircuitBreaker{threshold: threshold, coolOff: coolOff}
+}
+
+// recordFailure counts an API error and opens the breaker once the threshold
+// is exceeded.
+func (b *apiCircuitBreaker) recordFailure(now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutive++
+	if b.consecutive > b.threshold && !now.Before(b.openUntil) {
+		b.openUntil = now.Add(b.coolOff)
+		apiserverBackoffActive.Set(1)
+	}
+}
+
+// recordSuccess resets the consecutive error count.
+func (b *apiCircuitBreaker) recordSuccess() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutive = 0
+}
+
+// remaining returns how long the breaker stays open, or zero when closed.
+// After the cool-off the breaker closes and requests are let through again;
+// the next failure reopens it immediately.
+func (b *apiCircuitBreaker) remaining(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return 0
+	}
+	if now.Before(b.openUntil) {
+		return b.openUntil.Sub(now)
+	}
+	b.openUntil = time.Time{}
+	// 冷却期后仍保留阈值附近的计数，失败一次即重新打开
+	b.consecutive = b.threshold
+	apiserverBackoffActive.Set(0)
+	return 0
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestAPICircuitBreaker(t *testing.T) {
+	b := newAPICircuitBreaker(3, time.Minute)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		b.recordFailure(now)
+	}
+	if b.remaining(now) != 0 {
+		t.Fatal("expected the breaker to stay closed at the threshold")
+	}
+	b.recordSuccess()
+	for i := 0; i < 4; i++ {
+		b.recordFailure(now)
+	}
+	if got := b.remaining(now); got != time.Minute {
+		t.Fatalf("expected the breaker to open for 1m, got %v", got)
+	}
+	if testutil.ToFloat64(apiserverBackoffActive) != 1 {
+		t.Fatal("expected pod_monitor_apiserver_backoff_active to be 1")
+	}
+
+	// 冷却期结束后关闭；再失败一次立即重新打开
+	later := now.Add(time.Minute)
+	if b.remaining(later) != 0 || testutil.ToFloat64(apiserverBackoffActive) != 0 {
+		t.Fatal("expected the breaker to close after the cool-off")
+	}
+	b.recordFailure(later)
+	if b.remaining(later) == 0 {
+		t.Fatal("expected one more failure after the cool-off to reopen the breaker")
+	}
+	b.recordSuccess()
+	if b.remaining(later.Add(time.Minute)) != 0 {
+		t.Fatal("expected the breaker to close")
+	}
+
+	// nil 的断路器（未经 SetupWithManager）永远不打开
+	var disabled *apiCircuitBreaker
+	disabled.recordFailure(now)
+	if disabled.remaining(now) != 0 {
+		t.Fatal("expected a nil breaker to stay closed")
+	}
+}
+
+func TestIsRetriableAPIError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "pods"}
+	if !isRetriableAPIError(apierrors.NewTooManyRequests("slow down", 2)) {
+		t.Error("expected 429 to be retriable")
+	}
+	if !isRetriableAPIError(apierrors.NewServerTimeout(gr, "get", 1)) {
+		t.Error("expected a server timeout to be retriable")
+	}
+	if isRetriableAPIError(apierrors.NewForbidden(gr, "app", errors.New("denied"))) {
+		t.Error("expected 403 not to be retriable")
+	}
+	if d := apiErrorRequeueAfter(apierrors.NewTooManyRequests("slow down", 2)); d < 2*time.Second || d > 4*time.Second {
+		t.Errorf("expected the Retry-After to be honored with jitter, got %v", d)
+	}
+}
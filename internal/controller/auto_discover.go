@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// defaultAutoDiscoverMax caps the number of auto-discovered secrets when
+	// AutoDiscoverMax is unset.
+	defaultAutoDiscoverMax = 500
+	// autoDiscoverAnnotation set to "false" excludes a secret from auto-discovery.
+	autoDiscoverAnnotation = "pod-monitor.deraiven.io/auto-discover"
+)
+
+var (
+	// 因达到 --auto-discover-max 上限而未被监控的自动发现 Secret 的检查次数
+	autoDiscoverExceededTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_auto_discover_exceeded_total",
+			Help: "Number of checks of a secret with certificate keys that was not monitored because " +
+				"--auto-discover-max auto-discovered secrets are already monitored.",
+		},
+	)
+
+	// autoDiscoverSuffixes are the key suffixes inspected in auto-discover mode.
+	autoDiscoverSuffixes = []string{".crt", ".pem", ".cer"}
+
+	// 不可能包含证书的 Secret 类型，只凭元数据即可排除
+	nonCertificateSecretTypes = []corev1.SecretType{
+		corev1.SecretTypeDockercfg,
+		corev1.SecretTypeDockerConfigJson,
+		corev1.SecretTypeBasicAuth,
+		corev1.SecretTypeSSHAuth,
+		corev1.SecretTypeBootstrapToken,
+		"helm.sh/release.v1",
+	}
+)
+
+func init() {
+	registerMetrics(autoDiscoverExceededTotal)
+}
+
+// autoDiscoverCandidate reports from the metadata alone whether a secret may
+// hold auto-discoverable certificates, so that its data is only scanned when
+// it can.
+func (r *PodMonitorReconciler) autoDiscoverCandidate(secret *corev1.Secret) bool {
+	if !r.AutoDiscoverCerts || secret.Annotations[autoDiscoverAnnotation] == "false" {
+		return false
+	}
+	if len(r.AutoDiscoverNamespaces) > 0 && !slices.Contains(r.AutoDiscoverNamespaces, secret.Namespace) {
+		return false
+	}
+	return !slices.Contains(nonCertificateSecretTypes, secret.Type)
+}
+
+// autoDiscoverKeys returns the keys of a secret that end in a certificate
+// suffix and parse as certificates.
+func (r *PodMonitorReconciler) autoDiscoverKeys(secret *corev1.Secret) []string {
+	var keys []string
+	for _, key := range sortedDataKeys(secret) {
+		if !slices.ContainsFunc(autoDiscoverSuffixes, func(suffix string) bool {
+			return strings.HasSuffix(key, suffix)
+		}) {
+			continue
+		}
+		if cert, _, _ := r.parseLeafCertificate(secret.Data[key]); cert != nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// autoDiscoverMax returns the configured cap or its default.
+func (r *PodMonitorReconciler) autoDiscoverMax() int {
+	if r.AutoDiscoverMax > 0 {
+		return r.AutoDiscoverMax
+	}
+	return defaultAutoDiscoverMax
+}
+
+// admitAutoDiscovered adds a secret to the auto-discovered set unless the set
+// already holds max secrets. Secrets already in the set are always admitted.
+func (s *restartStateStore) admitAutoDiscovered(key string, max int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.autoDiscovered[key]; ok {
+		return true
+	}
+	if len(s.autoDiscovered) >= max {
+		return false
+	}
+	s.autoDiscovered[key] = struct{}{}
+	return true
+}
+
+// releaseAutoDiscovered frees the slot of a secret that no longer holds
+// certificates.
+func (s *restartStateStore) releaseAutoDiscovered(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.autoDiscovered, key)
+}
+
+// checkAutoDiscoveredCertificates checks the certificate keys of a secret that
+// none of the configured key lists matched. Secrets beyond AutoDiscoverMax
+// are skipped and counted; explicitly configured secrets never reach this
+// function and are not subject to the cap.
+func (r *PodMonitorReconciler) checkAutoDiscoveredCertificates(ctx context.Context, secret *corev1.Secret) {
+	log := logf.FromContext(ctx)
+	key := fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
+
+	var certKeys []string
+	if r.autoDiscoverCandidate(secret) {
+		certKeys = r.autoDiscoverKeys(secret)
+	}
+	if len(certKeys) == 0 {
+		stateStore.releaseAutoDiscovered(key)
+		return
+	}
+	if !stateStore.admitAutoDiscovered(key, r.autoDiscoverMax()) {
+		autoDiscoverExceededTotal.Inc()
+		log.V(1).Info("Auto-discovered secret not monitored, limit reached", "limit", r.autoDiscoverMax())
+		return
+	}
+	for _, certKey := range certKeys {
+		if err := r.checkCertificateExpiration(ctx, secret.Namespace, secret.Name, certKey,
+			secret.Data[certKey]); err != nil {
+			log.Error(err, "Failed to check auto-discovered certificate expiration", "key", certKey)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestAutoDiscoveredCertificates(t *testing.T) {
+	const namespace = "auto-discover-test"
+	certPEM := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "discovered"}).CertPEM()
+	newSecret := func(name string, secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+		secret := testsupport.NewSecret(namespace, name, data)
+		secret.Type = secretType
+		return secret
+	}
+	r := &PodMonitorReconciler{AutoDiscoverCerts: true, AutoDiscoverMax: 1}
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer stateStore.forgetSecret(namespace, "first")
+	defer stateStore.forgetSecret(namespace, "second")
+	monitored := func(name, key string) bool {
+		return testutil.ToFloat64(certificateExpirationTime.WithLabelValues(namespace, name, key,
+			certificateSourceSecret, "")) > 0
+	}
+
+	// 只检查证书后缀且能解析的键
+	r.checkAutoDiscoveredCertificates(context.Background(), newSecret("first", corev1.SecretTypeOpaque,
+		map[string][]byte{"server.cer": certPEM, "notes.pem": []byte("not a certificate"), "password": certPEM}))
+	if !monitored("first", "server.cer") {
+		t.Fatal("expected server.cer to be discovered")
+	}
+	if n := testutil.CollectAndCount(certificateExpirationTime); n != 1 {
+		t.Fatalf("expected only server.cer to be monitored, got %d series", n)
+	}
+
+	// 达到上限后，新的 Secret 只计数
+	before := testutil.ToFloat64(autoDiscoverExceededTotal)
+	r.checkAutoDiscoveredCertificates(context.Background(), newSecret("second", corev1.SecretTypeOpaque,
+		map[string][]byte{"ca.cer": certPEM}))
+	if got := testutil.ToFloat64(autoDiscoverExceededTotal) - before; got != 1 {
+		t.Fatalf("expected the capped secret to be counted, got %v", got)
+	}
+	if n := testutil.CollectAndCount(certificateExpirationTime); n != 1 {
+		t.Fatalf("expected the capped secret not to be monitored, got %d series", n)
+	}
+
+	// 已监控的 Secret 不再含证书时释放名额
+	r.checkAutoDiscoveredCertificates(context.Background(), newSecret("first", corev1.SecretTypeOpaque, nil))
+	r.checkAutoDiscoveredCertificates(context.Background(), newSecret("second", corev1.SecretTypeOpaque,
+		map[string][]byte{"ca.cer": certPEM}))
+	if !monitored("second", "ca.cer") {
+		t.Fatal("expected the freed slot to be used")
+	}
+}
+
+func TestDeletedAutoDiscoveredSecretsFreeSlots(t *testing.T) {
+	const namespace = "auto-discover-delete-test"
+	ctx := context.Background()
+	certPEM := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "discovered"}).CertPEM()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, AutoDiscoverCerts: true, AutoDiscoverMax: 2}
+	reconcile := func(name string) {
+		t.Helper()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	create := func(name string) *corev1.Secret {
+		t.Helper()
+		secret := testsupport.NewSecret(namespace, name, map[string][]byte{"server.cer": certPEM})
+		if err := c.Create(ctx, secret); err != nil {
+			t.Fatal(err)
+		}
+		reconcile(name)
+		return secret
+	}
+	labels := func(name string) testsupport.Labels {
+		return testsupport.Labels{"namespace": namespace, "secret_name": name, "cert_type": "server.cer"}
+	}
+	defer forgetSecretCertificates(namespace, "replacement")
+
+	// 反复创建、删除的 Secret 不会一直占用名额
+	for i := 0; i < 3; i++ {
+		var secrets []*corev1.Secret
+		for j := 0; j < 2; j++ {
+			secrets = append(secrets, create(fmt.Sprintf("churn-%d-%d", i, j)))
+		}
+		for _, secret := range secrets {
+			if err := c.Delete(ctx, secret); err != nil {
+				t.Fatal(err)
+			}
+			reconcile(secret.Name)
+			testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_expiration_timestamp_seconds",
+				labels(secret.Name))
+		}
+	}
+
+	before := testutil.ToFloat64(autoDiscoverExceededTotal)
+	create("replacement")
+	if got := testutil.ToFloat64(autoDiscoverExceededTotal) - before; got != 0 {
+		t.Errorf("expected a free slot after the deletions, %v checks were capped", got)
+	}
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_certificate_expiration_timestamp_seconds",
+		labels("replacement")); n != 1 {
+		t.Errorf("expected the new secret to be monitored, got %d series", n)
+	}
+}
+
+func TestAutoDiscoverCandidate(t *testing.T) {
+	r := &PodMonitorReconciler{AutoDiscoverCerts: true, AutoDiscoverNamespaces: []string{"apps"}}
+	tests := []struct {
+		name   string
+		secret corev1.Secret
+		want   bool
+	}{
+		{"opaque", corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "apps"}, Type: corev1.SecretTypeOpaque},
+			true},
+		{"other namespace", corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, false},
+		{"helm release", corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "apps"}, Type: "helm.sh/release.v1"},
+			false},
+		{"opted out", corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "apps",
+			Annotations: map[string]string{autoDiscoverAnnotation: "false"}}}, false},
+	}
+	for _, tt := range tests {
+		if got := r.autoDiscoverCandidate(&tt.secret); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Deraiven/pod-monitor-operator/internal/version"
+)
+
+var (
+	// 构建版本与生效的功能开关，值恒为 1
+	buildInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_build_info",
+			Help: "Build information and enabled features of the running operator. The value is always 1.",
+		},
+		append([]string{
+			"version",    // 版本
+			"git_commit", // 构建时的提交
+			"go_version", // Go 版本
+		}, buildInfoFeatures...),
+	)
+
+	// 当前生效的构建信息，供 /version 使用
+	currentBuildInfo   BuildInfo
+	currentBuildInfoMu sync.RWMutex
+)
+
+func init() {
+	registerMetrics(buildInfo)
+}
+
+// BuildInfo describes the running binary and its resolved configuration.
+type BuildInfo struct {
+	Version   string          `json:"version"`
+	GitCommit string          `json:"gitCommit"`
+	BuildDate string          `json:"buildDate"`
+	GoVersion string          `json:"goVersion"`
+	Features  map[string]bool `json:"features"`
+}
+
+// Feature names reported in BuildInfo.Features and as build_info labels.
+const (
+	FeatureLeaderElect                 = "leader_elect"
+	FeatureValidateCertificateHostname = "validate_certificate_hostnames"
+	FeatureExposeContainerInfo         = "expose_container_info"
+	FeatureAnnotateSecrets             = "annotate_secrets"
+	FeatureWatchEtcdCerts              = "watch_etcd_certs"
+	FeatureSimulateRestarts            = "simulate_restarts"
+	FeatureLinkerdMode                 = "linkerd_mode"
+	FeatureAutoDiscoverCerts           = "auto_discover_certs"
+)
+
+// buildInfoFeatures are the features exported as build_info labels, in label
+// order. Features missing from the map passed to SetBuildInfo are "false".
+var buildInfoFeatures = []string{
+	FeatureLeaderElect,
+	FeatureValidateCertificateHostname,
+	FeatureExposeContainerInfo,
+	FeatureAnnotateSecrets,
+	FeatureWatchEtcdCerts,
+	FeatureSimulateRestarts,
+	FeatureLinkerdMode,
+	FeatureKubeadm,
+	FeatureCSRWatch,
+	FeatureAutoDiscoverCerts,
+	FeatureWorkloadConditions,
+}
+
+// BuildInfoFeatures returns the resolved configuration of the reconciler as
+// reported by SetBuildInfo. Call it after DisableFeature so that features
+// turned off for missing permissions are reported as disabled.
+func (r *PodMonitorReconciler) BuildInfoFeatures() map[string]bool {
+	return map[string]bool{
+		FeatureValidateCertificateHostname: r.ValidateCertificateHostnames,
+		FeatureExposeContainerInfo:         r.ExposeContainerInfo,
+		FeatureAnnotateSecrets:             r.AnnotateSecrets,
+		FeatureWatchEtcdCerts:              r.WatchEtcdCerts,
+		FeatureLinkerdMode:                 r.LinkerdMode,
+		FeatureKubeadm:                     r.KubeadmMode,
+		FeatureCSRWatch:                    r.EnableCSRWatch,
+		FeatureAutoDiscoverCerts:           r.AutoDiscoverCerts,
+		FeatureWorkloadConditions:          r.PatchWorkloadConditions,
+	}
+}
+
+// SetBuildInfo exports pod_monitor_build_info for the given feature flags and
+// makes the build information available on the /version endpoint.
+func SetBuildInfo(features map[string]bool) {
+	info := BuildInfo{
+		Version:   version.Version,
+		GitCommit: version.GitCommit,
+		BuildDate: version.BuildDate,
+		GoVersion: runtime.Version(),
+		Features:  features,
+	}
+
+	currentBuildInfoMu.Lock()
+	currentBuildInfo = info
+	currentBuildInfoMu.Unlock()
+
+	labels := prometheus.Labels{
+		"version":    info.Version,
+		"git_commit": info.GitCommit,
+		"go_version": info.GoVersion,
+	}
+	for _, feature := range buildInfoFeatures {
+		labels[feature] = strconv.FormatBool(features[feature])
+	}
+	buildInfo.Reset()
+	buildInfo.With(labels).Set(1)
+}
+
+// getBuildInfo returns the build information set by SetBuildInfo.
+func getBuildInfo() BuildInfo {
+	currentBuildInfoMu.RLock()
+	defer currentBuildInfoMu.RUnlock()
+	info := currentBuildInfo
+	if info.Version == "" {
+		// SetBuildInfo 尚未调用时仍返回版本信息
+		info = BuildInfo{
+			Version:   version.Version,
+			GitCommit: version.GitCommit,
+			BuildDate: version.BuildDate,
+			GoVersion: runtime.Version(),
+		}
+	}
+	return info
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/internal/version"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+// resetBuildInfo clears what SetBuildInfo recorded.
+func resetBuildInfo() {
+	buildInfo.Reset()
+	currentBuildInfoMu.Lock()
+	currentBuildInfo = BuildInfo{}
+	currentBuildInfoMu.Unlock()
+}
+
+// getVersion requests /version and decodes the response.
+func getVersion(t *testing.T) BuildInfo {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	NewStateServer(":0").mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var info BuildInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestBuildInfoDefaults(t *testing.T) {
+	defer resetBuildInfo()
+
+	// SetBuildInfo 调用前 /version 仍返回未通过 ldflags 设置的默认值
+	info := getVersion(t)
+	if info.Version != "dev" || info.GitCommit != "dev" || info.BuildDate != "dev" ||
+		info.GoVersion != runtime.Version() {
+		t.Errorf("expected the dev defaults, got %+v", info)
+	}
+
+	SetBuildInfo(map[string]bool{})
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_build_info",
+		testsupport.Labels{"version": "dev", "git_commit": "dev", "go_version": runtime.Version(),
+			"leader_elect": "false", "watch_etcd_certs": "false"}, 1)
+}
+
+func TestBuildInfoFromLdflags(t *testing.T) {
+	// 模拟 -ldflags "-X .../internal/version.Version=..." 设置的变量
+	saved := [3]string{version.Version, version.GitCommit, version.BuildDate}
+	version.Version, version.GitCommit, version.BuildDate = "1.4.0", "2e8059e", "2025-06-01T00:00:00Z"
+	defer func() {
+		version.Version, version.GitCommit, version.BuildDate = saved[0], saved[1], saved[2]
+		resetBuildInfo()
+	}()
+
+	SetBuildInfo(map[string]bool{FeatureLeaderElect: true, FeatureWatchEtcdCerts: true})
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_build_info",
+		testsupport.Labels{"version": "1.4.0", "git_commit": "2e8059e", "go_version": runtime.Version(),
+			"leader_elect": "true", "watch_etcd_certs": "true", "annotate_secrets": "false"}, 1)
+	// 再次设置时替换而不是新增序列
+	SetBuildInfo(map[string]bool{FeatureLeaderElect: true})
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_build_info", nil); n != 1 {
+		t.Errorf("expected a single build_info series, got %d", n)
+	}
+
+	info := getVersion(t)
+	if info.Version != "1.4.0" || info.GitCommit != "2e8059e" || info.BuildDate != "2025-06-01T00:00:00Z" ||
+		info.GoVersion != runtime.Version() {
+		t.Errorf("unexpected build information %+v", info)
+	}
+	if !info.Features[FeatureLeaderElect] || info.Features[FeatureWatchEtcdCerts] {
+		t.Errorf("expected only leader_elect to be enabled, got %v", info.Features)
+	}
+}
+
+func TestBuildInfoResolvedFeatures(t *testing.T) {
+	defer resetBuildInfo()
+
+	// 缺少权限而关闭的功能按关闭上报
+	r := &PodMonitorReconciler{LinkerdMode: true, KubeadmMode: true, AutoDiscoverCerts: true}
+	r.DisableFeature(FeatureKubeadm)
+	features := r.BuildInfoFeatures()
+	features[FeatureLeaderElect] = true
+	SetBuildInfo(features)
+
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_build_info",
+		testsupport.Labels{"linkerd_mode": "true", "kubeadm": "false", "auto_discover_certs": "true",
+			"csr_watch": "false", "workload_conditions": "false", "leader_elect": "true"}, 1)
+	if info := getVersion(t); !info.Features[FeatureLinkerdMode] || info.Features[FeatureKubeadm] {
+		t.Errorf("expected /version to report the resolved features, got %v", info.Features)
+	}
+}
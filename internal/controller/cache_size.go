@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// cacheSizeInterval is how often the informer cache is counted.
+const cacheSizeInterval = 5 * time.Minute
+
+var (
+	// informer 缓存中每种资源的对象数，用于评估 Operator 的内存需求
+	operatorCacheSize = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_operator_cache_size",
+			Help: "Number of objects of each watched resource in the operator's informer cache, counted every " +
+				"five minutes.",
+		},
+		[]string{
+			"resource", // 资源名称，例如 pods、secrets
+		},
+	)
+)
+
+func init() {
+	registerMetrics(operatorCacheSize)
+}
+
+// cacheSizeReporter periodically counts the objects of the watched resources
+// in the informer cache. Every replica has its own cache, so it runs without
+// leader election.
+type cacheSizeReporter struct {
+	reader   client.Reader
+	interval time.Duration
+	// key: 资源名称，只包含已被监听的资源，避免为计数启动新的 informer；
+	// 构造函数返回 nil 时本轮不计数
+	lists map[string]func() client.ObjectList
+}
+
+var _ manager.Runnable = &cacheSizeReporter{}
+var _ manager.LeaderElectionRunnable = &cacheSizeReporter{}
+
+func newCacheSizeReporter(reader client.Reader, interval time.Duration,
+	lists map[string]func() client.ObjectList) *cacheSizeReporter {
+	return &cacheSizeReporter{reader: reader, interval: interval, lists: lists}
+}
+
+// Start counts the cache once it has synced, then every interval until the
+// context is cancelled.
+func (c *cacheSizeReporter) Start(ctx context.Context) error {
+	if syncer, ok := c.reader.(interface{ WaitForCacheSync(context.Context) bool }); ok {
+		if !syncer.WaitForCacheSync(ctx) {
+			return nil
+		}
+	}
+	c.report(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.report(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns false: followers fill their cache too.
+func (c *cacheSizeReporter) NeedLeaderElection() bool {
+	return false
+}
+
+// report lists every watched resource from the cache and exports its size.
+// The lists share the cached objects instead of copying them.
+func (c *cacheSizeReporter) report(ctx context.Context) {
+	log := logf.FromContext(ctx).WithName("cache-size")
+	for resource, newList := range c.lists {
+		list := newList()
+		if list == nil {
+			continue
+		}
+		if err := c.reader.List(ctx, list, client.UnsafeDisableDeepCopy); err != nil {
+			log.Error(err, "Failed to list cached objects", "resource", resource)
+			continue
+		}
+		operatorCacheSize.WithLabelValues(resource).Set(float64(meta.LenList(list)))
+	}
+}
+
+// cachedResourceLists returns the resources the controller watches, with a
+// constructor of their list type.
+func (r *PodMonitorReconciler) cachedResourceLists() map[string]func() client.ObjectList {
+	lists := map[string]func() client.ObjectList{
+		"pods": func() client.ObjectList { return &corev1.PodList{} },
+	}
+	if !r.DisableSecretWatch {
+		lists["secrets"] = func() client.ObjectList {
+			// Secret 监听被跳过时没有 informer，不计数
+			if r.skipSecretWatch.Load() {
+				return nil
+			}
+			return &corev1.SecretList{}
+		}
+	}
+	if !r.DisableNodeDrainTracking || r.EnableNodeWatch || r.WatchNodes {
+		lists["nodes"] = func() client.ObjectList { return &corev1.NodeList{} }
+	}
+	if r.ValidateCertificateHostnames {
+		lists["ingresses"] = func() client.ObjectList { return &networkingv1.IngressList{} }
+	}
+	if r.WatchPodDisruptionBudgets {
+		lists["poddisruptionbudgets"] = func() client.ObjectList { return &policyv1.PodDisruptionBudgetList{} }
+	}
+	if r.LinkerdMode && !r.DisableSecretWatch && !r.disableLinkerdNamespaceWatch {
+		lists["namespaces"] = func() client.ObjectList { return &corev1.NamespaceList{} }
+	}
+	if r.EnableCSRWatch {
+		lists["certificatesigningrequests"] = func() client.ObjectList {
+			return &certificatesv1.CertificateSigningRequestList{}
+		}
+	}
+	return lists
+}
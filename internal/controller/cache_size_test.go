@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCacheSizeReporter(t *testing.T) {
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		testsupport.NewPod("cache-size-test", "web-1").Build(),
+		testsupport.NewPod("cache-size-test", "web-2").Build(),
+		testsupport.NewSecret("cache-size-test", "web-tls", nil),
+	).Build()
+	defer operatorCacheSize.Reset()
+
+	// 只统计已监听的资源
+	r := &PodMonitorReconciler{DisableNodeDrainTracking: true}
+	lists := r.cachedResourceLists()
+	if len(lists) != 2 {
+		t.Fatalf("expected pods and secrets to be counted, got %d resources", len(lists))
+	}
+	newCacheSizeReporter(c, cacheSizeInterval, lists).report(context.Background())
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_operator_cache_size",
+		testsupport.Labels{"resource": "pods"}, 2)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_operator_cache_size",
+		testsupport.Labels{"resource": "secrets"}, 1)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_operator_cache_size",
+		testsupport.Labels{"resource": "nodes"})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCheckCertificateExpirationWithKeyFirst(t *testing.T) {
+	const namespace = "certificate-chain-test"
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	chain := testsupport.GenerateChain(t, testsupport.CertificateOptions{
+		CommonName: "web.example.com",
+		NotAfter:   now.Add(5 * 24 * time.Hour),
+	})
+	// 私钥在前、CA 在叶子证书之前的 crt.pem
+	data := append(chain.Leaf.KeyPEM(), testsupport.PEMBundle(chain.Intermediate, chain.Leaf)...)
+	r := &PodMonitorReconciler{Clock: clocktesting.NewFakePassiveClock(now)}
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer certificateChainExpirationTime.Reset()
+	defer stateStore.forgetSecret(namespace, "web-tls")
+
+	if err := r.checkCertificateExpiration(context.Background(), namespace, "web-tls", "crt.pem", data); err != nil {
+		t.Fatal(err)
+	}
+
+	// 主要指标来自叶子证书
+	got := testutil.ToFloat64(certificateInfo.WithLabelValues(namespace, "web-tls", "crt.pem", "false", "false",
+		certRoleLeaf))
+	if got != 1 {
+		t.Errorf("expected the leaf to be the primary certificate")
+	}
+	days := certificateDaysUntilExpiration.WithLabelValues(namespace, "web-tls", "crt.pem", certificateSourceSecret, "")
+	if got := testutil.ToFloat64(days); got != 5 {
+		t.Errorf("expected the leaf to expire in 5 days, got %v", got)
+	}
+	if n := testutil.CollectAndCount(certificateChainExpirationTime); n != 2 {
+		t.Errorf("expected a chain series per certificate, got %d", n)
+	}
+	// 文件中 CA 在前，叶子证书位于链中的第二个位置
+	for position, cert := range []*testsupport.Certificate{chain.Intermediate, chain.Leaf} {
+		series := certificateChainExpirationTime.WithLabelValues(namespace, "web-tls", "crt.pem",
+			strconv.Itoa(position), "CN="+cert.Cert.Subject.CommonName)
+		if got := testutil.ToFloat64(series); got != float64(cert.Cert.NotAfter.Unix()) {
+			t.Errorf("expected %s at position %d of the chain", cert.Cert.Subject, position)
+		}
+	}
+
+	// 已过期的叶子证书导出负的剩余天数
+	expired := testsupport.CertificateExpiringIn(t, now, -3, "old.example.com")
+	data = bytes.Join([][]byte{expired.CertPEM(), expired.KeyPEM()}, nil)
+	if err := r.checkCertificateExpiration(context.Background(), namespace, "web-tls", "crt.pem", data); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(days); got != -3 {
+		t.Errorf("expected the expired leaf to report -3 days, got %v", got)
+	}
+}
+
+// 实际 Linkerd 集群中的 crt.pem 只包含一张证书，作为真实格式的回归样本
+func TestCheckCertificateExpirationLinkerdFixture(t *testing.T) {
+	const namespace = "linkerd-fixture-test"
+	data, err := os.ReadFile(filepath.Join("testdata", "linkerd-identity-issuer.crt.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &PodMonitorReconciler{}
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer certificateChainExpirationTime.Reset()
+	defer stateStore.forgetSecret(namespace, "linkerd-identity-issuer")
+
+	if err := r.checkCertificateExpiration(context.Background(), namespace, "linkerd-identity-issuer", "crt.pem",
+		data); err != nil {
+		t.Fatal(err)
+	}
+	// 单张证书不导出链指标
+	if n := testutil.CollectAndCount(certificateChainExpirationTime); n != 0 {
+		t.Errorf("expected no chain series for a single certificate, got %d", n)
+	}
+	notAfter := time.Date(2035, 8, 4, 23, 12, 26, 0, time.UTC)
+	got := testutil.ToFloat64(certificateExpirationTime.WithLabelValues(namespace, "linkerd-identity-issuer",
+		"crt.pem", certificateSourceSecret, ""))
+	if got != float64(notAfter.Unix()) {
+		t.Errorf("expected the expiry of the fixture, got %v", got)
+	}
+	got = testutil.ToFloat64(certificateInfo.WithLabelValues(namespace, "linkerd-identity-issuer", "crt.pem",
+		"true", "false", certRoleIntermediate))
+	if got != 1 {
+		t.Errorf("expected the issuer to be exported as an intermediate CA")
+	}
+}
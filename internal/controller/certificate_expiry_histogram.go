@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// certificateExpiryBuckets are the upper bounds, in days, of the certificate
+// expiry histogram. Expired certificates fall into the 0 bucket.
+var certificateExpiryBuckets = []float64{0, 7, 14, 30, 60, 90, 180, 365, 730}
+
+// certificateDaysByNamespace returns the days until expiration of every known
+// certificate, grouped by namespace.
+func (s *restartStateStore) certificateDaysByNamespace(now time.Time) map[string][]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	days := make(map[string][]float64)
+	for _, c := range s.certificates {
+		days[c.Namespace] = append(days[c.Namespace], c.NotAfter.Sub(now).Hours()/24)
+	}
+	return days
+}
+
+// certificateExpiryHistogramCollector exports the distribution of the days
+// until expiration of all monitored certificates. It is built from the state
+// store at scrape time, so each certificate is counted exactly once however
+// often its secret is reconciled.
+type certificateExpiryHistogramCollector struct {
+	desc *prometheus.Desc
+}
+
+func newCertificateExpiryHistogramCollector() *certificateExpiryHistogramCollector {
+	return &certificateExpiryHistogramCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_certificate_days_until_expiration_histogram",
+			"Distribution of the days until expiration of the monitored certificates. "+
+				"Each certificate is counted once; expired certificates fall into the 0 bucket.",
+			[]string{"namespace"}, nil,
+		),
+	}
+}
+
+func (c *certificateExpiryHistogramCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *certificateExpiryHistogramCollector) Collect(ch chan<- prometheus.Metric) {
+	for namespace, days := range stateStore.certificateDaysByNamespace(time.Now()) {
+		sort.Float64s(days)
+		// 直方图的桶是累计的：每个上界统计不超过它的证书数
+		buckets := make(map[float64]uint64, len(certificateExpiryBuckets))
+		sum := 0.0
+		for _, d := range days {
+			sum += d
+		}
+		for _, bound := range certificateExpiryBuckets {
+			buckets[bound] = uint64(sort.Search(len(days), func(i int) bool { return days[i] > bound }))
+		}
+		ch <- prometheus.MustNewConstHistogram(c.desc, uint64(len(days)), sum, buckets, namespace)
+	}
+}
+
+func init() {
+	registerMetrics(newCertificateExpiryHistogramCollector())
+}
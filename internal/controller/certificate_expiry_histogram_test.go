@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCertificateExpiryHistogram(t *testing.T) {
+	const namespace = "cert-histogram-test"
+	now := time.Now()
+	r := &PodMonitorReconciler{}
+	check := func(certType string, cert *testsupport.Certificate) {
+		t.Helper()
+		if err := r.checkCertificateExpiration(context.Background(), namespace, "tls", certType,
+			cert.CertPEM()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer stateStore.forgetSecret(namespace, "tls")
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer certificateChainExpirationTime.Reset()
+
+	week := testsupport.CertificateExpiringIn(t, now, 5)
+	for certType, days := range map[string]int{"expired.crt": -1, "month.crt": 20, "year.crt": 400} {
+		check(certType, testsupport.CertificateExpiringIn(t, now, days))
+	}
+	// 同一证书多次检查只计一次
+	check("week.crt", week)
+	check("week.crt", week)
+
+	ch := make(chan prometheus.Metric, 16)
+	newCertificateExpiryHistogramCollector().Collect(ch)
+	close(ch)
+	var histogram *dto.Histogram
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatal(err)
+		}
+		if metric.GetLabel()[0].GetValue() == namespace {
+			histogram = metric.GetHistogram()
+		}
+	}
+	if histogram == nil {
+		t.Fatal("no histogram exported for the namespace")
+	}
+
+	if histogram.GetSampleCount() != 4 {
+		t.Errorf("expected 4 certificates to be counted once each, got %d", histogram.GetSampleCount())
+	}
+	want := map[float64]uint64{0: 1, 7: 2, 14: 2, 30: 3, 60: 3, 90: 3, 180: 3, 365: 3, 730: 4}
+	for _, bucket := range histogram.GetBucket() {
+		if got := bucket.GetCumulativeCount(); got != want[bucket.GetUpperBound()] {
+			t.Errorf("bucket le=%v: expected %d, got %d", bucket.GetUpperBound(), want[bucket.GetUpperBound()], got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/x509"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+)
+
+var (
+	// 证书的 SHA-256 指纹，值恒为 1；指纹变化即表示证书已轮换
+	certificateFingerprintInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_certificate_fingerprint_info",
+			Help: "SHA-256 fingerprint of each monitored certificate. The value is always 1; " +
+				"a change of fingerprint means the certificate was rotated.",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"cert_type",   // 证书所在的 key
+			"fingerprint", // DER 编码的 SHA-256，冒号分隔的大写十六进制
+		},
+	)
+)
+
+func init() {
+	registerMetrics(certificateFingerprintInfo)
+}
+
+// recordCertificateFingerprint exports the fingerprint of a certificate,
+// replacing the series of the certificate previously stored under the same
+// key.
+func recordCertificateFingerprint(namespace, secretName, certType string, cert *x509.Certificate) {
+	certificateFingerprintInfo.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+	})
+	certificateFingerprintInfo.With(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+		"fingerprint": certparse.Fingerprint(cert.Raw),
+	}).Set(1)
+}
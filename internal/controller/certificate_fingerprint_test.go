@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCertificateFingerprintInfoReplacedOnRotation(t *testing.T) {
+	const namespace = "certificate-fingerprint-test"
+	clock := clocktesting.NewFakePassiveClock(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	r := &PodMonitorReconciler{Clock: clock}
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer certificateChainExpirationTime.Reset()
+	defer certificateFingerprintInfo.Reset()
+	defer stateStore.forgetSecret(namespace, "web-tls")
+
+	first := testsupport.CertificateExpiringIn(t, clock.Now(), 30, "web.example.com")
+	second := testsupport.CertificateExpiringIn(t, clock.Now(), 90, "web.example.com")
+	for _, cert := range []*testsupport.Certificate{first, first, second} {
+		if err := r.checkCertificateExpiration(context.Background(), namespace, "web-tls", "tls.crt",
+			cert.CertPEM()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_certificate_fingerprint_info",
+		testsupport.Labels{"namespace": namespace}); n != 1 {
+		t.Fatalf("expected one series per certificate, got %d", n)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_fingerprint_info", testsupport.Labels{
+		"namespace":   namespace,
+		"secret_name": "web-tls",
+		"cert_type":   "tls.crt",
+		"fingerprint": certparse.Fingerprint(second.DER()),
+	}, 1)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 命名空间内未进入 critical 阈值的证书占比（0-100），便于顶层面板展示
+	namespaceCertificateHealthScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_namespace_certificate_health_score",
+			Help: "Percentage (0-100) of the monitored certificates of a namespace that are further from " +
+				"expiry than the critical threshold of the namespace policy",
+		},
+		[]string{
+			"namespace", // Secret 所在命名空间
+		},
+	)
+)
+
+func init() {
+	registerMetrics(namespaceCertificateHealthScore)
+}
+
+// computeNamespaceCertHealth returns the percentage of the certificates
+// recorded in a namespace that are healthy: more than criticalDays days from
+// expiry. It reports false when the namespace has no certificates.
+func computeNamespaceCertHealth(namespace string, criticalDays int32, now time.Time) (float64, bool) {
+	certs := stateStore.namespaceCertificates(namespace)
+	if len(certs) == 0 {
+		return 0, false
+	}
+	var good int
+	for _, cert := range certs {
+		// 过期证书的剩余天数为负，同样不计入
+		if cert.NotAfter.Sub(now).Hours()/24 > float64(criticalDays) {
+			good++
+		}
+	}
+	return 100 * float64(good) / float64(len(certs)), true
+}
+
+// updateNamespaceCertHealth refreshes the health score of a namespace after
+// one of its secrets was reconciled or deleted, and returns it. The series
+// is removed once the namespace has no certificates left.
+func updateNamespaceCertHealth(namespace string, criticalDays int32, now time.Time) (float64, bool) {
+	score, ok := computeNamespaceCertHealth(namespace, criticalDays, now)
+	if !ok {
+		namespaceCertificateHealthScore.DeleteLabelValues(namespace)
+		return 0, false
+	}
+	namespaceCertificateHealthScore.WithLabelValues(namespace).Set(score)
+	return score, true
+}
+
+// checkNamespaceCertHealth updates the health score of the namespace of a
+// reconciled secret and emits a Warning event on the secret when every
+// certificate of the namespace is critical or expired.
+func (r *PodMonitorReconciler) checkNamespaceCertHealth(secret *corev1.Secret, policy monitorPolicy, now time.Time) {
+	score, ok := updateNamespaceCertHealth(secret.Namespace, policy.CertCriticalDays, now)
+	if !ok || score > 0 {
+		return
+	}
+	r.eventf(secret, corev1.EventTypeWarning, EventReasonNamespaceCertificatesCritical,
+		"Every certificate in namespace %s is expired or expires in less than %d days",
+		secret.Namespace, policy.CertCriticalDays)
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestNamespaceCertificateHealthScore(t *testing.T) {
+	const namespace = "certificate-health-test"
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	policy := monitorPolicy{CertWarningDays: 30, CertCriticalDays: 7}
+	secrets := []string{"api-tls", "web-tls", "grpc-tls", "old-tls"}
+	defer func() {
+		for _, name := range secrets {
+			stateStore.forgetSecret(namespace, name)
+		}
+		namespaceCertificateHealthScore.DeleteLabelValues(namespace)
+	}()
+	labels := testsupport.Labels{"namespace": namespace}
+
+	if _, ok := computeNamespaceCertHealth(namespace, policy.CertCriticalDays, now); ok {
+		t.Fatal("expected no score for a namespace without certificates")
+	}
+
+	// 正好 7 天已到达 critical 阈值，不算作健康
+	stateStore.recordCertificate(namespace, "api-tls", "tls.crt", now.AddDate(0, 0, 90))
+	stateStore.recordCertificate(namespace, "web-tls", "tls.crt", now.AddDate(0, 0, 7))
+	stateStore.recordCertificate(namespace, "grpc-tls", "tls.crt", now.AddDate(0, 0, 3))
+	stateStore.recordCertificate(namespace, "old-tls", "ca.crt", now.AddDate(0, 0, -1))
+	updateNamespaceCertHealth(namespace, policy.CertCriticalDays, now)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_namespace_certificate_health_score", labels, 25)
+
+	// 只剩 critical 与过期的证书时分数为 0，并发出事件
+	stateStore.forgetSecret(namespace, "api-tls")
+	stateStore.forgetSecret(namespace, "web-tls")
+	recorder := record.NewFakeRecorder(1)
+	r := &PodMonitorReconciler{Recorder: recorder}
+	r.checkNamespaceCertHealth(testsupport.NewSecret(namespace, "grpc-tls", nil), policy, now)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_namespace_certificate_health_score", labels, 0)
+	if len(recorder.Events) != 1 {
+		t.Fatal("expected an event for a health score of 0")
+	}
+	if event := <-recorder.Events; !strings.Contains(event, " "+EventReasonNamespaceCertificatesCritical+" ") {
+		t.Errorf("expected reason %s, got %q", EventReasonNamespaceCertificatesCritical, event)
+	}
+
+	// 最后的证书删除后移除序列
+	stateStore.forgetSecret(namespace, "grpc-tls")
+	stateStore.forgetSecret(namespace, "old-tls")
+	updateNamespaceCertHealth(namespace, policy.CertCriticalDays, now)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_namespace_certificate_health_score", labels)
+}
+
+func TestNamespaceCertificateHealthScoreAfterDelete(t *testing.T) {
+	const namespace = "certificate-health-delete-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	healthy := testsupport.NewTLSSecret(namespace, "healthy", testsupport.CertificateExpiringIn(t, now, 90))
+	expiring := testsupport.NewTLSSecret(namespace, "expiring", testsupport.CertificateExpiringIn(t, now, 3))
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(healthy, expiring).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+	labels := testsupport.Labels{"namespace": namespace}
+	reconcile := func(name string) {
+		t.Helper()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer forgetSecretCertificates(namespace, "healthy")
+	defer namespaceCertificateHealthScore.DeleteLabelValues(namespace)
+
+	reconcile("healthy")
+	reconcile("expiring")
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_namespace_certificate_health_score", labels, 50)
+
+	// 删除的 Secret 的证书不再计入分数
+	if err := c.Delete(ctx, expiring); err != nil {
+		t.Fatal(err)
+	}
+	reconcile("expiring")
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_namespace_certificate_health_score", labels, 100)
+
+	if err := c.Delete(ctx, healthy); err != nil {
+		t.Fatal(err)
+	}
+	reconcile("healthy")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_namespace_certificate_health_score", labels)
+}
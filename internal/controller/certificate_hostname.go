@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+
+var (
+	// 证书与 Ingress 主机名是否匹配：1 表示不匹配，0 表示匹配
+	certificateHostnameMismatch = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_certificate_hostname_mismatch",
+			Help: "Whether the certificate in a secret fails to cover an Ingress host that references it (1 = mismatch, 0 = match)",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"host",        // Ingress TLS 中声明的主机名
+		},
+	)
+)
+
+func init() {
+	registerMetrics(certificateHostnameMismatch)
+}
+
+// reconcileSecretWithHostnameValidation verifies that every host listed in an
+// Ingress TLS block referencing the secret is covered by the certificate's
+// CN/SANs, and records the result in pod_monitor_certificate_hostname_mismatch.
+func (r *PodMonitorReconciler) reconcileSecretWithHostnameValidation(ctx context.Context, secret *corev1.Secret) error {
+	log := logf.FromContext(ctx)
+
+	// 先清理旧的结果，避免 Ingress 删除、主机名变更或证书无法解析后留下过期的序列
+	certificateHostnameMismatch.DeletePartialMatch(prometheus.Labels{
+		"namespace":   secret.Namespace,
+		"secret_name": secret.Name,
+	})
+
+	tlsCrt, exists := secret.Data[corev1.TLSCertKey]
+	if !exists {
+		return nil
+	}
+	cert, _, err := r.parseLeafCertificate(tlsCrt)
+	if cert == nil {
+		return err
+	}
+
+	var ingresses networkingv1.IngressList
+	if err := r.List(ctx, &ingresses, client.InNamespace(secret.Namespace)); err != nil {
+		return err
+	}
+
+	for _, ing := range ingresses.Items {
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName != secret.Name {
+				continue
+			}
+			for _, host := range tls.Hosts {
+				mismatch := 0.0
+				if err := cert.VerifyHostname(host); err != nil {
+					mismatch = 1
+					log.Info("Certificate does not cover Ingress host",
+						"namespace", secret.Namespace,
+						"secret", secret.Name,
+						"ingress", ing.Name,
+						"host", host)
+				}
+				certificateHostnameMismatch.With(prometheus.Labels{
+					"namespace":   secret.Namespace,
+					"secret_name": secret.Name,
+					"host":        host,
+				}).Set(mismatch)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ingressTLSSecrets maps an Ingress to reconcile requests for the secrets
+// referenced by its TLS blocks, so host changes are validated right away.
+func ingressTLSSecrets(_ context.Context, obj client.Object) []reconcile.Request {
+	ing, ok := obj.(*networkingv1.Ingress)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, tls := range ing.Spec.TLS {
+		if tls.SecretName == "" {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: ing.Namespace, Name: tls.SecretName},
+		})
+	}
+	return requests
+}
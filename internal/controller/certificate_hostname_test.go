@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func newTLSIngress(namespace, name, secretName string, hosts ...string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       networkingv1.IngressSpec{TLS: []networkingv1.IngressTLS{{Hosts: hosts, SecretName: secretName}}},
+	}
+}
+
+func TestCertificateHostnameValidation(t *testing.T) {
+	const namespace = "hostname-test"
+	ctx := context.Background()
+	cert := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "web",
+		DNSNames: []string{"web.example.com", "*.apps.example.com"}})
+	secret := testsupport.NewTLSSecret(namespace, "web-tls", cert)
+	ingress := newTLSIngress(namespace, "web", "web-tls", "web.example.com", "shop.apps.example.com",
+		"api.example.com")
+	// 引用其他 Secret 的 Ingress 不影响结果
+	other := newTLSIngress(namespace, "other", "other-tls", "other.example.com")
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ingress, other).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, ValidateCertificateHostnames: true}
+	defer certificateHostnameMismatch.Reset()
+	labels := func(host string) testsupport.Labels {
+		return testsupport.Labels{"namespace": namespace, "secret_name": "web-tls", "host": host}
+	}
+
+	if err := r.reconcileSecretWithHostnameValidation(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		labels("web.example.com"), 0)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		labels("shop.apps.example.com"), 0)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		labels("api.example.com"), 1)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		testsupport.Labels{"host": "other.example.com"})
+
+	// Ingress 移除主机名后，旧的序列被清理
+	ingress.Spec.TLS[0].Hosts = []string{"web.example.com"}
+	if err := c.Update(ctx, ingress); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reconcileSecretWithHostnameValidation(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		labels("api.example.com"))
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		testsupport.Labels{"namespace": namespace}); n != 1 {
+		t.Errorf("expected one host to be validated, got %d series", n)
+	}
+	// 证书无法解析时同样清理
+	secret.Data["tls.crt"] = []byte("not a certificate")
+	_ = r.reconcileSecretWithHostnameValidation(ctx, secret)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_hostname_mismatch",
+		testsupport.Labels{"namespace": namespace})
+}
+
+func TestIngressTLSSecrets(t *testing.T) {
+	ingress := newTLSIngress("web", "web", "web-tls", "web.example.com")
+	ingress.Spec.TLS = append(ingress.Spec.TLS, networkingv1.IngressTLS{Hosts: []string{"default.example.com"}},
+		networkingv1.IngressTLS{SecretName: "api-tls"})
+	got := ingressTLSSecrets(context.Background(), ingress)
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "web", Name: "web-tls"}},
+		{NamespacedName: types.NamespacedName{Namespace: "web", Name: "api-tls"}},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
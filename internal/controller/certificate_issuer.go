@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/x509"
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// 签发者不在 --trusted-ca-common-names 列表中的证书，值恒为 1
+	certificateIssuedByUnknownCA = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_certificate_issued_by_unknown_ca",
+			Help: "Set to 1 for certificates whose issuer common name is not in --trusted-ca-common-names. " +
+				"Not exported when the flag is unset.",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"cert_type",   // 证书类型
+			"issuer_cn",   // 签发者的 Common Name
+		},
+	)
+)
+
+func init() {
+	registerMetrics(certificateIssuedByUnknownCA)
+}
+
+// recordCertificateIssuer flags a certificate whose issuer is not a trusted
+// CA. The previous series of the certificate is removed first, so a reissue
+// by a trusted CA clears the flag.
+func (r *PodMonitorReconciler) recordCertificateIssuer(namespace, secretName, certType string,
+	cert *x509.Certificate) {
+	certificateIssuedByUnknownCA.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+	})
+	if len(r.TrustedCACommonNames) == 0 || slices.Contains(r.TrustedCACommonNames, cert.Issuer.CommonName) {
+		return
+	}
+	certificateIssuedByUnknownCA.With(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+		"issuer_cn":   cert.Issuer.CommonName,
+	}).Set(1)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCertificateIssuedByUnknownCA(t *testing.T) {
+	const namespace = "certificate-issuer-test"
+	ca := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "Corp Root CA", IsCA: true})
+	leaf := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{
+		CommonName: "web.example.com", Issuer: ca,
+	}).Cert
+	defer certificateIssuedByUnknownCA.Reset()
+
+	// 未配置受信任列表时不导出
+	r := &PodMonitorReconciler{}
+	r.recordCertificateIssuer(namespace, "web-tls", "tls.crt", leaf)
+	if n := testutil.CollectAndCount(certificateIssuedByUnknownCA); n != 0 {
+		t.Fatalf("expected no series without a trusted list, got %d", n)
+	}
+
+	r.TrustedCACommonNames = []string{"Other CA"}
+	r.recordCertificateIssuer(namespace, "web-tls", "tls.crt", leaf)
+	got := testutil.ToFloat64(certificateIssuedByUnknownCA.WithLabelValues(namespace, "web-tls", "tls.crt",
+		"Corp Root CA"))
+	if got != 1 {
+		t.Fatalf("expected the untrusted issuer to be flagged, got %v", got)
+	}
+
+	// 签发者加入受信任列表后清除
+	r.TrustedCACommonNames = append(r.TrustedCACommonNames, "Corp Root CA")
+	r.recordCertificateIssuer(namespace, "web-tls", "tls.crt", leaf)
+	if n := testutil.CollectAndCount(certificateIssuedByUnknownCA); n != 0 {
+		t.Fatalf("expected the flag to be cleared for a trusted issuer, got %d series", n)
+	}
+}
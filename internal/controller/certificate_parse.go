@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/x509"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+)
+
+const (
+	// defaultMaxSecretKeySize is the largest data value parsed for certificates.
+	defaultMaxSecretKeySize = 1 << 20
+	// defaultMaxPEMBlocksPerKey bounds the PEM blocks decoded from one value.
+	defaultMaxPEMBlocksPerKey = certparse.DefaultMaxBlocks
+)
+
+var (
+	// 证书链中每个证书的过期时间；仅在一个键包含多个证书时导出
+	certificateChainExpirationTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_certificate_chain_expiration_timestamp_seconds",
+			Help: "Unix timestamp in seconds at which each certificate of a multi-certificate secret value expires. " +
+				"The primary expiration metrics use the leaf of the chain.",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"cert_type",   // 证书所在的 key
+			"position",    // 证书在值中的位置，从 0 开始
+			"subject",     // 证书主体
+		},
+	)
+
+	// 因体积过大而跳过解析的 Secret 键
+	secretKeysSkippedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_secret_keys_skipped_total",
+			Help: "Total number of secret data keys skipped during certificate parsing because they exceed the size limit",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(secretKeysSkippedTotal)
+	registerMetrics(certificateChainExpirationTime)
+}
+
+// parseLeafCertificate parses the certificates of a secret value, skipping
+// private keys stored alongside them in any order, and returns the leaf with
+// the whole chain. Certificates before a malformed block are still returned,
+// together with the parse error.
+func (r *PodMonitorReconciler) parseLeafCertificate(data []byte) (*x509.Certificate, []*x509.Certificate, error) {
+	chain, err := certparse.ParseChain(data, r.maxPEMBlocksPerKey())
+	return certparse.Leaf(chain), chain, err
+}
+
+// recordCertificateChain exports the expiry of every certificate of a value
+// holding more than one, replacing the series of the previous chain.
+func recordCertificateChain(namespace, secretName, certType string, chain []*x509.Certificate) {
+	certificateChainExpirationTime.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+	})
+	if len(chain) < 2 {
+		return
+	}
+	for i, cert := range chain {
+		certificateChainExpirationTime.With(prometheus.Labels{
+			"namespace":   namespace,
+			"secret_name": secretName,
+			"cert_type":   certType,
+			"position":    strconv.Itoa(i),
+			"subject":     cert.Subject.String(),
+		}).Set(float64(cert.NotAfter.Unix()))
+	}
+}
+
+// maxSecretKeySize returns the configured size limit or its default.
+func (r *PodMonitorReconciler) maxSecretKeySize() int64 {
+	if r.MaxSecretKeySize > 0 {
+		return r.MaxSecretKeySize
+	}
+	return defaultMaxSecretKeySize
+}
+
+// maxPEMBlocksPerKey returns the configured block limit or its default.
+func (r *PodMonitorReconciler) maxPEMBlocksPerKey() int {
+	if r.MaxPEMBlocksPerKey > 0 {
+		return r.MaxPEMBlocksPerKey
+	}
+	return defaultMaxPEMBlocksPerKey
+}
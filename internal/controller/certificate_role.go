@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"crypto/x509"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Roles of a certificate in its chain, exported as the cert_role label.
+const (
+	certRoleRoot         = "root"
+	certRoleIntermediate = "intermediate"
+	certRoleLeaf         = "leaf"
+)
+
+var (
+	// 证书在信任链中的角色，值恒为 1；每个证书一条序列，轮换后替换
+	certificateInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_certificate_info",
+			Help: "Role of each monitored certificate in its chain: whether it is a CA, whether it is self-signed, " +
+				"and the derived role (root, intermediate or leaf). The value is always 1.",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"cert_type",   // 证书所在的 key
+			"is_ca",       // basicConstraints 中的 CA 标记
+			"self_signed", // 主体与签发者相同，且签名可由自身公钥验证
+			"cert_role",   // root、intermediate 或 leaf
+		},
+	)
+)
+
+func init() {
+	registerMetrics(certificateInfo)
+}
+
+// isSelfSigned reports whether the certificate names itself as its issuer and
+// its signature verifies with its own public key. The signature is checked
+// directly, as CheckSignatureFrom would reject a self-signed leaf for not
+// being a CA.
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		return false
+	}
+	return cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// certificateRole derives the role of a certificate in its chain. A
+// self-signed certificate that is not a CA is still a leaf.
+func certificateRole(cert *x509.Certificate) string {
+	switch {
+	case !cert.IsCA:
+		return certRoleLeaf
+	case isSelfSigned(cert):
+		return certRoleRoot
+	default:
+		return certRoleIntermediate
+	}
+}
+
+// recordCertificateInfo exports the role of a certificate, replacing the
+// series of the certificate previously stored under the same key.
+func recordCertificateInfo(namespace, secretName, certType string, cert *x509.Certificate) {
+	certificateInfo.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+	})
+	certificateInfo.With(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+		"is_ca":       strconv.FormatBool(cert.IsCA),
+		"self_signed": strconv.FormatBool(isSelfSigned(cert)),
+		"cert_role":   certificateRole(cert),
+	}).Set(1)
+}
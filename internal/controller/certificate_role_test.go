@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCertificateRole(t *testing.T) {
+	// 实际 Linkerd 集群中提取的 issuer 证书，由 root.linkerd.cluster.local 签发
+	data, err := os.ReadFile(filepath.Join("testdata", "linkerd-identity-issuer.crt.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatal("no PEM block in the Linkerd issuer fixture")
+	}
+	issuer, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{
+		CommonName: "root.linkerd.cluster.local", IsCA: true,
+	})
+	leaf := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{
+		CommonName: "web.default.serviceaccount.identity.linkerd.cluster.local", Issuer: root,
+	})
+	selfSignedLeaf := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "localhost"})
+
+	for name, tc := range map[string]struct {
+		cert       *x509.Certificate
+		selfSigned bool
+		role       string
+	}{
+		"linkerd root":     {root.Cert, true, certRoleRoot},
+		"linkerd issuer":   {issuer, false, certRoleIntermediate},
+		"leaf":             {leaf.Cert, false, certRoleLeaf},
+		"self-signed leaf": {selfSignedLeaf.Cert, true, certRoleLeaf},
+	} {
+		if got := isSelfSigned(tc.cert); got != tc.selfSigned {
+			t.Errorf("%s: expected self_signed=%v, got %v", name, tc.selfSigned, got)
+		}
+		if got := certificateRole(tc.cert); got != tc.role {
+			t.Errorf("%s: expected role %s, got %s", name, tc.role, got)
+		}
+	}
+
+	// 轮换为不同角色的证书时替换旧序列
+	defer certificateInfo.Reset()
+	recordCertificateInfo("linkerd", "linkerd-identity-issuer", "crt.pem", root.Cert)
+	recordCertificateInfo("linkerd", "linkerd-identity-issuer", "crt.pem", issuer)
+	if n := testutil.CollectAndCount(certificateInfo); n != 1 {
+		t.Fatalf("expected one series per certificate, got %d", n)
+	}
+	got := testutil.ToFloat64(certificateInfo.WithLabelValues("linkerd", "linkerd-identity-issuer", "crt.pem",
+		"true", "false", certRoleIntermediate))
+	if got != 1 {
+		t.Errorf("expected the issuer to be exported as an intermediate CA")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+)
+
+var (
+	// 检测到的证书轮换次数（证书 NotAfter 在两次检查之间发生变化）
+	certificateRotationDetectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_certificate_rotation_detected_total",
+			Help: "Total number of certificate rotations detected, i.e. changes of a certificate's NotAfter between checks",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"cert_type",   // 证书所在的 key
+		},
+	)
+)
+
+func init() {
+	registerMetrics(certificateRotationDetectedTotal)
+}
+
+// detectCertificateRotation compares the certificate's expiry with the one
+// seen by the previous check. The first observation of a certificate is not a
+// rotation. On a rotation the counter is incremented, a Normal event is
+// emitted on the secret and the rotation is streamed.
+func (r *PodMonitorReconciler) detectCertificateRotation(ctx context.Context, namespace, secretName, certType string,
+	notAfter, now time.Time) bool {
+	// 上一次的过期时间保存在 stateStore 中，Secret 删除时随之清理
+	previous, ok := stateStore.recordCertificate(namespace, secretName, certType, notAfter)
+	if !ok || notAfter.Equal(previous) {
+		return false
+	}
+
+	certificateRotationDetectedTotal.With(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+		"cert_type":   certType,
+	}).Inc()
+
+	if r.isLinkerdIssuer(namespace, secretName) {
+		stateStore.recordIssuerRotation(now)
+	}
+
+	r.publishCertificateRotated(namespace, &eventsv1.CertificateRotated{
+		Secret:           secretName,
+		CertType:         certType,
+		PreviousNotAfter: timestamppb.New(previous),
+		NotAfter:         timestamppb.New(notAfter),
+	}, now)
+
+	if r.Recorder == nil || r.notificationsSilenced(namespace) {
+		return true
+	}
+	// 从缓存读取 Secret，使事件关联到对象的 UID
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretName}, &secret); err != nil {
+		logf.FromContext(ctx).V(1).Info("Unable to get secret for rotation event", "namespace", namespace,
+			"secret", secretName, "error", err.Error())
+		return true
+	}
+	r.eventf(&secret, corev1.EventTypeNormal, EventReasonCertificateRotated,
+		"Certificate %s rotated: expiry changed from %s to %s", certType,
+		previous.UTC().Format(time.RFC3339), notAfter.UTC().Format(time.RFC3339))
+	return true
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCertificateRotationDetectedOnExpiryChange(t *testing.T) {
+	const namespace = "rotation-test"
+	defer stateStore.forgetSecret(namespace, "tls")
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer certificateChainExpirationTime.Reset()
+
+	clock := clocktesting.NewFakeClock(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	r := &PodMonitorReconciler{Clock: clock}
+	ctx := context.Background()
+	check := func(cert *testsupport.Certificate) {
+		t.Helper()
+		if err := r.checkCertificateExpiration(ctx, namespace, "tls", "tls.crt", cert.CertPEM()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	count := func() float64 {
+		return testutil.ToFloat64(certificateRotationDetectedTotal.WithLabelValues(namespace, "tls", "tls.crt"))
+	}
+
+	// 首次观察到证书不算轮换，同一证书再次检查也不算
+	current := testsupport.CertificateExpiringIn(t, clock.Now(), 30, "web.example.com")
+	check(current)
+	clock.Step(24 * time.Hour)
+	check(current)
+	if got := count(); got != 0 {
+		t.Fatalf("expected no rotations, got %v", got)
+	}
+
+	// 续期后的证书过期时间变化
+	check(testsupport.CertificateExpiringIn(t, clock.Now(), 90, "web.example.com"))
+	if got := count(); got != 1 {
+		t.Fatalf("expected 1 rotation, got %v", got)
+	}
+}
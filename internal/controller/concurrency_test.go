@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// reconcileBurst reconciles every request once with the given number of
+// workers and returns how long it took.
+func reconcileBurst(t testing.TB, r *PodMonitorReconciler, requests []ctrl.Request, workers int) time.Duration {
+	queue := make(chan ctrl.Request, len(requests))
+	for _, req := range requests {
+		queue <- req
+	}
+	close(queue)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range queue {
+				if _, err := r.Reconcile(context.Background(), req); err != nil {
+					t.Errorf("reconcile %s: %v", req.NamespacedName, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+// burstPods returns pods that each have one unprocessed container restart,
+// together with their reconcile requests.
+func burstPods(namespace string, pods int) ([]client.Object, []ctrl.Request) {
+	objects := make([]client.Object, 0, pods)
+	requests := make([]ctrl.Request, 0, pods)
+	for i := range pods {
+		name := fmt.Sprintf("pod-%d", i)
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "app",
+					RestartCount: 1,
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						Reason:     "Error",
+						ExitCode:   1,
+						FinishedAt: metav1.NewTime(time.Now()),
+					}},
+				}},
+			},
+		})
+		requests = append(requests, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+	}
+	return objects, requests
+}
+
+// deleteAndReconcile deletes the pods and reconciles them again so that no
+// state is left behind for other tests.
+func deleteAndReconcile(t testing.TB, r *PodMonitorReconciler, objects []client.Object, requests []ctrl.Request) {
+	for _, obj := range objects {
+		if err := r.Delete(context.Background(), obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reconcileBurst(t, r, requests, 4)
+}
+
+func TestConcurrentReconcileBurst(t *testing.T) {
+	const (
+		pods    = 1000
+		workers = 4
+	)
+	objects, requests := burstPods("burst-test", pods)
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+
+	elapsed := reconcileBurst(t, r, requests, workers)
+	if elapsed >= 10*time.Second {
+		t.Errorf("expected %d workers to reconcile %d pods in less than 10s, took %v", workers, pods, elapsed)
+	}
+	for _, req := range requests[:10] {
+		if got := stateStore.observedRestartCount(req.Namespace + "/" + req.Name + "/app"); got != 1 {
+			t.Fatalf("expected the restart of %s to be recorded, got count %d", req.Name, got)
+		}
+	}
+	deleteAndReconcile(t, r, objects, requests)
+}
+
+func BenchmarkConcurrentReconcileBurst(b *testing.B) {
+	objects, requests := burstPods("burst-bench", 1000)
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+
+	for range b.N {
+		reconcileBurst(b, r, requests, 4)
+	}
+	b.StopTimer()
+	deleteAndReconcile(b, r, objects, requests)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 容器镜像信息，值恒为 1，可在 PromQL 中与重启计数器 join
+	containerInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_info",
+			Help: "Image information of running containers. The value is always 1.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"image",     // 镜像
+			"image_id",  // 镜像 ID（包含 digest）
+		},
+	)
+
+	// 每个容器当前导出的镜像标签，用于镜像变化时替换旧序列
+	// key: "namespace/podName/containerName", value: [image, imageID]
+	exportedContainerImages = make(map[string][2]string)
+
+	// 保护 exportedContainerImages map 的互斥锁
+	containerImagesMutex sync.Mutex
+)
+
+func init() {
+	registerMetrics(batched(containerInfo))
+}
+
+// updateContainerInfo keeps exactly one pod_monitor_container_info series per
+// running container, replacing the previous series when the image changes.
+func updateContainerInfo(b *metricBatch, pod *corev1.Pod) {
+	containerImagesMutex.Lock()
+	defer containerImagesMutex.Unlock()
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		containerKey := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+		current := [2]string{cs.Image, cs.ImageID}
+		previous, exported := exportedContainerImages[containerKey]
+
+		// 镜像发生变化或容器不再运行时，删除旧序列
+		if exported && (previous != current || cs.State.Running == nil) {
+			b.delete(containerInfo.MetricVec, pod.Namespace, pod.Name, cs.Name, previous[0], previous[1])
+			delete(exportedContainerImages, containerKey)
+		}
+
+		if cs.State.Running == nil {
+			continue
+		}
+		b.set(containerInfo, 1, pod.Namespace, pod.Name, cs.Name, cs.Image, cs.ImageID)
+		exportedContainerImages[containerKey] = current
+	}
+}
+
+// cleanupContainerInfo removes the info series and state of a deleted pod.
+func cleanupContainerInfo(b *metricBatch, namespace, podName string) {
+	b.deletePartial(containerInfo.MetricVec, prometheus.Labels{
+		"namespace": namespace,
+		"pod":       podName,
+	})
+
+	prefix := fmt.Sprintf("%s/%s/", namespace, podName)
+	containerImagesMutex.Lock()
+	for key := range exportedContainerImages {
+		if strings.HasPrefix(key, prefix) {
+			delete(exportedContainerImages, key)
+		}
+	}
+	containerImagesMutex.Unlock()
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestContainerInfoFollowsImage(t *testing.T) {
+	const namespace = "container-info-test"
+	running := func(image, imageID string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: "app", Image: image, ImageID: imageID,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	}
+	update := func(cs corev1.ContainerStatus) {
+		var b metricBatch
+		updateContainerInfo(&b, testsupport.NewPod(namespace, "web").WithContainerStatus(cs).Build())
+		stateStore.commitMetrics(&b)
+	}
+	series := func() int {
+		return testsupport.CountSeries(t, metrics.Registry, "pod_monitor_container_info",
+			testsupport.Labels{"namespace": namespace, "pod": "web"})
+	}
+	defer func() {
+		var b metricBatch
+		cleanupContainerInfo(&b, namespace, "web")
+		stateStore.commitMetrics(&b)
+	}()
+
+	update(running("web:1.0", "docker.io/web@sha256:aaa"))
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_info",
+		testsupport.Labels{"namespace": namespace, "pod": "web", "container": "app", "image": "web:1.0",
+			"image_id": "docker.io/web@sha256:aaa"}, 1)
+
+	// 镜像变化时替换旧序列，而不是累积
+	update(running("web:1.1", "docker.io/web@sha256:bbb"))
+	if n := series(); n != 1 {
+		t.Fatalf("expected one series after the image change, got %d", n)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_info",
+		testsupport.Labels{"namespace": namespace, "pod": "web", "image": "web:1.1"}, 1)
+
+	// 容器不再运行时删除序列，重新运行后恢复
+	update(corev1.ContainerStatus{Name: "app", Image: "web:1.1", ImageID: "docker.io/web@sha256:bbb"})
+	if n := series(); n != 0 {
+		t.Fatalf("expected no series for a container that is not running, got %d", n)
+	}
+	update(running("web:1.1", "docker.io/web@sha256:bbb"))
+	if n := series(); n != 1 {
+		t.Fatalf("expected the series to return once the container runs, got %d", n)
+	}
+
+	// Pod 删除后清理序列与状态
+	var b metricBatch
+	cleanupContainerInfo(&b, namespace, "web")
+	stateStore.commitMetrics(&b)
+	if n := series(); n != 0 {
+		t.Errorf("expected the series of a deleted pod to be removed, got %d", n)
+	}
+	if _, ok := exportedContainerImages[namespace+"/web/app"]; ok {
+		t.Error("expected the state of a deleted pod to be removed")
+	}
+}
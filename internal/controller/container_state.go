@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Values of the state label of pod_monitor_container_state.
+const (
+	containerStateRunning    = "Running"
+	containerStateWaiting    = "Waiting"
+	containerStateTerminated = "Terminated"
+)
+
+// containerStates are all values of the state label, each exported for every
+// container.
+var containerStates = []string{containerStateRunning, containerStateWaiting, containerStateTerminated}
+
+var (
+	// 容器当前状态，枚举风格：当前状态为 1，其余两个状态为 0
+	containerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_state",
+			Help: "Current state of a container: 1 for the state it is in, 0 for the others.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"state",     // Running、Waiting 或 Terminated
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerState))
+}
+
+// currentContainerState returns the state a container is in, or "" when the
+// kubelet has not reported one yet.
+func currentContainerState(cs corev1.ContainerStatus) string {
+	switch {
+	case cs.State.Running != nil:
+		return containerStateRunning
+	case cs.State.Waiting != nil:
+		return containerStateWaiting
+	case cs.State.Terminated != nil:
+		return containerStateTerminated
+	}
+	return ""
+}
+
+// updateContainerState exports the three state series of every container,
+// with 1 for the current state. A container without a reported state has all
+// three at 0.
+func updateContainerState(b *metricBatch, pod *corev1.Pod) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		current := currentContainerState(cs)
+		for _, state := range containerStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+			b.set(containerState, value, pod.Namespace, pod.Name, cs.Name, state)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestContainerState(t *testing.T) {
+	const namespace = "container-state-test"
+	pod := testsupport.NewPod(namespace, "web").
+		WithContainer("app").
+		WithContainerStatus(corev1.ContainerStatus{Name: "sidecar", State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		}}).
+		WithContainerStatus(corev1.ContainerStatus{Name: "init-db", State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"},
+		}}).
+		WithContainerStatus(corev1.ContainerStatus{Name: "pending"}).
+		Build()
+	defer cleanupPod(namespace, "web")
+
+	var batch metricBatch
+	updateContainerState(&batch, pod)
+	stateStore.commitMetrics(&batch)
+
+	want := map[string]string{"app": containerStateRunning, "sidecar": containerStateWaiting,
+		"init-db": containerStateTerminated, "pending": ""}
+	for container, current := range want {
+		for _, state := range containerStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+			testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_state",
+				testsupport.Labels{"namespace": namespace, "pod": "web", "container": container, "state": state}, value)
+		}
+	}
+
+	// 状态变化后原状态归零
+	pod.Status.ContainerStatuses[1].State = corev1.ContainerState{
+		Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now())},
+	}
+	updateContainerState(&batch, pod)
+	stateStore.commitMetrics(&batch)
+	sidecar := testsupport.Labels{"namespace": namespace, "pod": "web", "container": "sidecar"}
+	sidecar["state"] = containerStateWaiting
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_state", sidecar, 0)
+	sidecar["state"] = containerStateRunning
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_state", sidecar, 1)
+
+	cleanupPod(namespace, "web")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_state",
+		testsupport.Labels{"namespace": namespace})
+}
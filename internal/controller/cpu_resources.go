@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 容器 CPU limit 与 request 的比值；等于 1 时容器在 request 用满后立即被节流
+	containerCPULimitRequestRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_cpu_limit_request_ratio",
+			Help: "Ratio of a container's CPU limit to its CPU request. Only exported when both are set.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerCPULimitRequestRatio))
+}
+
+// updateCPULimitRequestRatio exports the CPU limit/request ratio of every
+// container that sets both. A ratio of 1 combined with frequent restarts hints
+// at CPU starvation, which is otherwise invisible from the Kubernetes API.
+func updateCPULimitRequestRatio(b *metricBatch, pod *corev1.Pod) {
+	for _, c := range pod.Spec.Containers {
+		request, hasRequest := c.Resources.Requests[corev1.ResourceCPU]
+		limit, hasLimit := c.Resources.Limits[corev1.ResourceCPU]
+		if !hasRequest || !hasLimit || request.IsZero() {
+			b.delete(containerCPULimitRequestRatio.MetricVec, pod.Namespace, pod.Name, c.Name)
+			continue
+		}
+		b.set(containerCPULimitRequestRatio, float64(limit.MilliValue())/float64(request.MilliValue()),
+			pod.Namespace, pod.Name, c.Name)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCPULimitRequestRatio(t *testing.T) {
+	const namespace = "cpu-resources-test"
+	cpu := func(quantity string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(quantity)}
+	}
+	pod := testsupport.NewPod(namespace, "app").Build()
+	pod.Spec.Containers = []corev1.Container{
+		{Name: "guaranteed", Resources: corev1.ResourceRequirements{Requests: cpu("500m"), Limits: cpu("500m")}},
+		{Name: "burstable", Resources: corev1.ResourceRequirements{Requests: cpu("250m"), Limits: cpu("1")}},
+		{Name: "no-limit", Resources: corev1.ResourceRequirements{Requests: cpu("250m")}},
+		{Name: "zero-request", Resources: corev1.ResourceRequirements{Requests: cpu("0"), Limits: cpu("1")}},
+	}
+	update := func() {
+		var b metricBatch
+		updateCPULimitRequestRatio(&b, pod)
+		stateStore.commitMetrics(&b)
+	}
+	labels := func(container string) testsupport.Labels {
+		return testsupport.Labels{"namespace": namespace, "pod": "app", "container": container}
+	}
+	defer containerCPULimitRequestRatio.Reset()
+
+	update()
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_cpu_limit_request_ratio",
+		labels("guaranteed"), 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_cpu_limit_request_ratio",
+		labels("burstable"), 4)
+	// 缺少 request 或 limit、或 request 为 0 时不导出
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_cpu_limit_request_ratio",
+		labels("no-limit"))
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_cpu_limit_request_ratio",
+		labels("zero-request"))
+
+	// limit 被移除后删除序列
+	pod.Spec.Containers[1].Resources.Limits = nil
+	update()
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_cpu_limit_request_ratio",
+		labels("burstable"))
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultCPUThrottlingThreshold is the fraction of the CPU limit at or
+	// above which a restart is labeled suspected_cause="cpu_throttling".
+	defaultCPUThrottlingThreshold = 0.95
+
+	// suspectedCauseCPUThrottling is a hint, not a verdict: the metrics API
+	// only reports a recent average, which can be pinned at the limit for
+	// other reasons.
+	suspectedCauseCPUThrottling = "cpu_throttling"
+)
+
+// containerCPULimit returns the CPU limit of the named container, if set.
+func containerCPULimit(pod *corev1.Pod, container string) (int64, bool) {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, c := range containers {
+			if c.Name != container {
+				continue
+			}
+			limit, ok := c.Resources.Limits[corev1.ResourceCPU]
+			if !ok || limit.IsZero() {
+				return 0, false
+			}
+			return limit.MilliValue(), true
+		}
+	}
+	return 0, false
+}
+
+// suspectedCause returns the suspected_cause label of a restart: a hint
+// derived from the metrics API, or "" (no label) when nothing is suspected or
+// the metrics API integration is disabled. OOMKilled restarts have a known
+// cause and are not checked.
+func (r *PodMonitorReconciler) suspectedCause(ctx context.Context, pod *corev1.Pod, container, reason string) string {
+	if r.podMetrics == nil || reason == "OOMKilled" {
+		return ""
+	}
+	limit, ok := containerCPULimit(pod, container)
+	if !ok {
+		return ""
+	}
+	usage, ok := r.podMetrics.containerUsage(ctx, pod.Namespace, pod.Name, container)
+	if !ok {
+		return ""
+	}
+	cpu, ok := usage[corev1.ResourceCPU]
+	if !ok {
+		return ""
+	}
+
+	threshold := r.CPUThrottlingThreshold
+	if threshold <= 0 {
+		threshold = defaultCPUThrottlingThreshold
+	}
+	// 最近的 CPU 使用量贴近 limit，容器很可能被节流导致存活探针超时
+	if float64(cpu.MilliValue()) >= threshold*float64(limit) {
+		return suspectedCauseCPUThrottling
+	}
+	return ""
+}
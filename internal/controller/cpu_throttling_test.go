@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestSuspectedCauseCPUThrottling(t *testing.T) {
+	const namespace = "throttling-test"
+	usage := func(pod, cpu string) *metricsv1beta1.PodMetrics {
+		return &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: pod},
+			Containers: []metricsv1beta1.ContainerMetrics{{
+				Name:  "app",
+				Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}},
+		}
+	}
+	podWithLimit := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+			}}},
+		}
+	}
+	r := &PodMonitorReconciler{podMetrics: fakePodMetricsReader(usage("pinned", "490m"), usage("idle", "100m"))}
+	ctx := context.Background()
+
+	if got := r.suspectedCause(ctx, podWithLimit("pinned"), "app", "Error"); got != suspectedCauseCPUThrottling {
+		t.Errorf("expected usage at the limit to suggest throttling, got %q", got)
+	}
+	if got := r.suspectedCause(ctx, podWithLimit("idle"), "app", "Error"); got != "" {
+		t.Errorf("expected no suspected cause below the threshold, got %q", got)
+	}
+	if got := r.suspectedCause(ctx, podWithLimit("pinned"), "app", "OOMKilled"); got != "" {
+		t.Errorf("expected OOMKilled restarts not to be checked, got %q", got)
+	}
+	// 未启用 metrics API 时不带该标签
+	if got := (&PodMonitorReconciler{}).suspectedCause(ctx, podWithLimit("pinned"), "app", "Error"); got != "" {
+		t.Errorf("expected no suspected cause without the metrics API, got %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// crashLoopStates returns a snapshot of all containers in CrashLoopBackOff.
+func (s *restartStateStore) crashLoopStates() []crashLoopState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make([]crashLoopState, 0, len(s.crashLooping))
+	for _, state := range s.crashLooping {
+		states = append(states, state)
+	}
+	return states
+}
+
+// crashLoopCollector exports how long each container has been in
+// CrashLoopBackOff, computed at scrape time.
+type crashLoopCollector struct {
+	desc *prometheus.Desc
+}
+
+func newCrashLoopCollector() *crashLoopCollector {
+	return &crashLoopCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_container_crashloop_seconds",
+			"Seconds a container has been in CrashLoopBackOff, as observed by the operator",
+			[]string{"namespace", "pod", "container", "reason"}, nil,
+		),
+	}
+}
+
+func (c *crashLoopCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *crashLoopCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, state := range stateStore.crashLoopStates() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(state.Since).Seconds(),
+			state.Namespace, state.Pod, state.Container, state.LastReason)
+	}
+}
+
+func init() {
+	registerMetrics(newCrashLoopCollector())
+}
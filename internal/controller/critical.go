@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// criticalAnnotation set to "true" on a pod or on its Deployment or
+	// StatefulSet escalates its restarts: the Warning event and the
+	// notification are sent on every restart, with severity "critical".
+	criticalAnnotation = "pod-monitor.deraiven.io/critical"
+	// legacyCriticalAnnotation is the former name of criticalAnnotation,
+	// still honoured when criticalAnnotation is not set.
+	legacyCriticalAnnotation = "pod-monitor.io/critical"
+
+	// severityCritical is the severity of notifications and termination
+	// records of critical workloads.
+	severityCritical = "critical"
+
+	// workloadCriticalityTTL is how long the critical annotation of a
+	// workload is cached.
+	workloadCriticalityTTL = time.Minute
+)
+
+// isCriticalAnnotated reports whether annotations mark their object critical.
+func isCriticalAnnotated(annotations map[string]string) bool {
+	value, ok := annotations[criticalAnnotation]
+	if !ok {
+		value = annotations[legacyCriticalAnnotation]
+	}
+	critical, err := strconv.ParseBool(value)
+	return err == nil && critical
+}
+
+// escalate returns the policy of a critical pod: every restart reaches the
+// restart alert threshold.
+func (p monitorPolicy) escalate() monitorPolicy {
+	p.Critical = true
+	p.RestartAlertThreshold = 1
+	return p
+}
+
+// severity returns the severity of the restarts under the policy, empty for
+// non-critical pods.
+func (p monitorPolicy) severity() string {
+	if p.Critical {
+		return severityCritical
+	}
+	return ""
+}
+
+type workloadCriticality struct {
+	critical  bool
+	fetchedAt time.Time
+}
+
+// workloadCriticalityCache caches the critical annotation of workloads so
+// that pod reconciles do not read the workload every time.
+type workloadCriticalityCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	workloads map[string]workloadCriticality
+}
+
+func newWorkloadCriticalityCache(ttl time.Duration) *workloadCriticalityCache {
+	return &workloadCriticalityCache{ttl: ttl, workloads: make(map[string]workloadCriticality)}
+}
+
+// lookup reports whether the workload is annotated critical, reading it
+// through the client when the cached entry is missing or older than the TTL.
+// Workloads of kinds the operator cannot read are never critical.
+func (c *workloadCriticalityCache) lookup(ctx context.Context, reader client.Reader, workload workloadRef,
+	now time.Time) (bool, error) {
+	obj := newWorkloadObject(workload.Kind)
+	if obj == nil {
+		return false, nil
+	}
+
+	key := workload.key()
+	c.mu.Lock()
+	cached, ok := c.workloads[key]
+	c.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < c.ttl {
+		return cached.critical, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, rolloutLookupTimeout)
+	defer cancel()
+	err := reader.Get(lookupCtx, types.NamespacedName{Namespace: workload.Namespace, Name: workload.Name}, obj)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	// 工作负载已删除时按非 critical 缓存
+	entry := workloadCriticality{critical: err == nil && isCriticalAnnotated(obj.GetAnnotations()), fetchedAt: now}
+
+	c.mu.Lock()
+	c.workloads[key] = entry
+	// 清理过期条目，防止已删除工作负载的记录一直留在内存中
+	for k, other := range c.workloads {
+		if now.Sub(other.fetchedAt) >= c.ttl {
+			delete(c.workloads, k)
+		}
+	}
+	c.mu.Unlock()
+	return entry.critical, nil
+}
+
+// isCritical reports whether the pod or its owning workload is annotated
+// critical. A workload that cannot be read is treated as not critical.
+func (r *PodMonitorReconciler) isCritical(ctx context.Context, pod *corev1.Pod, workload workloadRef) bool {
+	if isCriticalAnnotated(pod.Annotations) {
+		return true
+	}
+	if r.criticalityCache == nil {
+		return false
+	}
+	critical, err := r.criticalityCache.lookup(ctx, r.Client, workload, r.now())
+	if err != nil {
+		logf.FromContext(ctx).V(1).Info("Unable to read the critical annotation of the workload",
+			"kind", workload.Kind, "name", workload.Name, "error", err.Error())
+	}
+	return critical
+}
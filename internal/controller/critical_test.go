@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCriticalWorkloadEscalatesEveryRestart(t *testing.T) {
+	const namespace = "critical-test"
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "payments",
+		Annotations: map[string]string{criticalAnnotation: "true"}}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment).Build()
+	recorder := record.NewFakeRecorder(20)
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder,
+		criticalityCache: newWorkloadCriticalityCache(workloadCriticalityTTL)}
+	sub := eventStream.subscribe([]string{namespace}, 20)
+	defer eventStream.unsubscribe(sub)
+
+	// payments 由带 critical 注解的 Deployment 管理，web 不是 critical
+	payments := testsupport.NewPod(namespace, "payments-7c9d-x2").WithOwner("ReplicaSet", "payments-7c9d").Build()
+	payments.Labels = map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "7c9d"}
+	web := testsupport.NewPod(namespace, "web").Build()
+	defer func() {
+		for _, pod := range []*corev1.Pod{payments, web} {
+			_ = c.Delete(ctx, pod)
+			_, _ = r.reconcilePod(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace,
+				Name: pod.Name}})
+		}
+	}()
+	restart := func(t *testing.T, pod *corev1.Pod, restartCount int32) {
+		t.Helper()
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			testsupport.TerminatedContainerStatus("app", restartCount, "Error", 1, time.Now()),
+		}
+		if restartCount == 1 {
+			if err := c.Create(ctx, pod); err != nil {
+				t.Fatal(err)
+			}
+		} else if err := c.Status().Update(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: pod.Name}}
+		if _, err := r.reconcilePod(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 按顺序取出流式通知，返回每条通知的类型与 severity
+	drain := func() []string {
+		var got []string
+		for {
+			select {
+			case event := <-sub.events:
+				switch p := event.Payload.(type) {
+				case *eventsv1.Event_ContainerRestart:
+					got = append(got, "restart:"+p.ContainerRestart.Severity)
+				case *eventsv1.Event_RestartThresholdCrossed:
+					got = append(got, "threshold:"+p.RestartThresholdCrossed.Severity)
+				}
+			default:
+				return got
+			}
+		}
+	}
+	countEvents := func(reason string) int {
+		var n int
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, " "+reason+" ") {
+					n++
+				}
+			default:
+				return n
+			}
+		}
+	}
+
+	// critical 的容器每次重启都发送通知与 RestartStorm 事件
+	for i := int32(1); i <= 2; i++ {
+		restart(t, payments, i)
+		if got := strings.Join(drain(), ","); got != "restart:critical,threshold:critical" {
+			t.Errorf("restart %d: expected critical restart and threshold notifications, got %s", i, got)
+		}
+		if n := countEvents(EventReasonRestartStorm); n != 1 {
+			t.Errorf("restart %d: expected one %s event, got %d", i, EventReasonRestartStorm, n)
+		}
+	}
+
+	// 非 critical 的容器行为不变：没有阈值时只发送重启通知
+	restart(t, web, 1)
+	if got := strings.Join(drain(), ","); got != "restart:" {
+		t.Errorf("expected a restart notification without severity, got %s", got)
+	}
+	if n := countEvents(EventReasonRestartStorm); n != 0 {
+		t.Errorf("expected no %s event for a non-critical pod, got %d", EventReasonRestartStorm, n)
+	}
+
+	// 终止记录中保留 severity
+	for _, rec := range stateStore.terminations(time.Time{}, namespace) {
+		want := ""
+		if rec.Pod == payments.Name {
+			want = severityCritical
+		}
+		if rec.Severity != want {
+			t.Errorf("%s: expected severity %q in the termination record, got %q", rec.Pod, want, rec.Severity)
+		}
+	}
+}
+
+func TestIsCriticalAnnotated(t *testing.T) {
+	for value, want := range map[string]bool{"true": true, "1": true, "false": false, "yes": false, "": false} {
+		if got := isCriticalAnnotated(map[string]string{criticalAnnotation: value}); got != want {
+			t.Errorf("%q: expected %v, got %v", value, want, got)
+		}
+	}
+
+	// 旧名称仍然生效，但新名称优先
+	if !isCriticalAnnotated(map[string]string{legacyCriticalAnnotation: "true"}) {
+		t.Error("expected the legacy annotation to mark the pod critical")
+	}
+	if isCriticalAnnotated(map[string]string{legacyCriticalAnnotation: "true", criticalAnnotation: "false"}) {
+		t.Error("expected the current annotation to take precedence over the legacy one")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=certificates.k8s.io,resources=certificatesigningrequests,verbs=get;list;watch
+
+const (
+	// defaultCSRPendingAlertThreshold is how long a CSR may wait for approval
+	// before a Warning event is emitted.
+	defaultCSRPendingAlertThreshold = 5 * time.Minute
+	// csrPendingRequeue is how often the pending duration of a CSR is refreshed.
+	csrPendingRequeue = time.Minute
+)
+
+var (
+	// 等待审批的 CSR 已等待的时间，审批或拒绝后删除
+	csrPendingDurationSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_csr_pending_duration_seconds",
+			Help: "Seconds since a CertificateSigningRequest awaiting approval was created",
+		},
+		[]string{
+			"name",        // CSR 名称
+			"signer_name", // 签发者，例如 kubernetes.io/kubelet-serving
+		},
+	)
+)
+
+func init() {
+	registerMetrics(csrPendingDurationSeconds)
+}
+
+// csrAlerts remembers the CSRs a pending Warning event was emitted for, so
+// that it is emitted once per CSR rather than on every refresh.
+type csrAlerts struct {
+	mu      sync.Mutex
+	alerted map[types.UID]struct{}
+}
+
+func newCSRAlerts() *csrAlerts {
+	return &csrAlerts{alerted: make(map[types.UID]struct{})}
+}
+
+// markAlerted records an alert for uid and reports whether it is the first.
+func (a *csrAlerts) markAlerted(uid types.UID) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.alerted[uid]; ok {
+		return false
+	}
+	a.alerted[uid] = struct{}{}
+	return true
+}
+
+// forget drops the alert of a CSR that is no longer pending.
+func (a *csrAlerts) forget(uid types.UID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.alerted, uid)
+}
+
+// csrPending reports whether a CSR still awaits approval: it has no Approved
+// condition or one that is not True, and it was neither denied nor failed.
+func csrPending(csr *certificatesv1.CertificateSigningRequest) bool {
+	pending := true
+	for _, cond := range csr.Status.Conditions {
+		switch cond.Type {
+		case certificatesv1.CertificateApproved:
+			if cond.Status == corev1.ConditionTrue {
+				pending = false
+			}
+		case certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			if cond.Status == corev1.ConditionTrue {
+				return false
+			}
+		}
+	}
+	return pending
+}
+
+// reconcileCSR exports how long a CSR has been waiting for approval and
+// emits a Warning event on it once it waited longer than
+// CSRPendingAlertThreshold. Pending CSRs are requeued every minute to keep
+// the duration current.
+func (r *PodMonitorReconciler) reconcileCSR(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var csr certificatesv1.CertificateSigningRequest
+	if err := r.Get(ctx, req.NamespacedName, &csr); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			logf.FromContext(ctx).Error(err, "unable to fetch CertificateSigningRequest")
+			return ctrl.Result{}, err
+		}
+		// CSR 已删除（通常在审批后被垃圾回收），清理指标
+		csrPendingDurationSeconds.DeletePartialMatch(prometheus.Labels{"name": req.Name})
+		return ctrl.Result{}, nil
+	}
+
+	if !csrPending(&csr) {
+		csrPendingDurationSeconds.DeletePartialMatch(prometheus.Labels{"name": csr.Name})
+		r.csrAlerts.forget(csr.UID)
+		return ctrl.Result{}, nil
+	}
+
+	pending := r.now().Sub(csr.CreationTimestamp.Time)
+	csrPendingDurationSeconds.With(prometheus.Labels{
+		"name":        csr.Name,
+		"signer_name": csr.Spec.SignerName,
+	}).Set(pending.Seconds())
+
+	threshold := r.CSRPendingAlertThreshold
+	if threshold <= 0 {
+		threshold = defaultCSRPendingAlertThreshold
+	}
+	if pending > threshold && r.csrAlerts.markAlerted(csr.UID) {
+		r.eventf(&csr, corev1.EventTypeWarning, EventReasonCSRPending,
+			"CertificateSigningRequest for signer %s has been pending approval for %s",
+			csr.Spec.SignerName, pending.Round(time.Second))
+	}
+	return ctrl.Result{RequeueAfter: csrPendingRequeue}, nil
+}
+
+// setupCSRController watches CertificateSigningRequests with a controller of
+// their own: like nodes they are cluster-scoped, so requests of the shared
+// controller could not tell them apart.
+func (r *PodMonitorReconciler) setupCSRController(mgr ctrl.Manager) error {
+	r.csrAlerts = newCSRAlerts()
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&certificatesv1.CertificateSigningRequest{}).
+		Named("csr").
+		Complete(reconcile.Func(func(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+			defer trackInFlight(reconcileControllerCSR)()
+			result, err := r.reconcileCSR(ctx, req)
+			observeObjectResult(reconcileControllerCSR, req.NamespacedName, err)
+			return result, err
+		}))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestCSRPending(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []certificatesv1.CertificateSigningRequestCondition
+		want       bool
+	}{
+		{"no condition", nil, true},
+		{"approved false", []certificatesv1.CertificateSigningRequestCondition{
+			{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionFalse}}, true},
+		{"approved", []certificatesv1.CertificateSigningRequestCondition{
+			{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}}, false},
+		{"denied", []certificatesv1.CertificateSigningRequestCondition{
+			{Type: certificatesv1.CertificateDenied, Status: corev1.ConditionTrue}}, false},
+	}
+	for _, tt := range tests {
+		csr := &certificatesv1.CertificateSigningRequest{
+			Status: certificatesv1.CertificateSigningRequestStatus{Conditions: tt.conditions},
+		}
+		if got := csrPending(csr); got != tt.want {
+			t.Errorf("%s: csrPending = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReconcileCSR(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	const signer = "kubernetes.io/kubelet-serving"
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "csr-test", UID: "csr-uid",
+			CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute))},
+		Spec: certificatesv1.CertificateSigningRequestSpec{SignerName: signer},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(csr).
+		WithStatusSubresource(csr).Build()
+	recorder := record.NewFakeRecorder(2)
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder,
+		Clock: clocktesting.NewFakePassiveClock(now), csrAlerts: newCSRAlerts()}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "csr-test"}}
+	labels := testsupport.Labels{"name": "csr-test", "signer_name": signer}
+	defer csrPendingDurationSeconds.Reset()
+
+	result, err := r.reconcileCSR(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RequeueAfter != csrPendingRequeue {
+		t.Errorf("pending CSR requeued after %v, want %v", result.RequeueAfter, csrPendingRequeue)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_csr_pending_duration_seconds", labels, 600)
+
+	// 告警事件只发出一次
+	if _, err := r.reconcileCSR(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(recorder.Events); n != 1 {
+		t.Fatalf("expected 1 event, got %d", n)
+	}
+	if event := <-recorder.Events; event != "Warning "+EventReasonCSRPending+
+		" CertificateSigningRequest for signer "+signer+" has been pending approval for 10m0s" {
+		t.Errorf("unexpected event %q", event)
+	}
+
+	csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+		{Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue}}
+	if err := c.Status().Update(ctx, csr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcileCSR(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_csr_pending_duration_seconds", labels)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+// dashboardTemplate is a Grafana 10 dashboard. It uses [[ ]] as template
+// delimiters because Grafana legend formats use {{ }}.
+//
+//go:embed dashboards/pod-monitor.json.tmpl
+var dashboardTemplate string
+
+// metricLabels names the labels the dashboard queries by.
+type metricLabels struct {
+	Namespace string
+	Pod       string
+	Container string
+	Secret    string
+	CertType  string
+}
+
+// metricSchema describes the names of the exported metrics. Dashboards are
+// rendered from it so they follow the metric names actually exported.
+type metricSchema struct {
+	Prefix string
+	Labels metricLabels
+}
+
+// defaultMetricSchema is the schema of the metrics as registered today.
+func defaultMetricSchema() metricSchema {
+	return metricSchema{
+		Prefix: "pod_monitor",
+		Labels: metricLabels{
+			Namespace: "namespace",
+			Pod:       "pod",
+			Container: "container",
+			Secret:    "secret_name",
+			CertType:  "cert_type",
+		},
+	}
+}
+
+// Metric returns the full name of a metric, e.g. container_restart_total.
+func (s metricSchema) Metric(name string) string {
+	return s.Prefix + "_" + name
+}
+
+// renderDashboard renders the Grafana dashboard for the schema. The result is
+// checked to be valid JSON.
+func renderDashboard(schema metricSchema) ([]byte, error) {
+	tmpl, err := template.New("dashboard").Delims("[[", "]]").Parse(dashboardTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("parsing dashboard template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, schema); err != nil {
+		return nil, fmt.Errorf("rendering dashboard: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("rendered dashboard is not valid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+// handleDashboard serves the Grafana dashboard for the exported metrics.
+func (s *StateServer) handleDashboard(w http.ResponseWriter, _ *http.Request) {
+	dashboard, err := renderDashboard(defaultMetricSchema())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(dashboard)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestDashboardGolden(t *testing.T) {
+	got, err := renderDashboard(defaultMetricSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Helm chart 中的 ConfigMap 读取同一份渲染结果
+	for _, golden := range []string{
+		filepath.Join("testdata", "pod-monitor-dashboard.golden.json"),
+		filepath.Join("..", "..", "pod-monitor", "dashboards", "pod-monitor.json"),
+	} {
+		if *updateGolden {
+			if err := os.WriteFile(golden, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("dashboard differs from %s; run go test ./internal/controller -run TestDashboardGolden -update",
+				golden)
+		}
+	}
+}
+
+// TestDashboardMetricsExist guards the default schema against renamed metrics.
+func TestDashboardMetricsExist(t *testing.T) {
+	schema := defaultMetricSchema()
+	collectors := map[string]prometheus.Collector{
+		"container_restart_total":                     podRestartTotal,
+		"container_crashloop_seconds":                 newCrashLoopCollector(),
+		"certificate_days_until_expiration":           certificateDaysUntilExpiration,
+		"container_oom_killed_total":                  containerOOMKilledTotal,
+		"certificate_days_until_expiration_histogram": newCertificateExpiryHistogramCollector(),
+	}
+	for name, c := range collectors {
+		ch := make(chan *prometheus.Desc, 1)
+		c.Describe(ch)
+		desc := (<-ch).String()
+		if !strings.Contains(desc, `fqName: "`+schema.Metric(name)+`"`) {
+			t.Errorf("metric %s not exported, got %s", schema.Metric(name), desc)
+		}
+		if !strings.Contains(desc, schema.Labels.Namespace) {
+			t.Errorf("metric %s has no label %s: %s", schema.Metric(name), schema.Labels.Namespace, desc)
+		}
+	}
+
+	// 运维健康面板的指标没有命名空间标签
+	for name, c := range map[string]prometheus.Collector{
+		"apiserver_backoff_active":    apiserverBackoffActive,
+		"operator_memory_usage_bytes": operatorMemoryUsage,
+	} {
+		ch := make(chan *prometheus.Desc, 1)
+		c.Describe(ch)
+		if desc := (<-ch).String(); !strings.Contains(desc, `fqName: "`+schema.Metric(name)+`"`) {
+			t.Errorf("metric %s not exported, got %s", schema.Metric(name), desc)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// Deployment 内所有 Pod 的容器重启次数之和（按容器名聚合）
+	deploymentContainerRestarts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_deployment_container_restarts_total",
+			Help: "Sum of the restart counts of a container across all current pods of a Deployment",
+		},
+		[]string{
+			"namespace",      // Deployment 所在命名空间
+			"deployment",     // Deployment 名称
+			"container_name", // 容器名称
+		},
+	)
+
+	// 每个 Deployment 容器的重启次数之和
+	// key: "namespace/deployment/containerName"
+	deploymentRestarts = make(map[string]int32)
+	// 参与求和的 Pod 数量，降为 0 时删除对应序列
+	// key: "namespace/deployment/containerName"
+	deploymentRestartPods = make(map[string]int)
+	// 每个 Pod 已计入的重启次数，用于按差值更新以及 Pod 删除时扣除
+	// key: "namespace/podName"
+	podRestartContributions = make(map[string]podRestartContribution)
+
+	// 保护以上三个 map 的互斥锁
+	deploymentRestartsMutex sync.Mutex
+)
+
+func init() {
+	registerMetrics(batched(deploymentContainerRestarts))
+}
+
+// podRestartContribution is what one pod adds to the aggregates of its
+// Deployment.
+type podRestartContribution struct {
+	deployment string
+	// key: containerName
+	restarts map[string]int32
+}
+
+// updateDeploymentRestarts replaces the pod's previous contribution to the
+// restart sums of its Deployment with its current restart counts. Pods not
+// owned by a Deployment are ignored.
+func updateDeploymentRestarts(b *metricBatch, pod *corev1.Pod, workload workloadRef) {
+	if workload.Kind != "Deployment" {
+		return
+	}
+
+	current := podRestartContribution{
+		deployment: workload.Name,
+		restarts:   make(map[string]int32, len(pod.Status.ContainerStatuses)),
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		current.restarts[cs.Name] = cs.RestartCount
+	}
+
+	podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	deploymentRestartsMutex.Lock()
+	defer deploymentRestartsMutex.Unlock()
+
+	if previous, ok := podRestartContributions[podKey]; ok {
+		subtractContributionLocked(b, pod.Namespace, previous)
+	}
+	podRestartContributions[podKey] = current
+	for container, restarts := range current.restarts {
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, current.deployment, container)
+		deploymentRestarts[key] += restarts
+		deploymentRestartPods[key]++
+		b.set(deploymentContainerRestarts, float64(deploymentRestarts[key]),
+			pod.Namespace, current.deployment, container)
+	}
+}
+
+// forgetDeploymentRestarts removes the contribution of a deleted pod.
+func forgetDeploymentRestarts(b *metricBatch, namespace, podName string) {
+	podKey := fmt.Sprintf("%s/%s", namespace, podName)
+	deploymentRestartsMutex.Lock()
+	defer deploymentRestartsMutex.Unlock()
+
+	if previous, ok := podRestartContributions[podKey]; ok {
+		subtractContributionLocked(b, namespace, previous)
+		delete(podRestartContributions, podKey)
+	}
+}
+
+// subtractContributionLocked removes a pod's contribution from the sums and
+// deletes the series no pod contributes to anymore. deploymentRestartsMutex
+// must be held.
+func subtractContributionLocked(b *metricBatch, namespace string, c podRestartContribution) {
+	for container, restarts := range c.restarts {
+		key := fmt.Sprintf("%s/%s/%s", namespace, c.deployment, container)
+		deploymentRestarts[key] -= restarts
+		deploymentRestartPods[key]--
+		if deploymentRestartPods[key] > 0 {
+			b.set(deploymentContainerRestarts, float64(deploymentRestarts[key]), namespace, c.deployment, container)
+			continue
+		}
+		delete(deploymentRestarts, key)
+		delete(deploymentRestartPods, key)
+		b.delete(deploymentContainerRestarts.MetricVec, namespace, c.deployment, container)
+	}
+}
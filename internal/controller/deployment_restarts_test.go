@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestDeploymentRestartsAggregateAcrossPods(t *testing.T) {
+	const namespace = "deployment-restarts-test"
+	workload := workloadRef{Namespace: namespace, Kind: "Deployment", Name: "web"}
+	newPod := func(name string, restarts int32) *corev1.Pod {
+		return testsupport.NewPod(namespace, name).
+			WithContainerStatus(corev1.ContainerStatus{Name: "app", RestartCount: restarts}).
+			Build()
+	}
+	update := func(pod *corev1.Pod) {
+		var batch metricBatch
+		updateDeploymentRestarts(&batch, pod, workload)
+		stateStore.commitMetrics(&batch)
+	}
+	forget := func(name string) {
+		var batch metricBatch
+		forgetDeploymentRestarts(&batch, namespace, name)
+		stateStore.commitMetrics(&batch)
+	}
+	sum := func() float64 {
+		return testutil.ToFloat64(deploymentContainerRestarts.WithLabelValues(namespace, "web", "app"))
+	}
+	defer forget("web-a")
+	defer forget("web-b")
+
+	update(newPod("web-a", 2))
+	update(newPod("web-b", 3))
+	if got := sum(); got != 5 {
+		t.Fatalf("expected 5 restarts, got %v", got)
+	}
+
+	// 同一 Pod 重复 reconcile 只按差值更新
+	update(newPod("web-a", 4))
+	update(newPod("web-a", 4))
+	if got := sum(); got != 7 {
+		t.Fatalf("expected 7 restarts, got %v", got)
+	}
+
+	forget("web-a")
+	if got := sum(); got != 3 {
+		t.Fatalf("expected 3 restarts after deleting a pod, got %v", got)
+	}
+	forget("web-b")
+	if n := testutil.CollectAndCount(deploymentContainerRestarts); n != 0 {
+		t.Fatalf("expected the series to be removed with the last pod, %d left", n)
+	}
+}
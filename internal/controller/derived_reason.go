@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Values of the derived_reason label, our own classification of a
+// termination next to the reason reported by the kubelet.
+const (
+	derivedReasonOOMKilled    = "oom_killed"
+	derivedReasonOOMSuspected = "oom_suspected"
+	derivedReasonSIGKILL      = "sigkill"
+	derivedReasonSIGTERM      = "sigterm"
+	derivedReasonError        = "error"
+	derivedReasonCompleted    = "completed"
+	derivedReasonUnknown      = "unknown"
+)
+
+const (
+	// 128 + 信号编号
+	exitCodeSIGKILL = 137
+	exitCodeSIGTERM = 143
+	signalSIGKILL   = 9
+	signalSIGTERM   = 15
+)
+
+var (
+	// 当前处于 MemoryPressure 的节点，仅在 --enable-node-watch 时维护
+	memoryPressureNodes   = make(map[string]struct{})
+	memoryPressureNodesMu sync.RWMutex
+)
+
+// setNodeMemoryPressure records whether a node reports MemoryPressure.
+func setNodeMemoryPressure(node string, pressure bool) {
+	memoryPressureNodesMu.Lock()
+	defer memoryPressureNodesMu.Unlock()
+	if pressure {
+		memoryPressureNodes[node] = struct{}{}
+	} else {
+		delete(memoryPressureNodes, node)
+	}
+}
+
+// nodeUnderMemoryPressure reports whether a node currently reports
+// MemoryPressure. It is always false without the node watch.
+func nodeUnderMemoryPressure(node string) bool {
+	memoryPressureNodesMu.RLock()
+	defer memoryPressureNodesMu.RUnlock()
+	_, ok := memoryPressureNodes[node]
+	return ok
+}
+
+// deriveReason classifies a termination from its exit code and signal
+// rather than trusting the reason reported by the kubelet alone:
+//
+//   - containerd only reports OOMKilled when the OOM killer hit the
+//     container's main process. An OOM kill of a child process (a worker
+//     forked by a shell entrypoint, a JVM started by a wrapper script) often
+//     takes the main process down with it: it exits with 137 and the reason
+//     is "Error".
+//   - cri-o reports the same case as "Error" too, and older releases leave
+//     the reason empty.
+//   - The kubelet reports ContainerStatusUnknown with exit code 137 when it
+//     lost track of a container (e.g. after a node reboot). Nothing was
+//     killed, so this is not an OOM.
+//
+// A SIGKILL reported as "Error" or without a reason, or any SIGKILL on a node
+// under memory pressure, is therefore oom_suspected. Liveness probe kills of
+// containers ignoring SIGTERM also end with SIGKILL and can show up here, so
+// the label is a suspicion, not a verdict.
+func deriveReason(terminated *corev1.ContainerStateTerminated, memoryPressure bool) string {
+	switch terminated.Reason {
+	case "OOMKilled":
+		return derivedReasonOOMKilled
+	case "ContainerStatusUnknown":
+		return derivedReasonUnknown
+	}
+
+	switch {
+	case terminated.ExitCode == exitCodeSIGKILL || terminated.Signal == signalSIGKILL:
+		if memoryPressure || terminated.Reason == "Error" || terminated.Reason == "" {
+			return derivedReasonOOMSuspected
+		}
+		return derivedReasonSIGKILL
+	case terminated.ExitCode == exitCodeSIGTERM || terminated.Signal == signalSIGTERM:
+		return derivedReasonSIGTERM
+	case terminated.ExitCode == 0:
+		return derivedReasonCompleted
+	default:
+		return derivedReasonError
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDeriveReason(t *testing.T) {
+	tests := []struct {
+		name           string
+		reason         string
+		exitCode       int32
+		signal         int32
+		memoryPressure bool
+		want           string
+	}{
+		{name: "reported OOM", reason: "OOMKilled", exitCode: 137, want: derivedReasonOOMKilled},
+		// containerd：子进程被 OOM kill，主进程以 137 退出，原因为 Error
+		{name: "containerd child OOM", reason: "Error", exitCode: 137, want: derivedReasonOOMSuspected},
+		// cri-o 旧版本不填写原因
+		{name: "cri-o empty reason", exitCode: 137, want: derivedReasonOOMSuspected},
+		{name: "signal only", reason: "Error", signal: 9, want: derivedReasonOOMSuspected},
+		{name: "SIGKILL under memory pressure", reason: "Unknown", exitCode: 137, memoryPressure: true,
+			want: derivedReasonOOMSuspected},
+		{name: "SIGKILL without OOM hint", reason: "Unknown", exitCode: 137, want: derivedReasonSIGKILL},
+		// kubelet 丢失容器状态时合成的 137 不是 OOM
+		{name: "kubelet status unknown", reason: "ContainerStatusUnknown", exitCode: 137, memoryPressure: true,
+			want: derivedReasonUnknown},
+		{name: "SIGTERM", reason: "Error", exitCode: 143, want: derivedReasonSIGTERM},
+		{name: "clean exit", reason: "Completed", want: derivedReasonCompleted},
+		{name: "application error", reason: "Error", exitCode: 1, want: derivedReasonError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminated := &corev1.ContainerStateTerminated{Reason: tt.reason, ExitCode: tt.exitCode, Signal: tt.signal}
+			if got := deriveReason(terminated, tt.memoryPressure); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// 从容器终止到 reconcilePod 处理该重启的延迟，用于监控“30 秒内可见”的承诺
+	detectionLagSeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "pod_monitor_detection_lag_seconds",
+			Help:    "Seconds from the termination of a container to the detection of its restart by the operator",
+			Buckets: []float64{0.5, 1, 2, 5, 10, 15, 20, 30, 45, 60, 120, 300},
+		},
+	)
+
+	// 因节点与 operator 时钟偏差导致延迟为负、被记为 0 的观测数
+	detectionLagClampedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_detection_lag_clamped_total",
+			Help: "Number of detection lag observations that were negative because of clock skew and recorded as 0",
+		},
+	)
+)
+
+func init() {
+	registerMetrics(detectionLagSeconds)
+	registerMetrics(detectionLagClampedTotal)
+}
+
+// markStarted records the time of the first reconcile.
+func (r *PodMonitorReconciler) markStarted() {
+	r.startedAt.CompareAndSwap(0, r.now().UnixNano())
+}
+
+// observeDetectionLag records how long after its termination a restart was
+// detected. Terminations that finished before the first reconcile are the
+// backlog of the initial sync rather than lag, and are not observed. The
+// finish time comes from the node's clock, so skew can make the lag negative;
+// such observations are recorded as 0 and counted.
+func (r *PodMonitorReconciler) observeDetectionLag(finishedAt time.Time) {
+	started := r.startedAt.Load()
+	if started == 0 || finishedAt.IsZero() || finishedAt.UnixNano() < started {
+		return
+	}
+	lag := r.now().Sub(finishedAt).Seconds()
+	if lag < 0 {
+		detectionLagClampedTotal.Inc()
+		lag = 0
+	}
+	detectionLagSeconds.Observe(lag)
+}
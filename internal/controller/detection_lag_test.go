@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestObserveDetectionLag(t *testing.T) {
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakeClock(start)
+	r := &PodMonitorReconciler{Clock: clock}
+
+	histogram := func() (uint64, float64) {
+		var m dto.Metric
+		if err := detectionLagSeconds.Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+	}
+	count, sum := histogram()
+	clamped := testutil.ToFloat64(detectionLagClampedTotal)
+	expect := func(t *testing.T, wantCount uint64, wantSum, wantClamped float64) {
+		t.Helper()
+		gotCount, gotSum := histogram()
+		if gotCount-count != wantCount || gotSum-sum != wantSum {
+			t.Errorf("expected %d more observations summing to %v, got %d summing to %v",
+				wantCount, wantSum, gotCount-count, gotSum-sum)
+		}
+		if got := testutil.ToFloat64(detectionLagClampedTotal) - clamped; got != wantClamped {
+			t.Errorf("expected %v clamped observations, got %v", wantClamped, got)
+		}
+	}
+
+	// 首次 reconcile 之前不记录
+	r.observeDetectionLag(start.Add(-time.Minute))
+	expect(t, 0, 0, 0)
+
+	r.markStarted()
+	clock.Step(time.Minute)
+	r.markStarted()
+	// 启动前结束的终止属于初次同步的积压
+	r.observeDetectionLag(start.Add(-time.Second))
+	expect(t, 0, 0, 0)
+
+	r.observeDetectionLag(start.Add(45 * time.Second))
+	expect(t, 1, 15, 0)
+
+	// 节点时钟超前时记为 0 并单独计数
+	r.observeDetectionLag(clock.Now().Add(5 * time.Second))
+	expect(t, 2, 15, 1)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var (
+	// Pod 被中断的次数，按 DisruptionTarget 条件的 reason 区分
+	// 例如 EvictionByEvictionAPI、PreemptionByScheduler、TerminationByKubelet
+	podDisruptionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_pod_disruptions_total",
+			Help: "Total number of pod disruptions, by the reason of the pod's DisruptionTarget condition",
+		},
+		[]string{
+			"namespace",     // Pod 所在命名空间
+			"workload_name", // 所属工作负载名称
+			"reason",        // DisruptionTarget 条件的 reason
+		},
+	)
+
+	// 已计数的 DisruptionTarget 条件，防止同一次中断在多次更新中被重复计数
+	// key: "namespace/podName"，value: 条件的 LastTransitionTime
+	recordedDisruptions = make(map[string]time.Time)
+	disruptionsMutex    sync.Mutex
+)
+
+func init() {
+	registerMetrics(podDisruptionsTotal)
+}
+
+// recordPodDisruption counts the pod's DisruptionTarget condition once per
+// transition. Requires Kubernetes 1.26 or later; older clusters never set the
+// condition.
+func recordPodDisruption(pod *corev1.Pod) {
+	var condition *corev1.PodCondition
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.DisruptionTarget {
+			condition = &pod.Status.Conditions[i]
+			break
+		}
+	}
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	transition := condition.LastTransitionTime.Time
+	disruptionsMutex.Lock()
+	if recorded, ok := recordedDisruptions[key]; ok && recorded.Equal(transition) {
+		disruptionsMutex.Unlock()
+		return
+	}
+	recordedDisruptions[key] = transition
+	disruptionsMutex.Unlock()
+
+	reason := condition.Reason
+	if reason == "" {
+		reason = "Unknown"
+	}
+	podDisruptionsTotal.WithLabelValues(pod.Namespace, resolveWorkload(pod).Name, reason).Inc()
+}
+
+// forgetPodDisruption drops the recorded condition of a deleted pod.
+func forgetPodDisruption(namespace, podName string) {
+	disruptionsMutex.Lock()
+	defer disruptionsMutex.Unlock()
+	delete(recordedDisruptions, fmt.Sprintf("%s/%s", namespace, podName))
+}
+
+// podDisruptionPredicate records the DisruptionTarget condition from the final
+// state of deleted pods. The condition is often set right before deletion, and
+// by the time the delete is reconciled the pod can no longer be read.
+func podDisruptionPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			if pod, ok := e.Object.(*corev1.Pod); ok {
+				recordPodDisruption(pod)
+			}
+			return true
+		},
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestPodDisruptionCountedOncePerTransition(t *testing.T) {
+	const namespace = "disruption-test"
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "app"}}
+	defer forgetPodDisruption(namespace, "app")
+
+	count := func() float64 {
+		return testutil.ToFloat64(podDisruptionsTotal.WithLabelValues(namespace, "app", "PreemptionByScheduler"))
+	}
+
+	// 没有条件时不计数
+	recordPodDisruption(pod)
+	if got := count(); got != 0 {
+		t.Fatalf("expected no disruption without the condition, got %v", got)
+	}
+
+	pod.Status.Conditions = []corev1.PodCondition{{
+		Type:               corev1.DisruptionTarget,
+		Status:             corev1.ConditionTrue,
+		Reason:             "PreemptionByScheduler",
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}}
+	recordPodDisruption(pod)
+	recordPodDisruption(pod)
+	// 删除事件携带同一条件，不应重复计数
+	if !podDisruptionPredicate().Delete(event.DeleteEvent{Object: pod}) {
+		t.Fatal("expected the delete event to be passed through")
+	}
+	if got := count(); got != 1 {
+		t.Fatalf("expected 1 disruption, got %v", got)
+	}
+
+	// 新的转换时间是一次新的中断
+	pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(time.Minute))
+	recordPodDisruption(pod)
+	if got := count(); got != 2 {
+		t.Fatalf("expected 2 disruptions, got %v", got)
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 容器通过环境变量引用 Secret 的次数；环境变量不会随 Secret 轮换更新，轮换常伴随重启
+	containerEnvSecretRefCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_env_secret_ref_count",
+			Help: "Number of envFrom secretRef entries and env secretKeyRef values of a container",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerEnvSecretRefCount))
+}
+
+// envSecretRefCount counts the secrets a container reads into environment
+// variables, through envFrom and through individual secretKeyRef values.
+func envSecretRefCount(c *corev1.Container) int {
+	count := 0
+	for _, from := range c.EnvFrom {
+		if from.SecretRef != nil {
+			count++
+		}
+	}
+	for _, env := range c.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// updateEnvSecretRefCount exports the secret references in the environment of
+// every container. Environment variables are only read at container start,
+// so a rotated secret takes effect through a restart.
+func updateEnvSecretRefCount(b *metricBatch, pod *corev1.Pod) {
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		b.set(containerEnvSecretRefCount, float64(envSecretRefCount(c)), pod.Namespace, pod.Name, c.Name)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnvSecretRefCount(t *testing.T) {
+	const namespace = "env-secret-refs-test"
+	secretRef := corev1.LocalObjectReference{Name: "db"}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "api"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{
+				Name: "app",
+				EnvFrom: []corev1.EnvFromSource{
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: secretRef}},
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: secretRef}},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: secretRef, Key: "password"},
+					}},
+					{Name: "MODE", Value: "production"},
+				},
+			},
+			{Name: "sidecar"},
+		}},
+	}
+	defer containerEnvSecretRefCount.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+
+	var batch metricBatch
+	updateEnvSecretRefCount(&batch, pod)
+	stateStore.commitMetrics(&batch)
+
+	if got := testutil.ToFloat64(containerEnvSecretRefCount.WithLabelValues(namespace, "api", "app")); got != 2 {
+		t.Errorf("expected 2 secret references, got %v", got)
+	}
+	if got := testutil.ToFloat64(containerEnvSecretRefCount.WithLabelValues(namespace, "api", "sidecar")); got != 0 {
+		t.Errorf("expected no secret references, got %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 因超出临时存储（ephemeral-storage）被 kubelet 驱逐的次数
+	// 按 Pod 级别限制或 emptyDir 驱逐时 container 为空
+	containerEphemeralStorageEvictionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_container_ephemeral_storage_evictions_total",
+			Help: "Total number of pods evicted by the kubelet for their ephemeral storage usage",
+		},
+		[]string{
+			"namespace",     // Pod 所在命名空间
+			"workload_name", // 所属工作负载名称
+			"container",     // 超出限制的容器，无法归因时为空
+		},
+	)
+
+	// 已计数的驱逐，key: "namespace/podName"
+	recordedStorageEvictions = make(map[string]struct{})
+	storageEvictionsMutex    sync.Mutex
+)
+
+func init() {
+	registerMetrics(batched(containerEphemeralStorageEvictionsTotal))
+}
+
+// ephemeralStorageEviction is what the kubelet reported about one eviction
+// for ephemeral storage. Quantities are as written in the message; fields the
+// message does not carry are empty.
+type ephemeralStorageEviction struct {
+	Container string `json:"container,omitempty"`
+	// Volume is the emptyDir volume that exceeded its size limit
+	Volume  string `json:"volume,omitempty"`
+	Usage   string `json:"usage,omitempty"`
+	Limit   string `json:"limit,omitempty"`
+	Request string `json:"request,omitempty"`
+}
+
+// quantityPattern matches a resource quantity, quoted or not.
+const quantityPattern = `"?(-?[0-9]+(?:\.[0-9]+)?[A-Za-z]*)"?`
+
+// Signatures of the eviction messages of the kubelet eviction manager. The
+// wording has been stable since 1.27 but quoting and punctuation vary; the
+// node pressure container line was reworded in earlier releases.
+var (
+	// Container app exceeded its local ephemeral storage limit "1Gi".
+	containerStorageLimitPattern = regexp.MustCompile(
+		`Container (\S+) exceeded its local ephemeral storage limit ` + quantityPattern)
+	// Pod ephemeral local storage usage exceeds the total limit of containers 2Gi.
+	podStorageLimitPattern = regexp.MustCompile(
+		`Pod ephemeral local storage usage exceeds the total limit of containers ` + quantityPattern)
+	// Usage of EmptyDir volume "cache" exceeds the limit "500Mi".
+	emptyDirLimitPattern = regexp.MustCompile(
+		`Usage of EmptyDir volume "?([^"\s]+?)"? exceeds the limit ` + quantityPattern)
+	// The node was low on resource: ephemeral-storage.
+	nodeStoragePressurePattern = regexp.MustCompile(`low on resource: \[?ephemeral-storage`)
+	// Threshold quantity: 10Gi, available: 512Mi.
+	storageThresholdPattern = regexp.MustCompile(`Threshold quantity: ` + quantityPattern + `, available: ` +
+		quantityPattern)
+	// Container app was using 6Gi, request is 0, has larger consumption of ephemeral-storage.
+	// Container app was using 6Gi, which exceeds its request of 0.
+	containerStorageUsagePattern = regexp.MustCompile(
+		`Container (\S+) was using ` + quantityPattern + `, (?:request is|which exceeds its request of) ` +
+			quantityPattern)
+)
+
+// parseEphemeralStorageEviction returns the evictions described by an
+// eviction message, or nil when the message is not about ephemeral storage.
+// A node pressure eviction lists every container using more than its
+// request; the usage of a limit eviction is its limit, which the message
+// does not repeat.
+func parseEphemeralStorageEviction(message string) []ephemeralStorageEviction {
+	if m := containerStorageLimitPattern.FindStringSubmatch(message); m != nil {
+		return []ephemeralStorageEviction{{Container: m[1], Limit: m[2]}}
+	}
+	if m := podStorageLimitPattern.FindStringSubmatch(message); m != nil {
+		return []ephemeralStorageEviction{{Limit: m[1]}}
+	}
+	if m := emptyDirLimitPattern.FindStringSubmatch(message); m != nil {
+		return []ephemeralStorageEviction{{Volume: m[1], Limit: m[2]}}
+	}
+	if !nodeStoragePressurePattern.MatchString(message) {
+		return nil
+	}
+
+	// 节点存储压力：阈值作为限制，逐个列出超出 request 的容器
+	var threshold string
+	if m := storageThresholdPattern.FindStringSubmatch(message); m != nil {
+		threshold = m[1]
+	}
+	var evictions []ephemeralStorageEviction
+	for _, m := range containerStorageUsagePattern.FindAllStringSubmatch(message, -1) {
+		evictions = append(evictions, ephemeralStorageEviction{
+			Container: m[1],
+			Usage:     m[2],
+			Limit:     threshold,
+			Request:   m[3],
+		})
+	}
+	if len(evictions) == 0 {
+		evictions = append(evictions, ephemeralStorageEviction{Limit: threshold})
+	}
+	return evictions
+}
+
+// evictionMessage returns the message of the kubelet eviction of a pod: its
+// status message, or that of its DisruptionTarget condition.
+func evictionMessage(pod *corev1.Pod) string {
+	if pod.Status.Reason == "Evicted" && pod.Status.Message != "" {
+		return pod.Status.Message
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == "TerminationByKubelet" {
+			return condition.Message
+		}
+	}
+	return ""
+}
+
+// recordEphemeralStorageEviction counts, records and reports a kubelet
+// eviction of the pod for its ephemeral storage, once per pod.
+func (r *PodMonitorReconciler) recordEphemeralStorageEviction(b *metricBatch, pod *corev1.Pod,
+	workload workloadRef) {
+	message := evictionMessage(pod)
+	if message == "" {
+		return
+	}
+	evictions := parseEphemeralStorageEviction(message)
+	if len(evictions) == 0 {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	storageEvictionsMutex.Lock()
+	if _, ok := recordedStorageEvictions[key]; ok {
+		storageEvictionsMutex.Unlock()
+		return
+	}
+	recordedStorageEvictions[key] = struct{}{}
+	storageEvictionsMutex.Unlock()
+
+	for _, eviction := range evictions {
+		b.inc(containerEphemeralStorageEvictionsTotal, pod.Namespace, workload.Name, eviction.Container)
+		stateStore.recordTermination(terminationRecord{
+			Timestamp:        r.now(),
+			Namespace:        pod.Namespace,
+			Pod:              pod.Name,
+			Container:        eviction.Container,
+			Reason:           EventReasonEphemeralStorageEvicted,
+			Node:             pod.Spec.NodeName,
+			Workload:         workload,
+			DuringRollout:    rolloutStateUnknown,
+			EphemeralStorage: &eviction,
+		})
+		r.warnPod(pod, EventReasonEphemeralStorageEvicted, "%s", eviction.describe())
+	}
+}
+
+// describe returns the message of the Warning event of an eviction.
+func (e ephemeralStorageEviction) describe() string {
+	var subject string
+	switch {
+	case e.Container != "":
+		subject = "Container " + e.Container
+	case e.Volume != "":
+		subject = "EmptyDir volume " + e.Volume
+	default:
+		subject = "Pod"
+	}
+	var details []string
+	if e.Usage != "" {
+		details = append(details, "usage: "+e.Usage)
+	}
+	if e.Request != "" {
+		details = append(details, "request: "+e.Request)
+	}
+	if e.Limit != "" {
+		details = append(details, "limit: "+e.Limit)
+	}
+	if len(details) == 0 {
+		return subject + " was evicted for its ephemeral storage usage"
+	}
+	return fmt.Sprintf("%s was evicted for its ephemeral storage usage (%s)", subject, strings.Join(details, ", "))
+}
+
+// forgetStorageEviction drops the recorded eviction of a deleted pod.
+func forgetStorageEviction(namespace, podName string) {
+	storageEvictionsMutex.Lock()
+	defer storageEvictionsMutex.Unlock()
+	delete(recordedStorageEvictions, fmt.Sprintf("%s/%s", namespace, podName))
+}
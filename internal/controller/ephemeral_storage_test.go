@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestParseEphemeralStorageEviction(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []ephemeralStorageEviction
+	}{
+		{
+			name:    "container limit",
+			message: `Container app exceeded its local ephemeral storage limit "1Gi". `,
+			want:    []ephemeralStorageEviction{{Container: "app", Limit: "1Gi"}},
+		},
+		{
+			// 部分版本不带引号
+			name:    "container limit unquoted",
+			message: `Container app exceeded its local ephemeral storage limit 1.5Gi.`,
+			want:    []ephemeralStorageEviction{{Container: "app", Limit: "1.5Gi"}},
+		},
+		{
+			name:    "pod limit",
+			message: `Pod ephemeral local storage usage exceeds the total limit of containers 2Gi. `,
+			want:    []ephemeralStorageEviction{{Limit: "2Gi"}},
+		},
+		{
+			name:    "emptyDir size limit",
+			message: `Usage of EmptyDir volume "cache" exceeds the limit "500Mi". `,
+			want:    []ephemeralStorageEviction{{Volume: "cache", Limit: "500Mi"}},
+		},
+		{
+			name: "node pressure",
+			message: `The node was low on resource: ephemeral-storage. Threshold quantity: 10Gi, available: 9876Mi. ` +
+				`Container app was using 6Gi, request is 0, has larger consumption of ephemeral-storage. ` +
+				`Container sidecar was using 1200Mi, request is 1Gi, has larger consumption of ephemeral-storage. `,
+			want: []ephemeralStorageEviction{
+				{Container: "app", Usage: "6Gi", Limit: "10Gi", Request: "0"},
+				{Container: "sidecar", Usage: "1200Mi", Limit: "10Gi", Request: "1Gi"},
+			},
+		},
+		{
+			name: "node pressure with negative availability",
+			message: `The node was low on resource: ephemeral-storage. Threshold quantity: 1Gi, available: -10Mi. ` +
+				`Container app was using 3Gi, request is 0, has larger consumption of ephemeral-storage. `,
+			want: []ephemeralStorageEviction{{Container: "app", Usage: "3Gi", Limit: "1Gi", Request: "0"}},
+		},
+		{
+			// 早期版本的容器用量措辞
+			name: "node pressure older wording",
+			message: `The node was low on resource: ephemeral-storage. ` +
+				`Container app was using 6Gi, which exceeds its request of 0. `,
+			want: []ephemeralStorageEviction{{Container: "app", Usage: "6Gi", Request: "0"}},
+		},
+		{
+			name:    "node pressure without container details",
+			message: `The node was low on resource: ephemeral-storage. Threshold quantity: 10Gi, available: 1Gi. `,
+			want:    []ephemeralStorageEviction{{Limit: "10Gi"}},
+		},
+		{
+			name: "memory pressure",
+			message: `The node was low on resource: memory. Threshold quantity: 100Mi, available: 50Mi. ` +
+				`Container app was using 2Gi, request is 1Gi, has larger consumption of memory. `,
+		},
+		{
+			name:    "preemption",
+			message: `Preempted in order to admit critical pod`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEphemeralStorageEviction(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecordEphemeralStorageEviction(t *testing.T) {
+	const namespace = "ephemeral-storage-test"
+	pod := testsupport.NewPod(namespace, "web-6d4cf56db6-abcde").
+		WithOwner("ReplicaSet", "web-6d4cf56db6").WithNode("node-1").Build()
+	pod.Labels = map[string]string{"pod-template-hash": "6d4cf56db6"}
+	pod.Status.Phase = corev1.PodFailed
+	pod.Status.Conditions = []corev1.PodCondition{{
+		Type:               corev1.DisruptionTarget,
+		Status:             corev1.ConditionTrue,
+		Reason:             "TerminationByKubelet",
+		Message:            `Container app exceeded its local ephemeral storage limit "1Gi". `,
+		LastTransitionTime: metav1.NewTime(time.Now()),
+	}}
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Recorder: recorder}
+	defer forgetStorageEviction(namespace, pod.Name)
+
+	// 同一次驱逐在多次 reconcile 中只计数一次
+	for range 2 {
+		var batch metricBatch
+		r.recordEphemeralStorageEviction(&batch, pod, resolveWorkload(pod))
+		stateStore.commitMetrics(&batch)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_ephemeral_storage_evictions_total",
+		testsupport.Labels{"namespace": namespace, "workload_name": "web", "container": "app"}, 1)
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one event, got %d", len(recorder.Events))
+	}
+	want := "Warning EphemeralStorageEvicted Container app was evicted for its ephemeral storage usage (limit: 1Gi)"
+	if got := <-recorder.Events; got != want {
+		t.Errorf("expected event %q, got %q", want, got)
+	}
+
+	var recorded *ephemeralStorageEviction
+	for _, rec := range stateStore.terminations(time.Time{}, namespace) {
+		if rec.Pod == pod.Name {
+			recorded = rec.EphemeralStorage
+		}
+	}
+	if recorded == nil || recorded.Container != "app" || recorded.Limit != "1Gi" {
+		t.Errorf("expected the eviction in the termination history, got %+v", recorded)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// etcdSecretNamespace is where the etcd certificate secrets live.
+	etcdSecretNamespace = "kube-system"
+
+	// Values of the source label on the certificate metrics.
+	certificateSourceSecret = "secret"
+	certificateSourceEtcd   = "etcd"
+)
+
+// DefaultEtcdSecretNames are the etcd certificate secrets watched by default.
+var DefaultEtcdSecretNames = []string{"etcd-certs", "etcd-client-certs"}
+
+// isEtcdSecret reports whether the secret is one of the configured etcd
+// certificate secrets.
+func (r *PodMonitorReconciler) isEtcdSecret(namespace, name string) bool {
+	return r.WatchEtcdCerts && namespace == etcdSecretNamespace && slices.Contains(r.EtcdSecretNames, name)
+}
+
+// certificateSource returns the source label for certificates of a secret.
+func (r *PodMonitorReconciler) certificateSource(namespace, secretName string) string {
+	if r.isEtcdSecret(namespace, secretName) {
+		return certificateSourceEtcd
+	}
+	if r.isKubeadmObject(namespace, secretName) {
+		return certificateSourceKubeadm
+	}
+	return certificateSourceSecret
+}
+
+// etcdSecretPredicate passes every event of the etcd secrets, so that they
+// are rechecked on any update and not only on data changes.
+func (r *PodMonitorReconciler) etcdSecretPredicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return r.isEtcdSecret(obj.GetNamespace(), obj.GetName())
+	})
+}
+
+// checkEtcdCertificates checks every .crt and .pem key of an etcd secret.
+// etcd certificates use many key names (server.crt, peer.crt,
+// healthcheck-client.crt, ...) that the default allowlist does not cover.
+func (r *PodMonitorReconciler) checkEtcdCertificates(ctx context.Context, secret *corev1.Secret) {
+	log := logf.FromContext(ctx)
+
+	for _, key := range sortedDataKeys(secret) {
+		if !strings.HasSuffix(key, ".crt") && !strings.HasSuffix(key, ".pem") {
+			continue
+		}
+		if err := r.checkCertificateExpiration(ctx, secret.Namespace, secret.Name, key, secret.Data[key]); err != nil {
+			log.Error(err, "Failed to check etcd certificate expiration", "key", key)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestIsEtcdSecret(t *testing.T) {
+	r := &PodMonitorReconciler{WatchEtcdCerts: true, EtcdSecretNames: DefaultEtcdSecretNames}
+	tests := []struct {
+		namespace, name string
+		want            bool
+	}{
+		{"kube-system", "etcd-certs", true},
+		{"kube-system", "etcd-client-certs", true},
+		{"kube-system", "etcd-peer-certs", false},
+		// 其他命名空间中的同名 Secret 不是 etcd 证书
+		{"default", "etcd-certs", false},
+	}
+	for _, tt := range tests {
+		if got := r.isEtcdSecret(tt.namespace, tt.name); got != tt.want {
+			t.Errorf("isEtcdSecret(%s/%s) = %v, want %v", tt.namespace, tt.name, got, tt.want)
+		}
+	}
+	// 未启用 etcd 监控时不匹配任何 Secret
+	if (&PodMonitorReconciler{EtcdSecretNames: DefaultEtcdSecretNames}).isEtcdSecret("kube-system", "etcd-certs") {
+		t.Error("expected no etcd secrets without WatchEtcdCerts")
+	}
+}
+
+func TestEtcdCertificateSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// etcd Secret 的所有 .crt / .pem 键都被检查，其余键被忽略
+	etcd := testsupport.NewSecret(etcdSecretNamespace, "etcd-certs", map[string][]byte{
+		"server.crt":             testsupport.CertificateExpiringIn(t, now, 100).CertPEM(),
+		"healthcheck-client.pem": testsupport.CertificateExpiringIn(t, now, 50).CertPEM(),
+		"server.key":             []byte("key"),
+	})
+	// 不在列表中的 Secret 保持默认来源
+	other := testsupport.NewTLSSecret(etcdSecretNamespace, "apiserver-tls", testsupport.CertificateExpiringIn(t, now, 200))
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(etcd, other).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now),
+		WatchEtcdCerts: true, EtcdSecretNames: []string{"etcd-certs"}}
+	defer func() {
+		forgetSecretCertificates(etcdSecretNamespace, "etcd-certs")
+		forgetSecretCertificates(etcdSecretNamespace, "apiserver-tls")
+	}()
+
+	for _, name := range []string{"etcd-certs", "apiserver-tls"} {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: etcdSecretNamespace, Name: name}}
+		if _, err := r.reconcileSecret(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": etcdSecretNamespace, "secret_name": "etcd-certs", "cert_type": "server.crt",
+			"source": certificateSourceEtcd}, 100)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": etcdSecretNamespace, "secret_name": "etcd-certs",
+			"cert_type": "healthcheck-client.pem", "source": certificateSourceEtcd}, 50)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": etcdSecretNamespace, "secret_name": "etcd-certs", "cert_type": "server.key"})
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": etcdSecretNamespace, "secret_name": "apiserver-tls", "cert_type": "tls.crt",
+			"source": certificateSourceSecret}, 200)
+}
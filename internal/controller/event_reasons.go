@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+// Reasons of the Kubernetes Events emitted by the operator, for use with
+// kubectl get events --field-selector reason=<reason>. Values that existed
+// before these constants were introduced are kept unchanged.
+const (
+	// EventReasonContainerRestarted is a Warning on a pod whose container
+	// restarted for any reason other than an OOM kill.
+	EventReasonContainerRestarted = "ContainerRestarted"
+	// EventReasonOOMKilledRestart is a Warning on a pod whose container
+	// restarted after the kubelet reported it OOMKilled.
+	EventReasonOOMKilledRestart = "OOMKilledRestart"
+	// EventReasonRestartStorm is a Warning on a pod whose container restart
+	// count reached the restart alert threshold of its policy.
+	EventReasonRestartStorm = "RestartThresholdExceeded"
+	// EventReasonRepeatedExitCode is a Warning on a pod whose container kept
+	// exiting with the same non-zero exit code.
+	EventReasonRepeatedExitCode = "RepeatedExitCode"
+	// EventReasonJobContainerFailed is a Warning on a Job pod whose container
+	// terminated with a non-zero exit code.
+	EventReasonJobContainerFailed = "JobContainerFailed"
+	// EventReasonImagePullFailed is a Warning on a pod whose container has been
+	// failing to pull its image for longer than the configured threshold.
+	EventReasonImagePullFailed = "ImagePullStuck"
+	// EventReasonCertificateExpiring is a Warning on a secret whose earliest
+	// certificate expires within the warning window of its policy.
+	EventReasonCertificateExpiring = "CertificateExpiringSoon"
+	// EventReasonCertificateCritical is a Warning on a secret whose earliest
+	// certificate expires within the critical window of its policy.
+	EventReasonCertificateCritical = "CertificateExpiryCritical"
+	// EventReasonCertificateExpired is a Warning on a secret whose earliest
+	// certificate has already expired.
+	EventReasonCertificateExpired = "CertificateExpired"
+	// EventReasonNamespaceCertificatesCritical is a Warning on a secret whose
+	// namespace has a certificate health score of 0: every certificate is
+	// expired or critical.
+	EventReasonNamespaceCertificatesCritical = "NamespaceCertificatesCritical"
+	// EventReasonCertificateRotated is a Normal event on a secret whose
+	// certificate expiry changed since the previous check.
+	EventReasonCertificateRotated = "CertificateRotated"
+	// EventReasonSecretSizeLarge is a Warning on a secret whose data is above
+	// the size warning threshold.
+	EventReasonSecretSizeLarge = "SecretSizeLarge"
+	// EventReasonEphemeralStorageEvicted is a Warning on a pod the kubelet
+	// evicted for its ephemeral storage usage.
+	EventReasonEphemeralStorageEvicted = "EphemeralStorageEvicted"
+	// EventReasonCSRPending is a Warning on a CertificateSigningRequest that
+	// has been waiting for approval longer than the alert threshold.
+	EventReasonCSRPending = "CertificateSigningRequestPending"
+)
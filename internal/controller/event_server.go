@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+)
+
+// EventServer serves the gRPC EventService, a push feed of restarts,
+// restart threshold crossings and certificate warnings and rotations.
+type EventServer struct {
+	eventsv1.UnimplementedEventServiceServer
+
+	addr       string
+	tlsConfig  *tls.Config
+	bufferSize int
+}
+
+var _ manager.Runnable = &EventServer{}
+var _ manager.LeaderElectionRunnable = &EventServer{}
+
+// NewEventServer creates an event server listening on addr. When tlsConfig is
+// nil the server accepts plaintext connections.
+func NewEventServer(addr string, tlsConfig *tls.Config) *EventServer {
+	return &EventServer{addr: addr, tlsConfig: tlsConfig, bufferSize: defaultEventBufferSize}
+}
+
+// Start runs the gRPC server until the context is cancelled.
+func (s *EventServer) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("event-server")
+
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.addr, err)
+	}
+
+	var opts []grpc.ServerOption
+	if s.tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tlsConfig)))
+	}
+	srv := grpc.NewServer(opts...)
+	eventsv1.RegisterEventServiceServer(srv, s)
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("Starting event server", "addr", s.addr, "tls", s.tlsConfig != nil)
+		errCh <- srv.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		// 流式调用不会自行结束，直接关闭所有连接
+		srv.Stop()
+		return nil
+	case err := <-errCh:
+		return err
+	}
+}
+
+// NeedLeaderElection returns true: events are only detected by the replica
+// that runs the reconcilers.
+func (s *EventServer) NeedLeaderElection() bool {
+	return true
+}
+
+// WatchEvents streams events from the requested namespaces until the client
+// goes away. A slow client loses its oldest buffered events rather than
+// delaying reconciles.
+func (s *EventServer) WatchEvents(req *eventsv1.WatchEventsRequest,
+	stream grpc.ServerStreamingServer[eventsv1.Event]) error {
+	sub := eventStream.subscribe(req.GetNamespaces(), s.bufferSize)
+	defer eventStream.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
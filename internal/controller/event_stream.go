@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+)
+
+// defaultEventBufferSize is the number of events buffered per stream client.
+const defaultEventBufferSize = 256
+
+var (
+	// 因客户端消费过慢而被丢弃的事件数
+	eventStreamDroppedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_event_stream_dropped_total",
+			Help: "Number of events dropped from the buffer of a slow event stream client",
+		},
+	)
+
+	// 当前连接的事件流客户端数
+	eventStreamSubscribers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_event_stream_subscribers",
+			Help: "Number of connected event stream clients",
+		},
+	)
+
+	// eventStream fans detected events out to the gRPC WatchEvents clients.
+	eventStream = newEventBroadcaster()
+)
+
+func init() {
+	registerMetrics(eventStreamDroppedTotal)
+	registerMetrics(eventStreamSubscribers)
+}
+
+// eventSubscription is the buffer of one stream client. Publishing never
+// blocks: when the buffer is full the oldest event is dropped.
+type eventSubscription struct {
+	namespaces []string
+	events     chan *eventsv1.Event
+	mu         sync.Mutex
+}
+
+func (s *eventSubscription) wants(namespace string) bool {
+	return len(s.namespaces) == 0 || slices.Contains(s.namespaces, namespace)
+}
+
+func (s *eventSubscription) push(event *eventsv1.Event) {
+	// 串行化发布者，保证“丢弃最旧 + 写入”是原子的
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		select {
+		case s.events <- event:
+			return
+		default:
+		}
+		select {
+		case <-s.events:
+			eventStreamDroppedTotal.Inc()
+		default:
+		}
+	}
+}
+
+// eventBroadcaster keeps the set of stream clients.
+type eventBroadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[*eventSubscription]struct{}
+}
+
+func newEventBroadcaster() *eventBroadcaster {
+	return &eventBroadcaster{subscribers: make(map[*eventSubscription]struct{})}
+}
+
+// subscribe registers a client interested in the given namespaces (all when
+// empty) with a buffer of the given size.
+func (b *eventBroadcaster) subscribe(namespaces []string, bufferSize int) *eventSubscription {
+	if bufferSize <= 0 {
+		bufferSize = defaultEventBufferSize
+	}
+	sub := &eventSubscription{namespaces: namespaces, events: make(chan *eventsv1.Event, bufferSize)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub] = struct{}{}
+	eventStreamSubscribers.Set(float64(len(b.subscribers)))
+	return sub
+}
+
+func (b *eventBroadcaster) unsubscribe(sub *eventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, sub)
+	eventStreamSubscribers.Set(float64(len(b.subscribers)))
+}
+
+// publish hands the event to every client interested in its namespace.
+func (b *eventBroadcaster) publish(event *eventsv1.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers {
+		if sub.wants(event.GetNamespace()) {
+			sub.push(event)
+		}
+	}
+}
+
+// publish streams an event unless its namespace is silenced.
+func (r *PodMonitorReconciler) publish(event *eventsv1.Event) {
+	if r.notificationsSilenced(event.GetNamespace()) {
+		return
+	}
+	eventStream.publish(event)
+}
+
+// publishContainerRestart streams a detected container restart.
+func (r *PodMonitorReconciler) publishContainerRestart(namespace string, restart *eventsv1.ContainerRestart, at time.Time) {
+	r.publish(&eventsv1.Event{
+		Time:      timestamppb.New(at),
+		Namespace: namespace,
+		Payload:   &eventsv1.Event_ContainerRestart{ContainerRestart: restart},
+	})
+}
+
+// publishRestartThresholdCrossed streams a container reaching its restart
+// alert threshold.
+func (r *PodMonitorReconciler) publishRestartThresholdCrossed(namespace string, crossed *eventsv1.RestartThresholdCrossed, at time.Time) {
+	r.publish(&eventsv1.Event{
+		Time:      timestamppb.New(at),
+		Namespace: namespace,
+		Payload:   &eventsv1.Event_RestartThresholdCrossed{RestartThresholdCrossed: crossed},
+	})
+}
+
+// publishCertificateWarning streams a certificate inside its warning window.
+func (r *PodMonitorReconciler) publishCertificateWarning(namespace string, warning *eventsv1.CertificateWarning, at time.Time) {
+	r.publish(&eventsv1.Event{
+		Time:      timestamppb.New(at),
+		Namespace: namespace,
+		Payload:   &eventsv1.Event_CertificateWarning{CertificateWarning: warning},
+	})
+}
+
+// publishCertificateRotated streams a replaced certificate.
+func (r *PodMonitorReconciler) publishCertificateRotated(namespace string, rotated *eventsv1.CertificateRotated, at time.Time) {
+	r.publish(&eventsv1.Event{
+		Time:      timestamppb.New(at),
+		Namespace: namespace,
+		Payload:   &eventsv1.Event_CertificateRotated{CertificateRotated: rotated},
+	})
+}
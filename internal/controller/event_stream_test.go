@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+)
+
+func TestEventBroadcasterDropsOldestForSlowClients(t *testing.T) {
+	b := newEventBroadcaster()
+	sub := b.subscribe([]string{"payments"}, 2)
+	defer b.unsubscribe(sub)
+
+	dropped := testutil.ToFloat64(eventStreamDroppedTotal)
+	for i := int32(1); i <= 3; i++ {
+		b.publish(&eventsv1.Event{
+			Namespace: "payments",
+			Payload:   &eventsv1.Event_ContainerRestart{ContainerRestart: &eventsv1.ContainerRestart{RestartCount: i}},
+		})
+	}
+	// 其他命名空间的事件不进入缓冲区
+	b.publish(&eventsv1.Event{Namespace: "web"})
+
+	if got := testutil.ToFloat64(eventStreamDroppedTotal) - dropped; got != 1 {
+		t.Fatalf("expected 1 dropped event, got %v", got)
+	}
+	for _, want := range []int32{2, 3} {
+		select {
+		case event := <-sub.events:
+			if got := event.GetContainerRestart().GetRestartCount(); got != want {
+				t.Fatalf("expected restart count %d, got %d", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected a buffered event")
+		}
+	}
+	select {
+	case event := <-sub.events:
+		t.Fatalf("expected no more events, got %v", event)
+	default:
+	}
+}
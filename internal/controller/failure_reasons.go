@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultFailureReasonWindow is the length of the window in which the
+// distinct failure reasons of a workload are counted.
+const defaultFailureReasonWindow = 24 * time.Hour
+
+// configureFailureReasonWindow sets the length of the failure reason window.
+// It is meant to be called once during setup and drops the counts collected
+// so far.
+func (s *restartStateStore) configureFailureReasonWindow(window time.Duration) {
+	if window <= 0 {
+		window = defaultFailureReasonWindow
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failureReasonWindow = newRestartWindow(window)
+}
+
+// distinctFailureReasons returns the number of distinct termination reasons
+// of each workload within the window, dropping expired buckets first.
+func distinctFailureReasons(window *restartWindow, now time.Time) map[workloadRef]int {
+	reasons := make(map[workloadRef]int)
+	for _, total := range window.advance(now) {
+		workload := workloadRef{Namespace: total.Namespace, Kind: total.WorkloadKind, Name: total.WorkloadName}
+		reasons[workload]++
+	}
+	return reasons
+}
+
+// failureReasonsCollector exports the number of distinct failure reasons of
+// each workload. The window is advanced at scrape time, so old reasons fall
+// out without new restarts.
+type failureReasonsCollector struct {
+	desc *prometheus.Desc
+}
+
+func newFailureReasonsCollector() *failureReasonsCollector {
+	return &failureReasonsCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_workload_distinct_failure_reasons",
+			"Number of distinct termination reasons of the containers of a workload within the failure reason window",
+			[]string{"namespace", "workload_kind", "workload_name"}, nil,
+		),
+	}
+}
+
+func (c *failureReasonsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *failureReasonsCollector) Collect(ch chan<- prometheus.Metric) {
+	stateStore.mu.RLock()
+	window := stateStore.failureReasonWindow
+	stateStore.mu.RUnlock()
+
+	for workload, reasons := range distinctFailureReasons(window, time.Now()) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(reasons),
+			workload.Namespace, workload.Kind, workload.Name)
+	}
+}
+
+func init() {
+	registerMetrics(newFailureReasonsCollector())
+}
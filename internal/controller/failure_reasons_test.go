@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestDistinctFailureReasonsDecay(t *testing.T) {
+	s := newRestartStateStore()
+	s.configureFailureReasonWindow(0)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	api := workloadRef{Namespace: "default", Kind: "Deployment", Name: "api"}
+	worker := workloadRef{Namespace: "default", Kind: "StatefulSet", Name: "worker"}
+
+	s.recordRestartInWindow(api, "OOMKilled", now.Add(-20*time.Hour), now)
+	s.recordRestartInWindow(api, "Error", now.Add(-time.Hour), now)
+	s.recordRestartInWindow(api, "Error", now.Add(-time.Minute), now)
+	// 正常退出不算失败原因
+	s.recordRestartInWindow(api, "Completed", now, now)
+	s.recordRestartInWindow(worker, "Error", now.Add(-2*time.Hour), now)
+
+	got := distinctFailureReasons(s.failureReasonWindow, now)
+	if got[api] != 2 || got[worker] != 1 || len(got) != 2 {
+		t.Fatalf("unexpected distinct reasons %v", got)
+	}
+	// 5 小时后 OOMKilled 已离开 24 小时窗口
+	if got := distinctFailureReasons(s.failureReasonWindow, now.Add(5*time.Hour)); got[api] != 1 {
+		t.Fatalf("expected the OOM kill to fall out of the window, got %v", got)
+	}
+	if got := distinctFailureReasons(s.failureReasonWindow, now.Add(25*time.Hour)); len(got) != 0 {
+		t.Fatalf("expected all reasons to expire, got %v", got)
+	}
+
+	// 命名空间窗口仍为一小时，包括正常退出，不区分工作负载
+	counts := map[string]int{}
+	for _, total := range s.restartWindow.advance(now) {
+		if total.WorkloadName != "" {
+			t.Fatalf("expected the namespace window not to key by workload, got %+v", total)
+		}
+		counts[total.Reason] = total.Count
+	}
+	if len(counts) != 2 || counts["Error"] != 1 || counts["Completed"] != 1 {
+		t.Fatalf("unexpected namespace window counts %v", counts)
+	}
+}
+
+func TestDistinctFailureReasonsMetric(t *testing.T) {
+	workload := workloadRef{Namespace: "failure-reasons-test", Kind: "Deployment", Name: "api"}
+	now := time.Now()
+	for _, reason := range []string{"OOMKilled", "Error", "Error"} {
+		stateStore.recordRestartInWindow(workload, reason, now, now)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_workload_distinct_failure_reasons",
+		testsupport.Labels{"namespace": workload.Namespace, "workload_kind": "Deployment", "workload_name": "api"}, 2)
+}
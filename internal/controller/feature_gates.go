@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+)
+
+// Names of the features gated on RBAC permissions, exported as the feature
+// label of pod_monitor_feature_enabled. Features also reported in build_info
+// reuse the build_info names.
+const (
+	FeatureSecrets              = "secrets"
+	FeatureForceRefresh         = "force_refresh"
+	FeatureEvents               = "events"
+	FeaturePolicies             = "policies"
+	FeatureNodeDrainTracking    = "node_drain_tracking"
+	FeatureNodeTopology         = "node_topology"
+	FeatureNodeReady            = "watch_nodes"
+	FeatureNodeConditions       = "node_conditions"
+	FeaturePodDisruptionBudgets = "pod_disruption_budgets"
+	FeatureMetricsAPI           = "metrics_api"
+	FeatureServiceMonitor       = "service_monitor"
+	FeaturePodMonitorStatus     = "pod_monitor_status"
+	FeatureRestartBudgets       = "restart_budgets"
+	FeatureProbeEvents          = "probe_events"
+	FeatureKubeadm              = "kubeadm"
+	FeatureCSRWatch             = "csr_watch"
+	FeatureLinkerdVersion       = "linkerd_version"
+	FeatureWorkloadConditions   = "workload_conditions"
+)
+
+var (
+	// 启动时按 RBAC 权限探测的功能开关：1 为启用，0 为因权限缺失而关闭
+	featureEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_feature_enabled",
+			Help: "Whether a configured feature is enabled (1) or was disabled at startup because the " +
+				"operator lacks the RBAC permissions it needs (0)",
+		},
+		[]string{"feature"},
+	)
+)
+
+func init() {
+	registerMetrics(featureEnabled)
+}
+
+// Feature is an optional part of the operator together with the permissions
+// it cannot work without.
+type Feature struct {
+	Name        string
+	Permissions []Permission
+}
+
+// ProbeFeatures checks the permissions of each feature with
+// SelfSubjectAccessReviews, exports pod_monitor_feature_enabled and returns
+// the missing permissions of every feature that must be disabled.
+func ProbeFeatures(ctx context.Context, c client.Client, features []Feature) (map[string][]Permission, error) {
+	disabled := make(map[string][]Permission)
+	for _, feature := range features {
+		missing, err := CheckPermissions(ctx, c, feature.Permissions)
+		if err != nil {
+			return disabled, err
+		}
+		if len(missing) > 0 {
+			disabled[feature.Name] = missing
+			featureEnabled.WithLabelValues(feature.Name).Set(0)
+			continue
+		}
+		featureEnabled.WithLabelValues(feature.Name).Set(1)
+	}
+	return disabled, nil
+}
+
+// permissions expands verbs on one resource into permissions.
+func permissions(group, resource string, verbs ...string) []Permission {
+	perms := make([]Permission, 0, len(verbs))
+	for _, verb := range verbs {
+		perms = append(perms, Permission{Group: group, Resource: resource, Verb: verb})
+	}
+	return perms
+}
+
+// Features returns the features enabled by the reconciler configuration that
+// can be turned off when the operator lacks their permissions.
+func (r *PodMonitorReconciler) Features() []Feature {
+	features := []Feature{
+		{Name: FeatureSecrets, Permissions: permissions("", "secrets", "get", "list", "watch")},
+		{Name: FeatureEvents, Permissions: permissions("", "events", "create", "patch")},
+		{Name: FeatureNodeDrainTracking, Permissions: permissions("", "nodes", "list", "watch")},
+		{Name: FeatureNodeTopology, Permissions: permissions("", "nodes", "get", "list", "watch")},
+		{Name: FeaturePolicies, Permissions: permissions(monitorv1alpha1.GroupVersion.Group, "podmonitorpolicies",
+			"list", "watch")},
+		{Name: FeaturePodMonitorStatus, Permissions: append(
+			permissions(monitorv1alpha1.GroupVersion.Group, "podmonitors", "list", "watch"),
+			permissions(monitorv1alpha1.GroupVersion.Group, "podmonitors/status", "update")...)},
+	}
+	if !r.DisableSecretWatch {
+		features = append(features, Feature{Name: FeatureForceRefresh,
+			Permissions: permissions("", "secrets", "patch")})
+	}
+	if r.ValidateCertificateHostnames {
+		features = append(features, Feature{Name: FeatureValidateCertificateHostname,
+			Permissions: permissions("networking.k8s.io", "ingresses", "list", "watch")})
+	}
+	if r.AnnotateSecrets {
+		features = append(features, Feature{Name: FeatureAnnotateSecrets,
+			Permissions: permissions("", "secrets", "patch")})
+	}
+	if r.EnableNodeWatch {
+		features = append(features, Feature{Name: FeatureNodeConditions,
+			Permissions: permissions("", "nodes", "get", "list", "watch")})
+	}
+	if r.WatchNodes {
+		features = append(features, Feature{Name: FeatureNodeReady,
+			Permissions: permissions("", "nodes", "list", "watch")})
+	}
+	if r.WatchPodDisruptionBudgets {
+		features = append(features, Feature{Name: FeaturePodDisruptionBudgets,
+			Permissions: permissions("policy", "poddisruptionbudgets", "list", "watch")})
+	}
+	if r.UseMetricsAPI {
+		features = append(features, Feature{Name: FeatureMetricsAPI,
+			Permissions: permissions("metrics.k8s.io", "pods", "get")})
+	}
+	if r.WatchProbeEvents {
+		features = append(features, Feature{Name: FeatureProbeEvents,
+			Permissions: permissions("", "events", "list", "watch")})
+	}
+	if r.KubeadmMode {
+		features = append(features, Feature{Name: FeatureKubeadm, Permissions: append(
+			permissions("", "secrets", "get"), permissions("", "configmaps", "get")...)})
+	}
+	if r.EnableCSRWatch {
+		features = append(features, Feature{Name: FeatureCSRWatch,
+			Permissions: permissions("certificates.k8s.io", "certificatesigningrequests", "list", "watch")})
+	}
+	if r.LinkerdMode && !r.DisableSecretWatch {
+		features = append(features, Feature{Name: FeatureLinkerdVersion,
+			Permissions: permissions("", "namespaces", "list", "watch")})
+	}
+	if r.PatchWorkloadConditions {
+		features = append(features, Feature{Name: FeatureWorkloadConditions, Permissions: append(
+			permissions("apps", "deployments/status", "update"), permissions("apps", "statefulsets/status", "update")...)})
+	}
+	return features
+}
+
+// DisableFeature turns off a feature returned by Features. It must be called
+// before SetupWithManager.
+func (r *PodMonitorReconciler) DisableFeature(name string) {
+	switch name {
+	case FeatureSecrets:
+		r.DisableSecretWatch = true
+	case FeatureForceRefresh:
+		r.disableForceRefreshAck = true
+	case FeatureEvents:
+		r.Recorder = nil
+	case FeaturePolicies:
+		r.DisablePolicies = true
+	case FeaturePodMonitorStatus:
+		r.DisablePodMonitorStatus = true
+	case FeatureNodeDrainTracking:
+		r.DisableNodeDrainTracking = true
+	case FeatureNodeTopology:
+		r.disableNodeTopology = true
+	case FeatureValidateCertificateHostname:
+		r.ValidateCertificateHostnames = false
+	case FeatureAnnotateSecrets:
+		r.AnnotateSecrets = false
+	case FeatureNodeConditions:
+		r.EnableNodeWatch = false
+	case FeatureNodeReady:
+		r.WatchNodes = false
+	case FeaturePodDisruptionBudgets:
+		r.WatchPodDisruptionBudgets = false
+	case FeatureMetricsAPI:
+		r.UseMetricsAPI = false
+	case FeatureProbeEvents:
+		r.WatchProbeEvents = false
+	case FeatureKubeadm:
+		r.KubeadmMode = false
+	case FeatureCSRWatch:
+		r.EnableCSRWatch = false
+	case FeatureLinkerdVersion:
+		r.disableLinkerdNamespaceWatch = true
+	case FeatureWorkloadConditions:
+		r.PatchWorkloadConditions = false
+	}
+}
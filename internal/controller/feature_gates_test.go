@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestProbeFeaturesDisablesForbiddenFeatures(t *testing.T) {
+	// 模拟未授予 nodes 的任何权限
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "nodes"
+			return nil
+		},
+	}).Build()
+	defer featureEnabled.Reset()
+	defer rbacPermissionMissing.Reset()
+
+	r := &PodMonitorReconciler{WatchNodes: true}
+	features := r.Features()
+	disabled, err := ProbeFeatures(context.Background(), c, features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disabled) != 3 || disabled[FeatureNodeDrainTracking] == nil || disabled[FeatureNodeReady] == nil ||
+		disabled[FeatureNodeTopology] == nil {
+		t.Fatalf("expected only the node features to be disabled, got %v", disabled)
+	}
+	for name := range disabled {
+		r.DisableFeature(name)
+	}
+	if !r.DisableNodeDrainTracking || r.WatchNodes || !r.disableNodeTopology {
+		t.Errorf("expected node drain tracking, node Ready tracking and node topology to be turned off")
+	}
+	if r.DisableSecretWatch {
+		t.Errorf("expected secret monitoring to stay enabled")
+	}
+
+	if got := testutil.ToFloat64(featureEnabled.WithLabelValues(FeatureNodeReady)); got != 0 {
+		t.Errorf("expected %s to be exported as disabled, got %v", FeatureNodeReady, got)
+	}
+	if got := testutil.ToFloat64(featureEnabled.WithLabelValues(FeatureSecrets)); got != 1 {
+		t.Errorf("expected %s to be exported as enabled, got %v", FeatureSecrets, got)
+	}
+	if n := testutil.CollectAndCount(featureEnabled); n != len(features) {
+		t.Errorf("expected one series per probed feature, got %d for %d features", n, len(features))
+	}
+}
+
+func TestProbeFeaturesWithoutSecretPatch(t *testing.T) {
+	// 模拟只授予 secrets 的只读权限
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectAccessReview)
+			attrs := review.Spec.ResourceAttributes
+			review.Status.Allowed = attrs.Resource != "secrets" || attrs.Verb != "patch"
+			return nil
+		},
+	}).Build()
+	defer featureEnabled.Reset()
+	defer rbacPermissionMissing.Reset()
+
+	r := &PodMonitorReconciler{AnnotateSecrets: true}
+	disabled, err := ProbeFeatures(context.Background(), c, r.Features())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(disabled) != 2 || disabled[FeatureForceRefresh] == nil || disabled[FeatureAnnotateSecrets] == nil {
+		t.Fatalf("expected only the features patching secrets to be disabled, got %v", disabled)
+	}
+	for name := range disabled {
+		r.DisableFeature(name)
+	}
+	if r.AnnotateSecrets || r.DisableSecretWatch {
+		t.Errorf("expected secrets to be monitored without being annotated")
+	}
+
+	// 没有 patch 权限时保留 force-refresh 注解，而不是让 reconcile 失败
+	secret := testsupport.NewSecret("default", "tls", nil)
+	secret.Annotations = map[string]string{forceRefreshAnnotation: "1"}
+	if err := r.acknowledgeForceRefresh(context.Background(), secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := secret.Annotations[forceRefreshAnnotation]; !ok {
+		t.Error("expected the force-refresh annotation to be kept")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport/podmonitortest"
+)
+
+// 通过 podmonitortest 以外部使用者的方式驱动 reconciler
+func TestReconcileWithTestReconciler(t *testing.T) {
+	const namespace = "harness-test"
+	ctx := context.Background()
+	finishedAt := podmonitortest.StartTime.Add(-time.Minute)
+	pod := testsupport.NewPod(namespace, "web").
+		WithTerminatedContainer("app", 1, "OOMKilled", 137, finishedAt).
+		Build()
+	secret := testsupport.NewTLSSecret(namespace, "web-tls",
+		testsupport.CertificateExpiringIn(t, podmonitortest.StartTime, 10, "web.example.com"))
+	c := podmonitortest.NewFakeClient(pod, secret)
+	r, err := podmonitortest.NewTestReconciler(c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	podReq := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "web"}}
+	secretReq := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "web-tls"}}
+	defer func() {
+		_ = c.Delete(ctx, pod)
+		_, _ = r.Reconcile(ctx, podReq)
+		_ = c.Delete(ctx, secret)
+		_, _ = r.Reconcile(ctx, secretReq)
+	}()
+
+	if _, err := r.Reconcile(ctx, podReq); err != nil {
+		t.Fatal(err)
+	}
+	container := testsupport.Labels{"namespace": namespace, "pod": "web", "container": "app"}
+	testsupport.AssertMetricValue(t, r.Registry, "pod_monitor_container_restart_total",
+		testsupport.Labels{"namespace": namespace, "pod": "web", "container": "app", "reason": "OOMKilled"}, 1)
+	testsupport.AssertMetricValue(t, r.Registry, "pod_monitor_container_oom_killed_total", container, 1)
+	testsupport.AssertMetricValue(t, r.Registry, "pod_monitor_container_last_termination_info", container,
+		float64(finishedAt.Unix()))
+	// 终止的容器运行了一分钟
+	testsupport.AssertMetricValue(t, r.Registry, "pod_monitor_container_last_termination_started_at_seconds",
+		container, float64(finishedAt.Add(-time.Minute).Unix()))
+	if len(r.Events.Events) == 0 {
+		t.Error("expected a Warning event for the restart")
+	}
+
+	if _, err := r.Reconcile(ctx, secretReq); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertMetricValue(t, r.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": namespace, "secret_name": "web-tls", "cert_type": "tls.crt"}, 10)
+
+	// 删除 Pod 后清理其终止信息；重启计数器保留
+	if err := c.Delete(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, podReq); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertNoMetric(t, r.Registry, "pod_monitor_container_last_termination_info", container)
+	testsupport.AssertNoMetric(t, r.Registry, "pod_monitor_container_last_termination_started_at_seconds", container)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 共享节点网络、PID 或 IPC 命名空间的 Pod 清单，值恒为 1
+	// 只为至少共享其中一种命名空间的 Pod 导出序列
+	podHostAccessInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_pod_host_access_info",
+			Help: "Pods sharing the node's network, PID or IPC namespace. The value is always 1.",
+		},
+		[]string{
+			"namespace",    // Pod 所在命名空间
+			"pod",          // Pod 名称
+			"host_network", // spec.hostNetwork
+			"host_pid",     // spec.hostPID
+			"host_ipc",     // spec.hostIPC
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(podHostAccessInfo))
+}
+
+// updateHostAccessInfo exports the host namespaces shared by the pod. The
+// fields are immutable, so the series never needs to be replaced.
+func updateHostAccessInfo(b *metricBatch, pod *corev1.Pod) {
+	spec := pod.Spec
+	if !spec.HostNetwork && !spec.HostPID && !spec.HostIPC {
+		return
+	}
+	b.set(podHostAccessInfo, 1, pod.Namespace, pod.Name, strconv.FormatBool(spec.HostNetwork),
+		strconv.FormatBool(spec.HostPID), strconv.FormatBool(spec.HostIPC))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestHostAccessInfo(t *testing.T) {
+	const namespace = "host-access-test"
+	defer podHostAccessInfo.Reset()
+
+	tests := []struct {
+		name    string
+		network bool
+		pid     bool
+		ipc     bool
+	}{
+		{"isolated", false, false, false},
+		{"host-network", true, false, false},
+		{"host-pid-ipc", false, true, true},
+	}
+	var b metricBatch
+	for _, tt := range tests {
+		pod := testsupport.NewPod(namespace, tt.name).Build()
+		pod.Spec.HostNetwork, pod.Spec.HostPID, pod.Spec.HostIPC = tt.network, tt.pid, tt.ipc
+		updateHostAccessInfo(&b, pod)
+	}
+	stateStore.commitMetrics(&b)
+
+	// 不共享任何节点命名空间的 Pod 不导出序列
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_pod_host_access_info",
+		testsupport.Labels{"namespace": namespace, "pod": "isolated"})
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_host_access_info",
+		testsupport.Labels{"namespace": namespace, "pod": "host-network",
+			"host_network": "true", "host_pid": "false", "host_ipc": "false"}, 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_host_access_info",
+		testsupport.Labels{"namespace": namespace, "pod": "host-pid-ipc",
+			"host_network": "false", "host_pid": "true", "host_ipc": "true"}, 1)
+
+	// Pod 删除后清理
+	cleanupPod(namespace, "host-network")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_pod_host_access_info",
+		testsupport.Labels{"namespace": namespace, "pod": "host-network"})
+}
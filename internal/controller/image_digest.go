@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// expectedImageDigestAnnotationPrefix is the prefix of the pod annotations
+// pinning the image digest of a container, e.g.
+// "expected-image-digest.pod-monitor.deraiven.io/app: sha256:abc123". The
+// container name is the annotation name: a key can contain only one "/", so
+// "pod-monitor.deraiven.io/expected-image-digest/<container>" is not a valid
+// annotation key.
+const expectedImageDigestAnnotationPrefix = "expected-image-digest.pod-monitor.deraiven.io/"
+
+var (
+	// 运行中容器的镜像 digest 与注解中期望的 digest 不一致时为 1，一致时为 0
+	containerImageDigestMismatch = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_image_digest_mismatch",
+			Help: "Whether the image digest of a container differs from the digest pinned in the pod's " +
+				"expected-image-digest annotation (1) or matches it (0)",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerImageDigestMismatch))
+}
+
+// imageDigest returns the digest part of a container status ImageID, which
+// depending on the runtime looks like "docker-pullable://repo@sha256:...",
+// "repo@sha256:..." or "sha256:...".
+func imageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		return imageID[i+1:]
+	}
+	return imageID
+}
+
+// updateImageDigestMismatch compares the image digest of every container with
+// a pinned digest against its annotation. Containers without an annotation, or
+// that have not pulled their image yet, have no series.
+func updateImageDigestMismatch(b *metricBatch, pod *corev1.Pod) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		expected := pod.Annotations[expectedImageDigestAnnotationPrefix+cs.Name]
+		if expected == "" || cs.ImageID == "" {
+			b.delete(containerImageDigestMismatch.MetricVec, pod.Namespace, pod.Name, cs.Name)
+			continue
+		}
+		mismatch := 0.0
+		if !strings.EqualFold(imageDigest(cs.ImageID), strings.TrimSpace(expected)) {
+			mismatch = 1
+		}
+		b.set(containerImageDigestMismatch, mismatch, pod.Namespace, pod.Name, cs.Name)
+	}
+}
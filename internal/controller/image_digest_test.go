@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestImageDigestMismatch(t *testing.T) {
+	const namespace = "image-digest-test"
+	const digest = "sha256:2f1c7a5e0d4b3c8e9f6a1b2c3d4e5f60718293a4b5c6d7e8f9a0b1c2d3e4f5a6"
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "web",
+			Annotations: map[string]string{
+				expectedImageDigestAnnotationPrefix + "app":     digest,
+				expectedImageDigestAnnotationPrefix + "sidecar": digest,
+			},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", ImageID: "docker.io/library/nginx@" + digest},
+			{Name: "sidecar", ImageID: "docker-pullable://envoy@sha256:0000"},
+			{Name: "unpinned", ImageID: "docker.io/library/busybox@sha256:1111"},
+		}},
+	}
+	update := func() {
+		var batch metricBatch
+		updateImageDigestMismatch(&batch, pod)
+		stateStore.commitMetrics(&batch)
+	}
+	mismatch := func(container string) float64 {
+		return testutil.ToFloat64(containerImageDigestMismatch.WithLabelValues(namespace, "web", container))
+	}
+	defer containerImageDigestMismatch.Reset()
+
+	update()
+	// 未固定 digest 的容器没有序列
+	if n := testutil.CollectAndCount(containerImageDigestMismatch); n != 2 {
+		t.Fatalf("expected only the two pinned containers to have series, got %d", n)
+	}
+	if got := mismatch("app"); got != 0 {
+		t.Errorf("expected the pinned digest to match, got %v", got)
+	}
+	if got := mismatch("sidecar"); got != 1 {
+		t.Errorf("expected a digest mismatch, got %v", got)
+	}
+
+	// 移除注解后删除序列
+	delete(pod.Annotations, expectedImageDigestAnnotationPrefix+"sidecar")
+	update()
+	if n := testutil.CollectAndCount(containerImageDigestMismatch); n != 1 {
+		t.Fatalf("expected the sidecar series to be removed, %d series left", n)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultImagePullStuckThreshold is how long a container may keep failing to
+// pull its image before a Warning event is emitted.
+const defaultImagePullStuckThreshold = 10 * time.Minute
+
+// imagePullState records since when a container continuously fails to pull
+// its image.
+type imagePullState struct {
+	Namespace string
+	Pod       string
+	Container string
+	Image     string
+	Since     time.Time
+	Warned    bool
+}
+
+// isImagePullFailure reports whether a waiting reason is an image pull error.
+func isImagePullFailure(reason string) bool {
+	return reason == "ErrImagePull" || reason == "ImagePullBackOff"
+}
+
+// observeImagePull records a container that is failing to pull its image and
+// returns its state. The first-seen time is kept across calls.
+func (s *restartStateStore) observeImagePull(key string, state imagePullState) imagePullState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.imagePullStuck[key]; ok {
+		state.Since = existing.Since
+		state.Warned = existing.Warned
+	}
+	s.imagePullStuck[key] = state
+	return state
+}
+
+// markImagePullWarned records that the Warning event of a container was sent.
+func (s *restartStateStore) markImagePullWarned(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.imagePullStuck[key]; ok {
+		state.Warned = true
+		s.imagePullStuck[key] = state
+	}
+}
+
+// clearImagePull forgets a container that is no longer failing to pull.
+func (s *restartStateStore) clearImagePull(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.imagePullStuck, key)
+}
+
+// imagePullStates returns a snapshot of all containers stuck pulling.
+func (s *restartStateStore) imagePullStates() []imagePullState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make([]imagePullState, 0, len(s.imagePullStuck))
+	for _, state := range s.imagePullStuck {
+		states = append(states, state)
+	}
+	return states
+}
+
+// trackImagePulls updates the image pull state of every container and emits
+// a Warning event once a container has been stuck longer than the threshold.
+// It returns when the pod should be reconciled again for a pending event.
+func (r *PodMonitorReconciler) trackImagePulls(pod *corev1.Pod, now time.Time) time.Duration {
+	threshold := r.ImagePullStuckThreshold
+	if threshold <= 0 {
+		threshold = defaultImagePullStuckThreshold
+	}
+
+	var requeueAfter time.Duration
+	for _, cs := range pod.Status.ContainerStatuses {
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+
+		if cs.State.Running != nil {
+			stateStore.clearImagePull(key)
+			continue
+		}
+		if cs.State.Waiting == nil || !isImagePullFailure(cs.State.Waiting.Reason) {
+			continue
+		}
+
+		state := stateStore.observeImagePull(key, imagePullState{
+			Namespace: pod.Namespace,
+			Pod:       pod.Name,
+			Container: cs.Name,
+			Image:     cs.Image,
+			Since:     now,
+		})
+		if state.Warned {
+			continue
+		}
+
+		stuck := now.Sub(state.Since)
+		if stuck < threshold {
+			// 到达阈值时重新检查，以便及时发出事件
+			if remaining := threshold - stuck; requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+		r.warnPod(pod, EventReasonImagePullFailed, "Container %s has been failing to pull image %s for %s",
+			cs.Name, cs.Image, stuck.Round(time.Second))
+		stateStore.markImagePullWarned(key)
+	}
+	return requeueAfter
+}
+
+// imagePullStuckCollector exports how long each container has been failing to
+// pull its image, computed at scrape time.
+type imagePullStuckCollector struct {
+	desc *prometheus.Desc
+}
+
+func newImagePullStuckCollector() *imagePullStuckCollector {
+	return &imagePullStuckCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_container_image_pull_stuck_seconds",
+			"Seconds a container has continuously been in ErrImagePull or ImagePullBackOff",
+			[]string{"namespace", "pod", "container"}, nil,
+		),
+	}
+}
+
+func (c *imagePullStuckCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *imagePullStuckCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, state := range stateStore.imagePullStates() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(state.Since).Seconds(),
+			state.Namespace, state.Pod, state.Container)
+	}
+}
+
+func init() {
+	registerMetrics(newImagePullStuckCollector())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestImagePullStuck(t *testing.T) {
+	const namespace = "image-pull-test"
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Recorder: recorder, ImagePullStuckThreshold: 10 * time.Minute}
+	collector := newImagePullStuckCollector()
+	waiting := func(reason string) *corev1.Pod {
+		return testsupport.NewPod(namespace, "web").
+			WithContainerStatus(corev1.ContainerStatus{Name: "app", Image: "registry.example.com/web:1.2",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}}}).
+			WithContainerStatus(corev1.ContainerStatus{Name: "sidecar", Image: "sidecar:1",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}}).
+			Build()
+	}
+	defer stateStore.forgetPod(namespace, "web")
+
+	// 首次发现拉取失败时记录时间，到达阈值时重新检查
+	if got := r.trackImagePulls(waiting("ErrImagePull"), now); got != 10*time.Minute {
+		t.Fatalf("expected a recheck after 10m, got %v", got)
+	}
+	if n := testutil.CollectAndCount(collector); n != 1 {
+		t.Fatalf("expected only the failing container to be exported, got %d series", n)
+	}
+
+	// 原因在 ErrImagePull 和 ImagePullBackOff 之间切换不重置起始时间
+	if got := r.trackImagePulls(waiting("ImagePullBackOff"), now.Add(4*time.Minute)); got != 6*time.Minute {
+		t.Fatalf("expected a recheck after 6m, got %v", got)
+	}
+	if n := len(recorder.Events); n != 0 {
+		t.Fatalf("expected no event below the threshold, got %d", n)
+	}
+
+	// 超过阈值后只发出一次事件，消息包含镜像
+	for _, at := range []time.Duration{10 * time.Minute, 20 * time.Minute} {
+		if got := r.trackImagePulls(waiting("ImagePullBackOff"), now.Add(at)); got != 0 {
+			t.Errorf("expected no recheck once warned, got %v", got)
+		}
+	}
+	if n := len(recorder.Events); n != 1 {
+		t.Fatalf("expected one %s event, got %d", EventReasonImagePullFailed, n)
+	}
+	if event := <-recorder.Events; !strings.Contains(event, EventReasonImagePullFailed) ||
+		!strings.Contains(event, "registry.example.com/web:1.2") {
+		t.Errorf("unexpected event %q", event)
+	}
+
+	// 容器运行后清理
+	running := testsupport.NewPod(namespace, "web").
+		WithContainerStatus(corev1.ContainerStatus{Name: "app",
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}).Build()
+	r.trackImagePulls(running, now.Add(21*time.Minute))
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("expected a running container to be cleared, got %d series", n)
+	}
+
+	// Pod 删除后清理
+	r.trackImagePulls(waiting("ErrImagePull"), now.Add(22*time.Minute))
+	stateStore.forgetPod(namespace, "web")
+	if n := testutil.CollectAndCount(collector); n != 0 {
+		t.Errorf("expected a deleted pod to be cleared, got %d series", n)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Values of the result label of pod_monitor_api_server_requests_total.
+const (
+	apiRequestResultSuccess  = "success"
+	apiRequestResultNotFound = "not_found"
+	apiRequestResultError    = "error"
+)
+
+var (
+	// 控制器发出的 Get/List/Patch 调用次数；Get/List 通常由 informer 缓存应答，
+	// 持续快速增长说明存在失控的 reconcile 循环
+	apiServerRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_api_server_requests_total",
+			Help: "Total number of Get, List and Patch calls made by the controller, by resource, verb and result",
+		},
+		[]string{
+			"resource", // 对象类型（小写 Kind），例如 pod、secret
+			"verb",     // get、list 或 patch
+			"result",   // success、not_found 或 error
+		},
+	)
+)
+
+func init() {
+	registerMetrics(apiServerRequestsTotal)
+}
+
+// instrumentedClient counts the Get, List and Patch calls made through it.
+type instrumentedClient struct {
+	client.Client
+	counter *prometheus.CounterVec
+}
+
+func newInstrumentedClient(c client.Client, counter *prometheus.CounterVec) *instrumentedClient {
+	return &instrumentedClient{Client: c, counter: counter}
+}
+
+func (c *instrumentedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object,
+	opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	c.observe(obj, "get", err)
+	return err
+}
+
+func (c *instrumentedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := c.Client.List(ctx, list, opts...)
+	c.observe(list, "list", err)
+	return err
+}
+
+func (c *instrumentedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch,
+	opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.observe(obj, "patch", err)
+	return err
+}
+
+func (c *instrumentedClient) observe(obj runtime.Object, verb string, err error) {
+	result := apiRequestResultSuccess
+	switch {
+	case apierrors.IsNotFound(err):
+		result = apiRequestResultNotFound
+	case err != nil:
+		result = apiRequestResultError
+	}
+	c.counter.WithLabelValues(c.resourceOf(obj), verb, result).Inc()
+}
+
+// resourceOf returns the lowercase kind of the object, without the List
+// suffix for lists.
+func (c *instrumentedClient) resourceOf(obj runtime.Object) string {
+	gvk, err := c.GroupVersionKindFor(obj)
+	if err != nil {
+		return "unknown"
+	}
+	return strings.ToLower(strings.TrimSuffix(gvk.Kind, "List"))
+}
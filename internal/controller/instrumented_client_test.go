@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestInstrumentedClientCountsRequests(t *testing.T) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_api_requests_total"},
+		[]string{"resource", "verb", "result"})
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tls"}}
+	c := newInstrumentedClient(fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build(), counter)
+	ctx := context.Background()
+
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "tls"}, &corev1.Secret{}); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "missing"}, &corev1.Secret{})
+	if err := c.List(ctx, &corev1.PodList{}); err != nil {
+		t.Fatal(err)
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+	secret.Annotations = map[string]string{"a": "b"}
+	if err := c.Patch(ctx, secret, patch); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range [][]string{
+		{"secret", "get", apiRequestResultSuccess},
+		{"secret", "get", apiRequestResultNotFound},
+		{"pod", "list", apiRequestResultSuccess},
+		{"secret", "patch", apiRequestResultSuccess},
+	} {
+		if got := testutil.ToFloat64(counter.WithLabelValues(want...)); got != 1 {
+			t.Errorf("expected 1 request for %v, got %v", want, got)
+		}
+	}
+}
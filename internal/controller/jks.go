@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	jksMagic              uint32 = 0xFEEDFEED
+	jksPrivateKeyTag      uint32 = 1
+	jksTrustedCertTag     uint32 = 2
+	jksIntegritySalt             = "Mighty Aphrodite"
+	jksMaxEntries                = 1024
+	jksMaxCertificateSize        = 1 << 20
+)
+
+// jksPasswordAnnotation holds the store password used to verify the integrity
+// of JKS files in the secret. Certificates in a JKS are not encrypted, so the
+// password is optional.
+const jksPasswordAnnotation = "pod-monitor.deraiven.io/jks-password"
+
+// checkJKSCertificates parses a JKS file from the secret and checks the
+// expiration of every certificate in it. Certificates are reported with the
+// cert_type "<key>[<index>]".
+func (r *PodMonitorReconciler) checkJKSCertificates(ctx context.Context, secret *corev1.Secret, key string, data []byte) error {
+	if int64(len(data)) > r.maxSecretKeySize() {
+		return fmt.Errorf("jks: %d bytes exceeds the size limit of %d bytes", len(data), r.maxSecretKeySize())
+	}
+	certs, err := parseCertificatesFromJKS(data, []byte(secret.Annotations[jksPasswordAnnotation]))
+	if err != nil {
+		return err
+	}
+	for i, cert := range certs {
+		r.recordCertificateExpiration(ctx, secret.Namespace, secret.Name, fmt.Sprintf("%s[%d]", key, i), cert)
+	}
+	return nil
+}
+
+// parseCertificatesFromJKS extracts all certificates from a Java KeyStore: the
+// trusted certificate entries and the certificate chains of private key
+// entries. Private keys themselves are never decrypted. When a password is
+// given, the keystore integrity digest is verified against it.
+func parseCertificatesFromJKS(data, password []byte) ([]*x509.Certificate, error) {
+	if len(data) < 12+sha1.Size {
+		return nil, errors.New("jks: data too short")
+	}
+
+	body, digest := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+	if len(password) > 0 && !bytes.Equal(jksDigest(body, password), digest) {
+		return nil, errors.New("jks: keystore integrity check failed, wrong password or corrupted data")
+	}
+
+	r := bytes.NewReader(body)
+	var magic, version, count uint32
+	for _, v := range []*uint32{&magic, &version, &count} {
+		if err := binary.Read(r, binary.BigEndian, v); err != nil {
+			return nil, fmt.Errorf("jks: reading header: %w", err)
+		}
+	}
+	if magic != jksMagic {
+		return nil, errors.New("jks: not a Java KeyStore")
+	}
+	if version != 1 && version != 2 {
+		return nil, fmt.Errorf("jks: unsupported version %d", version)
+	}
+	if count > jksMaxEntries {
+		return nil, fmt.Errorf("jks: too many entries (%d)", count)
+	}
+
+	var certs []*x509.Certificate
+	for i := uint32(0); i < count; i++ {
+		var tag uint32
+		if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+			return nil, fmt.Errorf("jks: reading entry %d: %w", i, err)
+		}
+		// alias 与时间戳对证书解析没有用处，直接跳过
+		if _, err := readJKSString(r); err != nil {
+			return nil, fmt.Errorf("jks: reading alias of entry %d: %w", i, err)
+		}
+		if _, err := r.Seek(8, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+
+		switch tag {
+		case jksPrivateKeyTag:
+			// 跳过加密的私钥，只读取证书链
+			if _, err := readJKSBlob(r); err != nil {
+				return nil, fmt.Errorf("jks: reading key of entry %d: %w", i, err)
+			}
+			var chainLen uint32
+			if err := binary.Read(r, binary.BigEndian, &chainLen); err != nil {
+				return nil, fmt.Errorf("jks: reading chain of entry %d: %w", i, err)
+			}
+			if chainLen > jksMaxEntries {
+				return nil, fmt.Errorf("jks: chain of entry %d too long (%d)", i, chainLen)
+			}
+			for j := uint32(0); j < chainLen; j++ {
+				cert, err := readJKSCertificate(r, version)
+				if err != nil {
+					return nil, fmt.Errorf("jks: reading chain of entry %d: %w", i, err)
+				}
+				certs = append(certs, cert)
+			}
+		case jksTrustedCertTag:
+			cert, err := readJKSCertificate(r, version)
+			if err != nil {
+				return nil, fmt.Errorf("jks: reading certificate of entry %d: %w", i, err)
+			}
+			certs = append(certs, cert)
+		default:
+			return nil, fmt.Errorf("jks: unknown entry tag %d", tag)
+		}
+	}
+
+	return certs, nil
+}
+
+// readJKSCertificate reads one certificate. Version 2 stores prefix each
+// certificate with its type, which must be X.509.
+func readJKSCertificate(r *bytes.Reader, version uint32) (*x509.Certificate, error) {
+	if version == 2 {
+		certType, err := readJKSString(r)
+		if err != nil {
+			return nil, err
+		}
+		if certType != "X.509" {
+			return nil, fmt.Errorf("unsupported certificate type %q", certType)
+		}
+	}
+	raw, err := readJKSBlob(r)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(raw)
+}
+
+// readJKSString reads a Java modified-UTF-8 string with a 16-bit length.
+func readJKSString(r *bytes.Reader) (string, error) {
+	var n uint16
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// readJKSBlob reads a byte array with a 32-bit length.
+func readJKSBlob(r *bytes.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	if n > jksMaxCertificateSize || int64(n) > int64(r.Len()) {
+		return nil, fmt.Errorf("invalid length %d", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// jksDigest computes the keystore integrity digest defined by the JKS format:
+// SHA-1 over the password as UTF-16BE, the fixed salt and the keystore body.
+func jksDigest(body, password []byte) []byte {
+	h := sha1.New()
+	for _, c := range utf16.Encode([]rune(string(password))) {
+		h.Write([]byte{byte(c >> 8), byte(c)})
+	}
+	h.Write([]byte(jksIntegritySalt))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+// buildJKS writes a version 2 keystore with one trusted certificate entry and
+// one private key entry carrying the given chain.
+func buildJKS(password string, trusted []byte, chain ...[]byte) []byte {
+	var buf bytes.Buffer
+	u32 := func(v uint32) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	str := func(s string) {
+		_ = binary.Write(&buf, binary.BigEndian, uint16(len(s)))
+		buf.WriteString(s)
+	}
+	blob := func(b []byte) {
+		u32(uint32(len(b)))
+		buf.Write(b)
+	}
+
+	u32(jksMagic)
+	u32(2)
+	u32(2)
+
+	u32(jksTrustedCertTag)
+	str("root")
+	_ = binary.Write(&buf, binary.BigEndian, int64(0))
+	str("X.509")
+	blob(trusted)
+
+	u32(jksPrivateKeyTag)
+	str("server")
+	_ = binary.Write(&buf, binary.BigEndian, int64(0))
+	blob([]byte("encrypted-key-is-never-decrypted"))
+	u32(uint32(len(chain)))
+	for _, c := range chain {
+		str("X.509")
+		blob(c)
+	}
+
+	body := buf.Bytes()
+	return append(body, jksDigest(body, []byte(password))...)
+}
+
+func TestParseCertificatesFromJKS(t *testing.T) {
+	root := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "root"}).DER()
+	leaf := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "leaf"}).DER()
+	issuer := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{CommonName: "issuer"}).DER()
+	data := buildJKS("changeit", root, leaf, issuer)
+
+	certs, err := parseCertificatesFromJKS(data, []byte("changeit"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, c := range certs {
+		names = append(names, c.Subject.CommonName)
+	}
+	if len(names) != 3 || names[0] != "root" || names[1] != "leaf" || names[2] != "issuer" {
+		t.Fatalf("unexpected certificates: %v", names)
+	}
+
+	if _, err := parseCertificatesFromJKS(data, nil); err != nil {
+		t.Fatalf("parsing without password should skip the integrity check: %v", err)
+	}
+	if _, err := parseCertificatesFromJKS(data, []byte("wrong")); err == nil {
+		t.Fatal("expected integrity check failure with wrong password")
+	}
+	if _, err := parseCertificatesFromJKS(data[:40], nil); err == nil {
+		t.Fatal("expected error for truncated keystore")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// Job 中以非零退出码结束的容器
+	jobContainerFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_job_container_failures_total",
+			Help: "Total number of containers in Job pods that terminated with a non-zero exit code",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"job",       // Job 名称
+			"container", // 容器名称
+			"reason",    // 终止原因
+			"exit_code", // 退出码
+		},
+	)
+)
+
+func init() {
+	registerMetrics(jobContainerFailuresTotal)
+}
+
+// isCompletedJobContainer reports whether a termination is the normal exit of
+// a container in a Job pod, which must not be treated as a restart.
+func isCompletedJobContainer(workload workloadRef, terminated *corev1.ContainerStateTerminated) bool {
+	return workload.Kind == "Job" && terminated.Reason == "Completed" && terminated.ExitCode == 0
+}
+
+// reportJobFailures counts every container of a Job pod that terminated with
+// a non-zero exit code. Job pods usually never restart, so such failures are
+// not visible to the restart detection. Each container is reported once; it
+// returns the number of failures reported by this call.
+func (r *PodMonitorReconciler) reportJobFailures(pod *corev1.Pod, workload workloadRef) int {
+	if workload.Kind != "Job" {
+		return 0
+	}
+
+	var reportedNow int
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		terminated := cs.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+		if !stateStore.markJobFailureReported(key) {
+			continue
+		}
+
+		reason := terminated.Reason
+		if reason == "" {
+			reason = "Unknown"
+		}
+		exitCode := strconv.Itoa(int(terminated.ExitCode))
+		jobContainerFailuresTotal.With(prometheus.Labels{
+			"namespace": pod.Namespace,
+			"job":       workload.Name,
+			"container": cs.Name,
+			"reason":    reason,
+			"exit_code": exitCode,
+		}).Inc()
+
+		r.warnPod(pod, EventReasonJobContainerFailed, "Container %s of Job %s failed (reason: %s, exit code: %s)",
+			cs.Name, workload.Name, reason, exitCode)
+		reportedNow++
+	}
+	return reportedNow
+}
+
+// markJobFailureReported records that the failure of a Job container was
+// reported and returns false if it had been reported already.
+func (s *restartStateStore) markJobFailureReported(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, reported := s.jobFailures[key]; reported {
+		return false
+	}
+	s.jobFailures[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestIsCompletedJobContainer(t *testing.T) {
+	job := workloadRef{Kind: "Job", Name: "backup"}
+	tests := []struct {
+		name       string
+		workload   workloadRef
+		terminated corev1.ContainerStateTerminated
+		want       bool
+	}{
+		{"completed sidecar", job, corev1.ContainerStateTerminated{Reason: "Completed"}, true},
+		{"failed", job, corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}, false},
+		{"completed with exit code", job, corev1.ContainerStateTerminated{Reason: "Completed", ExitCode: 2}, false},
+		{"deployment", workloadRef{Kind: "Deployment", Name: "web"},
+			corev1.ContainerStateTerminated{Reason: "Completed"}, false},
+	}
+	for _, tt := range tests {
+		if got := isCompletedJobContainer(tt.workload, &tt.terminated); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestJobFailuresReportedOnce(t *testing.T) {
+	const namespace = "job-failures-test"
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Recorder: recorder}
+	job := workloadRef{Kind: "Job", Name: "backup"}
+	terminated := func(name, reason string, exitCode int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode}}}
+	}
+	pod := testsupport.NewPod(namespace, "backup-x7k2").
+		WithContainerStatus(terminated("main", "Error", 2)).
+		WithContainerStatus(terminated("linkerd-proxy", "Completed", 0)).
+		WithContainerStatus(corev1.ContainerStatus{Name: "running"}).Build()
+	defer stateStore.forgetPod(namespace, pod.Name)
+	defer jobContainerFailuresTotal.Reset()
+	failures := func() float64 {
+		return testutil.ToFloat64(jobContainerFailuresTotal.WithLabelValues(namespace, "backup", "main", "Error", "2"))
+	}
+
+	// 只有非零退出的容器计数并告警，且每个容器只上报一次
+	if n := r.reportJobFailures(pod, job); n != 1 {
+		t.Fatalf("expected one failure to be reported, got %d", n)
+	}
+	if n := r.reportJobFailures(pod, job); n != 0 {
+		t.Fatalf("expected the failure to be reported once, got %d more", n)
+	}
+	if got := failures(); got != 1 {
+		t.Errorf("expected the failure to be counted once, got %v", got)
+	}
+	if n := len(recorder.Events); n != 1 {
+		t.Errorf("expected one %s event, got %d", EventReasonJobContainerFailed, n)
+	}
+	if n := testutil.CollectAndCount(jobContainerFailuresTotal); n != 1 {
+		t.Errorf("expected only the failed container to be counted, got %d series", n)
+	}
+
+	// 不属于 Job 的 Pod 不走该路径
+	if n := r.reportJobFailures(pod, workloadRef{Kind: "Deployment", Name: "web"}); n != 0 {
+		t.Errorf("expected pods outside Jobs to be ignored, got %d failures", n)
+	}
+
+	// Pod 删除后状态被清理
+	stateStore.forgetPod(namespace, pod.Name)
+	if n := r.reportJobFailures(pod, job); n != 1 {
+		t.Errorf("expected the state of a deleted pod to be forgotten, got %d failures", n)
+	}
+}
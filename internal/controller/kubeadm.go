@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+
+const (
+	// certificateSourceKubeadm labels the certificates of the kubeadm objects.
+	certificateSourceKubeadm = "kubeadm"
+
+	// kubeadmCheckInterval is how often the kubeadm objects are read.
+	kubeadmCheckInterval = time.Hour
+
+	// clusterInfoKubeconfigKey is the key of the kubeconfig in cluster-info.
+	clusterInfoKubeconfigKey = "kubeconfig"
+	// clusterInfoCertType is the cert_type label of the cluster CA read from
+	// the cluster-info kubeconfig.
+	clusterInfoCertType = "certificate-authority-data"
+)
+
+// kubeadmObject is a secret or ConfigMap that kubeadm creates with
+// certificates of the control plane.
+type kubeadmObject struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+var (
+	// kubeadm init --upload-certs 上传的控制平面证书，可能已加密
+	kubeadmCertsSecret = kubeadmObject{Kind: "Secret", Namespace: "kube-system", Name: "kubeadm-certs"}
+	// 节点加入集群时使用的公开 kubeconfig，包含集群 CA
+	clusterInfoConfigMap = kubeadmObject{Kind: "ConfigMap", Namespace: "kube-public", Name: "cluster-info"}
+	// kube-apiserver 发布的客户端 CA
+	apiserverAuthConfigMap = kubeadmObject{Kind: "ConfigMap", Namespace: "kube-system",
+		Name: "extension-apiserver-authentication"}
+
+	// kubeadmObjects are the objects checked in kubeadm mode.
+	kubeadmObjects = []kubeadmObject{kubeadmCertsSecret, clusterInfoConfigMap, apiserverAuthConfigMap}
+
+	// apiserverAuthComponents maps the certificate keys of
+	// extension-apiserver-authentication to their component label.
+	apiserverAuthComponents = map[string]string{
+		"client-ca-file":               "client-ca",
+		"requestheader-client-ca-file": "requestheader-client-ca",
+	}
+)
+
+var (
+	// kubeadm 模式下检查的对象是否缺失，1 为缺失，0 为存在
+	kubeadmObjectMissing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_kubeadm_object_missing",
+			Help: "Set to 1 when a kubeadm object checked for control-plane certificates does not exist, 0 otherwise.",
+		},
+		[]string{
+			"namespace", // 对象所在命名空间
+			"kind",      // Secret 或 ConfigMap
+			"name",      // 对象名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(kubeadmObjectMissing)
+}
+
+// isKubeadmObject reports whether namespace/name is one of the kubeadm
+// objects checked in kubeadm mode.
+func (r *PodMonitorReconciler) isKubeadmObject(namespace, name string) bool {
+	if !r.KubeadmMode {
+		return false
+	}
+	for _, obj := range kubeadmObjects {
+		if obj.Namespace == namespace && obj.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateComponent returns the component label of a certificate: the
+// control-plane component for the kubeadm objects, "" for any other secret.
+func (r *PodMonitorReconciler) certificateComponent(namespace, secretName, certType string) string {
+	if !r.isKubeadmObject(namespace, secretName) {
+		return ""
+	}
+	switch secretName {
+	case clusterInfoConfigMap.Name:
+		return "cluster-ca"
+	case apiserverAuthConfigMap.Name:
+		return apiserverAuthComponents[certType]
+	}
+	// kubeadm-certs 的键名即组件名，例如 front-proxy-ca.crt、etcd-ca.crt
+	return strings.TrimSuffix(certType, ".crt")
+}
+
+// checkKubeadmSecret checks every .crt key of the kubeadm-certs secret.
+// kubeadm encrypts the uploaded certificates; encrypted entries are not
+// certificate data and are skipped.
+func (r *PodMonitorReconciler) checkKubeadmSecret(ctx context.Context, secret *corev1.Secret) {
+	log := logf.FromContext(ctx)
+
+	for _, key := range sortedDataKeys(secret) {
+		if !strings.HasSuffix(key, ".crt") {
+			continue
+		}
+		if err := r.checkCertificateExpiration(ctx, secret.Namespace, secret.Name, key, secret.Data[key]); err != nil {
+			log.Error(err, "Failed to check kubeadm certificate expiration", "key", key)
+		}
+	}
+}
+
+// checkClusterInfo checks the certificate-authority-data of the cluster-info
+// kubeconfig.
+func (r *PodMonitorReconciler) checkClusterInfo(ctx context.Context, cm *corev1.ConfigMap) {
+	log := logf.FromContext(ctx)
+
+	config, err := clientcmd.Load([]byte(cm.Data[clusterInfoKubeconfigKey]))
+	if err != nil {
+		log.Error(err, "Failed to parse the cluster-info kubeconfig")
+		return
+	}
+	names := make([]string, 0, len(config.Clusters))
+	for name := range config.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// cluster-info 只包含一个集群；有多个时使用第一个带 CA 的集群
+	for _, name := range names {
+		data := config.Clusters[name].CertificateAuthorityData
+		if len(data) == 0 {
+			continue
+		}
+		if err := r.checkCertificateExpiration(ctx, cm.Namespace, cm.Name, clusterInfoCertType, data); err != nil {
+			log.Error(err, "Failed to check cluster CA expiration", "cluster", name)
+		}
+		return
+	}
+}
+
+// checkAPIServerAuthentication checks the client CAs published by the
+// kube-apiserver in extension-apiserver-authentication.
+func (r *PodMonitorReconciler) checkAPIServerAuthentication(ctx context.Context, cm *corev1.ConfigMap) {
+	log := logf.FromContext(ctx)
+
+	for key := range apiserverAuthComponents {
+		data, ok := cm.Data[key]
+		if !ok {
+			continue
+		}
+		if err := r.checkCertificateExpiration(ctx, cm.Namespace, cm.Name, key, []byte(data)); err != nil {
+			log.Error(err, "Failed to check kube-apiserver client CA expiration", "key", key)
+		}
+	}
+}
+
+// checkKubeadmObjects reads every kubeadm object and exports the expiry of
+// the certificates it holds. Missing objects are reported in
+// pod_monitor_kubeadm_object_missing and their certificate series deleted.
+func (r *PodMonitorReconciler) checkKubeadmObjects(ctx context.Context, reader client.Reader) {
+	log := logf.FromContext(ctx)
+
+	for _, obj := range kubeadmObjects {
+		key := types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}
+		var err error
+		switch obj.Kind {
+		case "Secret":
+			var secret corev1.Secret
+			if err = reader.Get(ctx, key, &secret); err == nil {
+				r.checkKubeadmSecret(ctx, &secret)
+			}
+		case "ConfigMap":
+			var cm corev1.ConfigMap
+			if err = reader.Get(ctx, key, &cm); err == nil {
+				if obj == clusterInfoConfigMap {
+					r.checkClusterInfo(ctx, &cm)
+				} else {
+					r.checkAPIServerAuthentication(ctx, &cm)
+				}
+			}
+		}
+
+		missing := kubeadmObjectMissing.With(prometheus.Labels{
+			"namespace": obj.Namespace,
+			"kind":      obj.Kind,
+			"name":      obj.Name,
+		})
+		switch {
+		case err == nil:
+			missing.Set(0)
+		case apierrors.IsNotFound(err):
+			// 非 kubeadm 集群没有这些对象，只上报缺失
+			missing.Set(1)
+			forgetSecretCertificates(obj.Namespace, obj.Name)
+		default:
+			log.Error(err, "Failed to read kubeadm object", "kind", obj.Kind, "namespace", obj.Namespace,
+				"name", obj.Name)
+		}
+	}
+}
+
+// kubeadmCertificateChecker periodically checks the kubeadm objects. The
+// ConfigMaps are read from the API server rather than from the cache, so
+// that the operator does not cache every ConfigMap of the cluster. Only the
+// leader exports certificate metrics, so it runs on the leader only.
+type kubeadmCertificateChecker struct {
+	r        *PodMonitorReconciler
+	reader   client.Reader
+	interval time.Duration
+}
+
+var _ manager.Runnable = &kubeadmCertificateChecker{}
+var _ manager.LeaderElectionRunnable = &kubeadmCertificateChecker{}
+
+func newKubeadmCertificateChecker(r *PodMonitorReconciler, reader client.Reader,
+	interval time.Duration) *kubeadmCertificateChecker {
+	return &kubeadmCertificateChecker{r: r, reader: reader, interval: interval}
+}
+
+// Start checks the kubeadm objects immediately, then every interval until
+// the context is cancelled.
+func (c *kubeadmCertificateChecker) Start(ctx context.Context) error {
+	ctx = logf.IntoContext(ctx, logf.FromContext(ctx).WithName("kubeadm"))
+	c.r.checkKubeadmObjects(ctx, c.reader)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.r.checkKubeadmObjects(ctx, c.reader)
+		}
+	}
+}
+
+// NeedLeaderElection returns true: certificate metrics are only exported by
+// the leader.
+func (c *kubeadmCertificateChecker) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestKubeadmObjects(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// kubeadm-certs 中加密的条目不是证书数据，只检查明文证书
+	secret := testsupport.NewSecret(kubeadmCertsSecret.Namespace, kubeadmCertsSecret.Name, map[string][]byte{
+		"ca.crt":             testsupport.CertificateExpiringIn(t, now, 300).CertPEM(),
+		"front-proxy-ca.crt": []byte("\x8a\x01encrypted"),
+		"ca.key":             []byte("key"),
+	})
+	kubeconfig, err := clientcmd.Write(clientcmdapi.Config{Clusters: map[string]*clientcmdapi.Cluster{
+		"": {Server: "https://10.0.0.1:6443", CertificateAuthorityData: testsupport.CertificateExpiringIn(t, now, 200).CertPEM()},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clusterInfo := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: clusterInfoConfigMap.Namespace, Name: clusterInfoConfigMap.Name},
+		Data:       map[string]string{clusterInfoKubeconfigKey: string(kubeconfig)},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret, clusterInfo).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now),
+		KubeadmMode: true}
+	defer func() {
+		for _, obj := range kubeadmObjects {
+			forgetSecretCertificates(obj.Namespace, obj.Name)
+		}
+		kubeadmObjectMissing.Reset()
+	}()
+
+	r.checkKubeadmObjects(ctx, c)
+
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": "kube-system", "secret_name": "kubeadm-certs", "cert_type": "ca.crt",
+			"source": certificateSourceKubeadm, "component": "ca"}, 300)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": "kube-system", "secret_name": "kubeadm-certs", "cert_type": "front-proxy-ca.crt"})
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": "kube-public", "secret_name": "cluster-info", "cert_type": clusterInfoCertType,
+			"source": certificateSourceKubeadm, "component": "cluster-ca"}, 200)
+
+	missing := func(obj kubeadmObject) testsupport.Labels {
+		return testsupport.Labels{"namespace": obj.Namespace, "kind": obj.Kind, "name": obj.Name}
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_kubeadm_object_missing",
+		missing(kubeadmCertsSecret), 0)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_kubeadm_object_missing",
+		missing(clusterInfoConfigMap), 0)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_kubeadm_object_missing",
+		missing(apiserverAuthConfigMap), 1)
+
+	// 对象删除后只保留缺失指标
+	if err := c.Delete(ctx, clusterInfo); err != nil {
+		t.Fatal(err)
+	}
+	r.checkKubeadmObjects(ctx, c)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_kubeadm_object_missing",
+		missing(clusterInfoConfigMap), 1)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": "kube-public", "secret_name": "cluster-info"})
+}
+
+func TestCertificateComponent(t *testing.T) {
+	r := &PodMonitorReconciler{KubeadmMode: true}
+	tests := []struct {
+		namespace, name, certType string
+		want                      string
+	}{
+		{"kube-system", "kubeadm-certs", "etcd-ca.crt", "etcd-ca"},
+		{"kube-public", "cluster-info", clusterInfoCertType, "cluster-ca"},
+		{"kube-system", "extension-apiserver-authentication", "requestheader-client-ca-file", "requestheader-client-ca"},
+		{"default", "kubeadm-certs", "ca.crt", ""},
+	}
+	for _, tt := range tests {
+		if got := r.certificateComponent(tt.namespace, tt.name, tt.certType); got != tt.want {
+			t.Errorf("certificateComponent(%s/%s, %s) = %q, want %q", tt.namespace, tt.name, tt.certType, got, tt.want)
+		}
+	}
+	// 未启用 kubeadm 模式时不设置组件
+	if got := (&PodMonitorReconciler{}).certificateComponent("kube-system", "kubeadm-certs", "ca.crt"); got != "" {
+		t.Errorf("component without kubeadm mode = %q", got)
+	}
+}
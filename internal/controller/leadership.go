@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	dto "github.com/prometheus/client_model/go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// metricPrefix is the prefix of all metric families owned by the operator.
+const metricPrefix = "pod_monitor_"
+
+// isLeader is true while this replica holds the leader lease (or leader
+// election is disabled).
+var isLeader atomic.Bool
+
+// LeaderTracker records whether this replica is the leader. It is a
+// leader election runnable, so it starts when the lease is acquired and is
+// stopped when it is lost.
+type LeaderTracker struct{}
+
+var _ manager.Runnable = &LeaderTracker{}
+var _ manager.LeaderElectionRunnable = &LeaderTracker{}
+
+// NewLeaderTracker creates a leader tracker.
+func NewLeaderTracker() *LeaderTracker {
+	return &LeaderTracker{}
+}
+
+// Start marks the replica as leader until the context is cancelled.
+func (t *LeaderTracker) Start(ctx context.Context) error {
+	logf.FromContext(ctx).WithName("leadership").Info("Acquired leadership, exporting pod-monitor metrics")
+	isLeader.Store(true)
+	<-ctx.Done()
+	isLeader.Store(false)
+	return nil
+}
+
+// NeedLeaderElection returns true so Start is only called on the leader.
+func (t *LeaderTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// leaderGatedRegistry hides the operator's metric families, except
+// pod_monitor_build_info, while the replica is not the leader. A standby
+// replica never reconciles, so its series would be stale or empty and would
+// conflict with the leader's when both are scraped. A new leader re-primes
+// the metrics from the initial list of its informers.
+type leaderGatedRegistry struct {
+	metrics.RegistererGatherer
+}
+
+// LeaderGatedRegistry wraps the registry served on /metrics so that only the
+// leader exports pod_monitor_* series.
+func LeaderGatedRegistry(registry metrics.RegistererGatherer) metrics.RegistererGatherer {
+	return leaderGatedRegistry{RegistererGatherer: registry}
+}
+
+func (r leaderGatedRegistry) Gather() ([]*dto.MetricFamily, error) {
+	families, err := r.RegistererGatherer.Gather()
+	if isLeader.Load() {
+		return families, err
+	}
+	kept := families[:0]
+	for _, family := range families {
+		name := family.GetName()
+		if strings.HasPrefix(name, metricPrefix) && name != metricPrefix+"build_info" {
+			continue
+		}
+		kept = append(kept, family)
+	}
+	return kept, err
+}
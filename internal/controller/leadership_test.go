@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestLeaderGatedRegistryHidesMetricsOnStandby(t *testing.T) {
+	inner := prometheus.NewRegistry()
+	for _, name := range []string{"pod_monitor_build_info", "pod_monitor_test_restarts", "controller_runtime_test"} {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: name})
+		gauge.Set(1)
+		inner.MustRegister(gauge)
+	}
+	registry := LeaderGatedRegistry(inner)
+
+	exported := func() []string {
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := make([]string, 0, len(families))
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		return names
+	}
+	waitFor := func(leader bool) {
+		for deadline := time.Now().Add(5 * time.Second); isLeader.Load() != leader; {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for leadership %v", leader)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// 备用副本只导出 build_info 和非 pod_monitor_ 指标
+	want := []string{"controller_runtime_test", "pod_monitor_build_info"}
+	if got := exported(); !slices.Equal(got, want) {
+		t.Fatalf("expected standby to export %v, got %v", want, got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = NewLeaderTracker().Start(ctx)
+	}()
+	waitFor(true)
+	want = []string{"controller_runtime_test", "pod_monitor_build_info", "pod_monitor_test_restarts"}
+	if got := exported(); !slices.Equal(got, want) {
+		t.Fatalf("expected leader to export %v, got %v", want, got)
+	}
+
+	// 失去 leader 身份后再次隐藏
+	cancel()
+	<-done
+	want = []string{"controller_runtime_test", "pod_monitor_build_info"}
+	if got := exported(); !slices.Equal(got, want) {
+		t.Fatalf("expected former leader to export %v, got %v", want, got)
+	}
+}
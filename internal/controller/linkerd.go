@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// linkerdProxyContainer is the name of the sidecar injected by Linkerd.
+	linkerdProxyContainer = "linkerd-proxy"
+	// linkerdIssuerSecret holds the identity issuer certificate in the Linkerd
+	// control plane namespace.
+	linkerdIssuerSecret = "linkerd-identity-issuer"
+	// defaultLinkerdNamespace is the Linkerd control plane namespace.
+	defaultLinkerdNamespace = "linkerd"
+	// defaultLinkerdRotationWindow is how long after an issuer rotation a proxy
+	// restart is counted as following it.
+	defaultLinkerdRotationWindow = 10 * time.Minute
+)
+
+var (
+	// linkerd-proxy 边车容器的重启次数，按代理版本（镜像 tag）区分
+	linkerdProxyRestartTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_linkerd_proxy_restart_total",
+			Help: "Total number of linkerd-proxy sidecar restarts, by proxy version",
+		},
+		[]string{
+			"namespace",     // Pod 所在命名空间
+			"pod",           // Pod 名称
+			"proxy_version", // linkerd-proxy 镜像 tag
+		},
+	)
+
+	// issuer 证书轮换后窗口内发生的 linkerd-proxy 重启次数
+	// 代理应在不重启的情况下加载新证书，批量重启说明轮换出了问题
+	linkerdProxyRestartsAfterRotationTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_linkerd_proxy_restarts_after_rotation_total",
+			Help: "Total number of linkerd-proxy restarts within the configured window after a rotation of " +
+				"the Linkerd identity issuer certificate. Proxies should pick up new certificates without restarting.",
+		},
+		[]string{
+			"namespace",     // Pod 所在命名空间
+			"proxy_version", // linkerd-proxy 镜像 tag
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(linkerdProxyRestartTotal))
+	registerMetrics(batched(linkerdProxyRestartsAfterRotationTotal))
+}
+
+// recordIssuerRotation remembers when the Linkerd identity issuer was last
+// seen to rotate, for the pod reconciles that follow.
+func (s *restartStateStore) recordIssuerRotation(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issuerRotatedAt = at
+}
+
+// lastIssuerRotation returns when the Linkerd identity issuer last rotated,
+// or the zero time if no rotation was seen since the operator started.
+func (s *restartStateStore) lastIssuerRotation() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.issuerRotatedAt
+}
+
+// isLinkerdIssuer reports whether the secret holds the Linkerd identity issuer.
+func (r *PodMonitorReconciler) isLinkerdIssuer(namespace, name string) bool {
+	if !r.LinkerdMode {
+		return false
+	}
+	return namespace == r.linkerdNamespace() && name == linkerdIssuerSecret
+}
+
+// linkerdNamespace returns the namespace of the Linkerd control plane.
+func (r *PodMonitorReconciler) linkerdNamespace() string {
+	if r.LinkerdNamespace == "" {
+		return defaultLinkerdNamespace
+	}
+	return r.LinkerdNamespace
+}
+
+// proxyVersion returns the tag of a container image, "latest" when the image
+// has none and "unknown" when it is pinned by digest only.
+func proxyVersion(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+		if !strings.Contains(image[strings.LastIndex(image, "/")+1:], ":") {
+			return "unknown"
+		}
+	}
+	// 冒号也可能出现在仓库端口中，只在最后一个路径段中查找 tag
+	name := image[strings.LastIndex(image, "/")+1:]
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return "latest"
+}
+
+// containerImage returns the image of the named container or init container
+// in the pod spec; native sidecars are init containers.
+func containerImage(pod *corev1.Pod, container string) string {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, c := range containers {
+			if c.Name == container {
+				return c.Image
+			}
+		}
+	}
+	return ""
+}
+
+// recordLinkerdProxyRestart counts a restart of the linkerd-proxy sidecar and,
+// when it finished within LinkerdRotationWindow after an issuer rotation, the
+// restarts following rotations.
+func (r *PodMonitorReconciler) recordLinkerdProxyRestart(b *metricBatch, pod *corev1.Pod, container string,
+	finishedAt time.Time) {
+	if !r.LinkerdMode || container != linkerdProxyContainer {
+		return
+	}
+	version := proxyVersion(containerImage(pod, container))
+	b.inc(linkerdProxyRestartTotal, pod.Namespace, pod.Name, version)
+
+	rotatedAt := stateStore.lastIssuerRotation()
+	if rotatedAt.IsZero() {
+		return
+	}
+	window := r.LinkerdRotationWindow
+	if window <= 0 {
+		window = defaultLinkerdRotationWindow
+	}
+	if since := finishedAt.Sub(rotatedAt); since >= 0 && since <= window {
+		b.inc(linkerdProxyRestartsAfterRotationTotal, pod.Namespace, version)
+	}
+}
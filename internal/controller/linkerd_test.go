@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestProxyVersion(t *testing.T) {
+	for image, want := range map[string]string{
+		"cr.l5d.io/linkerd/proxy:stable-2.14.10":               "stable-2.14.10",
+		"registry.local:5000/linkerd/proxy:edge-24.5.1":        "edge-24.5.1",
+		"registry.local:5000/linkerd/proxy":                    "latest",
+		"cr.l5d.io/linkerd/proxy:stable-2.14.10@sha256:abcdef": "stable-2.14.10",
+		"cr.l5d.io/linkerd/proxy@sha256:abcdef":                "unknown",
+	} {
+		if got := proxyVersion(image); got != want {
+			t.Errorf("proxyVersion(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+func TestLinkerdProxyRestartsAfterIssuerRotation(t *testing.T) {
+	const namespace = "linkerd-proxy-test"
+	r := &PodMonitorReconciler{LinkerdMode: true, LinkerdRotationWindow: 5 * time.Minute}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "app", Image: "web:1.0"},
+			{Name: linkerdProxyContainer, Image: "cr.l5d.io/linkerd/proxy:stable-2.14.10"},
+		}},
+	}
+	defer linkerdProxyRestartTotal.Reset()
+	defer linkerdProxyRestartsAfterRotationTotal.Reset()
+	defer stateStore.recordIssuerRotation(time.Time{})
+
+	if !r.isLinkerdIssuer("linkerd", linkerdIssuerSecret) || r.isLinkerdIssuer(namespace, linkerdIssuerSecret) {
+		t.Fatalf("expected only the issuer secret in the linkerd namespace to be recognized")
+	}
+
+	rotatedAt := time.Now()
+	stateStore.recordIssuerRotation(rotatedAt)
+	restart := func(container string, finishedAt time.Time) {
+		var batch metricBatch
+		r.recordLinkerdProxyRestart(&batch, pod, container, finishedAt)
+		stateStore.commitMetrics(&batch)
+	}
+	restart("app", rotatedAt.Add(time.Minute))
+	restart(linkerdProxyContainer, rotatedAt.Add(time.Minute))
+	restart(linkerdProxyContainer, rotatedAt.Add(time.Hour))
+
+	if got := testutil.ToFloat64(linkerdProxyRestartTotal.WithLabelValues(namespace, "web", "stable-2.14.10")); got != 2 {
+		t.Errorf("expected 2 proxy restarts, got %v", got)
+	}
+	got := testutil.ToFloat64(linkerdProxyRestartsAfterRotationTotal.WithLabelValues(namespace, "stable-2.14.10"))
+	if got != 1 {
+		t.Errorf("expected 1 proxy restart within the rotation window, got %v", got)
+	}
+}
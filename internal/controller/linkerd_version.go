@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+const (
+	// linkerdVersionKey is the annotation, or label, holding the version of
+	// the Linkerd control plane.
+	linkerdVersionKey = "linkerd.io/control-plane-version"
+	// linkerdConfigMap is the ConfigMap of the Linkerd control plane, labeled
+	// with its version.
+	linkerdConfigMap = "linkerd-config"
+	// linkerdVersionUnknown is the linkerd_version label when the version is
+	// not published.
+	linkerdVersionUnknown = "unknown"
+)
+
+var (
+	// Linkerd 控制平面命名空间中证书的过期时间，附带控制平面版本，便于跨集群排查
+	linkerdCertificateExpirationTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_linkerd_certificate_expiration_timestamp_seconds",
+			Help: "Unix timestamp in seconds when a certificate of the Linkerd control plane namespace expires, " +
+				"by Linkerd control-plane version",
+		},
+		[]string{
+			"namespace",       // Linkerd 控制平面命名空间
+			"secret_name",     // Secret 名称
+			"cert_type",       // 证书类型
+			"linkerd_version", // 控制平面版本，未知时为 unknown
+		},
+	)
+)
+
+func init() {
+	registerMetrics(linkerdCertificateExpirationTime)
+}
+
+// linkerdVersion returns the version of the Linkerd control plane: the
+// linkerd.io/control-plane-version annotation or label of its namespace,
+// else the label of the linkerd-config ConfigMap, else "unknown".
+func (r *PodMonitorReconciler) linkerdVersion(ctx context.Context) string {
+	log := logf.FromContext(ctx)
+
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: r.linkerdNamespace()}, &ns); err != nil {
+		log.V(1).Info("Unable to get the Linkerd namespace", "error", err.Error())
+	} else if version := linkerdVersionOf(&ns); version != "" {
+		return version
+	}
+
+	// ConfigMap 直接从 API server 读取，避免缓存集群中的所有 ConfigMap
+	reader := r.apiReader
+	if reader == nil {
+		reader = r.Client
+	}
+	var cm corev1.ConfigMap
+	key := types.NamespacedName{Namespace: r.linkerdNamespace(), Name: linkerdConfigMap}
+	if err := reader.Get(ctx, key, &cm); err != nil {
+		log.V(1).Info("Unable to get the Linkerd config", "error", err.Error())
+	} else if version := linkerdVersionOf(&cm); version != "" {
+		return version
+	}
+	return linkerdVersionUnknown
+}
+
+// linkerdVersionOf returns the control-plane version an object is annotated
+// or labeled with.
+func linkerdVersionOf(obj client.Object) string {
+	if version := obj.GetAnnotations()[linkerdVersionKey]; version != "" {
+		return version
+	}
+	return obj.GetLabels()[linkerdVersionKey]
+}
+
+// recordLinkerdCertificate exports the expiry of a certificate of the
+// Linkerd control plane namespace with the current control-plane version.
+func (r *PodMonitorReconciler) recordLinkerdCertificate(ctx context.Context, namespace, secretName, certType string,
+	expirationTime float64) {
+	if !r.LinkerdMode || namespace != r.linkerdNamespace() {
+		return
+	}
+	// 版本升级后删除旧版本的序列
+	labels := prometheus.Labels{"namespace": namespace, "secret_name": secretName, "cert_type": certType}
+	linkerdCertificateExpirationTime.DeletePartialMatch(labels)
+	labels["linkerd_version"] = r.linkerdVersion(ctx)
+	linkerdCertificateExpirationTime.With(labels).Set(expirationTime)
+}
+
+// linkerdNamespaceRequests maps a change of the Linkerd namespace, such as a
+// new control-plane version, to a recheck of the identity issuer.
+func (r *PodMonitorReconciler) linkerdNamespaceRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != r.linkerdNamespace() {
+		return nil
+	}
+	key := types.NamespacedName{Namespace: obj.GetName(), Name: linkerdIssuerSecret}
+	// issuer 不存在时不入队，否则请求会被当作 Pod 处理
+	if err := r.Get(ctx, key, &corev1.Secret{}); err != nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
+
+// linkerdNamespaceHandler enqueues the identity issuer when the Linkerd
+// namespace changes.
+func (r *PodMonitorReconciler) linkerdNamespaceHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(r.linkerdNamespaceRequests)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestLinkerdVersion(t *testing.T) {
+	ctx := context.Background()
+	namespace := func(annotations, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: defaultLinkerdNamespace,
+			Annotations: annotations, Labels: labels}}
+	}
+	config := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: defaultLinkerdNamespace,
+		Name: linkerdConfigMap, Labels: map[string]string{linkerdVersionKey: "stable-2.13.4"}}}
+
+	tests := []struct {
+		name    string
+		objects []client.Object
+		want    string
+	}{
+		{"namespace annotation", []client.Object{
+			namespace(map[string]string{linkerdVersionKey: "stable-2.14.1"}, nil), config}, "stable-2.14.1"},
+		{"namespace label", []client.Object{
+			namespace(nil, map[string]string{linkerdVersionKey: "edge-24.1.2"}), config}, "edge-24.1.2"},
+		{"linkerd-config label", []client.Object{namespace(nil, nil), config}, "stable-2.13.4"},
+		{"not published", []client.Object{namespace(nil, nil)}, linkerdVersionUnknown},
+		{"no namespace", nil, linkerdVersionUnknown},
+	}
+	for _, tt := range tests {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tt.objects...).Build()
+		r := &PodMonitorReconciler{Client: c, LinkerdMode: true}
+		if got := r.linkerdVersion(ctx); got != tt.want {
+			t.Errorf("%s: linkerdVersion = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinkerdCertificateVersionLabel(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: defaultLinkerdNamespace,
+		Annotations: map[string]string{linkerdVersionKey: "stable-2.14.1"}}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ns).Build()
+	r := &PodMonitorReconciler{Client: c, Clock: clocktesting.NewFakePassiveClock(now), LinkerdMode: true}
+	defer forgetSecretCertificates(defaultLinkerdNamespace, linkerdIssuerSecret)
+
+	cert := testsupport.CertificateExpiringIn(t, now, 30).Cert
+	r.recordCertificateExpiration(ctx, defaultLinkerdNamespace, linkerdIssuerSecret, "tls.crt", cert)
+	labels := testsupport.Labels{"namespace": defaultLinkerdNamespace, "secret_name": linkerdIssuerSecret,
+		"cert_type": "tls.crt"}
+	labels["linkerd_version"] = "stable-2.14.1"
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_linkerd_certificate_expiration_timestamp_seconds",
+		labels, float64(cert.NotAfter.Unix()))
+
+	// 升级后只保留新版本的序列
+	ns.Annotations[linkerdVersionKey] = "stable-2.15.0"
+	if err := c.Update(ctx, ns); err != nil {
+		t.Fatal(err)
+	}
+	r.recordCertificateExpiration(ctx, defaultLinkerdNamespace, linkerdIssuerSecret, "tls.crt", cert)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_linkerd_certificate_expiration_timestamp_seconds",
+		labels)
+	labels["linkerd_version"] = "stable-2.15.0"
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_linkerd_certificate_expiration_timestamp_seconds",
+		labels, float64(cert.NotAfter.Unix()))
+
+	// 其他命名空间的证书不带版本
+	r.recordCertificateExpiration(ctx, "default", "web-tls", "tls.crt", cert)
+	defer forgetSecretCertificates("default", "web-tls")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_linkerd_certificate_expiration_timestamp_seconds",
+		testsupport.Labels{"namespace": "default"})
+}
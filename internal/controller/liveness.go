@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+
+const (
+	// Values of the cause label of restarts when WatchProbeEvents is set.
+	causeLivenessKill = "liveness_kill"
+	causeCrash        = "crash"
+
+	// defaultLivenessCorrelationWindow is how long after a failed liveness
+	// probe a termination of the container is attributed to it.
+	defaultLivenessCorrelationWindow = 2 * time.Minute
+	// maxLivenessFailures bounds the number of containers with a recent
+	// liveness failure kept in memory.
+	maxLivenessFailures = 10000
+
+	// livenessEventReason is the reason of the kubelet events of failed probes.
+	livenessEventReason = "Unhealthy"
+)
+
+// ProbeEventsFieldSelector selects the events the cache needs to keep when
+// WatchProbeEvents is set, so that the other events of the cluster are not
+// cached.
+func ProbeEventsFieldSelector() fields.Selector {
+	return fields.OneTermEqualSelector("reason", livenessEventReason)
+}
+
+// livenessTracker remembers the last failed liveness probe of each container,
+// so that a restart shortly after it can be told apart from a crash.
+type livenessTracker struct {
+	window time.Duration
+
+	mu sync.Mutex
+	// key: "namespace/podName/containerName"，值为最近一次存活探针失败的时间
+	failures map[string]time.Time
+}
+
+func newLivenessTracker(window time.Duration) *livenessTracker {
+	if window <= 0 {
+		window = defaultLivenessCorrelationWindow
+	}
+	return &livenessTracker{window: window, failures: make(map[string]time.Time)}
+}
+
+// livenessFailure returns the container and time of a kubelet event reporting
+// a failed liveness probe; ok is false for any other event.
+func livenessFailure(e *corev1.Event) (container string, at time.Time, ok bool) {
+	if e.Reason != livenessEventReason || !strings.HasPrefix(e.Message, "Liveness probe failed") ||
+		e.InvolvedObject.Kind != "Pod" {
+		return "", time.Time{}, false
+	}
+	// fieldPath 形如 spec.containers{app}，边车容器为 spec.initContainers{proxy}
+	fieldPath := e.InvolvedObject.FieldPath
+	start, end := strings.Index(fieldPath, "{"), strings.LastIndex(fieldPath, "}")
+	if start < 0 || end <= start+1 {
+		return "", time.Time{}, false
+	}
+	return fieldPath[start+1 : end], eventTime(e), true
+}
+
+// eventTime returns when an event was last observed.
+func eventTime(e *corev1.Event) time.Time {
+	at := e.LastTimestamp.Time
+	if e.Series != nil && e.Series.LastObservedTime.After(at) {
+		at = e.Series.LastObservedTime.Time
+	}
+	if e.EventTime.After(at) {
+		at = e.EventTime.Time
+	}
+	if at.IsZero() {
+		at = e.FirstTimestamp.Time
+	}
+	return at
+}
+
+// observe records a failed liveness probe reported by an event and drops
+// the failures that fell out of the correlation window. When the tracker is
+// full, the oldest failure is dropped.
+func (t *livenessTracker) observe(e *corev1.Event, now time.Time) {
+	container, at, ok := livenessFailure(e)
+	if !ok {
+		return
+	}
+	key := fmt.Sprintf("%s/%s/%s", e.InvolvedObject.Namespace, e.InvolvedObject.Name, container)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if previous, ok := t.failures[key]; ok && previous.After(at) {
+		return
+	}
+	t.failures[key] = at
+
+	var oldestKey string
+	var oldest time.Time
+	for k, failedAt := range t.failures {
+		if now.Sub(failedAt) > t.window {
+			delete(t.failures, k)
+			continue
+		}
+		if oldestKey == "" || failedAt.Before(oldest) {
+			oldestKey, oldest = k, failedAt
+		}
+	}
+	if len(t.failures) > maxLivenessFailures {
+		delete(t.failures, oldestKey)
+	}
+}
+
+// cause returns liveness_kill when the container failed a liveness probe
+// within the correlation window before it terminated, crash otherwise.
+func (t *livenessTracker) cause(namespace, podName, container string, finishedAt time.Time) string {
+	key := fmt.Sprintf("%s/%s/%s", namespace, podName, container)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failedAt, ok := t.failures[key]
+	// 事件时间只精确到秒，终止时间可能略早于探针失败事件
+	if !ok || failedAt.After(finishedAt.Add(time.Second)) || finishedAt.Sub(failedAt) > t.window {
+		return causeCrash
+	}
+	return causeLivenessKill
+}
+
+// eventHandler returns a handler that only feeds kubelet events into the
+// tracker; events never enqueue reconcile requests.
+func (t *livenessTracker) eventHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if ev, ok := e.Object.(*corev1.Event); ok {
+				t.observe(ev, time.Now())
+			}
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			// 重复的探针失败会更新同一个事件的计数与时间
+			if ev, ok := e.ObjectNew.(*corev1.Event); ok {
+				t.observe(ev, time.Now())
+			}
+		},
+	}
+}
+
+// probeCause returns the cause label of a restart that was not requested
+// through restartedAt: liveness_kill or crash when probe events are
+// watched, else "".
+func (r *PodMonitorReconciler) probeCause(pod *corev1.Pod, container string, finishedAt time.Time) string {
+	if r.livenessTracker == nil {
+		return ""
+	}
+	return r.livenessTracker.cause(pod.Namespace, pod.Name, container, finishedAt)
+}
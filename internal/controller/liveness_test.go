@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func livenessEvent(namespace, pod, fieldPath, message string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod, FieldPath: fieldPath},
+		Reason:         livenessEventReason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestLivenessTrackerCause(t *testing.T) {
+	const namespace = "liveness-test"
+	now := time.Now().Truncate(time.Second)
+	tracker := newLivenessTracker(time.Minute)
+
+	tracker.observe(livenessEvent(namespace, "web", "spec.containers{app}",
+		"Liveness probe failed: HTTP probe failed with statuscode: 500", now), now)
+	// 就绪探针失败不参与关联
+	tracker.observe(livenessEvent(namespace, "web", "spec.containers{sidecar}",
+		"Readiness probe failed: connection refused", now), now)
+
+	tests := []struct {
+		name       string
+		container  string
+		finishedAt time.Time
+		want       string
+	}{
+		{"within window", "app", now.Add(30 * time.Second), causeLivenessKill},
+		{"same second", "app", now.Add(-500 * time.Millisecond), causeLivenessKill},
+		{"outside window", "app", now.Add(2 * time.Minute), causeCrash},
+		{"before failure", "app", now.Add(-time.Minute), causeCrash},
+		{"readiness failure", "sidecar", now.Add(time.Second), causeCrash},
+		{"no failure", "other", now.Add(time.Second), causeCrash},
+	}
+	for _, tt := range tests {
+		if got := tracker.cause(namespace, "web", tt.container, tt.finishedAt); got != tt.want {
+			t.Errorf("%s: cause = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLivenessTrackerBounded(t *testing.T) {
+	const namespace = "liveness-bounded-test"
+	now := time.Now()
+	tracker := newLivenessTracker(time.Minute)
+
+	tracker.observe(livenessEvent(namespace, "stale", "spec.containers{app}",
+		"Liveness probe failed", now.Add(-2*time.Minute)), now)
+	if len(tracker.failures) != 0 {
+		t.Fatalf("failure outside the window kept: %v", tracker.failures)
+	}
+
+	for i := 0; i <= maxLivenessFailures; i++ {
+		tracker.observe(livenessEvent(namespace, fmt.Sprintf("pod-%d", i), "spec.containers{app}",
+			"Liveness probe failed", now.Add(-time.Minute+time.Duration(i)*time.Millisecond)), now)
+	}
+	if len(tracker.failures) != maxLivenessFailures {
+		t.Fatalf("tracker holds %d failures, want %d", len(tracker.failures), maxLivenessFailures)
+	}
+	// 容量满时淘汰最早的失败记录
+	if _, ok := tracker.failures[namespace+"/pod-0/app"]; ok {
+		t.Error("oldest failure not evicted")
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metricOpKind int
+
+const (
+	metricOpSet metricOpKind = iota
+	metricOpInc
+	metricOpDelete
+	metricOpDeletePartial
+)
+
+// metricOp is a single pending update of a metric vector. Label values are
+// positional, which avoids building and hashing a label map per update.
+type metricOp struct {
+	kind    metricOpKind
+	gauge   *prometheus.GaugeVec
+	counter *prometheus.CounterVec
+	vec     *prometheus.MetricVec
+	values  []string
+	labels  prometheus.Labels
+	value   float64
+}
+
+// metricBatch collects the series a reconcile sets and deletes so they can be
+// applied in one step. Each batched collector exposes either none or all of
+// the batch's updates to it. The registry collects concurrently and without
+// a common lock, so a commit can land between two collectors of one scrape:
+// updates spanning several metrics are not atomic across them.
+type metricBatch struct {
+	ops []metricOp
+}
+
+// set records a gauge update.
+func (b *metricBatch) set(g *prometheus.GaugeVec, value float64, labelValues ...string) {
+	b.ops = append(b.ops, metricOp{kind: metricOpSet, gauge: g, values: labelValues, value: value})
+}
+
+// inc records a counter increment.
+func (b *metricBatch) inc(c *prometheus.CounterVec, labelValues ...string) {
+	b.ops = append(b.ops, metricOp{kind: metricOpInc, counter: c, values: labelValues})
+}
+
+// delete records the removal of one series.
+func (b *metricBatch) delete(vec *prometheus.MetricVec, labelValues ...string) {
+	b.ops = append(b.ops, metricOp{kind: metricOpDelete, vec: vec, values: labelValues})
+}
+
+// deletePartial records the removal of all series matching the labels.
+func (b *metricBatch) deletePartial(vec *prometheus.MetricVec, labels prometheus.Labels) {
+	b.ops = append(b.ops, metricOp{kind: metricOpDeletePartial, vec: vec, labels: labels})
+}
+
+func (op metricOp) apply() {
+	switch op.kind {
+	case metricOpSet:
+		op.gauge.WithLabelValues(op.values...).Set(op.value)
+	case metricOpInc:
+		op.counter.WithLabelValues(op.values...).Inc()
+	case metricOpDelete:
+		op.vec.DeleteLabelValues(op.values...)
+	case metricOpDeletePartial:
+		op.vec.DeletePartialMatch(op.labels)
+	}
+}
+
+// commitMetrics applies a batch while holding the metrics lock, so a
+// collector registered through batchedCollector never exposes a half-applied
+// batch of its own series.
+func (s *restartStateStore) commitMetrics(b *metricBatch) {
+	if len(b.ops) == 0 {
+		return
+	}
+	s.metricsMu.Lock()
+	defer s.metricsMu.Unlock()
+	for _, op := range b.ops {
+		op.apply()
+	}
+	b.ops = b.ops[:0]
+}
+
+// batchedCollector makes a scrape of the wrapped collector wait for pending
+// batch commits.
+type batchedCollector struct {
+	prometheus.Collector
+}
+
+// batched wraps a collector that is updated through metric batches.
+func batched(c prometheus.Collector) prometheus.Collector {
+	return batchedCollector{Collector: c}
+}
+
+func (c batchedCollector) Collect(ch chan<- prometheus.Metric) {
+	stateStore.metricsMu.RLock()
+	defer stateStore.metricsMu.RUnlock()
+	c.Collector.Collect(ch)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newBenchmarkPod returns a running pod with the given number of containers,
+// each with CPU requests and limits.
+func newBenchmarkPod(containers int) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "bench", Name: "bench-pod"}}
+	for i := 0; i < containers; i++ {
+		name := fmt.Sprintf("c%d", i)
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+			Name: name,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+			},
+		})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:    name,
+			Image:   "example.com/app:v1",
+			ImageID: "example.com/app@sha256:0000",
+			State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+// updatePodMetricsDirect is the per-container update path used before metric
+// batching: a label map is built and hashed for every series.
+func updatePodMetricsDirect(pod *corev1.Pod) {
+	for i, c := range pod.Spec.Containers {
+		request := c.Resources.Requests[corev1.ResourceCPU]
+		limit := c.Resources.Limits[corev1.ResourceCPU]
+		containerCPULimitRequestRatio.With(prometheus.Labels{
+			"namespace": pod.Namespace,
+			"pod":       pod.Name,
+			"container": c.Name,
+		}).Set(float64(limit.MilliValue()) / float64(request.MilliValue()))
+
+		cs := pod.Status.ContainerStatuses[i]
+		containerInfo.With(prometheus.Labels{
+			"namespace": pod.Namespace,
+			"pod":       pod.Name,
+			"container": cs.Name,
+			"image":     cs.Image,
+			"image_id":  cs.ImageID,
+		}).Set(1)
+	}
+}
+
+func updatePodMetricsBatched(pod *corev1.Pod) {
+	var batch metricBatch
+	updateCPULimitRequestRatio(&batch, pod)
+	updateContainerInfo(&batch, pod)
+	stateStore.commitMetrics(&batch)
+}
+
+func TestMetricBatchCommit(t *testing.T) {
+	pod := newBenchmarkPod(2)
+	defer cleanupContainerInfo(&metricBatch{}, pod.Namespace, pod.Name)
+
+	var batch metricBatch
+	updateCPULimitRequestRatio(&batch, pod)
+	if got := len(batch.ops); got != 2 {
+		t.Fatalf("expected 2 pending updates, got %d", got)
+	}
+	stateStore.commitMetrics(&batch)
+	if len(batch.ops) != 0 {
+		t.Fatal("expected the batch to be empty after commit")
+	}
+
+	gauge, err := containerCPULimitRequestRatio.GetMetricWithLabelValues("bench", "bench-pod", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(gauge); got != 2 {
+		t.Fatalf("expected ratio 2, got %v", got)
+	}
+
+	batch.deletePartial(containerCPULimitRequestRatio.MetricVec, prometheus.Labels{"pod": "bench-pod"})
+	stateStore.commitMetrics(&batch)
+	if n := testutil.CollectAndCount(containerCPULimitRequestRatio); n != 0 {
+		t.Fatalf("expected all series to be deleted, %d left", n)
+	}
+}
+
+func BenchmarkPodMetricsDirect(b *testing.B) {
+	pod := newBenchmarkPod(20)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		updatePodMetricsDirect(pod)
+	}
+}
+
+func BenchmarkPodMetricsBatched(b *testing.B) {
+	pod := newBenchmarkPod(20)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		updatePodMetricsBatched(pod)
+	}
+}
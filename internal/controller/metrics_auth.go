@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/apis/apiserver"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authentication/request/bearertoken"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	authenticationv1 "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Values of --metrics-auth-mode.
+const (
+	// MetricsAuthModeRBAC authenticates scrapes with a TokenReview and
+	// authorizes them with a SubjectAccessReview.
+	MetricsAuthModeRBAC = "rbac"
+	// MetricsAuthModeToken accepts a single static bearer token.
+	MetricsAuthModeToken = "token"
+	// MetricsAuthModeNone serves the metrics without authentication.
+	MetricsAuthModeNone = "none"
+)
+
+var (
+	// 认证或授权失败的抓取请求数，按返回的 HTTP 状态码区分
+	metricsAuthFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_metrics_auth_failures_total",
+			Help: "Total number of scrapes of the metrics endpoint rejected by authentication or authorization, " +
+				"by HTTP status code",
+		},
+		[]string{
+			"code", // 401、403，或授权服务出错时的 500
+		},
+	)
+)
+
+func init() {
+	registerMetrics(metricsAuthFailuresTotal)
+}
+
+// MetricsAuthOptions configures the authentication of the metrics endpoint.
+type MetricsAuthOptions struct {
+	// Mode is one of MetricsAuthModeRBAC, MetricsAuthModeToken and
+	// MetricsAuthModeNone.
+	Mode string
+	// TokenFile holds the bearer token accepted in token mode.
+	TokenFile string
+	// Resource, when set, is the resource SubjectAccessReviews check in rbac
+	// mode, as resource[.group][/subresource], e.g. services/proxy. Without
+	// it the request path is checked as a non-resource URL.
+	Resource string
+	// Namespace and Name narrow the checked resource.
+	Namespace string
+	Name      string
+}
+
+// metricsAuthResource is a parsed MetricsAuthOptions.Resource.
+type metricsAuthResource struct {
+	group, resource, subresource string
+}
+
+// parseMetricsAuthResource parses resource[.group][/subresource].
+func parseMetricsAuthResource(s string) (metricsAuthResource, error) {
+	var res metricsAuthResource
+	name, sub, _ := strings.Cut(s, "/")
+	res.resource, res.group, _ = strings.Cut(name, ".")
+	res.subresource = sub
+	if res.resource == "" || strings.Contains(sub, "/") {
+		return res, fmt.Errorf("invalid metrics auth resource %q, want resource[.group][/subresource]", s)
+	}
+	return res, nil
+}
+
+// attributes returns the authorization attributes of a scrape.
+func (o MetricsAuthOptions) attributes(res *metricsAuthResource, u user.Info, req *http.Request) authorizer.Attributes {
+	if res == nil {
+		return authorizer.AttributesRecord{User: u, Verb: strings.ToLower(req.Method), Path: req.URL.Path}
+	}
+	return authorizer.AttributesRecord{
+		User:            u,
+		Verb:            "get",
+		Namespace:       o.Namespace,
+		APIGroup:        res.group,
+		Resource:        res.resource,
+		Subresource:     res.subresource,
+		Name:            o.Name,
+		ResourceRequest: true,
+	}
+}
+
+// webhookRetryBackoff is the retry backoff of TokenReviews and
+// SubjectAccessReviews, the same as controller-runtime's metrics filter.
+var webhookRetryBackoff = wait.Backoff{Duration: 500 * time.Millisecond, Factor: 1.5, Jitter: 0.2, Steps: 5}
+
+// NewMetricsAuthFilterProvider returns the FilterProvider of the metrics
+// server for the given options, or nil in none mode. Scrapes rejected with
+// 401 or 403 are counted in pod_monitor_metrics_auth_failures_total.
+func NewMetricsAuthFilterProvider(opts MetricsAuthOptions) (
+	func(*rest.Config, *http.Client) (metricsserver.Filter, error), error) {
+	var res *metricsAuthResource
+	if opts.Resource != "" {
+		parsed, err := parseMetricsAuthResource(opts.Resource)
+		if err != nil {
+			return nil, err
+		}
+		res = &parsed
+	}
+
+	switch opts.Mode {
+	case MetricsAuthModeNone:
+		return nil, nil
+	case MetricsAuthModeToken:
+		data, err := os.ReadFile(opts.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading metrics auth token: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return nil, fmt.Errorf("metrics auth token file %s is empty", opts.TokenFile)
+		}
+		authn := bearertoken.New(staticTokenAuthenticator(token))
+		return func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+			return metricsAuthFilter(authn, authorizerfactory.NewAlwaysAllowAuthorizer(), opts, res), nil
+		}, nil
+	case MetricsAuthModeRBAC:
+		return func(config *rest.Config, httpClient *http.Client) (metricsserver.Filter, error) {
+			authn, authz, err := newDelegatingMetricsAuth(config, httpClient)
+			if err != nil {
+				return nil, err
+			}
+			return metricsAuthFilter(authn, authz, opts, res), nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown metrics auth mode %q, want %s, %s or %s", opts.Mode,
+		MetricsAuthModeRBAC, MetricsAuthModeToken, MetricsAuthModeNone)
+}
+
+// staticTokenAuthenticator accepts a single bearer token.
+func staticTokenAuthenticator(token string) authenticator.Token {
+	return authenticator.TokenFunc(func(_ context.Context, got string) (*authenticator.Response, bool, error) {
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return nil, false, nil
+		}
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "metrics-token"}}, true, nil
+	})
+}
+
+// newDelegatingMetricsAuth returns an authenticator and an authorizer that
+// delegate to TokenReviews and SubjectAccessReviews, with short caches so
+// that every scrape does not reach the API server.
+func newDelegatingMetricsAuth(config *rest.Config, httpClient *http.Client) (
+	authenticator.Request, authorizer.Authorizer, error) {
+	authenticationClient, err := authenticationv1.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	authorizationClient, err := authorizationv1.NewForConfigAndClient(config, httpClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	authn, _, err := authenticatorfactory.DelegatingAuthenticatorConfig{
+		Anonymous:                &apiserver.AnonymousAuthConfig{Enabled: false},
+		CacheTTL:                 time.Minute,
+		TokenAccessReviewClient:  authenticationClient,
+		TokenAccessReviewTimeout: 10 * time.Second,
+		WebhookRetryBackoff:      &webhookRetryBackoff,
+	}.New()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create authenticator: %w", err)
+	}
+	authz, err := authorizerfactory.DelegatingAuthorizerConfig{
+		SubjectAccessReviewClient: authorizationClient,
+		AllowCacheTTL:             5 * time.Minute,
+		DenyCacheTTL:              30 * time.Second,
+		WebhookRetryBackoff:       &webhookRetryBackoff,
+	}.New()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create authorizer: %w", err)
+	}
+	return authn, authz, nil
+}
+
+// metricsAuthFilter authenticates and authorizes every scrape before
+// serving it, answering 401 to unauthenticated and 403 to unauthorized
+// requests, and 500 when the authorizer fails.
+func metricsAuthFilter(authn authenticator.Request, authz authorizer.Authorizer, opts MetricsAuthOptions,
+	res *metricsAuthResource) metricsserver.Filter {
+	return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			reject := func(code int, msg string) {
+				metricsAuthFailuresTotal.WithLabelValues(strconv.Itoa(code)).Inc()
+				http.Error(w, msg, code)
+			}
+
+			// 与 API server 一致，认证出错（包括无效 token）按 401 处理
+			resp, ok, err := authn.AuthenticateRequest(req)
+			if err != nil || !ok {
+				if err != nil {
+					log.V(1).Info("Metrics scrape authentication failed", "error", err.Error())
+				}
+				reject(http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			attributes := opts.attributes(res, resp.User, req)
+			decision, reason, err := authz.Authorize(req.Context(), attributes)
+			if err != nil {
+				log.Error(err, "Metrics scrape authorization failed", "user", resp.User.GetName())
+				reject(http.StatusInternalServerError, "Authorization failed")
+				return
+			}
+			if decision != authorizer.DecisionAllow {
+				log.V(1).Info("Metrics scrape denied", "user", resp.User.GetName(), "reason", reason)
+				reject(http.StatusForbidden, fmt.Sprintf("Authorization denied for user %s", resp.User.GetName()))
+				return
+			}
+			handler.ServeHTTP(w, req)
+		}), nil
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+// fakeMetricsAuthn authenticates "Bearer good", "Bearer denied" and
+// "Bearer broken", and fails on "Bearer invalid".
+var fakeMetricsAuthn = authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+	switch req.Header.Get("Authorization") {
+	case "Bearer good":
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "prometheus"}}, true, nil
+	case "Bearer denied":
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "intruder"}}, true, nil
+	case "Bearer broken":
+		return &authenticator.Response{User: &user.DefaultInfo{Name: "broken"}}, true, nil
+	case "Bearer invalid":
+		return nil, false, errors.New("invalid bearer token")
+	}
+	return nil, false, nil
+})
+
+func serveMetricsScrape(t *testing.T, filter http.Handler, token string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	filter.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestMetricsAuthFilter(t *testing.T) {
+	metricsAuthFailuresTotal.Reset()
+	defer metricsAuthFailuresTotal.Reset()
+
+	var got authorizer.Attributes
+	authz := authorizer.AuthorizerFunc(func(_ context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		got = a
+		if a.GetUser().GetName() == "broken" {
+			return authorizer.DecisionNoOpinion, "", errors.New("subject access review failed")
+		}
+		if a.GetUser().GetName() != "prometheus" {
+			return authorizer.DecisionNoOpinion, "no binding", nil
+		}
+		return authorizer.DecisionAllow, "", nil
+	})
+	opts := MetricsAuthOptions{Mode: MetricsAuthModeRBAC, Namespace: "monitoring", Name: "pod-monitor"}
+	res, err := parseMetricsAuthResource("services/proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	filter, err := metricsAuthFilter(fakeMetricsAuthn, authz, opts, &res)(logr.Discard(), served)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for token, want := range map[string]int{
+		"": http.StatusUnauthorized, "invalid": http.StatusUnauthorized, "denied": http.StatusForbidden,
+		"broken": http.StatusInternalServerError,
+	} {
+		if code := serveMetricsScrape(t, filter, token); code != want {
+			t.Errorf("token %q: got status %d, want %d", token, code, want)
+		}
+	}
+	if code := serveMetricsScrape(t, filter, "good"); code != http.StatusOK {
+		t.Errorf("authorized scrape: got status %d", code)
+	}
+	if !got.IsResourceRequest() || got.GetVerb() != "get" || got.GetResource() != "services" ||
+		got.GetSubresource() != "proxy" || got.GetNamespace() != "monitoring" || got.GetName() != "pod-monitor" {
+		t.Errorf("unexpected authorization attributes %+v", got)
+	}
+
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_metrics_auth_failures_total",
+		testsupport.Labels{"code": "401"}, 2)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_metrics_auth_failures_total",
+		testsupport.Labels{"code": "403"}, 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_metrics_auth_failures_total",
+		testsupport.Labels{"code": "500"}, 1)
+
+	// 未配置资源时按非资源 URL 授权
+	filter, _ = metricsAuthFilter(fakeMetricsAuthn, authz, opts, nil)(logr.Discard(), served)
+	serveMetricsScrape(t, filter, "good")
+	if got.IsResourceRequest() || got.GetPath() != "/metrics" || got.GetVerb() != "get" {
+		t.Errorf("unexpected non-resource attributes %+v", got)
+	}
+}
+
+func TestMetricsAuthTokenMode(t *testing.T) {
+	metricsAuthFailuresTotal.Reset()
+	defer metricsAuthFailuresTotal.Reset()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewMetricsAuthFilterProvider(MetricsAuthOptions{Mode: MetricsAuthModeToken, TokenFile: tokenFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newFilter, err := provider(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	filter, err := newFilter(logr.Discard(), served)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if code := serveMetricsScrape(t, filter, "s3cret"); code != http.StatusOK {
+		t.Errorf("valid token: got status %d", code)
+	}
+	if code := serveMetricsScrape(t, filter, "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("invalid token: got status %d", code)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_metrics_auth_failures_total",
+		testsupport.Labels{"code": "401"}, 1)
+
+	if provider, err := NewMetricsAuthFilterProvider(MetricsAuthOptions{Mode: MetricsAuthModeNone}); err != nil ||
+		provider != nil {
+		t.Errorf("none mode: got provider %v, error %v", provider != nil, err)
+	}
+	if _, err := NewMetricsAuthFilterProvider(MetricsAuthOptions{Mode: "basic"}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+	if _, err := NewMetricsAuthFilterProvider(MetricsAuthOptions{Mode: MetricsAuthModeRBAC, Resource: "/proxy"}); err == nil {
+		t.Error("expected an error for an invalid resource")
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// collectors lists every collector of the package in registration order, so
+// that they can also be registered with a registry other than the one of
+// controller-runtime.
+var collectors []prometheus.Collector
+
+// registerMetrics registers collectors with the controller-runtime registry.
+// It is only called from init functions.
+func registerMetrics(cs ...prometheus.Collector) {
+	metrics.Registry.MustRegister(cs...)
+	collectors = append(collectors, cs...)
+}
+
+// RegisterMetrics registers the metrics of the package with reg, e.g. an
+// isolated registry in tests or the registry of a manager that does not
+// serve controller-runtime's. The metric values are shared with the
+// controller-runtime registry.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var (
+	// 影响调度的节点条件：1 表示压力或未就绪，0 表示正常
+	nodeConditionStatus = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_node_condition_status",
+			Help: "Whether a node condition affecting scheduling is abnormal (1: not Ready or under pressure) or normal (0)",
+		},
+		[]string{
+			"node",           // 节点名称
+			"condition_type", // Ready、MemoryPressure、DiskPressure、PIDPressure 或 NetworkUnavailable
+		},
+	)
+)
+
+func init() {
+	registerMetrics(nodeConditionStatus)
+}
+
+// schedulingConditions maps the node conditions exported by
+// pod_monitor_node_condition_status to the status that is normal for them.
+var schedulingConditions = map[corev1.NodeConditionType]corev1.ConditionStatus{
+	corev1.NodeReady:              corev1.ConditionTrue,
+	corev1.NodeMemoryPressure:     corev1.ConditionFalse,
+	corev1.NodeDiskPressure:       corev1.ConditionFalse,
+	corev1.NodePIDPressure:        corev1.ConditionFalse,
+	corev1.NodeNetworkUnavailable: corev1.ConditionFalse,
+}
+
+// nodeConditionsPredicate passes node updates only when the type or status of
+// a condition changed; heartbeats only bump the condition timestamps.
+func nodeConditionsPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, okOld := e.ObjectOld.(*corev1.Node)
+			newNode, okNew := e.ObjectNew.(*corev1.Node)
+			if !okOld || !okNew {
+				return false
+			}
+			return !equality.Semantic.DeepEqual(conditionStatuses(oldNode), conditionStatuses(newNode))
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// conditionStatuses returns the status of every condition of a node.
+func conditionStatuses(node *corev1.Node) map[corev1.NodeConditionType]corev1.ConditionStatus {
+	statuses := make(map[corev1.NodeConditionType]corev1.ConditionStatus, len(node.Status.Conditions))
+	for _, cond := range node.Status.Conditions {
+		statuses[cond.Type] = cond.Status
+	}
+	return statuses
+}
+
+// reconcileNode exports the scheduling-relevant conditions of a node.
+func (r *PodMonitorReconciler) reconcileNode(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var node corev1.Node
+	if err := r.Get(ctx, req.NamespacedName, &node); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			logf.FromContext(ctx).Error(err, "unable to fetch Node")
+			return ctrl.Result{}, err
+		}
+		// 节点已删除，清理其条件指标
+		nodeConditionStatus.DeletePartialMatch(prometheus.Labels{"node": req.Name})
+		setNodeMemoryPressure(req.Name, false)
+		return ctrl.Result{}, nil
+	}
+
+	for _, cond := range node.Status.Conditions {
+		normal, ok := schedulingConditions[cond.Type]
+		if !ok {
+			continue
+		}
+		value := 0.0
+		if cond.Status != normal {
+			value = 1
+		}
+		nodeConditionStatus.WithLabelValues(node.Name, string(cond.Type)).Set(value)
+		if cond.Type == corev1.NodeMemoryPressure {
+			// 供重启原因推断使用
+			setNodeMemoryPressure(node.Name, cond.Status == corev1.ConditionTrue)
+		}
+	}
+	return ctrl.Result{}, nil
+}
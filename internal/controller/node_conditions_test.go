@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestReconcileNodeExportsConditions(t *testing.T) {
+	const nodeName = "node-conditions-test"
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+			{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionTrue},
+			{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse},
+			{Type: "KernelDeadlock", Status: corev1.ConditionTrue},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(node).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: nodeName}}
+	ctx := context.Background()
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	status := func(condition corev1.NodeConditionType) float64 {
+		return testutil.ToFloat64(nodeConditionStatus.WithLabelValues(nodeName, string(condition)))
+	}
+	if status(corev1.NodeReady) != 0 || status(corev1.NodeMemoryPressure) != 1 || status(corev1.NodeDiskPressure) != 0 {
+		t.Fatalf("unexpected condition values: Ready=%v MemoryPressure=%v DiskPressure=%v",
+			status(corev1.NodeReady), status(corev1.NodeMemoryPressure), status(corev1.NodeDiskPressure))
+	}
+
+	// 仅心跳时间变化的更新被过滤
+	heartbeat := node.DeepCopy()
+	heartbeat.Status.Conditions[0].LastHeartbeatTime = metav1.NewTime(time.Now())
+	if nodeConditionsPredicate().Update(event.UpdateEvent{ObjectOld: node, ObjectNew: heartbeat}) {
+		t.Error("expected a heartbeat-only update to be filtered")
+	}
+	changed := node.DeepCopy()
+	changed.Status.Conditions[1].Status = corev1.ConditionFalse
+	if !nodeConditionsPredicate().Update(event.UpdateEvent{ObjectOld: node, ObjectNew: changed}) {
+		t.Error("expected a condition status change to pass")
+	}
+
+	if err := c.Delete(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(nodeConditionStatus); n != 0 {
+		t.Fatalf("expected the series of a deleted node to be removed, %d left", n)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Values of the node_ready_at_restart label of the restart counter.
+const (
+	nodeReadyAtRestartTrue    = "true"
+	nodeReadyAtRestartFalse   = "false"
+	nodeReadyAtRestartUnknown = "unknown" // 未启用 --watch-nodes
+)
+
+var (
+	// 节点的 Ready 状态：1 为 Ready，0 为 NotReady 或 Unknown
+	nodeReady = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_node_ready",
+			Help: "Whether the node's Ready condition is True (1) or not (0)",
+		},
+		[]string{
+			"node", // 节点名称
+		},
+	)
+
+	// 节点从 Ready 变为 NotReady 的次数
+	nodeNotReadyTransitionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_node_notready_transitions_total",
+			Help: "Total number of transitions of the node's Ready condition from True to False or Unknown",
+		},
+		[]string{
+			"node", // 节点名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(nodeReady)
+	registerMetrics(nodeNotReadyTransitionsTotal)
+}
+
+// nodeReadyState 记录节点最近一次 NotReady 的区间
+type nodeReadyState struct {
+	ready        bool
+	notReadyAt   time.Time // 最近一次变为 NotReady 的时间
+	readyAgainAt time.Time // 之后恢复 Ready 的时间；仍为 NotReady 时为零值
+}
+
+// nodeReadyTracker follows the Ready condition of nodes so that restarts on
+// a flapping node can be grouped by node.
+type nodeReadyTracker struct {
+	window time.Duration
+
+	mu    sync.RWMutex
+	nodes map[string]nodeReadyState
+}
+
+func newNodeReadyTracker(window time.Duration) *nodeReadyTracker {
+	if window <= 0 {
+		window = defaultDrainCorrelationWindow
+	}
+	return &nodeReadyTracker{window: window, nodes: make(map[string]nodeReadyState)}
+}
+
+// readyCondition returns whether the node is Ready and since when.
+func readyCondition(node *corev1.Node) (bool, time.Time) {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue, cond.LastTransitionTime.Time
+		}
+	}
+	return false, time.Time{}
+}
+
+// observe updates the Ready state of a node from its latest status.
+func (t *nodeReadyTracker) observe(node *corev1.Node, now time.Time) {
+	ready, since := readyCondition(node)
+	if since.IsZero() {
+		since = now
+	}
+	if ready {
+		nodeReady.WithLabelValues(node.Name).Set(1)
+	} else {
+		nodeReady.WithLabelValues(node.Name).Set(0)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, known := t.nodes[node.Name]
+	switch {
+	case !known:
+		// 首次观察到的节点不算一次转换
+		state = nodeReadyState{ready: ready}
+		if !ready {
+			state.notReadyAt = since
+		}
+	case state.ready && !ready:
+		nodeNotReadyTransitionsTotal.WithLabelValues(node.Name).Inc()
+		state = nodeReadyState{ready: false, notReadyAt: since}
+	case !state.ready && ready:
+		state.ready = true
+		state.readyAgainAt = since
+	}
+	t.nodes[node.Name] = state
+}
+
+// forget drops the state and series of a deleted node.
+func (t *nodeReadyTracker) forget(nodeName string) {
+	t.mu.Lock()
+	delete(t.nodes, nodeName)
+	t.mu.Unlock()
+	nodeReady.DeleteLabelValues(nodeName)
+	nodeNotReadyTransitionsTotal.DeleteLabelValues(nodeName)
+}
+
+// notReadyAround reports whether the node was NotReady at the given time or
+// within the correlation window before it.
+func (t *nodeReadyTracker) notReadyAround(nodeName string, at time.Time) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	state, ok := t.nodes[nodeName]
+	if !ok || state.notReadyAt.IsZero() || at.Before(state.notReadyAt) {
+		return false
+	}
+	return state.readyAgainAt.IsZero() || at.Sub(state.readyAgainAt) <= t.window
+}
+
+// eventHandler returns a handler that only feeds node events into the tracker.
+func (t *nodeReadyTracker) eventHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if node, ok := e.Object.(*corev1.Node); ok {
+				t.observe(node, time.Now())
+			}
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if node, ok := e.ObjectNew.(*corev1.Node); ok {
+				t.observe(node, time.Now())
+			}
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			t.forget(e.Object.GetName())
+		},
+	}
+}
+
+// nodeReadyAtRestart returns the node_ready_at_restart label of a restart that
+// finished at the given time.
+func (r *PodMonitorReconciler) nodeReadyAtRestart(pod *corev1.Pod, finishedAt time.Time) string {
+	if r.readyTracker == nil || pod.Spec.NodeName == "" {
+		return nodeReadyAtRestartUnknown
+	}
+	if r.readyTracker.notReadyAround(pod.Spec.NodeName, finishedAt) {
+		return nodeReadyAtRestartFalse
+	}
+	return nodeReadyAtRestartTrue
+}
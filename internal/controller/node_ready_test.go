@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeReadyTrackerCorrelatesRestarts(t *testing.T) {
+	const nodeName = "node-ready-test"
+	tracker := newNodeReadyTracker(10 * time.Minute)
+	defer tracker.forget(nodeName)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	nodeAt := func(status corev1.ConditionStatus, at time.Time) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             status,
+				LastTransitionTime: metav1.NewTime(at),
+			}}},
+		}
+	}
+	r := &PodMonitorReconciler{readyTracker: tracker}
+	pod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: nodeName}}
+
+	tracker.observe(nodeAt(corev1.ConditionTrue, start), start)
+	if got := r.nodeReadyAtRestart(pod, start.Add(time.Minute)); got != nodeReadyAtRestartTrue {
+		t.Fatalf("expected a restart on a Ready node to be labeled true, got %q", got)
+	}
+
+	notReadyAt := start.Add(5 * time.Minute)
+	tracker.observe(nodeAt(corev1.ConditionUnknown, notReadyAt), notReadyAt)
+	if got := testutil.ToFloat64(nodeReady.WithLabelValues(nodeName)); got != 0 {
+		t.Fatalf("expected the node to be reported NotReady, got %v", got)
+	}
+	if got := testutil.ToFloat64(nodeNotReadyTransitionsTotal.WithLabelValues(nodeName)); got != 1 {
+		t.Fatalf("expected 1 NotReady transition, got %v", got)
+	}
+
+	readyAt := start.Add(8 * time.Minute)
+	tracker.observe(nodeAt(corev1.ConditionTrue, readyAt), readyAt)
+	// NotReady 期间及恢复后关联窗口内的重启都被标记
+	for _, at := range []time.Time{notReadyAt.Add(time.Minute), readyAt.Add(5 * time.Minute)} {
+		if got := r.nodeReadyAtRestart(pod, at); got != nodeReadyAtRestartFalse {
+			t.Errorf("expected a restart at %v to be labeled false, got %q", at, got)
+		}
+	}
+	if got := r.nodeReadyAtRestart(pod, readyAt.Add(11*time.Minute)); got != nodeReadyAtRestartTrue {
+		t.Errorf("expected a restart after the window to be labeled true, got %q", got)
+	}
+
+	// 未启用 --watch-nodes 时标签为 unknown
+	if got := (&PodMonitorReconciler{}).nodeReadyAtRestart(pod, readyAt); got != nodeReadyAtRestartUnknown {
+		t.Errorf("expected unknown without node tracking, got %q", got)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultDrainCorrelationWindow is how long after a node cordon a container
+// restart on that node is still considered part of a planned drain.
+const defaultDrainCorrelationWindow = 10 * time.Minute
+
+// nodeCordonState 记录节点最近一次被 cordon / uncordon 的时间
+type nodeCordonState struct {
+	cordonedAt   time.Time
+	uncordonedAt time.Time // 节点仍处于 cordon 状态时为零值
+}
+
+// nodeDrainTracker keeps a small in-memory cache of recently cordoned nodes so
+// that restarts caused by planned drains can be told apart from real crashes.
+type nodeDrainTracker struct {
+	window time.Duration
+
+	mu       sync.RWMutex
+	cordoned map[string]nodeCordonState
+}
+
+func newNodeDrainTracker(window time.Duration) *nodeDrainTracker {
+	if window <= 0 {
+		window = defaultDrainCorrelationWindow
+	}
+	return &nodeDrainTracker{
+		window:   window,
+		cordoned: make(map[string]nodeCordonState),
+	}
+}
+
+// observe updates the cordon state of a node from its latest spec.
+func (t *nodeDrainTracker) observe(node *corev1.Node, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, known := t.cordoned[node.Name]
+	switch {
+	case node.Spec.Unschedulable && (!known || !state.uncordonedAt.IsZero()):
+		// 新的 cordon：记录开始时间
+		t.cordoned[node.Name] = nodeCordonState{cordonedAt: now}
+	case !node.Spec.Unschedulable && known && state.uncordonedAt.IsZero():
+		// 节点恢复调度，保留记录直到关联窗口结束
+		state.uncordonedAt = now
+		t.cordoned[node.Name] = state
+	}
+
+	// 顺带清理已经超出关联窗口的记录，保证缓存足够小
+	for name, s := range t.cordoned {
+		if !s.uncordonedAt.IsZero() && now.Sub(s.uncordonedAt) > t.window {
+			delete(t.cordoned, name)
+		}
+	}
+}
+
+// observeExisting records a node seen for the first time, e.g. when the
+// informer lists the nodes at startup. A cordon that began before the node was
+// seen is dated by the TimeAdded of the unschedulable taint if set, and is
+// otherwise left undated so that restarts on the node are not counted as
+// planned.
+func (t *nodeDrainTracker) observeExisting(node *corev1.Node, now time.Time) {
+	if !node.Spec.Unschedulable {
+		t.observe(node, now)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, known := t.cordoned[node.Name]; !known {
+		t.cordoned[node.Name] = nodeCordonState{cordonedAt: unschedulableSince(node)}
+	}
+}
+
+// unschedulableSince returns when the unschedulable taint was added to the
+// node, or the zero time when the taint carries no time.
+func unschedulableSince(node *corev1.Node) time.Time {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable && taint.TimeAdded != nil {
+			return taint.TimeAdded.Time
+		}
+	}
+	return time.Time{}
+}
+
+// forget drops any state kept for a deleted node.
+func (t *nodeDrainTracker) forget(nodeName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.cordoned, nodeName)
+}
+
+// recentlyCordoned reports whether the node was cordoned no more than the
+// correlation window before the given time.
+func (t *nodeDrainTracker) recentlyCordoned(nodeName string, at time.Time) bool {
+	if nodeName == "" {
+		return false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	state, ok := t.cordoned[nodeName]
+	if !ok || state.cordonedAt.IsZero() {
+		// cordon 开始时间未知（启动时已处于 cordon 状态）时不视为计划内
+		return false
+	}
+	return !at.Before(state.cordonedAt) && at.Sub(state.cordonedAt) <= t.window
+}
+
+// eventHandler returns a handler that only feeds node events into the tracker;
+// node events never enqueue reconcile requests.
+func (t *nodeDrainTracker) eventHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			// 启动时 informer 为已有节点发出 Create 事件，此时 cordon 可能早已开始
+			if node, ok := e.Object.(*corev1.Node); ok {
+				t.observeExisting(node, time.Now())
+			}
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if node, ok := e.ObjectNew.(*corev1.Node); ok {
+				t.observe(node, time.Now())
+			}
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			t.forget(e.Object.GetName())
+		},
+	}
+}
+
+// isPlannedDisruption reports whether the pod carries a DisruptionTarget
+// condition set by the taint manager or the eviction API (kubectl drain).
+func isPlannedDisruption(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.DisruptionTarget || cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Reason {
+		case "DeletionByTaintManager", "EvictionByEvictionAPI":
+			return true
+		}
+	}
+	return false
+}
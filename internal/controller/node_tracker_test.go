@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestNodeDrainTrackerWindow(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newNodeDrainTracker(10 * time.Minute)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	tracker.observe(node, now)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before the cordon", now.Add(-time.Minute), false},
+		{"at the cordon", now, true},
+		{"within the window", now.Add(9 * time.Minute), true},
+		{"after the window", now.Add(11 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		if got := tracker.recentlyCordoned("worker-1", tt.at); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+	if tracker.recentlyCordoned("worker-2", now) || tracker.recentlyCordoned("", now) {
+		t.Error("expected other nodes and unscheduled pods not to be cordoned")
+	}
+
+	// 重复的 cordon 更新不重置开始时间
+	tracker.observe(node, now.Add(5*time.Minute))
+	if tracker.recentlyCordoned("worker-1", now.Add(12*time.Minute)) {
+		t.Error("expected the window to start at the first cordon")
+	}
+
+	// uncordon 后记录保留到窗口结束，随后在下一次观察时清理
+	node.Spec.Unschedulable = false
+	tracker.observe(node, now.Add(15*time.Minute))
+	tracker.observe(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-2"}}, now.Add(26*time.Minute))
+	if _, ok := tracker.cordoned["worker-1"]; ok {
+		t.Error("expected the uncordoned node to be dropped after the window")
+	}
+
+	// 新的 cordon 重新开始窗口；删除节点清除状态
+	node.Spec.Unschedulable = true
+	tracker.observe(node, now.Add(30*time.Minute))
+	if !tracker.recentlyCordoned("worker-1", now.Add(31*time.Minute)) {
+		t.Error("expected a new cordon to start a new window")
+	}
+	tracker.forget("worker-1")
+	if tracker.recentlyCordoned("worker-1", now.Add(31*time.Minute)) {
+		t.Error("expected a deleted node to be forgotten")
+	}
+}
+
+func TestPlannedRestartDuringDrain(t *testing.T) {
+	const namespace = "node-drain-test"
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	recorder := record.NewFakeRecorder(20)
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder,
+		SuppressPlannedRestartEvents: true, drainTracker: newNodeDrainTracker(0)}
+	finishedAt := time.Now().Add(-time.Minute)
+	r.drainTracker.observe(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "drained"},
+		Spec: corev1.NodeSpec{Unschedulable: true}}, finishedAt.Add(-5*time.Minute))
+
+	restart := func(name, node string) {
+		t.Helper()
+		pod := testsupport.NewPod(namespace, name).WithNode(node).
+			WithContainerStatus(testsupport.TerminatedContainerStatus("app", 1, "Error", 1, finishedAt)).Build()
+		if err := c.Create(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		if _, err := r.reconcilePod(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			_ = c.Delete(ctx, pod)
+			_, _ = r.reconcilePod(ctx, req)
+		})
+	}
+	warnings := func() int {
+		var n int
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, " "+EventReasonContainerRestarted+" ") {
+					n++
+				}
+			default:
+				return n
+			}
+		}
+	}
+
+	// 被 cordon 的节点上的重启标记为计划内，且不发出 Warning 事件
+	restart("on-drained", "drained")
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total",
+		testsupport.Labels{"namespace": namespace, "pod": "on-drained", "planned": "true"}, 1)
+	if n := warnings(); n != 0 {
+		t.Errorf("expected the planned restart not to emit a warning, got %d", n)
+	}
+
+	// 其他节点上的重启照常告警
+	restart("elsewhere", "healthy")
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total",
+		testsupport.Labels{"namespace": namespace, "pod": "elsewhere", "planned": "false"}, 1)
+	if n := warnings(); n != 1 {
+		t.Errorf("expected the unplanned restart to emit one warning, got %d", n)
+	}
+}
+
+func TestNodeDrainTrackerCordonedAtStartup(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newNodeDrainTracker(10 * time.Minute)
+	h := tracker.eventHandler()
+	cordoned := func(name string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.NodeSpec{Unschedulable: true, Taints: taints}}
+	}
+
+	// 启动时已处于 cordon 状态的节点：开始时间未知，不视为计划内
+	undated := cordoned("undated")
+	h.Create(context.Background(), event.CreateEvent{Object: undated}, nil)
+	if tracker.recentlyCordoned("undated", time.Now()) {
+		t.Error("expected a cordon of unknown age not to be planned")
+	}
+	// 后续的更新不会把观察时间当作 cordon 时间
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: undated, ObjectNew: undated}, nil)
+	if tracker.recentlyCordoned("undated", time.Now()) {
+		t.Error("expected an update of an undated cordon not to start a window")
+	}
+
+	// unschedulable 污点带有时间时按其计算窗口
+	tracker.observeExisting(cordoned("dated", corev1.Taint{Key: corev1.TaintNodeUnschedulable,
+		Effect: corev1.TaintEffectNoSchedule, TimeAdded: ptr.To(metav1.NewTime(now))}), now.Add(time.Hour))
+	if !tracker.recentlyCordoned("dated", now.Add(5*time.Minute)) {
+		t.Error("expected the taint time to start the window")
+	}
+	if tracker.recentlyCordoned("dated", now.Add(11*time.Minute)) {
+		t.Error("expected the window to end relative to the taint time")
+	}
+
+	// uncordon 后的新 cordon 正常开始窗口
+	schedulable := undated.DeepCopy()
+	schedulable.Spec.Unschedulable = false
+	tracker.observe(schedulable, now)
+	tracker.observe(undated, now.Add(time.Minute))
+	if !tracker.recentlyCordoned("undated", now.Add(2*time.Minute)) {
+		t.Error("expected a cordon observed later to start a window")
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+)
+
+// objectDegradedAfter is the number of consecutive failed reconciles after
+// which an object is reported as degraded.
+const objectDegradedAfter = 3
+
+// Values of the error_class label of pod_monitor_object_errors_total.
+const (
+	errorClassForbidden          = "forbidden"
+	errorClassNotFoundPersistent = "not_found_persistent"
+	errorClassParseError         = "parse_error"
+	errorClassTimeout            = "timeout"
+	errorClassOther              = "other"
+)
+
+var (
+	// 单个对象 reconcile 失败的次数，按错误类别区分；对象恢复或删除后清除
+	objectErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_object_errors_total",
+			Help: "Total number of failed reconciles of an object, by error class. " +
+				"Cleared when the object reconciles successfully again or is deleted.",
+		},
+		[]string{
+			"kind",        // pod 或 secret
+			"namespace",   // 对象所在命名空间
+			"name",        // 对象名称
+			"error_class", // forbidden、not_found_persistent、parse_error、timeout 或 other
+		},
+	)
+
+	// 对象最近连续多次 reconcile 失败时为 1
+	objectDegraded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_object_degraded",
+			Help: "Set to 1 while the last reconciles of an object have all failed",
+		},
+		[]string{
+			"kind",      // pod 或 secret
+			"namespace", // 对象所在命名空间
+			"name",      // 对象名称
+		},
+	)
+
+	// 每个对象连续失败的次数
+	// key: "kind/namespace/name"
+	objectFailureStreaks = make(map[string]int)
+	objectFailuresMutex  sync.Mutex
+)
+
+func init() {
+	registerMetrics(objectErrorsTotal)
+	registerMetrics(objectDegraded)
+}
+
+// classifyObjectError maps a reconcile error to a bounded error class.
+func classifyObjectError(err error) string {
+	var certErr x509.CertificateInvalidError
+	switch {
+	case apierrors.IsForbidden(err):
+		return errorClassForbidden
+	case apierrors.IsNotFound(err):
+		return errorClassNotFoundPersistent
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return errorClassTimeout
+	case errors.Is(err, certparse.ErrNotCertificateData), errors.As(err, &certErr),
+		runtime.IsNotRegisteredError(err), runtime.IsMissingKind(err), runtime.IsMissingVersion(err):
+		return errorClassParseError
+	default:
+		return errorClassOther
+	}
+}
+
+// observeObjectResult counts a failed reconcile of an object, marking it
+// degraded after objectDegradedAfter consecutive failures, and clears its
+// series once a reconcile succeeds (which includes the reconcile of its
+// deletion).
+func observeObjectResult(kind string, key types.NamespacedName, err error) {
+	streakKey := kind + "/" + key.Namespace + "/" + key.Name
+
+	objectFailuresMutex.Lock()
+	defer objectFailuresMutex.Unlock()
+
+	if err == nil {
+		if _, failing := objectFailureStreaks[streakKey]; failing {
+			delete(objectFailureStreaks, streakKey)
+			labels := prometheus.Labels{"kind": kind, "namespace": key.Namespace, "name": key.Name}
+			objectErrorsTotal.DeletePartialMatch(labels)
+			objectDegraded.Delete(labels)
+		}
+		return
+	}
+
+	objectErrorsTotal.WithLabelValues(kind, key.Namespace, key.Name, classifyObjectError(err)).Inc()
+	objectFailureStreaks[streakKey]++
+	if objectFailureStreaks[streakKey] >= objectDegradedAfter {
+		objectDegraded.WithLabelValues(kind, key.Namespace, key.Name).Set(1)
+	}
+}
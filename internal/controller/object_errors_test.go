@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+)
+
+func TestClassifyObjectError(t *testing.T) {
+	secrets := schema.GroupResource{Resource: "secrets"}
+	for err, want := range map[error]string{
+		apierrors.NewForbidden(secrets, "tls", errors.New("denied")):       errorClassForbidden,
+		apierrors.NewNotFound(secrets, "tls"):                              errorClassNotFoundPersistent,
+		apierrors.NewServerTimeout(secrets, "get", 1):                      errorClassTimeout,
+		fmt.Errorf("waiting: %w", context.DeadlineExceeded):                errorClassTimeout,
+		fmt.Errorf("parsing tls.crt: %w", certparse.ErrNotCertificateData): errorClassParseError,
+		errors.New("something else"):                                       errorClassOther,
+		apierrors.NewConflict(secrets, "tls", errors.New("conflicting")):   errorClassOther,
+	} {
+		if got := classifyObjectError(err); got != want {
+			t.Errorf("classifyObjectError(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
+
+func TestObjectErrorsClearedOnSuccess(t *testing.T) {
+	key := types.NamespacedName{Namespace: "object-errors-test", Name: "tls"}
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "tls", errors.New("denied"))
+	degraded := func() int {
+		return testutil.CollectAndCount(objectDegraded)
+	}
+
+	for i := 1; i < objectDegradedAfter; i++ {
+		observeObjectResult(reconcileControllerSecret, key, forbidden)
+	}
+	if n := degraded(); n != 0 {
+		t.Fatalf("expected no degraded object before %d failures, got %d", objectDegradedAfter, n)
+	}
+	observeObjectResult(reconcileControllerSecret, key, forbidden)
+	if n := degraded(); n != 1 {
+		t.Fatalf("expected the object to be degraded, got %d series", n)
+	}
+	got := testutil.ToFloat64(objectErrorsTotal.WithLabelValues(reconcileControllerSecret, key.Namespace, key.Name,
+		errorClassForbidden))
+	if got != objectDegradedAfter {
+		t.Fatalf("expected %d errors, got %v", objectDegradedAfter, got)
+	}
+
+	// 成功 reconcile 后清除所有序列
+	observeObjectResult(reconcileControllerSecret, key, nil)
+	if n := testutil.CollectAndCount(objectErrorsTotal) + degraded(); n != 0 {
+		t.Fatalf("expected the series to be cleared, %d left", n)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get
+
+// podMetricsTimeout bounds the metrics API query made during a reconcile.
+const podMetricsTimeout = 5 * time.Second
+
+var (
+	// OOMKilled 时容器最近的内存 working set，最新一次的值覆盖之前的值
+	// 不带 pod 标签，避免 Pod 重建后留下大量过期序列
+	containerOOMWorkingSetBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_oom_working_set_bytes",
+			Help: "Memory working set of the container reported by the metrics API when its last OOMKilled termination was detected",
+		},
+		[]string{
+			"namespace",     // Pod 所在命名空间
+			"workload_name", // 所属工作负载名称
+			"container",     // 容器名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerOOMWorkingSetBytes))
+}
+
+// podMetricsReader queries metrics.k8s.io PodMetrics. The clientset is only
+// created on first use so that clusters without metrics-server start cleanly.
+type podMetricsReader struct {
+	config *rest.Config
+
+	once   sync.Once
+	client metricsclient.Interface
+	err    error
+}
+
+func newPodMetricsReader(config *rest.Config) *podMetricsReader {
+	return &podMetricsReader{config: config}
+}
+
+// containerUsage returns the resource usage the metrics API last reported for
+// the container. ok is false when it is unavailable for any reason.
+func (m *podMetricsReader) containerUsage(ctx context.Context, namespace, pod, container string) (corev1.ResourceList, bool) {
+	if m == nil {
+		return nil, false
+	}
+	m.once.Do(func() {
+		m.client, m.err = metricsclient.NewForConfig(m.config)
+	})
+	if m.err != nil {
+		logf.FromContext(ctx).V(1).Info("Unable to create metrics API client", "error", m.err.Error())
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, podMetricsTimeout)
+	defer cancel()
+	podMetrics, err := m.client.MetricsV1beta1().PodMetricses(namespace).Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		// metrics-server 未安装或 Pod 已消失时静默跳过
+		logf.FromContext(ctx).V(1).Info("Unable to get pod metrics", "pod", pod, "error", err.Error())
+		return nil, false
+	}
+	for _, c := range podMetrics.Containers {
+		if c.Name == container {
+			return c.Usage, true
+		}
+	}
+	return nil, false
+}
+
+// workingSet returns the memory working set the metrics API last reported for
+// the container.
+func (m *podMetricsReader) workingSet(ctx context.Context, namespace, pod, container string) (int64, bool) {
+	usage, ok := m.containerUsage(ctx, namespace, pod, container)
+	if !ok {
+		return 0, false
+	}
+	memory, found := usage[corev1.ResourceMemory]
+	if !found {
+		return 0, false
+	}
+	return memory.Value(), true
+}
+
+// recordOOMWorkingSet exports the container's working set after an OOMKilled
+// termination, when the metrics API integration is enabled.
+func (r *PodMonitorReconciler) recordOOMWorkingSet(ctx context.Context, b *metricBatch, pod *corev1.Pod,
+	container string, workload workloadRef) {
+	bytes, ok := r.podMetrics.workingSet(ctx, pod.Namespace, pod.Name, container)
+	if !ok {
+		return
+	}
+	b.set(containerOOMWorkingSetBytes, float64(bytes), pod.Namespace, workload.Name, container)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
+)
+
+// fakePodMetricsReader returns a reader answering from the given PodMetrics.
+func fakePodMetricsReader(podMetrics ...*metricsv1beta1.PodMetrics) *podMetricsReader {
+	client := metricsfake.NewSimpleClientset()
+	// fake tracker 按 "podmetricses" 存储对象，而 Get 使用资源名 "pods"，这里直接应答
+	client.PrependReactor("get", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		get := action.(k8stesting.GetAction)
+		for _, m := range podMetrics {
+			if m.Namespace == get.GetNamespace() && m.Name == get.GetName() {
+				return true, m, nil
+			}
+		}
+		return true, nil, apierrors.NewNotFound(metricsv1beta1.Resource("pods"), get.GetName())
+	})
+	reader := &podMetricsReader{client: client}
+	// 已注入 client，跳过延迟创建
+	reader.once.Do(func() {})
+	return reader
+}
+
+func TestPodMetricsReaderWorkingSet(t *testing.T) {
+	reader := fakePodMetricsReader(&metricsv1beta1.PodMetrics{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "oom-test", Name: "app"},
+		Containers: []metricsv1beta1.ContainerMetrics{{
+			Name:  "main",
+			Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		}},
+	})
+	ctx := context.Background()
+
+	if got, ok := reader.workingSet(ctx, "oom-test", "app", "main"); !ok || got != 256<<20 {
+		t.Fatalf("expected 256Mi, got %d (ok=%v)", got, ok)
+	}
+	// Pod 已消失或容器不存在时静默跳过
+	if _, ok := reader.workingSet(ctx, "oom-test", "gone", "main"); ok {
+		t.Fatal("expected no value for a missing pod")
+	}
+	if _, ok := reader.workingSet(ctx, "oom-test", "app", "sidecar"); ok {
+		t.Fatal("expected no value for a missing container")
+	}
+	// 未启用时不查询
+	var disabled *podMetricsReader
+	if _, ok := disabled.workingSet(ctx, "oom-test", "app", "main"); ok {
+		t.Fatal("expected no value when the metrics API is disabled")
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// operatorMemoryUsage exports the heap memory allocated by the operator. It is
+// read at scrape time rather than on every reconcile because ReadMemStats
+// briefly stops the world.
+var operatorMemoryUsage = prometheus.NewGaugeFunc(
+	prometheus.GaugeOpts{
+		Name: "pod_monitor_operator_memory_usage_bytes",
+		Help: "Bytes of allocated heap objects of the operator (runtime.MemStats.Alloc), read at scrape time.",
+	},
+	func() float64 {
+		var memStats runtime.MemStats
+		runtime.ReadMemStats(&memStats)
+		return float64(memStats.Alloc)
+	},
+)
+
+func init() {
+	registerMetrics(operatorMemoryUsage)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+
+var (
+	// PDB 是否已达到最低可用数；此时驱逐会被阻止，重启中的 Pod 恢复可能被拖慢
+	podDisruptionBudgetAtCapacity = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_pod_disruption_budget_at_capacity",
+			Help: "1 when a PodDisruptionBudget has no healthy pods to spare (currentHealthy <= desiredHealthy), else 0",
+		},
+		[]string{
+			"namespace", // PDB 所在命名空间
+			"pdb_name",  // PDB 名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(podDisruptionBudgetAtCapacity)
+}
+
+// isPDBAtCapacity reports whether the budget allows no further disruption.
+// Budgets that select no pods are never at capacity.
+func isPDBAtCapacity(pdb *policyv1.PodDisruptionBudget) bool {
+	return pdb.Status.ExpectedPods > 0 && pdb.Status.CurrentHealthy <= pdb.Status.DesiredHealthy
+}
+
+func observePDB(pdb *policyv1.PodDisruptionBudget) {
+	value := 0.0
+	if isPDBAtCapacity(pdb) {
+		value = 1
+	}
+	podDisruptionBudgetAtCapacity.WithLabelValues(pdb.Namespace, pdb.Name).Set(value)
+}
+
+// pdbEventHandler keeps pod_monitor_pod_disruption_budget_at_capacity up to
+// date from PodDisruptionBudget events without triggering reconciles.
+func pdbEventHandler() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, e event.CreateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if pdb, ok := e.Object.(*policyv1.PodDisruptionBudget); ok {
+				observePDB(pdb)
+			}
+		},
+		UpdateFunc: func(_ context.Context, e event.UpdateEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if pdb, ok := e.ObjectNew.(*policyv1.PodDisruptionBudget); ok {
+				observePDB(pdb)
+			}
+		},
+		DeleteFunc: func(_ context.Context, e event.DeleteEvent, _ workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			podDisruptionBudgetAtCapacity.DeleteLabelValues(e.Object.GetNamespace(), e.Object.GetName())
+		},
+	}
+}
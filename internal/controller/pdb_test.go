@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestPDBAtCapacity(t *testing.T) {
+	const namespace = "pdb-test"
+	newPDB := func(expected, current, desired int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"},
+			Status: policyv1.PodDisruptionBudgetStatus{
+				ExpectedPods: expected, CurrentHealthy: current, DesiredHealthy: desired},
+		}
+	}
+	h := pdbEventHandler()
+	ctx := context.Background()
+	atCapacity := func() float64 {
+		return testutil.ToFloat64(podDisruptionBudgetAtCapacity.WithLabelValues(namespace, "web"))
+	}
+	defer podDisruptionBudgetAtCapacity.Reset()
+
+	tests := []struct {
+		name string
+		pdb  *policyv1.PodDisruptionBudget
+		want bool
+	}{
+		{"healthy pods to spare", newPDB(3, 3, 2), false},
+		{"at minAvailable", newPDB(3, 2, 2), true},
+		{"below minAvailable", newPDB(3, 1, 2), true},
+		// 未选中任何 Pod 的 PDB 不计为已满
+		{"no pods selected", newPDB(0, 0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := isPDBAtCapacity(tt.pdb); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// 事件只更新指标，不触发 Reconcile
+	h.Create(ctx, event.CreateEvent{Object: newPDB(3, 3, 2)}, nil)
+	if got := atCapacity(); got != 0 {
+		t.Errorf("expected a PDB with pods to spare to report 0, got %v", got)
+	}
+	h.Update(ctx, event.UpdateEvent{ObjectOld: newPDB(3, 3, 2), ObjectNew: newPDB(3, 2, 2)}, nil)
+	if got := atCapacity(); got != 1 {
+		t.Errorf("expected a PDB at minAvailable to report 1, got %v", got)
+	}
+	h.Delete(ctx, event.DeleteEvent{Object: newPDB(3, 2, 2)}, nil)
+	if n := testutil.CollectAndCount(podDisruptionBudgetAtCapacity); n != 0 {
+		t.Errorf("expected a deleted PDB to be removed, got %d series", n)
+	}
+}
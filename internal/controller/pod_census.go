@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 各命名空间中处于各阶段的 Pod 数量
+	podsByPhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_pods_by_phase",
+			Help: "Number of pods per namespace and phase. Succeeded pods are excluded unless enabled.",
+		},
+		[]string{
+			"namespace", // 命名空间
+			"phase",     // Pod 阶段
+		},
+	)
+)
+
+func init() {
+	registerMetrics(podsByPhase)
+}
+
+// podPhaseCensus tracks the phase of every observed pod and keeps
+// pod_monitor_pods_by_phase in sync with it.
+type podPhaseCensus struct {
+	mu sync.Mutex
+	// key: "namespace/podName"
+	phases map[string]podPhaseEntry
+	// key: "namespace/phase"
+	counts map[string]int
+}
+
+type podPhaseEntry struct {
+	namespace string
+	phase     corev1.PodPhase
+}
+
+func newPodPhaseCensus() *podPhaseCensus {
+	return &podPhaseCensus{
+		phases: make(map[string]podPhaseEntry),
+		counts: make(map[string]int),
+	}
+}
+
+// phaseCensus 是所有 Pod 的阶段统计
+var phaseCensus = newPodPhaseCensus()
+
+// observe records the current phase of a pod.
+func (c *podPhaseCensus) observe(namespace, podName string, phase corev1.PodPhase) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := namespace + "/" + podName
+	if old, ok := c.phases[key]; ok {
+		if old.phase == phase {
+			return
+		}
+		c.decrementLocked(old)
+	}
+	entry := podPhaseEntry{namespace: namespace, phase: phase}
+	c.phases[key] = entry
+	c.counts[namespace+"/"+string(phase)]++
+	podsByPhase.WithLabelValues(namespace, string(phase)).Inc()
+}
+
+// forget removes a pod from the census.
+func (c *podPhaseCensus) forget(namespace, podName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := namespace + "/" + podName
+	if old, ok := c.phases[key]; ok {
+		c.decrementLocked(old)
+		delete(c.phases, key)
+	}
+}
+
+func (c *podPhaseCensus) decrementLocked(entry podPhaseEntry) {
+	countKey := entry.namespace + "/" + string(entry.phase)
+	c.counts[countKey]--
+	if c.counts[countKey] <= 0 {
+		// 计数归零时删除序列，避免已清空的命名空间残留
+		delete(c.counts, countKey)
+		podsByPhase.DeleteLabelValues(entry.namespace, string(entry.phase))
+		return
+	}
+	podsByPhase.WithLabelValues(entry.namespace, string(entry.phase)).Dec()
+}
+
+// updatePhaseCensus records the pod's phase, leaving Succeeded pods out of the
+// census unless IncludeSucceededPods is set.
+func (r *PodMonitorReconciler) updatePhaseCensus(pod *corev1.Pod) {
+	if pod.Status.Phase == corev1.PodSucceeded && !r.IncludeSucceededPods {
+		phaseCensus.forget(pod.Namespace, pod.Name)
+		return
+	}
+	phaseCensus.observe(pod.Namespace, pod.Name, pod.Status.Phase)
+}
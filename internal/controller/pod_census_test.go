@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestPodPhaseCensus(t *testing.T) {
+	const namespace = "pod-census-test"
+	r := &PodMonitorReconciler{}
+	observe := func(name string, phase corev1.PodPhase) {
+		pod := testsupport.NewPod(namespace, name).Build()
+		pod.Status.Phase = phase
+		r.updatePhaseCensus(pod)
+	}
+	assertCount := func(phase corev1.PodPhase, want float64) {
+		t.Helper()
+		labels := testsupport.Labels{"namespace": namespace, "phase": string(phase)}
+		if want == 0 {
+			testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_pods_by_phase", labels)
+			return
+		}
+		testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pods_by_phase", labels, want)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		defer phaseCensus.forget(namespace, name)
+	}
+
+	observe("a", corev1.PodPending)
+	observe("b", corev1.PodRunning)
+	observe("a", corev1.PodRunning)
+	observe("a", corev1.PodRunning)
+	assertCount(corev1.PodPending, 0)
+	assertCount(corev1.PodRunning, 2)
+
+	// Succeeded 的 Pod 默认不计入，且从原阶段中移除
+	observe("b", corev1.PodSucceeded)
+	assertCount(corev1.PodRunning, 1)
+	assertCount(corev1.PodSucceeded, 0)
+
+	// 启用后计入 Succeeded
+	r.IncludeSucceededPods = true
+	observe("c", corev1.PodSucceeded)
+	assertCount(corev1.PodSucceeded, 1)
+
+	// 删除的 Pod 移出统计，计数归零的序列被删除
+	phaseCensus.forget(namespace, "a")
+	phaseCensus.forget(namespace, "c")
+	assertCount(corev1.PodRunning, 0)
+	assertCount(corev1.PodSucceeded, 0)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Pod annotations that tune eventing for a single pod. Metrics are recorded
+// regardless of them.
+const (
+	// burstThresholdAnnotation overrides the restart alert threshold of the
+	// namespace policy for the pod's containers.
+	burstThresholdAnnotation = "pod-monitor.deraiven.io/burst-threshold"
+	// warningDisabledAnnotation set to "true" suppresses Warning events on the
+	// pod.
+	warningDisabledAnnotation = "pod-monitor.deraiven.io/warning-disabled"
+
+	// Former names of the annotations, still honoured when the current ones
+	// are not set.
+	legacyBurstThresholdAnnotation  = "pod-monitor.io/burst-threshold"
+	legacyWarningDisabledAnnotation = "pod-monitor.io/warning-disabled"
+)
+
+// podAnnotation returns the value of an annotation, falling back to its
+// former name.
+func podAnnotation(pod *corev1.Pod, name, legacyName string) string {
+	if value, ok := pod.Annotations[name]; ok {
+		return value
+	}
+	return pod.Annotations[legacyName]
+}
+
+// podOverrides are the parsed eventing annotations of a pod.
+type podOverrides struct {
+	// 原始注解值，变化时重新解析
+	burstRaw    string
+	disabledRaw string
+
+	// BurstThreshold 为 0 表示未设置，使用策略中的阈值
+	BurstThreshold   int32
+	WarningsDisabled bool
+}
+
+// parsePodOverrides parses the eventing annotations of a pod. Invalid values
+// are ignored and reported in the returned errors.
+func parsePodOverrides(pod *corev1.Pod) (podOverrides, []error) {
+	o := podOverrides{
+		burstRaw:    podAnnotation(pod, burstThresholdAnnotation, legacyBurstThresholdAnnotation),
+		disabledRaw: podAnnotation(pod, warningDisabledAnnotation, legacyWarningDisabledAnnotation),
+	}
+	var errs []error
+	if o.burstRaw != "" {
+		threshold, err := strconv.ParseInt(o.burstRaw, 10, 32)
+		if err != nil || threshold <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive integer, got %q", burstThresholdAnnotation, o.burstRaw))
+		} else {
+			o.BurstThreshold = int32(threshold)
+		}
+	}
+	if o.disabledRaw != "" {
+		disabled, err := strconv.ParseBool(o.disabledRaw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a boolean, got %q", warningDisabledAnnotation, o.disabledRaw))
+		} else {
+			o.WarningsDisabled = disabled
+		}
+	}
+	return o, errs
+}
+
+// apply overrides the restart alert threshold of the policy.
+func (o podOverrides) apply(policy monitorPolicy) monitorPolicy {
+	if o.BurstThreshold > 0 {
+		policy.RestartAlertThreshold = o.BurstThreshold
+	}
+	return policy
+}
+
+// podOverrides returns the cached overrides of a pod, parsing its annotations
+// again only when they changed. Invalid values are logged once per change.
+func (s *restartStateStore) podOverrides(ctx context.Context, pod *corev1.Pod) podOverrides {
+	key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+
+	s.mu.RLock()
+	cached, ok := s.overrides[key]
+	s.mu.RUnlock()
+	if ok && cached.burstRaw == podAnnotation(pod, burstThresholdAnnotation, legacyBurstThresholdAnnotation) &&
+		cached.disabledRaw == podAnnotation(pod, warningDisabledAnnotation, legacyWarningDisabledAnnotation) {
+		return cached
+	}
+
+	overrides, errs := parsePodOverrides(pod)
+	for _, err := range errs {
+		logf.FromContext(ctx).Info("Ignoring invalid pod annotation", "pod", pod.Name, "reason", err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if overrides == (podOverrides{}) {
+		// 没有注解的 Pod 不占用缓存
+		delete(s.overrides, key)
+	} else {
+		s.overrides[key] = overrides
+	}
+	return overrides
+}
+
+// warningsDisabled reports whether Warning events are suppressed for the pod,
+// as of the last parse of its annotations.
+func (s *restartStateStore) warningsDisabled(namespace, podName string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.overrides[fmt.Sprintf("%s/%s", namespace, podName)].WarningsDisabled
+}
+
+// warnPod emits a Warning event on the pod unless the pod disables them with
+// the pod-monitor.deraiven.io/warning-disabled annotation or its namespace is silenced.
+func (r *PodMonitorReconciler) warnPod(pod *corev1.Pod, reason, messageFmt string, args ...interface{}) {
+	if stateStore.warningsDisabled(pod.Namespace, pod.Name) {
+		return
+	}
+	r.eventf(pod, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
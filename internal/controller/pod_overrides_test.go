@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestPodOverridesFromAnnotations(t *testing.T) {
+	const namespace = "overrides-test"
+	defer stateStore.forgetPod(namespace, "trainer")
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      "trainer",
+		Annotations: map[string]string{
+			burstThresholdAnnotation:  "10",
+			warningDisabledAnnotation: "true",
+		},
+	}}
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Recorder: recorder}
+
+	overrides := stateStore.podOverrides(ctx, pod)
+	if got := overrides.apply(monitorPolicy{RestartAlertThreshold: 3}).RestartAlertThreshold; got != 10 {
+		t.Fatalf("expected the annotation to override the threshold, got %d", got)
+	}
+	r.warnPod(pod, EventReasonContainerRestarted, "restarted")
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected Warning events to be suppressed, got %q", <-recorder.Events)
+	}
+
+	// 注解变化后重新解析；非法值被忽略
+	pod.Annotations[burstThresholdAnnotation] = "many"
+	pod.Annotations[warningDisabledAnnotation] = "false"
+	overrides = stateStore.podOverrides(ctx, pod)
+	if got := overrides.apply(monitorPolicy{RestartAlertThreshold: 3}).RestartAlertThreshold; got != 3 {
+		t.Fatalf("expected an invalid threshold to be ignored, got %d", got)
+	}
+	r.warnPod(pod, EventReasonContainerRestarted, "restarted")
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected the Warning event once warnings are enabled again, got %d", len(recorder.Events))
+	}
+}
+
+func TestLegacyPodOverrideAnnotations(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		legacyBurstThresholdAnnotation:  "10",
+		legacyWarningDisabledAnnotation: "true",
+		warningDisabledAnnotation:       "false",
+	}}}
+	overrides, errs := parsePodOverrides(pod)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	// 旧名称仍然生效，但新名称优先
+	if overrides.BurstThreshold != 10 {
+		t.Errorf("expected the legacy threshold annotation to apply, got %d", overrides.BurstThreshold)
+	}
+	if overrides.WarningsDisabled {
+		t.Error("expected the current annotation to take precedence over the legacy one")
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultTerminationWarnThreshold is how long a pod may be terminating before
+// it is exported as stuck.
+const defaultTerminationWarnThreshold = time.Minute
+
+// terminatingPod is a pod with a deletion timestamp.
+type terminatingPod struct {
+	Namespace         string
+	Pod               string
+	DeletionTimestamp time.Time
+	// WarnThreshold is how long the pod may terminate before it is exported.
+	WarnThreshold time.Duration
+}
+
+// setTerminatingPod records or clears the deletion timestamp of a pod.
+func (s *restartStateStore) setTerminatingPod(key string, state *terminatingPod) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == nil {
+		delete(s.terminating, key)
+		return
+	}
+	s.terminating[key] = *state
+}
+
+// terminatingPods returns a snapshot of all terminating pods.
+func (s *restartStateStore) terminatingPods() []terminatingPod {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pods := make([]terminatingPod, 0, len(s.terminating))
+	for _, state := range s.terminating {
+		pods = append(pods, state)
+	}
+	return pods
+}
+
+// updatePodTermination records the deletion timestamp of a terminating pod
+// for pod_monitor_pod_termination_duration_seconds. The pod is forgotten with
+// the rest of its state once it is fully deleted.
+func (r *PodMonitorReconciler) updatePodTermination(pod *corev1.Pod) {
+	key := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+	if pod.DeletionTimestamp == nil {
+		stateStore.setTerminatingPod(key, nil)
+		return
+	}
+	threshold := r.TerminationWarnThreshold
+	if threshold <= 0 {
+		threshold = defaultTerminationWarnThreshold
+	}
+	stateStore.setTerminatingPod(key, &terminatingPod{
+		Namespace:         pod.Namespace,
+		Pod:               pod.Name,
+		DeletionTimestamp: pod.DeletionTimestamp.Time,
+		WarnThreshold:     threshold,
+	})
+}
+
+// podTerminationCollector exports how long pods have been terminating, once
+// past the warning threshold. It is computed at scrape time because a pod
+// stuck on a finalizer or volume unmount is not updated, and so not
+// reconciled, again.
+type podTerminationCollector struct {
+	desc *prometheus.Desc
+}
+
+func newPodTerminationCollector() *podTerminationCollector {
+	return &podTerminationCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_pod_termination_duration_seconds",
+			"Seconds since the deletion of a pod that is still terminating, "+
+				"exported once it exceeds --termination-warn-threshold",
+			[]string{"namespace", "pod"}, nil,
+		),
+	}
+}
+
+func (c *podTerminationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *podTerminationCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, state := range stateStore.terminatingPods() {
+		elapsed := now.Sub(state.DeletionTimestamp)
+		if elapsed <= state.WarnThreshold {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, elapsed.Seconds(),
+			state.Namespace, state.Pod)
+	}
+}
+
+func init() {
+	registerMetrics(newPodTerminationCollector())
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestPodTerminationDuration(t *testing.T) {
+	const namespace = "pod-termination-test"
+	r := &PodMonitorReconciler{TerminationWarnThreshold: 5 * time.Minute}
+	stuck := testsupport.NewPod(namespace, "stuck").Build()
+	stuck.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	recent := testsupport.NewPod(namespace, "recent").Build()
+	recent.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	running := testsupport.NewPod(namespace, "running").Build()
+	defer cleanupPod(namespace, "stuck")
+	defer cleanupPod(namespace, "recent")
+	defer cleanupPod(namespace, "running")
+
+	duration := func(pod string) []float64 {
+		t.Helper()
+		values, err := testsupport.Series(metrics.Registry, "pod_monitor_pod_termination_duration_seconds",
+			testsupport.Labels{"namespace": namespace, "pod": pod})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return values
+	}
+
+	r.updatePodTermination(stuck)
+	r.updatePodTermination(recent)
+	r.updatePodTermination(running)
+
+	// 抓取时计算，至少为删除后的一小时
+	if values := duration("stuck"); len(values) != 1 || values[0] < time.Hour.Seconds() ||
+		values[0] > time.Hour.Seconds()+60 {
+		t.Errorf("termination duration of stuck = %v, want about 3600", values)
+	}
+	// 未超过阈值或未在终止的 Pod 没有序列
+	if values := duration("recent"); len(values) != 0 {
+		t.Errorf("termination duration of recent = %v, want none", values)
+	}
+	if values := duration("running"); len(values) != 0 {
+		t.Errorf("termination duration of running = %v, want none", values)
+	}
+
+	// Pod 彻底删除后序列消失
+	cleanupPod(namespace, "stuck")
+	if values := duration("stuck"); len(values) != 0 {
+		t.Errorf("termination duration of a deleted pod = %v, want none", values)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// podUIDLabel returns the pod_uid label of the restart metrics: the pod UID
+// with IncludePodUID, else "" (no label).
+func (r *PodMonitorReconciler) podUIDLabel(pod *corev1.Pod) string {
+	if !r.IncludePodUID {
+		return ""
+	}
+	return string(pod.UID)
+}
+
+// podUID returns the UID whose state is held for a pod name.
+func (s *restartStateStore) podUID(namespace, podName string) (types.UID, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	uid, ok := s.podUIDs[fmt.Sprintf("%s/%s", namespace, podName)]
+	return uid, ok
+}
+
+// setPodUID records the UID whose state is held for a pod name.
+func (s *restartStateStore) setPodUID(namespace, podName string, uid types.UID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.podUIDs[fmt.Sprintf("%s/%s", namespace, podName)] = uid
+}
+
+// trackPodUID ties the state of a pod name to the UID of the pod. When the
+// name is reused by a new pod (a StatefulSet pod recreated before its deletion
+// was reconciled), the metrics and state of the previous instance are dropped
+// so the new pod does not inherit its restart baseline. Only with
+// IncludePodUID, and always for the mirror pods of static pods, which the
+// kubelet recreates under the same name; by default state is keyed by name.
+func (r *PodMonitorReconciler) trackPodUID(ctx context.Context, pod *corev1.Pod) {
+	mirror := isMirrorPod(pod)
+	if !r.IncludePodUID && !mirror {
+		return
+	}
+	if previous, ok := stateStore.podUID(pod.Namespace, pod.Name); ok && previous != pod.UID {
+		logf.FromContext(ctx).Info("Pod was recreated with the same name, dropping the state of the previous instance",
+			"pod", pod.Name, "previousUID", previous, "uid", pod.UID)
+		cleanupPod(pod.Namespace, pod.Name)
+		if mirror {
+			seedObservedRestartCounts(pod)
+		}
+	}
+	stateStore.setPodUID(pod.Namespace, pod.Name, pod.UID)
+}
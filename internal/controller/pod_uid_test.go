@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestRecreatedPodDoesNotInheritRestartBaseline(t *testing.T) {
+	const namespace = "pod-uid-test"
+	const containerKey = namespace + "/web-0/app"
+	newPod := func(uid string) *corev1.Pod {
+		return testsupport.NewPod(namespace, "web-0").WithUID("uid-" + uid).Build()
+	}
+	defer stateStore.forgetPod(namespace, "web-0")
+
+	// 默认按名称记录状态，重建的 Pod 继承旧的基线
+	r := &PodMonitorReconciler{}
+	r.trackPodUID(context.Background(), newPod("a"))
+	stateStore.setObservedRestartCount(containerKey, 3)
+	r.trackPodUID(context.Background(), newPod("b"))
+	if got := stateStore.observedRestartCount(containerKey); got != 3 {
+		t.Fatalf("expected name-keyed state by default, got %d", got)
+	}
+	if got := r.podUIDLabel(newPod("b")); got != "" {
+		t.Fatalf("expected no pod_uid label by default, got %q", got)
+	}
+
+	r.IncludePodUID = true
+	r.trackPodUID(context.Background(), newPod("a"))
+	if got := stateStore.observedRestartCount(containerKey); got != 3 {
+		t.Fatalf("expected the state of the first instance to be kept, got %d", got)
+	}
+	r.trackPodUID(context.Background(), newPod("b"))
+	if got := stateStore.observedRestartCount(containerKey); got != 0 {
+		t.Fatalf("expected the recreated pod to start from a clean baseline, got %d", got)
+	}
+	if got := r.podUIDLabel(newPod("b")); got != "uid-b" {
+		t.Fatalf("expected the pod UID as label, got %q", got)
+	}
+}
@@ -19,31 +19,262 @@ package controller
 import (
 	"context"
 	"crypto/x509"
-	"encoding/pem"
+	"errors"
+	"sort"
+	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"fmt"                                            // 引入 fmt 包
 	"github.com/prometheus/client_golang/prometheus" // 引入 prometheus 客户端
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
-	"sigs.k8s.io/controller-runtime/pkg/metrics" // SDK 的 metrics 包
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
 )
 
 // PodMonitorReconciler reconciles a PodMonitor object
 type PodMonitorReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MaxConcurrentReconciles is the number of reconcile workers. Pods and
+	// secrets share the workers; controller-runtime never reconciles the same
+	// object concurrently. Defaults to 1 when zero.
+	MaxConcurrentReconciles int
+	// DrainCorrelationWindow is how long after a node cordon a restart on that
+	// node is reported as planned. Defaults to 10 minutes when zero.
+	DrainCorrelationWindow time.Duration
+	// SuppressPlannedRestartEvents skips the Warning event for planned restarts.
+	SuppressPlannedRestartEvents bool
+	// ValidateCertificateHostnames checks TLS secrets against the hosts of the
+	// Ingresses that reference them.
+	ValidateCertificateHostnames bool
+	// ExposeContainerInfo exports pod_monitor_container_info for running
+	// containers. Off by default because of its cardinality.
+	ExposeContainerInfo bool
+	// SecretSizeWarnThreshold is the total data size in bytes above which a
+	// secret gets a Warning event, once each time its size crosses it. Zero
+	// disables the event.
+	SecretSizeWarnThreshold int64
+	// HistorySize bounds the cluster-wide termination history served on the
+	// state API; HistoryPerContainer bounds the records kept per container.
+	HistorySize         int
+	HistoryPerContainer int
+	// RestartWindow is the length of the sliding window of
+	// pod_monitor_restarts_last_window. Defaults to one hour.
+	RestartWindow time.Duration
+	// FailureReasonWindow is the length of the sliding window of
+	// pod_monitor_workload_distinct_failure_reasons. Defaults to 24 hours.
+	FailureReasonWindow time.Duration
+	// MaxSecretKeySize is the largest secret value parsed for certificates;
+	// MaxPEMBlocksPerKey bounds the PEM blocks decoded from one value.
+	MaxSecretKeySize   int64
+	MaxPEMBlocksPerKey int
+	// AnnotateSecrets writes the earliest certificate expiry onto monitored
+	// secrets as pod-monitor.deraiven.io/* annotations. Each write bumps the
+	// secret's resourceVersion and is sent to every watcher of the secret, so
+	// writes are limited to at most one per secret per day.
+	AnnotateSecrets bool
+	// WatchEtcdCerts checks the etcd certificate secrets named in
+	// EtcdSecretNames in kube-system, labeling their metrics source="etcd".
+	WatchEtcdCerts  bool
+	EtcdSecretNames []string
+	// KubeadmMode checks the control-plane certificates that kubeadm publishes
+	// in the kubeadm-certs secret, the cluster-info kubeconfig and the
+	// extension-apiserver-authentication ConfigMap, labeling their metrics
+	// source="kubeadm" with the component they belong to.
+	KubeadmMode bool
+	// RestartVelocityAlpha is the smoothing factor of the restart velocity EWMA,
+	// in (0, 1]: the weight of a rate observed over one minute. Without
+	// restarts the velocity decays by a factor 1-alpha per minute. Defaults to
+	// 0.2 when unset.
+	RestartVelocityAlpha float64
+	// ImagePullStuckThreshold is how long a container may fail to pull its
+	// image before a Warning event is emitted. Defaults to 10 minutes.
+	ImagePullStuckThreshold time.Duration
+	// TerminationWarnThreshold is how long a pod may be terminating before it
+	// is exported in pod_monitor_pod_termination_duration_seconds. Defaults to
+	// 1 minute.
+	TerminationWarnThreshold time.Duration
+	// IncludeSucceededPods keeps Succeeded pods in pod_monitor_pods_by_phase.
+	// They are excluded by default because finished Job pods linger until TTL.
+	IncludeSucceededPods bool
+	// RepeatedExitCodeEventThreshold is the number of consecutive terminations
+	// with the same non-zero exit code at which a Warning event is emitted.
+	// Zero disables the event.
+	RepeatedExitCodeEventThreshold int
+	// WatchPodDisruptionBudgets exports whether each PodDisruptionBudget is at
+	// capacity.
+	WatchPodDisruptionBudgets bool
+	// EnableNodeWatch reconciles nodes when their conditions change, exporting
+	// pod_monitor_node_condition_status.
+	EnableNodeWatch bool
+	// WatchNodes follows the Ready condition of nodes, exporting
+	// pod_monitor_node_ready and labeling restarts on nodes that were NotReady
+	// within DrainCorrelationWindow.
+	WatchNodes bool
+	// WatchProbeEvents watches the kubelet events of failed liveness probes
+	// and labels restarts within LivenessCorrelationWindow (default 2
+	// minutes) of one cause="liveness_kill", other restarts cause="crash".
+	WatchProbeEvents          bool
+	LivenessCorrelationWindow time.Duration
+	// EnableCSRWatch exports how long CertificateSigningRequests have been
+	// waiting for approval and emits a Warning event on those pending longer
+	// than CSRPendingAlertThreshold (default 5 minutes).
+	EnableCSRWatch           bool
+	CSRPendingAlertThreshold time.Duration
+	// PatchWorkloadConditions sets PodMonitorCrashLooping=True on the owning
+	// Deployment or StatefulSet of a pod whose container reaches the restart
+	// alert threshold, and back to False once no container crossed it for
+	// WorkloadConditionCoolDown (default 30 minutes). Only workloads the
+	// operator marked are ever cleared.
+	PatchWorkloadConditions   bool
+	WorkloadConditionCoolDown time.Duration
+	// SlackNotifier, when set, is notified of certificates expiring within
+	// the critical window of their namespace policy.
+	SlackNotifier *SlackNotifier
+	// APIErrorThreshold is the number of consecutive API server errors after
+	// which pod reconciles are paused for APIBackoffCoolOff. Defaults to 20
+	// errors and 30 seconds.
+	APIErrorThreshold int
+	APIBackoffCoolOff time.Duration
+	// UseMetricsAPI queries metrics.k8s.io for the memory working set of
+	// containers terminated with OOMKilled. Requires metrics-server.
+	UseMetricsAPI bool
+	// CPUThrottlingThreshold is the fraction of the CPU limit at or above which
+	// the recent CPU usage of a restarted container labels the restart
+	// suspected_cause="cpu_throttling". This is a hint, not a verdict. Only
+	// used with UseMetricsAPI; defaults to 0.95.
+	CPUThrottlingThreshold float64
+	// LinkerdMode tracks linkerd-proxy sidecars: their restarts by proxy
+	// version, and the restarts within LinkerdRotationWindow (default 10
+	// minutes) after a rotation of the identity issuer in LinkerdNamespace
+	// (default "linkerd").
+	LinkerdMode           bool
+	LinkerdNamespace      string
+	LinkerdRotationWindow time.Duration
+	// DisableSecretWatch, DisableNodeDrainTracking and DisablePolicies turn
+	// off secret monitoring, planned-restart detection through node cordons
+	// and PodMonitorPolicy lookups, e.g. when the operator is not allowed to
+	// read those resources. See Features and DisableFeature.
+	DisableSecretWatch       bool
+	DisableNodeDrainTracking bool
+	DisablePolicies          bool
+	// DisablePodMonitorStatus stops writing the monitoring summary into the
+	// status of PodMonitors.
+	DisablePodMonitorStatus bool
+	// AutoDiscoverCerts checks the .crt, .pem and .cer keys of secrets that
+	// none of the known key lists match, in AutoDiscoverNamespaces if set.
+	// At most AutoDiscoverMax (default 500) such secrets are monitored.
+	AutoDiscoverCerts      bool
+	AutoDiscoverNamespaces []string
+	AutoDiscoverMax        int
+	// IncludePodUID adds a pod_uid label to the restart and termination
+	// metrics and ties the restart state of a pod name to its UID, so a pod
+	// recreated with the same name starts from a clean baseline.
+	IncludePodUID bool
+	// TrustedCACommonNames, when set, flags certificates whose issuer common
+	// name is not in the list with pod_monitor_certificate_issued_by_unknown_ca.
+	TrustedCACommonNames []string
+	// WatchFilter decides which pods and secrets are reconciled. Defaults to
+	// DefaultWatchFilter.
+	WatchFilter WatchFilter
+	// Clock is the time source of reconciles. Defaults to the real clock;
+	// tests set a fake one. Scrape-time metrics always use the real clock.
+	Clock clock.PassiveClock
+	// Rand returns random numbers in [0, 1) used to jitter requeues. Defaults
+	// to math/rand; tests set a deterministic one.
+	Rand func() float64
+	// SecretStartupSpread is the period over which the first checks of the
+	// secrets that exist at startup are randomly spread. 0 checks them all
+	// at once.
+	SecretStartupSpread time.Duration
+	// SecretRecheckJitter is the fraction by which the hourly certificate
+	// recheck of each secret is randomly lengthened or shortened, so that
+	// secrets do not all reconcile at once. 0 disables jitter.
+	SecretRecheckJitter float64
+	// SecretWatchConfigFile is a YAML file listing the secrets to monitor by
+	// namespace/name and by label selector. It is reread every 30 seconds.
+	// Secrets named by the etcd, Linkerd and kubeadm options are always
+	// monitored. Unset monitors every secret the WatchFilter allows; a file
+	// selecting no secret skips the Secret watch.
+	SecretWatchConfigFile string
+
+	drainTracker  *nodeDrainTracker
+	readyTracker  *nodeReadyTracker
+	topologyCache *nodeTopologyCache
+	apiBreaker    *apiCircuitBreaker
+	podMetrics    *podMetricsReader
+	// 工作负载状态查询失败后暂停查询的截止时间（time.Time）
+	rolloutLookupDisabledUntil atomic.Value
+	// 上一次更新 PodMonitor 状态的时间（UnixNano）
+	podMonitorStatusUpdatedAt atomic.Int64
+	// 首次 reconcile 的时间（UnixNano），此前结束的终止不计入检测延迟
+	startedAt atomic.Int64
+	// 工作负载 pod-monitor.deraiven.io/critical 注解的缓存
+	criticalityCache *workloadCriticalityCache
+	// 最近的存活探针失败，仅在 WatchProbeEvents 时设置
+	livenessTracker *livenessTracker
+	// 已发出等待审批告警的 CSR
+	csrAlerts *csrAlerts
+	// 直接读取 API server 的客户端，用于不需要缓存的少量对象
+	apiReader client.Reader
+	// 设置过 PodMonitorCrashLooping 条件的工作负载
+	workloadConditions *workloadConditionTracker
+	// Secret 定期检查的调度，未设置时固定每小时检查一次
+	secretRechecks *secretRecheckScheduler
+	// 当前的 Secret 监听配置，nil 表示监听所有 Secret
+	secretWatch atomic.Pointer[secretWatchSet]
+	// 配置未选中任何 Secret 时不注册 Secret 监听
+	skipSecretWatch atomic.Bool
+	// 配置重新加载后，监控状态变化的 Secret 经此入队
+	secretWatchEvents chan event.GenericEvent
+	// 跳过的 Secret 监听在配置重新加载后注册到此控制器
+	secretController controller.Controller
+	watchCache       cache.Cache
+	// Secret 监听使用的 WatchFilter
+	watchFilterForSecrets WatchFilter
+	// 缺少 secrets 的 patch 权限时不移除 force-refresh 注解，注解的值变化仍触发检查
+	disableForceRefreshAck bool
+	// 缺少 namespaces 的 list/watch 权限时不监听 Linkerd 命名空间，版本只在 reconcile 时读取
+	disableLinkerdNamespaceWatch bool
+	// 缺少 nodes 的读取权限时不导出 Pod 拓扑信息，避免启动永远无法同步的 Node informer
+	disableNodeTopology bool
+}
+
+// now returns the current time of Clock, or of the real clock if unset.
+func (r *PodMonitorReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+	return time.Now()
 }
 
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=pods/status,verbs=get
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=monitor.storehub.com,resources=podmonitors,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitor.storehub.com,resources=podmonitors/status,verbs=get;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -69,6 +300,40 @@ var (
 			"container", // 容器名称
 			"reason",    // 终止原因 (e.g., OOMKilled)
 			"exit_code", // 退出码
+			"simulated", // 是否为模拟数据（--simulate-restarts）
+			"pod_uid",   // Pod UID（--include-pod-uid），否则为空
+		},
+	)
+
+	// 上一次终止的容器的启动时间，与 pod_monitor_container_last_termination_info 相减得到其运行时长
+	podLastTerminationStartedAt = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_last_termination_started_at_seconds",
+			Help: "Unix timestamp at which the last terminated instance of a container started.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"reason",    // 终止原因 (e.g., OOMKilled)
+			"exit_code", // 退出码
+			"simulated", // 是否为模拟数据（--simulate-restarts）
+			"pod_uid",   // Pod UID（--include-pod-uid），否则为空
+		},
+	)
+
+	// OOMKilled 终止次数
+	containerOOMKilledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_container_oom_killed_total",
+			Help: "Total number of container terminations with reason OOMKilled",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"simulated", // 是否为模拟数据（--simulate-restarts）
+			"pod_uid",   // Pod UID（--include-pod-uid），否则为空
 		},
 	)
 
@@ -78,11 +343,39 @@ var (
 			Name: "pod_monitor_container_restart_total",
 			Help: "Total number of container restarts",
 		},
+		[]string{
+			"namespace",      // Pod 所在命名空间
+			"pod",            // Pod 名称
+			"container",      // 容器名称
+			"reason",         // 终止原因
+			"planned",        // 是否发生在计划内的节点排空期间
+			"during_rollout", // 重启时所属工作负载是否正在滚动更新
+			// 重启时节点是否 Ready（关联窗口内 NotReady 为 false，未启用 --watch-nodes 时为 unknown）
+			"node_ready_at_restart",
+			// 基于 metrics API 的疑似原因提示（如 cpu_throttling），未启用 --use-metrics-api 时为空
+			"suspected_cause",
+			// 由 restartedAt 注解触发的重启为 operator_initiated；启用 --watch-probe-events 时
+			// 存活探针失败后的重启为 liveness_kill，其余为 crash；否则为空
+			"cause",
+			// 根据退出码、信号与节点内存压力推断的原因，如 oom_suspected
+			"derived_reason",
+			// Pod UID，仅在 --include-pod-uid 时设置，区分同名重建的 Pod
+			"pod_uid",
+		},
+	)
+
+	// 按终止原因累计的终止次数，原因变化时仍可累加
+	containerTerminationReasonTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_container_last_termination_reason_total",
+			Help: "Total number of container terminations per termination reason",
+		},
 		[]string{
 			"namespace", // Pod 所在命名空间
 			"pod",       // Pod 名称
 			"container", // 容器名称
 			"reason",    // 终止原因
+			"pod_uid",   // Pod UID（--include-pod-uid），否则为空
 		},
 	)
 
@@ -99,6 +392,7 @@ var (
 			"reason",        // 终止原因
 			"exit_code",     // 退出码
 			"restart_count", // 重启次数作为唯一标识符
+			"pod_uid",       // Pod UID（--include-pod-uid），否则为空
 		},
 	)
 
@@ -112,6 +406,8 @@ var (
 			"namespace",   // Secret 所在命名空间
 			"secret_name", // Secret 名称
 			"cert_type",   // 证书类型 (ca-cert, issuer-cert, etc.)
+			"source",      // 证书来源 (secret, etcd, kubeadm)
+			"component",   // 控制平面组件，仅 kubeadm 模式下的证书设置，其余为空
 		},
 	)
 
@@ -125,25 +421,35 @@ var (
 			"namespace",   // Secret 所在命名空间
 			"secret_name", // Secret 名称
 			"cert_type",   // 证书类型
+			"source",      // 证书来源 (secret, etcd, kubeadm)
+			"component",   // 控制平面组件，仅 kubeadm 模式下的证书设置，其余为空
 		},
 	)
 
-	// 用于存储我们已经观察到的容器重启次数，防止重复处理
-	// key: "namespace/podName/containerName", value: restartCount
-	// 注意：这是一个简单的内存存储，如果 Operator 重启，状态会丢失。
-	// 生产环境可以考虑更持久化的方案。
-	observedRestarts = make(map[string]int32)
-
-	// 保护 observedRestarts map 的读写锁
-	restartsMutex sync.RWMutex
+	// Secret 数据总大小（字节），用于发现接近 1 MiB 上限的 Secret
+	secretDataSizeBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_secret_data_size_bytes",
+			Help: "Total size in bytes of all values in the secret's data",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+		},
+	)
 )
 
 func init() {
-	metrics.Registry.MustRegister(podLastTerminationInfo)
-	metrics.Registry.MustRegister(podRestartTotal)
-	metrics.Registry.MustRegister(podRestartEvents)
-	metrics.Registry.MustRegister(certificateExpirationTime)
-	metrics.Registry.MustRegister(certificateDaysUntilExpiration)
+	// 由 metricBatch 更新的指标需要包装，使抓取与批量提交互斥
+	registerMetrics(batched(podLastTerminationInfo))
+	registerMetrics(batched(podLastTerminationStartedAt))
+	registerMetrics(batched(podRestartTotal))
+	registerMetrics(batched(containerOOMKilledTotal))
+	registerMetrics(batched(containerTerminationReasonTotal))
+	registerMetrics(batched(podRestartEvents))
+	registerMetrics(certificateExpirationTime)
+	registerMetrics(certificateDaysUntilExpiration)
+	registerMetrics(secretDataSizeBytes)
 }
 
 //func (r *PodMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -155,15 +461,55 @@ func init() {
 //}
 
 func (r *PodMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// 尝试获取 Secret
-	var secret corev1.Secret
-	if err := r.Get(ctx, req.NamespacedName, &secret); err == nil {
-		// 如果是 Secret，处理证书监控
-		return r.reconcileSecret(ctx, req)
+	// 首次 reconcile 之前结束的终止不计入检测延迟
+	r.markStarted()
+
+	// 每轮 reconcile 后刷新 PodMonitor 的汇总状态（限频）
+	defer r.maybeUpdatePodMonitorStatus(ctx)
+
+	// Pod 和 Secret 都有命名空间，只有集群级别的 Node 请求没有
+	if req.Namespace == "" {
+		defer trackInFlight(reconcileControllerNode)()
+		result, err := r.reconcileNode(ctx, req)
+		observeObjectResult(reconcileControllerNode, req.NamespacedName, err)
+		return result, err
+	}
+
+	// 尝试获取 Secret；未监听 Secret 时所有请求都是 Pod
+	if !r.DisableSecretWatch && !r.skipSecretWatch.Load() {
+		var secret corev1.Secret
+		err := r.Get(ctx, req.NamespacedName, &secret)
+		if err == nil && r.secretWatched(&secret) {
+			// 如果是 Secret，处理证书监控
+			defer trackInFlight(reconcileControllerSecret)()
+			result, err := r.reconcileSecret(ctx, req)
+			observeObjectResult(reconcileControllerSecret, req.NamespacedName, err)
+			return result, err
+		}
+		if isRetriableAPIError(err) {
+			// API server 限流或超时：稍后重试，而不是返回错误触发立即重试
+			r.apiBreaker.recordFailure(r.now())
+			return ctrl.Result{RequeueAfter: apiErrorRequeueAfter(err)}, nil
+		}
+		// 已删除的 Secret 与 Pod 的请求无法区分：监控过的 Secret 先清理其状态，
+		// 再按 Pod 处理，同名 Pod 仍照常 reconcile
+		// 配置重新加载后不再选中的 Secret 同样清理
+		if (err == nil || apierrors.IsNotFound(err)) && stateStore.isKnownSecret(req.Namespace, req.Name) {
+			r.forgetDeletedSecret(ctx, req.NamespacedName)
+		}
+	}
+
+	// 连续 API 错误过多时暂停 Pod reconcile；Secret 数量少，不受影响
+	if wait := r.apiBreaker.remaining(r.now()); wait > 0 {
+		logf.FromContext(ctx).V(1).Info("Pod reconciles paused after API server errors", "retryAfter", wait)
+		return ctrl.Result{RequeueAfter: wait + apiErrorRequeueAfter(nil)}, nil
 	}
 
 	// 否则处理 Pod 事件
-	return r.reconcilePod(ctx, req)
+	defer trackInFlight(reconcileControllerPod)()
+	result, err := r.reconcilePod(ctx, req)
+	observeObjectResult(reconcileControllerPod, req.NamespacedName, err)
+	return result, err
 }
 
 // reconcilePod 处理 Pod 相关的逻辑
@@ -174,97 +520,338 @@ func (r *PodMonitorReconciler) reconcilePod(ctx context.Context, req ctrl.Reques
 	var pod corev1.Pod
 	if err := r.Get(ctx, req.NamespacedName, &pod); err != nil {
 		if client.IgnoreNotFound(err) != nil {
+			r.apiBreaker.recordFailure(r.now())
+			if isRetriableAPIError(err) {
+				log.V(1).Info("API server throttled or timed out, retrying later", "error", err.Error())
+				return ctrl.Result{RequeueAfter: apiErrorRequeueAfter(err)}, nil
+			}
 			log.Error(err, "unable to fetch Pod")
 			return ctrl.Result{}, err
 		}
-		// 如果 Pod 已被删除，清理相关指标和内存状态
+		// 如果 Pod 已被删除，补记最终状态中未观察到的终止，再清理相关指标和内存状态
 		log.Info("Pod deleted, cleaning up metrics and memory state", "namespace", req.Namespace, "pod", req.Name)
 
-		// 清理最后一次终止信息指标
-		podLastTerminationInfo.DeletePartialMatch(prometheus.Labels{
-			"namespace": req.Namespace,
-			"pod":       req.Name,
-		})
-
-		// 清理内存中的 observedRestarts 数据，防止内存泄漏
-		prefix := fmt.Sprintf("%s/%s/", req.Namespace, req.Name)
-		restartsMutex.Lock()
-		for key := range observedRestarts {
-			if strings.HasPrefix(key, prefix) {
-				delete(observedRestarts, key)
-				log.V(1).Info("Cleaned up restart state", "key", key)
-			}
-		}
-		restartsMutex.Unlock()
+		r.reconcileDeletedPod(ctx, req.Namespace, req.Name)
+		return ctrl.Result{}, nil
+	}
 
-		// 注意：不清理 podRestartTotal 和 podRestartEvents
-		// 因为这些是历史记录，应该保留
+	r.apiBreaker.recordSuccess()
+	// 同名 Pod 已被重建：上一个实例的最终状态不再补记，避免其重启次数掩盖新实例的重启
+	takePodTombstone(pod.Namespace, pod.Name)
 
-		return ctrl.Result{}, nil
+	// 可选：同名 Pod 被重建时丢弃上一个实例的状态
+	r.trackPodUID(ctx, &pod)
+
+	// 本次 reconcile 的指标更新先收集到批次中，最后一次性提交
+	var batch metricBatch
+	defer stateStore.commitMetrics(&batch)
+
+	// Pod 注解可调整告警阈值或关闭 Warning 事件，指标不受影响
+	overrides := stateStore.podOverrides(ctx, &pod)
+	// 命名空间级别的 PodMonitorPolicy 覆盖全局设置；先于任何事件解析，同时刷新静默窗口
+	policy := overrides.apply(r.policyFor(ctx, pod.Namespace))
+
+	// 可选：导出运行中容器的镜像信息
+	if r.ExposeContainerInfo {
+		updateContainerInfo(&batch, &pod)
+	}
+
+	// 导出 Pod 所在可用区/区域，便于区分基础设施问题与应用问题
+	if err := r.updatePodTopology(ctx, &batch, &pod); err != nil {
+		log.Error(err, "Failed to resolve node topology", "node", pod.Spec.NodeName)
+	}
+
+	updateCPULimitRequestRatio(&batch, &pod)
+	// 记录未设置 CPU 或内存 request 的容器
+	updateResourceRequestMissing(&batch, &pod)
+	// 记录容器通过环境变量引用的 Secret 数量
+	updateEnvSecretRefCount(&batch, &pod)
+	// 记录容器挂载的 Secret 卷数量
+	updateVolumeMountSecretCount(&batch, &pod)
+	// 校验固定了 digest 的容器实际运行的镜像
+	updateImageDigestMismatch(&batch, &pod)
+	// 记录共享节点命名空间的 Pod
+	updateHostAccessInfo(&batch, &pod)
+	// 记录容器的安全上下文
+	updateSecurityContextInfo(&batch, &pod)
+	// 记录每个容器上一个已终止实例的信息
+	updatePreviousStateInfo(&batch, &pod)
+	// 记录每个容器的当前状态（Running / Waiting / Terminated）
+	updateContainerState(&batch, &pod)
+	// 记录正在终止的 Pod，卡在 finalizer 或卷卸载上的 Pod 在抓取时导出
+	r.updatePodTermination(&pod)
+	r.updateRestartVelocity(&pod, r.now())
+
+	workload := resolveWorkload(&pod)
+	// 按 Deployment 聚合容器重启次数
+	updateDeploymentRestarts(&batch, &pod, workload)
+
+	r.updatePhaseCensus(&pod)
+	// 记录 DisruptionTarget 条件（驱逐、抢占等不一定表现为重启的中断）
+	recordPodDisruption(&pod)
+	// kubelet 因临时存储超限驱逐的 Pod 单独计数
+	r.recordEphemeralStorageEviction(&batch, &pod, workload)
+	// Job 中以非零退出码结束的容器通过单独的失败指标上报
+	r.reportJobFailures(&pod, workload)
+	// 跟踪持续拉取镜像失败的容器
+	requeueAfter := r.trackImagePulls(&pod, r.now())
+
+	// Pod 或其工作负载标记为 critical 时，每次重启都发出事件与通知
+	if r.isCritical(ctx, &pod, workload) {
+		policy = policy.escalate()
 	}
 
 	// 2. 遍历所有容器状态
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range policy.containerStatuses(&pod) {
+		if policy.isExcluded(cs.Name) {
+			continue
+		}
 		// 创建一个唯一的键来识别这个容器
 		containerKey := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
 
+		// 记录当前处于 CrashLoopBackOff 的容器
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			crashLoop := &crashLoopState{
+				Namespace:    pod.Namespace,
+				Pod:          pod.Name,
+				Container:    cs.Name,
+				RestartCount: cs.RestartCount,
+				Since:        r.now(),
+			}
+			if cs.LastTerminationState.Terminated != nil {
+				crashLoop.LastReason = cs.LastTerminationState.Terminated.Reason
+			}
+			stateStore.setCrashLooping(containerKey, crashLoop)
+		} else {
+			stateStore.setCrashLooping(containerKey, nil)
+		}
+
+		// 首次启动即失败的容器单独计数，与持续的重启循环区分
+		reportStartFailure(&batch, &pod, cs)
+		// 只统计 Operator 开始跟踪该容器之后的重启
+		updateRestartSinceObservation(&batch, &pod, containerKey, cs)
+		// 距上一次终止的时间在抓取时计算
+		updateRestartGap(&pod, containerKey, cs)
+
 		// 3. 检查重启条件
 		// 条件 1: 容器重启次数 > 我们已记录的次数
 		// 条件 2: 容器存在上一次终止的状态
 
-		// 使用读锁检查当前记录的重启次数
-		restartsMutex.RLock()
-		observedCount := observedRestarts[containerKey]
-		restartsMutex.RUnlock()
+		// 检查当前记录的重启次数
+		observedCount := stateStore.observedRestartCount(containerKey)
 
 		if cs.RestartCount > observedCount && cs.LastTerminationState.Terminated != nil {
-			log.Info("Detected container restart", "pod", pod.Name, "container", cs.Name, "restartCount", cs.RestartCount)
-
-			// 4. 提取信息并更新 Prometheus 指标
-			lastState := cs.LastTerminationState.Terminated
-			reason := lastState.Reason
-			if reason == "" {
-				reason = "Unknown"
+			if isCompletedJobContainer(workload, cs.LastTerminationState.Terminated) {
+				// Job 中正常退出的容器（如 sidecar 在任务完成时退出）不算作重启
+				log.V(1).Info("Ignoring completed container of Job pod", "pod", pod.Name, "container", cs.Name)
+			} else {
+				r.recordContainerRestart(ctx, &batch, &pod, cs, workload, policy.severity())
+				r.checkRestartThreshold(&pod, cs, observedCount, policy)
+				if policy.RestartAlertThreshold > 0 && cs.RestartCount >= policy.RestartAlertThreshold {
+					r.markWorkloadCrashLooping(ctx, workload, &pod, cs, policy.RestartAlertThreshold)
+				}
 			}
-			exitCode := fmt.Sprintf("%d", lastState.ExitCode)
-			// 将完成时间转换为 Unix 时间戳 (float64)
-			finishedAt := float64(lastState.FinishedAt.Time.Unix())
-
-			// 4.1 更新最后一次终止信息（保持向后兼容）
-			podLastTerminationInfo.With(prometheus.Labels{
-				"namespace": pod.Namespace,
-				"pod":       pod.Name,
-				"container": cs.Name,
-				"reason":    reason,
-				"exit_code": exitCode,
-			}).Set(finishedAt)
-
-			// 4.2 增加重启计数器（持久化）
-			podRestartTotal.With(prometheus.Labels{
-				"namespace": pod.Namespace,
-				"pod":       pod.Name,
-				"container": cs.Name,
-				"reason":    reason,
-			}).Inc()
-
-			// 4.3 记录重启事件（每次重启创建独立记录）
-			podRestartEvents.With(prometheus.Labels{
-				"namespace":     pod.Namespace,
-				"pod":           pod.Name,
-				"container":     cs.Name,
-				"reason":        reason,
-				"exit_code":     exitCode,
-				"restart_count": fmt.Sprintf("%d", cs.RestartCount),
-			}).Set(finishedAt)
 
 			// 5. 更新我们内存中记录的重启次数
-			restartsMutex.Lock()
-			observedRestarts[containerKey] = cs.RestartCount
-			restartsMutex.Unlock()
+			stateStore.setObservedRestartCount(containerKey, cs.RestartCount)
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// cleanupPod removes the metrics and in-memory state of a pod that no longer
+// exists, or of the previous instance of a recreated pod.
+func cleanupPod(namespace, name string) {
+	// 该 Pod 的所有指标删除在一个批次中提交
+	podLabels := prometheus.Labels{
+		"namespace": namespace,
+		"pod":       name,
+	}
+	var batch metricBatch
+
+	// 清理最后一次终止信息指标
+	batch.deletePartial(podLastTerminationInfo.MetricVec, podLabels)
+	batch.deletePartial(podLastTerminationStartedAt.MetricVec, podLabels)
+
+	// 清理容器镜像信息指标
+	cleanupContainerInfo(&batch, namespace, name)
+
+	// 清理状态存储中该 Pod 的容器状态
+	stateStore.forgetPod(namespace, name)
+
+	// 清理阶段统计
+	phaseCensus.forget(namespace, name)
+	forgetStartFailures(namespace, name)
+	forgetPodDisruption(namespace, name)
+	forgetStorageEviction(namespace, name)
+
+	// 清理容器状态指标
+	batch.deletePartial(containerState.MetricVec, podLabels)
+	batch.deletePartial(containerRestartSinceObservation.MetricVec, podLabels)
+
+	// 从所属 Deployment 的重启次数之和中扣除该 Pod
+	forgetDeploymentRestarts(&batch, namespace, name)
+
+	// 清理重复退出码指标
+	batch.deletePartial(containerRepeatedExitCode.MetricVec, podLabels)
+
+	// 清理 CPU limit/request 比值指标
+	batch.deletePartial(containerCPULimitRequestRatio.MetricVec, podLabels)
+
+	// 清理缺少 request 的容器指标
+	batch.deletePartial(containerResourceRequestMissing.MetricVec, podLabels)
+
+	// 清理环境变量 Secret 引用计数
+	batch.deletePartial(containerEnvSecretRefCount.MetricVec, podLabels)
+
+	// 清理 Secret 卷挂载数量
+	batch.deletePartial(containerVolumeMountSecretCount.MetricVec, podLabels)
+
+	// 清理镜像 digest 校验指标
+	batch.deletePartial(containerImageDigestMismatch.MetricVec, podLabels)
+
+	// 清理共享节点命名空间的 Pod 清单
+	batch.deletePartial(podHostAccessInfo.MetricVec, podLabels)
+
+	// 清理容器安全上下文清单
+	batch.deletePartial(containerSecurityContextInfo.MetricVec, podLabels)
+
+	// 清理 linkerd-proxy 的重启计数
+	batch.deletePartial(linkerdProxyRestartTotal.MetricVec, podLabels)
+
+	// 清理上一个容器实例的信息
+	batch.deletePartial(containerPreviousStateInfo.MetricVec, podLabels)
+
+	// 清理 Pod 拓扑信息指标
+	batch.deletePartial(podTopologyInfo.MetricVec, podLabels)
+
+	// 清理重启速率状态与指标
+	forgetRestartVelocity(namespace, name)
+
+	stateStore.commitMetrics(&batch)
+
+	// 注意：不清理 podRestartTotal 和 podRestartEvents
+	// 因为这些是历史记录，应该保留
+}
+
+// recordContainerRestart updates the restart metrics, the state store and
+// emits an event for a newly observed container restart. The severity is
+// "critical" for critical pods, whose planned restarts are not suppressed.
+func (r *PodMonitorReconciler) recordContainerRestart(ctx context.Context, b *metricBatch, pod *corev1.Pod,
+	cs corev1.ContainerStatus, workload workloadRef, severity string) {
+	log := logf.FromContext(ctx)
+	log.Info("Detected container restart", "pod", pod.Name, "container", cs.Name, "restartCount", cs.RestartCount)
+
+	// 4. 提取信息并更新 Prometheus 指标
+	lastState := cs.LastTerminationState.Terminated
+	reason := lastState.Reason
+	if reason == "" {
+		reason = "Unknown"
+	}
+	exitCode := fmt.Sprintf("%d", lastState.ExitCode)
+	// 将完成时间转换为 Unix 时间戳 (float64)
+	finishedAt := float64(lastState.FinishedAt.Time.Unix())
+
+	// 记录从终止到检测到重启的延迟
+	r.observeDetectionLag(lastState.FinishedAt.Time)
+
+	// 4.1 更新最后一次终止信息（保持向后兼容）
+	podUID := r.podUIDLabel(pod)
+	b.set(podLastTerminationInfo, finishedAt, pod.Namespace, pod.Name, cs.Name, reason, exitCode, "false", podUID)
+	// 未能启动的容器没有启动时间
+	if !lastState.StartedAt.IsZero() {
+		b.set(podLastTerminationStartedAt, float64(lastState.StartedAt.Time.Unix()),
+			pod.Namespace, pod.Name, cs.Name, reason, exitCode, "false", podUID)
+	}
+	b.inc(containerTerminationReasonTotal, pod.Namespace, pod.Name, cs.Name, reason, podUID)
+	// 退出码 128+N 表示容器被信号 N 终止
+	if signal, ok := terminationSignal(lastState.ExitCode); ok {
+		b.inc(containerTerminatedBySignalTotal, pod.Namespace, pod.Name, cs.Name, signal)
+	}
+
+	if reason == "OOMKilled" {
+		b.inc(containerOOMKilledTotal, pod.Namespace, pod.Name, cs.Name, "false", podUID)
+		r.recordOOMWorkingSet(ctx, b, pod, cs.Name, workload)
+	}
+
+	// 跟踪连续相同的退出码
+	r.updateRepeatedExitCode(b, pod, cs.Name, lastState.ExitCode)
+
+	// linkerd-proxy 边车的重启与 issuer 轮换关联
+	r.recordLinkerdProxyRestart(b, pod, cs.Name, lastState.FinishedAt.Time)
+
+	// 判断此次重启是否紧随节点 cordon / drain 发生
+	planned := r.isPlannedRestart(pod, lastState.FinishedAt.Time)
+	// 判断重启时所属工作负载是否正在滚动更新
+	owner, duringRollout := r.lookupWorkload(ctx, workload)
+	// Pod 或其工作负载的 restartedAt 注解更新后的重启是有意触发的
+	cause := restartCause(pod, cs.Name, owner)
+	if cause == "" {
+		// 启用探针事件监听时，区分存活探针失败导致的重启与崩溃
+		cause = r.probeCause(pod, cs.Name, lastState.FinishedAt.Time)
+	}
+
+	// 4.2 增加重启计数器（持久化）
+	b.inc(podRestartTotal, pod.Namespace, pod.Name, cs.Name, reason, strconv.FormatBool(planned), duringRollout,
+		r.nodeReadyAtRestart(pod, lastState.FinishedAt.Time), r.suspectedCause(ctx, pod, cs.Name, reason), cause,
+		deriveReason(lastState, nodeUnderMemoryPressure(pod.Spec.NodeName)), podUID)
+
+	// 4.3 记录重启事件（每次重启创建独立记录）
+	b.set(podRestartEvents, finishedAt, pod.Namespace, pod.Name, cs.Name, reason, exitCode,
+		fmt.Sprintf("%d", cs.RestartCount), podUID)
+
+	// 4.4 记录到所属工作负载的重启历史中，供报告使用
+	stateStore.recordWorkloadRestart(workload, lastState.FinishedAt.Time, r.now())
+	stateStore.recordRestartInWindow(workload, reason, lastState.FinishedAt.Time, r.now())
+	stateStore.recordTermination(terminationRecord{
+		Timestamp:     lastState.FinishedAt.Time,
+		Namespace:     pod.Namespace,
+		Pod:           pod.Name,
+		Container:     cs.Name,
+		Reason:        reason,
+		ExitCode:      lastState.ExitCode,
+		Node:          pod.Spec.NodeName,
+		Workload:      workload,
+		DuringRollout: duringRollout,
+		Severity:      severity,
+	})
+
+	r.publishContainerRestart(pod.Namespace, &eventsv1.ContainerRestart{
+		Pod:           pod.Name,
+		Container:     cs.Name,
+		RestartCount:  cs.RestartCount,
+		Reason:        reason,
+		ExitCode:      lastState.ExitCode,
+		Planned:       planned,
+		DuringRollout: duringRollout,
+		Severity:      severity,
+	}, lastState.FinishedAt.Time)
+
+	// 4.5 发出 Warning 事件；计划内重启可按配置跳过，有意触发的重启不告警
+	if cause == causeOperatorInitiated {
+		log.Info("Restart was requested through the restartedAt annotation, not emitting a warning",
+			"pod", pod.Name, "container", cs.Name)
+	} else if !(planned && r.SuppressPlannedRestartEvents) || severity == severityCritical {
+		eventReason := EventReasonContainerRestarted
+		if reason == "OOMKilled" {
+			eventReason = EventReasonOOMKilledRestart
+		}
+		r.warnPod(pod, eventReason, "Container %s restarted (reason: %s, exit code: %s, restart count: %d)",
+			cs.Name, reason, exitCode, cs.RestartCount)
+	}
+}
+
+// isPlannedRestart reports whether a restart that finished at the given time
+// closely follows a cordon/drain of the pod's node.
+func (r *PodMonitorReconciler) isPlannedRestart(pod *corev1.Pod, finishedAt time.Time) bool {
+	if isPlannedDisruption(pod) {
+		return true
+	}
+	if r.drainTracker == nil {
+		return false
+	}
+	return r.drainTracker.recentlyCordoned(pod.Spec.NodeName, finishedAt)
 }
 
 // reconcileSecret 处理 Secret 相关的逻辑
@@ -275,73 +862,250 @@ func (r *PodMonitorReconciler) reconcileSecret(ctx context.Context, req ctrl.Req
 	var secret corev1.Secret
 	if err := r.Get(ctx, req.NamespacedName, &secret); err != nil {
 		if client.IgnoreNotFound(err) != nil {
+			if isRetriableAPIError(err) {
+				log.V(1).Info("API server throttled or timed out, retrying later", "error", err.Error())
+				return ctrl.Result{RequeueAfter: apiErrorRequeueAfter(err)}, nil
+			}
 			log.Error(err, "unable to fetch secret")
 			return ctrl.Result{}, err
 		}
 		// 如果 Secret 已被删除，清理相关指标
-		certificateExpirationTime.DeletePartialMatch(prometheus.Labels{
-			"namespace":   req.Namespace,
-			"secret_name": req.Name,
-		})
-		certificateDaysUntilExpiration.DeletePartialMatch(prometheus.Labels{
-			"namespace":   req.Namespace,
-			"secret_name": req.Name,
-		})
+		r.forgetDeletedSecret(ctx, req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
+	stateStore.trackSecret(req.Namespace, req.Name)
+
+	// 刚检查过且未变化的 Secret 跳过随后到来的定期检查
+	if requeueAfter, ok := r.secretRechecks.skip(req.NamespacedName, secret.ResourceVersion, r.now()); ok {
+		log.V(1).Info("Skipping recheck coalesced with a recent check", "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// 先解析命名空间策略，同时刷新静默窗口，之后才发出事件
+	policy := r.policyFor(ctx, secret.Namespace)
+
+	// 统计 Secret 数据总大小
+	var dataSize int64
+	for _, value := range secret.Data {
+		dataSize += int64(len(value))
+	}
+	secretDataSizeBytes.With(prometheus.Labels{
+		"namespace":   req.Namespace,
+		"secret_name": req.Name,
+	}).Set(float64(dataSize))
+	// 只在超过阈值时发出一次事件，降回阈值以下后再次超过时重新发出
+	large := r.SecretSizeWarnThreshold > 0 && dataSize > r.SecretSizeWarnThreshold
+	if stateStore.setSecretLarge(req.Namespace, req.Name, large) {
+		r.eventf(&secret, corev1.EventTypeWarning, EventReasonSecretSizeLarge,
+			"Secret data is %d bytes, above the warning threshold of %d bytes", dataSize, r.SecretSizeWarnThreshold)
+	}
+
+	// 统计 Secret 中所有可解析的证书数量
+	secretCertCount.With(prometheus.Labels{
+		"namespace":   req.Namespace,
+		"secret_name": req.Name,
+	}).Set(float64(r.countSecretCertificates(&secret)))
+
+	// 类型要求的证书键缺失或为空时单独上报，例如 cert-manager 续期失败
+	updateSecretMissingCertKey(&secret)
 
 	// 检查证书数据
-	// 优先检查 tls.crt（Kubernetes TLS Secret 的标准格式）
-	if tlsCrt, exists := secret.Data["tls.crt"]; exists {
+	// 注解中声明的证书键会替换默认的键列表
+	secretMissingKey.DeletePartialMatch(prometheus.Labels{
+		"namespace":   req.Namespace,
+		"secret_name": req.Name,
+	})
+	if keys, ok := annotatedCertificateKeys(&secret); ok {
+		r.checkAnnotatedCertificates(ctx, &secret, keys)
+	} else if r.isEtcdSecret(req.Namespace, req.Name) {
+		// etcd 证书使用多种键名，检查所有 .crt / .pem 键
+		r.checkEtcdCertificates(ctx, &secret)
+	} else if r.isKubeadmObject(req.Namespace, req.Name) {
+		// kubeadm-certs 中每个 .crt 键对应一个控制平面组件
+		r.checkKubeadmSecret(ctx, &secret)
+	} else if tlsCrt, exists := secret.Data["tls.crt"]; exists {
+		// 优先检查 tls.crt（Kubernetes TLS Secret 的标准格式）
 		if err := r.checkCertificateExpiration(ctx, req.Namespace, req.Name, "tls.crt", tlsCrt); err != nil {
 			log.Error(err, "Failed to check certificate expiration", "key", "tls.crt")
 		}
 	} else {
 		// 如果没有 tls.crt，检查其他常见的证书文件
 		certificateKeys := []string{"crt.pem", "cert.pem", "ca.crt", "issuer.crt", "ca.pem", "issuer.pem"}
+		found := false
 		for _, key := range certificateKeys {
 			if data, exists := secret.Data[key]; exists {
 				if err := r.checkCertificateExpiration(ctx, req.Namespace, req.Name, key, data); err != nil {
 					log.Error(err, "Failed to check certificate expiration", "key", key)
 				}
 				// 只处理找到的第一个证书文件
+				found = true
 				break
 			}
 		}
+		// 没有已知的证书键时，自动发现模式检查其余的证书键
+		if !found && r.AutoDiscoverCerts {
+			r.checkAutoDiscoveredCertificates(ctx, &secret)
+		}
 	}
 
-	// 定期重新检查，每小时一次
-	return ctrl.Result{RequeueAfter: time.Hour}, nil
-}
+	// 检查 Java KeyStore（.jks）格式的证书库
+	for _, key := range sortedDataKeys(&secret) {
+		if !strings.HasSuffix(key, ".jks") {
+			continue
+		}
+		if err := r.checkJKSCertificates(ctx, &secret, key, secret.Data[key]); err != nil {
+			log.Error(err, "Failed to check JKS certificates", "key", key)
+		}
+	}
 
-// parseCertificateFromPEM parses a PEM encoded certificate and returns the x509 certificate
-func parseCertificateFromPEM(pemData []byte) (*x509.Certificate, error) {
-	block, _ := pem.Decode(pemData)
-	if block == nil {
-		return nil, fmt.Errorf("failed to parse PEM block")
+	// 可选：校验证书是否覆盖引用它的 Ingress 主机名
+	if r.ValidateCertificateHostnames {
+		if err := r.reconcileSecretWithHostnameValidation(ctx, &secret); err != nil {
+			log.Error(err, "Failed to validate certificate hostnames")
+		}
 	}
 
-	cert, err := x509.ParseCertificate(block.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	// 按命名空间策略的告警天数对即将过期的证书发出事件
+	r.checkCertificateSeverity(&secret, policy, r.now())
+	// 刷新命名空间的证书健康分数，全部证书都已 critical 时发出事件
+	r.checkNamespaceCertHealth(&secret, policy, r.now())
+
+	// 可选：将证书过期信息写入 Secret 注解，便于 kubectl describe 查看
+	if err := r.syncSecretAnnotations(ctx, &secret, r.now()); err != nil {
+		log.Error(err, "Failed to update secret annotations")
+		return ctrl.Result{}, err
 	}
 
-	return cert, nil
+	// 手动触发的重新检查已完成，移除 force-refresh 注解
+	if err := r.acknowledgeForceRefresh(ctx, &secret); err != nil {
+		log.Error(err, "Failed to remove force-refresh annotation")
+		return ctrl.Result{}, err
+	}
+
+	// 定期重新检查，约每小时一次，各 Secret 的检查时间相互错开
+	return ctrl.Result{RequeueAfter: r.secretRechecks.schedule(req.NamespacedName, secret.ResourceVersion, r.now())}, nil
+}
+
+// forgetSecretCertificates deletes the metrics and state of a secret that no
+// longer exists.
+func forgetSecretCertificates(namespace, secretName string) {
+	certificateExpirationTime.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateDaysUntilExpiration.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateHostnameMismatch.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	secretDataSizeBytes.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	secretMissingKey.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	secretMissingCertKey.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	secretCertCount.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateRotationDetectedTotal.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateInfo.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateChainExpirationTime.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateFingerprintInfo.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	certificateIssuedByUnknownCA.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	linkerdCertificateExpirationTime.DeletePartialMatch(prometheus.Labels{
+		"namespace":   namespace,
+		"secret_name": secretName,
+	})
+	stateStore.forgetSecret(namespace, secretName)
+}
+
+// forgetDeletedSecret drops the metrics and state of a deleted secret and
+// rescores the remaining certificates of its namespace.
+func (r *PodMonitorReconciler) forgetDeletedSecret(ctx context.Context, key types.NamespacedName) {
+	logf.FromContext(ctx).V(1).Info("Secret deleted, cleaning up metrics and state", "namespace", key.Namespace,
+		"secret", key.Name)
+	forgetSecretCertificates(key.Namespace, key.Name)
+	r.secretRechecks.forget(key)
+	r.SlackNotifier.forgetSecret(key.Namespace, key.Name)
+	// 剩余证书的健康分数按命名空间策略重新计算
+	updateNamespaceCertHealth(key.Namespace, r.policyFor(ctx, key.Namespace).CertCriticalDays, r.now())
+}
+
+// sortedDataKeys returns the data keys of a secret in a stable order
+func sortedDataKeys(secret *corev1.Secret) []string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // checkCertificateExpiration checks the certificate expiration and updates metrics
 func (r *PodMonitorReconciler) checkCertificateExpiration(ctx context.Context, namespace, secretName, certType string, certData []byte) error {
 	log := logf.FromContext(ctx)
 
-	cert, err := parseCertificateFromPEM(certData)
-	if err != nil {
+	// 过大的值直接跳过（已在 countSecretCertificates 中计数）
+	if int64(len(certData)) > r.maxSecretKeySize() {
+		log.Info("Skipping oversized secret key", "namespace", namespace, "secret", secretName,
+			"certType", certType, "size", len(certData))
+		return nil
+	}
+
+	// 同一个键中可能同时写入证书与私钥，顺序不定；以叶子证书作为主要的过期指标
+	cert, chain, err := r.parseLeafCertificate(certData)
+	if errors.Is(err, certparse.ErrNotCertificateData) {
+		// 非 PEM 也非 DER 的数据不记录错误堆栈，避免每小时刷屏
+		log.Info("Skipping secret key that does not contain a certificate", "namespace", namespace,
+			"secret", secretName, "certType", certType)
+		return nil
+	}
+	if cert == nil {
 		log.Error(err, "Failed to parse certificate", "namespace", namespace, "secret", secretName, "certType", certType)
 		return err
 	}
+	if err != nil {
+		log.Info("Ignoring malformed certificates after the first ones", "namespace", namespace,
+			"secret", secretName, "certType", certType, "error", err.Error())
+	}
+
+	r.recordCertificateExpiration(ctx, namespace, secretName, certType, cert)
+	recordCertificateChain(namespace, secretName, certType, chain)
+	return nil
+}
+
+// recordCertificateExpiration updates the expiration metrics of a parsed certificate
+func (r *PodMonitorReconciler) recordCertificateExpiration(ctx context.Context, namespace, secretName, certType string, cert *x509.Certificate) {
+	log := logf.FromContext(ctx)
 
 	// Calculate expiration time and days until expiration
 	expirationTime := cert.NotAfter
-	now := time.Now()
+	now := r.now()
 	daysUntilExpiration := expirationTime.Sub(now).Hours() / 24
 
 	log.Info("Certificate expiration info",
@@ -352,28 +1116,146 @@ func (r *PodMonitorReconciler) checkCertificateExpiration(ctx context.Context, n
 		"daysUntilExpiration", daysUntilExpiration)
 
 	// Update metrics
+	source := r.certificateSource(namespace, secretName)
+	component := r.certificateComponent(namespace, secretName, certType)
 	certificateExpirationTime.With(prometheus.Labels{
 		"namespace":   namespace,
 		"secret_name": secretName,
 		"cert_type":   certType,
+		"source":      source,
+		"component":   component,
 	}).Set(float64(expirationTime.Unix()))
 
 	certificateDaysUntilExpiration.With(prometheus.Labels{
 		"namespace":   namespace,
 		"secret_name": secretName,
 		"cert_type":   certType,
+		"source":      source,
+		"component":   component,
 	}).Set(daysUntilExpiration)
 
-	return nil
-}
+	// 记录证书是否为 CA、是否自签名
+	recordCertificateInfo(namespace, secretName, certType, cert)
+	recordCertificateFingerprint(namespace, secretName, certType, cert)
+	// 可选：检查签发者是否在受信任的 CA 列表中
+	r.recordCertificateIssuer(namespace, secretName, certType, cert)
+
+	// 可选：证书进入 critical 窗口时发送 Slack 通知
+	r.notifyCertificateCritical(ctx, namespace, secretName, certType, expirationTime, daysUntilExpiration)
 
+	// Linkerd 控制平面的证书附带控制平面版本
+	r.recordLinkerdCertificate(ctx, namespace, secretName, certType, float64(expirationTime.Unix()))
+
+	// 证书 NotAfter 变化时记录一次轮换
+	r.detectCertificateRotation(ctx, namespace, secretName, certType, expirationTime, now)
+}
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PodMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Pod{}).
-		// 监听所有 Secret 对象
-		Watches(&corev1.Secret{}, &handler.EnqueueRequestForObject{}).
-		Named("podmonitor").
-		Complete(r)
+	r.Client = newInstrumentedClient(r.Client, apiServerRequestsTotal)
+	if !r.disableNodeTopology {
+		r.topologyCache = newNodeTopologyCache(nodeTopologyTTL)
+	}
+	r.criticalityCache = newWorkloadCriticalityCache(workloadCriticalityTTL)
+	r.apiReader = mgr.GetAPIReader()
+	r.apiBreaker = newAPICircuitBreaker(r.APIErrorThreshold, r.APIBackoffCoolOff)
+	r.secretRechecks = newSecretRecheckScheduler(secretRecheckInterval, r.SecretRecheckJitter, r.randFloat64())
+	if r.UseMetricsAPI {
+		r.podMetrics = newPodMetricsReader(mgr.GetConfig())
+	}
+	stateStore.configureHistory(r.HistorySize, r.HistoryPerContainer)
+	stateStore.configureRestartWindow(r.RestartWindow)
+	stateStore.configureFailureReasonWindow(r.FailureReasonWindow)
+
+	filter := r.watchFilter()
+	b := ctrl.NewControllerManagedBy(mgr).
+		// 删除事件携带 Pod 的最终状态，在此记录删除前设置的 DisruptionTarget 条件，
+		// 并保留最终状态，供 reconcile 时 Pod 已不存在的情况补记终止
+		For(&corev1.Pod{}, builder.WithPredicates(watchFilterPredicate(filter.AllowPod), podDisruptionPredicate(),
+			podTombstonePredicate()))
+
+	if !r.DisableSecretWatch {
+		// 监听 WatchFilter 和配置允许的 Secret；更新事件只在数据、注解变化或 force-refresh 时触发
+		var err error
+		if b, err = r.setupSecretWatch(mgr, b, filter); err != nil {
+			return err
+		}
+	}
+	r.logSecretWatch(mgr.GetLogger().WithName("podmonitor"), filter)
+
+	if r.LinkerdMode && !r.DisableSecretWatch && !r.disableLinkerdNamespaceWatch {
+		// 控制平面版本注解变化时重新检查 issuer 证书
+		b = b.Watches(&corev1.Namespace{}, r.linkerdNamespaceHandler())
+	}
+
+	if !r.DisableNodeDrainTracking {
+		// 监听 Node 的 cordon 状态，仅更新缓存，不触发 reconcile
+		r.drainTracker = newNodeDrainTracker(r.DrainCorrelationWindow)
+		b = b.Watches(&corev1.Node{}, r.drainTracker.eventHandler())
+	}
+
+	if r.ValidateCertificateHostnames {
+		// Ingress 的 TLS 配置变化时，重新校验其引用的 Secret
+		b = b.Watches(&networkingv1.Ingress{}, handler.EnqueueRequestsFromMapFunc(ingressTLSSecrets))
+	}
+
+	if r.EnableNodeWatch {
+		// 仅在节点条件变化时 reconcile，心跳更新被过滤
+		b = b.Watches(&corev1.Node{}, &handler.EnqueueRequestForObject{},
+			builder.WithPredicates(nodeConditionsPredicate()))
+	}
+
+	if r.WatchNodes {
+		// 节点 Ready 状态变化只更新缓存与指标，不触发 reconcile
+		r.readyTracker = newNodeReadyTracker(r.DrainCorrelationWindow)
+		b = b.Watches(&corev1.Node{}, r.readyTracker.eventHandler())
+	}
+
+	if r.WatchProbeEvents {
+		// 存活探针失败事件只更新缓存，不触发 reconcile
+		r.livenessTracker = newLivenessTracker(r.LivenessCorrelationWindow)
+		b = b.Watches(&corev1.Event{}, r.livenessTracker.eventHandler())
+	}
+
+	if r.WatchPodDisruptionBudgets {
+		// PDB 变化时只更新指标，不触发 reconcile
+		b = b.Watches(&policyv1.PodDisruptionBudget{}, pdbEventHandler())
+	}
+
+	if r.KubeadmMode {
+		// kubeadm 对象定期直接从 API server 读取，不缓存集群中的所有 ConfigMap
+		if err := mgr.Add(newKubeadmCertificateChecker(r, mgr.GetAPIReader(), kubeadmCheckInterval)); err != nil {
+			return err
+		}
+	}
+	if r.PatchWorkloadConditions {
+		r.workloadConditions = newWorkloadConditionTracker()
+		if err := mgr.Add(newWorkloadConditionClearer(r, workloadConditionCheckInterval)); err != nil {
+			return err
+		}
+	}
+	if r.EnableCSRWatch {
+		if err := r.setupCSRController(mgr); err != nil {
+			return err
+		}
+	}
+	if r.SlackNotifier != nil {
+		// 通知在单独的 goroutine 中发送，不阻塞 reconcile
+		if err := mgr.Add(r.SlackNotifier); err != nil {
+			return err
+		}
+	}
+	// 定期统计 informer 缓存中各资源的对象数
+	if err := mgr.Add(newCacheSizeReporter(mgr.GetCache(), cacheSizeInterval, r.cachedResourceLists())); err != nil {
+		return err
+	}
+
+	c, err := b.Named("podmonitor").
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.secretController = c
+	return nil
 }
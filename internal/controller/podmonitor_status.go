@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+)
+
+// podMonitorStatusInterval is the minimum time between two status updates of
+// the PodMonitors; reconciles in between do not update them.
+const podMonitorStatusInterval = 30 * time.Second
+
+// Condition types of PodMonitorStatus.
+const (
+	podMonitorConditionReady             = "Ready"
+	podMonitorConditionCertificatesValid = "CertificatesValid"
+)
+
+// podCount returns the number of pods of a namespace in the census.
+func (c *podPhaseCensus) podCount(namespace string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := 0
+	for key, n := range c.counts {
+		if strings.HasPrefix(key, namespace+"/") {
+			count += n
+		}
+	}
+	return count
+}
+
+// certificateCounts returns how many certificates of a namespace expire within
+// warnDays and how many have already expired.
+func (s *restartStateStore) certificateCounts(namespace string, warnDays int32, now time.Time) (int, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	warnBefore := now.Add(time.Duration(warnDays) * 24 * time.Hour)
+	expiringSoon, expired := 0, 0
+	for _, cert := range s.certificates {
+		switch {
+		case cert.Namespace != namespace:
+		case !cert.NotAfter.After(now):
+			expired++
+		case cert.NotAfter.Before(warnBefore):
+			expiringSoon++
+		}
+	}
+	return expiringSoon, expired
+}
+
+// namespaceSeries returns the number of series of a vec in a namespace.
+func namespaceSeries(c prometheus.Collector, namespace string) int {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	count := 0
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			continue
+		}
+		for _, label := range metric.GetLabel() {
+			if label.GetName() == "namespace" && label.GetValue() == namespace {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// UpdatePodMonitorStatus writes the monitoring summary of its namespace into
+// the status of every PodMonitor: the pod census, the secrets with a
+// pod_monitor_secret_data_size_bytes series and the certificates of the state
+// store, checked against the namespace's policy. PodMonitors whose summary
+// did not change are not updated.
+func (r *PodMonitorReconciler) UpdatePodMonitorStatus(ctx context.Context, now time.Time) error {
+	var monitors monitorv1alpha1.PodMonitorList
+	if err := r.List(ctx, &monitors); err != nil {
+		return fmt.Errorf("listing PodMonitors: %w", err)
+	}
+
+	var lastErr error
+	for i := range monitors.Items {
+		monitor := &monitors.Items[i]
+		namespace := monitor.Namespace
+		expiringSoon, expired := stateStore.certificateCounts(namespace,
+			r.policyFor(ctx, namespace).CertWarningDays, now)
+
+		previous := monitor.Status.DeepCopy()
+		status := &monitor.Status
+		status.MonitoredPodCount = int32(phaseCensus.podCount(namespace))
+		status.MonitoredSecretCount = int32(namespaceSeries(secretDataSizeBytes, namespace))
+		status.CertificatesExpiringSoon = int32(expiringSoon)
+		status.CertificatesExpired = int32(expired)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               podMonitorConditionReady,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: monitor.Generation,
+			Reason:             "Monitoring",
+			Message:            "The operator is monitoring the pods and secrets of the namespace",
+		})
+		certificates := metav1.Condition{
+			Type:               podMonitorConditionCertificatesValid,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: monitor.Generation,
+			Reason:             "NoneExpired",
+			Message:            "No monitored certificate has expired",
+		}
+		if expired > 0 {
+			certificates.Status = metav1.ConditionFalse
+			certificates.Reason = "CertificatesExpired"
+			certificates.Message = fmt.Sprintf("%d monitored certificates have expired", expired)
+		}
+		meta.SetStatusCondition(&status.Conditions, certificates)
+
+		// 只有时间变化时不更新，避免每 30 秒写一次所有 PodMonitor
+		if equality.Semantic.DeepEqual(previous, status) {
+			continue
+		}
+		status.LastReconcileTime = metav1.NewTime(now)
+		if err := r.Status().Update(ctx, monitor); err != nil {
+			// 冲突在下一次更新时自然恢复
+			logf.FromContext(ctx).V(1).Info("Unable to update PodMonitor status", "namespace", namespace,
+				"name", monitor.Name, "error", err.Error())
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// maybeUpdatePodMonitorStatus updates the PodMonitor statuses at most once
+// per podMonitorStatusInterval. Only one reconcile worker performs an update.
+func (r *PodMonitorReconciler) maybeUpdatePodMonitorStatus(ctx context.Context) {
+	if r.DisablePodMonitorStatus {
+		return
+	}
+	now := r.now()
+	last := r.podMonitorStatusUpdatedAt.Load()
+	if now.Sub(time.Unix(0, last)) < podMonitorStatusInterval ||
+		!r.podMonitorStatusUpdatedAt.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	if err := r.UpdatePodMonitorStatus(ctx, now); err != nil {
+		logf.FromContext(ctx).V(1).Info("Unable to update PodMonitor statuses", "error", err.Error())
+	}
+}
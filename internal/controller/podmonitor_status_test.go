@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestUpdatePodMonitorStatus(t *testing.T) {
+	const namespace = "podmonitor-status-test"
+	now := time.Now()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = monitorv1alpha1.AddToScheme(scheme)
+	monitor := &monitorv1alpha1.PodMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "summary"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(monitor).
+		WithStatusSubresource(&monitorv1alpha1.PodMonitor{}).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme}
+
+	phaseCensus.observe(namespace, "web-a", corev1.PodRunning)
+	phaseCensus.observe(namespace, "web-b", corev1.PodPending)
+	phaseCensus.observe("other", "web-c", corev1.PodRunning)
+	defer phaseCensus.forget(namespace, "web-a")
+	defer phaseCensus.forget(namespace, "web-b")
+	defer phaseCensus.forget("other", "web-c")
+
+	secretDataSizeBytes.WithLabelValues(namespace, "tls").Set(2048)
+	defer secretDataSizeBytes.DeleteLabelValues(namespace, "tls")
+	stateStore.recordCertificate(namespace, "tls", "tls.crt", now.Add(-time.Hour))
+	stateStore.recordCertificate(namespace, "tls", "ca.crt", now.Add(7*24*time.Hour))
+	stateStore.recordCertificate(namespace, "tls", "issuer.crt", now.Add(365*24*time.Hour))
+	defer stateStore.forgetSecret(namespace, "tls")
+
+	if err := r.UpdatePodMonitorStatus(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+
+	var updated monitorv1alpha1.PodMonitor
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(monitor), &updated); err != nil {
+		t.Fatal(err)
+	}
+	status := updated.Status
+	if status.MonitoredPodCount != 2 || status.MonitoredSecretCount != 1 {
+		t.Errorf("expected 2 pods and 1 secret, got %d pods and %d secrets",
+			status.MonitoredPodCount, status.MonitoredSecretCount)
+	}
+	if status.CertificatesExpired != 1 || status.CertificatesExpiringSoon != 1 {
+		t.Errorf("expected 1 expired and 1 expiring certificate, got %d and %d",
+			status.CertificatesExpired, status.CertificatesExpiringSoon)
+	}
+	if !status.LastReconcileTime.Time.Equal(now.Truncate(time.Second)) {
+		t.Errorf("expected the last reconcile time to be %v, got %v", now, status.LastReconcileTime)
+	}
+	if !meta.IsStatusConditionTrue(status.Conditions, podMonitorConditionReady) {
+		t.Errorf("expected the Ready condition to be true")
+	}
+	if !meta.IsStatusConditionFalse(status.Conditions, podMonitorConditionCertificatesValid) {
+		t.Errorf("expected the CertificatesValid condition to be false with an expired certificate")
+	}
+
+	// 汇总未变化时不更新状态
+	later := now.Add(time.Minute)
+	if err := r.UpdatePodMonitorStatus(context.Background(), later); err != nil {
+		t.Fatal(err)
+	}
+	var unchanged monitorv1alpha1.PodMonitor
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(monitor), &unchanged); err != nil {
+		t.Fatal(err)
+	}
+	if unchanged.ResourceVersion != updated.ResourceVersion {
+		t.Errorf("expected an unchanged summary not to update the status")
+	}
+}
+
+func TestPodMonitorStatusAfterSecretDeleted(t *testing.T) {
+	const namespace = "podmonitor-status-delete-test"
+	ctx := context.Background()
+	now := time.Now()
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = monitorv1alpha1.AddToScheme(scheme)
+	monitor := &monitorv1alpha1.PodMonitor{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "summary"}}
+	secret := testsupport.NewTLSSecret(namespace, "expired", testsupport.CertificateExpiringIn(t, now, -1))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(monitor, secret).
+		WithStatusSubresource(&monitorv1alpha1.PodMonitor{}).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme, DisablePodMonitorStatus: true}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secret)}
+	defer forgetSecretCertificates(namespace, "expired")
+	certificatesValid := func() bool {
+		t.Helper()
+		if err := r.UpdatePodMonitorStatus(ctx, now); err != nil {
+			t.Fatal(err)
+		}
+		var updated monitorv1alpha1.PodMonitor
+		if err := c.Get(ctx, client.ObjectKeyFromObject(monitor), &updated); err != nil {
+			t.Fatal(err)
+		}
+		return meta.IsStatusConditionTrue(updated.Status.Conditions, podMonitorConditionCertificatesValid)
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if certificatesValid() {
+		t.Fatal("expected CertificatesValid to be false with an expired certificate")
+	}
+
+	// 删除过期证书所在的 Secret 后条件恢复
+	if err := c.Delete(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if !certificatesValid() {
+		t.Error("expected CertificatesValid to clear once the secret is deleted")
+	}
+}
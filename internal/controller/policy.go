@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventsv1 "github.com/Deraiven/pod-monitor-operator/api/events/v1"
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=monitor.storehub.com,resources=podmonitorpolicies,verbs=get;list;watch
+
+// Built-in values used when neither a namespace policy nor the "default"
+// PodMonitorPolicy sets a field.
+const (
+	defaultCertWarningDays  = 30
+	defaultCertCriticalDays = 7
+)
+
+// monitorPolicy is the effective configuration for one namespace.
+type monitorPolicy struct {
+	// RestartAlertThreshold 为 0 时不发出阈值事件
+	RestartAlertThreshold int32
+	CertWarningDays       int32
+	CertCriticalDays      int32
+	ExcludedContainers    []string
+	MonitorInitContainers bool
+	Silences              []monitorv1alpha1.SilenceWindow
+	// Critical 由 pod-monitor.deraiven.io/critical 注解设置，每次重启都发出事件与通知
+	Critical bool
+}
+
+func builtinMonitorPolicy() monitorPolicy {
+	return monitorPolicy{
+		CertWarningDays:  defaultCertWarningDays,
+		CertCriticalDays: defaultCertCriticalDays,
+	}
+}
+
+// isExcluded reports whether restarts of the container are ignored.
+func (p monitorPolicy) isExcluded(container string) bool {
+	return slices.Contains(p.ExcludedContainers, container)
+}
+
+// containerStatuses returns the statuses checked for restarts: the regular
+// containers, plus the init containers when the policy enables them.
+func (p monitorPolicy) containerStatuses(pod *corev1.Pod) []corev1.ContainerStatus {
+	if !p.MonitorInitContainers {
+		return pod.Status.ContainerStatuses
+	}
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// apply overrides the policy with the fields set in spec.
+func (p monitorPolicy) apply(spec monitorv1alpha1.PodMonitorPolicySpec) monitorPolicy {
+	if spec.RestartAlertThreshold != nil {
+		p.RestartAlertThreshold = *spec.RestartAlertThreshold
+	}
+	if spec.CertWarningDays != nil {
+		p.CertWarningDays = *spec.CertWarningDays
+	}
+	if spec.CertCriticalDays != nil {
+		p.CertCriticalDays = *spec.CertCriticalDays
+	}
+	if spec.ExcludedContainers != nil {
+		p.ExcludedContainers = spec.ExcludedContainers
+	}
+	if spec.MonitorInitContainers != nil {
+		p.MonitorInitContainers = *spec.MonitorInitContainers
+	}
+	if spec.Silences != nil {
+		p.Silences = spec.Silences
+	}
+	return p
+}
+
+// policyFor returns the effective policy of a namespace: the built-in values,
+// overridden by the "default" PodMonitorPolicy, overridden by the policy that
+// lists the namespace. When several policies list it, the first by name wins.
+// If the policies cannot be listed (e.g. the CRD is not installed), the
+// built-in values are used, as they are when DisablePolicies is set.
+func (r *PodMonitorReconciler) policyFor(ctx context.Context, namespace string) monitorPolicy {
+	if r.DisablePolicies {
+		return builtinMonitorPolicy()
+	}
+
+	var policies monitorv1alpha1.PodMonitorPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		logf.FromContext(ctx).V(1).Info("Unable to list PodMonitorPolicies, using built-in defaults",
+			"error", err.Error())
+		return builtinMonitorPolicy()
+	}
+	// 记录各策略的静默窗口，在发出事件时判断
+	silences.update(policies.Items)
+	return resolvePolicy(policies.Items, namespace)
+}
+
+// resolvePolicy returns the effective policy of a namespace given all
+// policies; see policyFor.
+func resolvePolicy(policies []monitorv1alpha1.PodMonitorPolicy, namespace string) monitorPolicy {
+	policy := builtinMonitorPolicy()
+	var specific *monitorv1alpha1.PodMonitorPolicy
+	for i := range policies {
+		p := &policies[i]
+		if p.Name == monitorv1alpha1.DefaultPodMonitorPolicyName {
+			policy = policy.apply(p.Spec)
+			continue
+		}
+		if slices.Contains(p.Spec.Namespaces, namespace) && (specific == nil || p.Name < specific.Name) {
+			specific = p
+		}
+	}
+	if specific != nil {
+		policy = policy.apply(specific.Spec)
+	}
+	return policy
+}
+
+// checkRestartThreshold emits a Warning event when a container's restart
+// count reaches the policy threshold, and streams the crossing once when the
+// previously observed count was still below it, or on every restart of a
+// critical container.
+func (r *PodMonitorReconciler) checkRestartThreshold(pod *corev1.Pod, cs corev1.ContainerStatus, observed int32,
+	policy monitorPolicy) {
+	if policy.RestartAlertThreshold <= 0 || cs.RestartCount < policy.RestartAlertThreshold {
+		return
+	}
+	// critical 的容器每次重启都发送通知
+	if observed < policy.RestartAlertThreshold || policy.Critical {
+		r.publishRestartThresholdCrossed(pod.Namespace, &eventsv1.RestartThresholdCrossed{
+			Pod:          pod.Name,
+			Container:    cs.Name,
+			RestartCount: cs.RestartCount,
+			Threshold:    policy.RestartAlertThreshold,
+			Severity:     policy.severity(),
+		}, r.now())
+	}
+	r.warnPod(pod, EventReasonRestartStorm,
+		"Container %s restarted %d times, reaching the threshold of %d",
+		cs.Name, cs.RestartCount, policy.RestartAlertThreshold)
+}
+
+// checkCertificateSeverity emits a Warning event for the earliest expiring
+// certificate of a secret when it is within the policy warning or critical
+// window.
+func (r *PodMonitorReconciler) checkCertificateSeverity(secret *corev1.Secret, policy monitorPolicy, now time.Time) {
+	notAfter, ok := stateStore.earliestCertificate(secret.Namespace, secret.Name)
+	if !ok {
+		return
+	}
+
+	days := int32(notAfter.Sub(now).Hours() / 24)
+	var severity eventsv1.CertificateSeverity
+	switch {
+	case notAfter.Before(now):
+		severity = eventsv1.CertificateSeverity_CERTIFICATE_SEVERITY_CRITICAL
+		r.eventf(secret, corev1.EventTypeWarning, EventReasonCertificateExpired,
+			"Certificate expired %d days ago", -days)
+	case days < policy.CertCriticalDays:
+		severity = eventsv1.CertificateSeverity_CERTIFICATE_SEVERITY_CRITICAL
+		r.eventf(secret, corev1.EventTypeWarning, EventReasonCertificateCritical,
+			"Certificate expires in %d days (critical below %d days)", days, policy.CertCriticalDays)
+	case days < policy.CertWarningDays:
+		severity = eventsv1.CertificateSeverity_CERTIFICATE_SEVERITY_WARNING
+		r.eventf(secret, corev1.EventTypeWarning, EventReasonCertificateExpiring,
+			"Certificate expires in %d days (warning below %d days)", days, policy.CertWarningDays)
+	default:
+		return
+	}
+
+	r.publishCertificateWarning(secret.Namespace, &eventsv1.CertificateWarning{
+		Secret:              secret.Name,
+		NotAfter:            timestamppb.New(notAfter),
+		DaysUntilExpiration: days,
+		Severity:            severity,
+	}, now)
+}
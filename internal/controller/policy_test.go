@@ -0,0 +1,142 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestPolicyForMergesDefaultAndNamespacePolicies(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := monitorv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		&monitorv1alpha1.PodMonitorPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: monitorv1alpha1.DefaultPodMonitorPolicyName},
+			Spec: monitorv1alpha1.PodMonitorPolicySpec{
+				RestartAlertThreshold: ptr.To[int32](5),
+				CertWarningDays:       ptr.To[int32](14),
+			},
+		},
+		&monitorv1alpha1.PodMonitorPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "b-batch"},
+			Spec: monitorv1alpha1.PodMonitorPolicySpec{
+				Namespaces:            []string{"batch"},
+				RestartAlertThreshold: ptr.To[int32](50),
+			},
+		},
+		&monitorv1alpha1.PodMonitorPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-batch"},
+			Spec: monitorv1alpha1.PodMonitorPolicySpec{
+				Namespaces:            []string{"batch"},
+				RestartAlertThreshold: ptr.To[int32](20),
+				ExcludedContainers:    []string{"istio-proxy"},
+				MonitorInitContainers: ptr.To(true),
+			},
+		},
+	).Build()
+	r := &PodMonitorReconciler{Client: c}
+
+	got := r.policyFor(context.Background(), "batch")
+	if got.RestartAlertThreshold != 20 {
+		t.Errorf("expected the first namespace policy by name to win, got threshold %d", got.RestartAlertThreshold)
+	}
+	if got.CertWarningDays != 14 || got.CertCriticalDays != defaultCertCriticalDays {
+		t.Errorf("expected unset fields to fall back to default policy and built-ins, got %+v", got)
+	}
+	if !got.isExcluded("istio-proxy") || !got.MonitorInitContainers {
+		t.Errorf("expected namespace overrides to apply, got %+v", got)
+	}
+
+	got = r.policyFor(context.Background(), "web")
+	if got.RestartAlertThreshold != 5 || got.isExcluded("istio-proxy") {
+		t.Errorf("expected the default policy for an unlisted namespace, got %+v", got)
+	}
+
+	// CRD 未安装时回退到内置默认值
+	r = &PodMonitorReconciler{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+	if got := r.policyFor(context.Background(), "batch"); got.CertWarningDays != defaultCertWarningDays ||
+		got.RestartAlertThreshold != 0 {
+		t.Errorf("expected built-in defaults when policies cannot be listed, got %+v", got)
+	}
+}
+
+func TestCertificateSeverityEventReasons(t *testing.T) {
+	const namespace = "certificate-severity-test"
+	clock := clocktesting.NewFakePassiveClock(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	policy := monitorPolicy{CertWarningDays: 30, CertCriticalDays: 7}
+	secret := testsupport.NewSecret(namespace, "web-tls", nil)
+	defer certificateExpirationTime.Reset()
+	defer certificateDaysUntilExpiration.Reset()
+	defer certificateInfo.Reset()
+	defer certificateChainExpirationTime.Reset()
+	defer stateStore.forgetSecret(namespace, "web-tls")
+
+	tests := []struct {
+		days   int
+		reason string
+	}{
+		{-2, EventReasonCertificateExpired},
+		{3, EventReasonCertificateCritical},
+		{6, EventReasonCertificateCritical},
+		// 阈值按整天比较，正好 7 天时已不再是 critical
+		{7, EventReasonCertificateExpiring},
+		{20, EventReasonCertificateExpiring},
+		{29, EventReasonCertificateExpiring},
+		{30, ""},
+		{365, ""},
+	}
+	for _, tt := range tests {
+		stateStore.forgetSecret(namespace, "web-tls")
+		recorder := record.NewFakeRecorder(1)
+		r := &PodMonitorReconciler{Recorder: recorder, Clock: clock}
+		cert := testsupport.CertificateExpiringIn(t, clock.Now(), tt.days, "web.example.com")
+		if err := r.checkCertificateExpiration(context.Background(), namespace, "web-tls", "tls.crt",
+			cert.CertPEM()); err != nil {
+			t.Fatal(err)
+		}
+
+		r.checkCertificateSeverity(secret, policy, clock.Now())
+		if tt.reason == "" {
+			if len(recorder.Events) != 0 {
+				t.Errorf("%d days: expected no event, got %q", tt.days, <-recorder.Events)
+			}
+			continue
+		}
+		if len(recorder.Events) != 1 {
+			t.Fatalf("%d days: expected one event for %s", tt.days, tt.reason)
+		}
+		if event := <-recorder.Events; !strings.Contains(event, " "+tt.reason+" ") {
+			t.Errorf("%d days: expected reason %s, got %q", tt.days, tt.reason, event)
+		}
+	}
+}
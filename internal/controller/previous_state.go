@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxPreviousContainerIDLength bounds the previous_container_id label.
+const maxPreviousContainerIDLength = 32
+
+var (
+	// 上一个已终止容器实例的信息，值恒为 1，每个容器最多一条序列
+	containerPreviousStateInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_previous_state_info",
+			Help: "Information about the previous, terminated instance of a container. The value is always 1.",
+		},
+		[]string{
+			"namespace",             // Pod 所在命名空间
+			"pod",                   // Pod 名称
+			"container",             // 容器名称
+			"previous_container_id", // 上一个实例的容器 ID（去掉运行时前缀，截断为 32 个字符）
+			"started_at_timestamp",  // 上一个实例的启动时间（Unix 秒）
+			"finished_at_timestamp", // 上一个实例的终止时间（Unix 秒）
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerPreviousStateInfo))
+}
+
+// shortContainerID strips the runtime prefix (e.g. "containerd://") from a
+// container ID and truncates it.
+func shortContainerID(id string) string {
+	if i := strings.Index(id, "://"); i >= 0 {
+		id = id[i+len("://"):]
+	}
+	if len(id) > maxPreviousContainerIDLength {
+		id = id[:maxPreviousContainerIDLength]
+	}
+	return id
+}
+
+// updatePreviousStateInfo keeps one series per container describing its last
+// terminated instance, replacing it when the container terminates again.
+func updatePreviousStateInfo(b *metricBatch, pod *corev1.Pod) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		b.deletePartial(containerPreviousStateInfo.MetricVec, prometheus.Labels{
+			"namespace": pod.Namespace,
+			"pod":       pod.Name,
+			"container": cs.Name,
+		})
+		terminated := cs.LastTerminationState.Terminated
+		if terminated == nil {
+			continue
+		}
+		b.set(containerPreviousStateInfo, 1, pod.Namespace, pod.Name, cs.Name,
+			shortContainerID(terminated.ContainerID),
+			strconv.FormatInt(terminated.StartedAt.Unix(), 10),
+			strconv.FormatInt(terminated.FinishedAt.Unix(), 10))
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPreviousStateInfoReplacedOnNewTermination(t *testing.T) {
+	const namespace = "previous-state-test"
+	started := time.Unix(1735732800, 0)
+	terminatedAt := func(id string, finished time.Time) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ContainerID: "containerd://" + id,
+			StartedAt:   metav1.NewTime(started),
+			FinishedAt:  metav1.NewTime(finished),
+		}}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", LastTerminationState: terminatedAt(strings.Repeat("a", 64), started.Add(time.Minute))},
+			{Name: "sidecar"},
+		}},
+	}
+	update := func() {
+		var batch metricBatch
+		updatePreviousStateInfo(&batch, pod)
+		stateStore.commitMetrics(&batch)
+	}
+	defer containerPreviousStateInfo.Reset()
+
+	update()
+	// 没有终止记录的容器不导出序列
+	if n := testutil.CollectAndCount(containerPreviousStateInfo); n != 1 {
+		t.Fatalf("expected 1 series, got %d", n)
+	}
+
+	pod.Status.ContainerStatuses[0].LastTerminationState = terminatedAt(strings.Repeat("b", 64), started.Add(time.Hour))
+	update()
+	if n := testutil.CollectAndCount(containerPreviousStateInfo); n != 1 {
+		t.Fatalf("expected the previous series to be replaced, got %d series", n)
+	}
+	got := testutil.ToFloat64(containerPreviousStateInfo.WithLabelValues(namespace, "web", "app",
+		strings.Repeat("b", maxPreviousContainerIDLength), "1735732800", "1735736400"))
+	if got != 1 {
+		t.Fatalf("expected the series of the latest termination, got %v", got)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// 启动自检发现缺失的 RBAC 权限时为 1，已授予时为 0
+	rbacPermissionMissing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_rbac_permission_missing",
+			Help: "Whether the startup RBAC self-check found the permission missing (1) or granted (0)",
+		},
+		[]string{
+			"resource",  // 资源，例如 pods
+			"verb",      // 动作，例如 list
+			"namespace", // 命名空间；为空表示所有命名空间
+		},
+	)
+)
+
+func init() {
+	registerMetrics(rbacPermissionMissing)
+}
+
+// Permission is an API access the operator needs. An empty Namespace means
+// all namespaces.
+type Permission struct {
+	Group     string
+	Resource  string
+	Verb      string
+	Namespace string
+}
+
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.Group != "" {
+		resource = p.Resource + "." + p.Group
+	}
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s", p.Verb, resource)
+	}
+	return fmt.Sprintf("%s %s in namespace %s", p.Verb, resource, p.Namespace)
+}
+
+// RequiredPermissions returns the permissions without which pods or secrets
+// cannot be reconciled.
+func RequiredPermissions() []Permission {
+	var permissions []Permission
+	for _, resource := range []string{"pods", "secrets"} {
+		for _, verb := range []string{"get", "list", "watch"} {
+			permissions = append(permissions, Permission{Resource: resource, Verb: verb})
+		}
+	}
+	return permissions
+}
+
+// CheckPermissions asks the API server, through SelfSubjectAccessReviews,
+// whether the operator has each permission, exports
+// pod_monitor_rbac_permission_missing and returns the missing ones. Creating
+// SelfSubjectAccessReviews is allowed to every authenticated user.
+func CheckPermissions(ctx context.Context, c client.Client, permissions []Permission) ([]Permission, error) {
+	var missing []Permission
+	for _, p := range permissions {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Group:     p.Group,
+					Resource:  p.Resource,
+					Verb:      p.Verb,
+					Namespace: p.Namespace,
+				},
+			},
+		}
+		if err := c.Create(ctx, review); err != nil {
+			return missing, fmt.Errorf("checking permission to %s: %w", p, err)
+		}
+		if review.Status.Allowed {
+			rbacPermissionMissing.WithLabelValues(p.Resource, p.Verb, p.Namespace).Set(0)
+			continue
+		}
+		rbacPermissionMissing.WithLabelValues(p.Resource, p.Verb, p.Namespace).Set(1)
+		missing = append(missing, p)
+	}
+	return missing, nil
+}
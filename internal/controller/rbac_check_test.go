@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestCheckPermissionsReportsMissing(t *testing.T) {
+	// 模拟只授予了 pods 的权限，未授予 secrets 的 watch
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectAccessReview)
+			attrs := review.Spec.ResourceAttributes
+			review.Status.Allowed = attrs.Resource == "pods" || attrs.Verb != "watch"
+			return nil
+		},
+	}).Build()
+	defer rbacPermissionMissing.Reset()
+
+	missing, err := CheckPermissions(context.Background(), c, RequiredPermissions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || missing[0] != (Permission{Resource: "secrets", Verb: "watch"}) {
+		t.Fatalf("expected only watch secrets to be missing, got %v", missing)
+	}
+	if got := testutil.ToFloat64(rbacPermissionMissing.WithLabelValues("secrets", "watch", "")); got != 1 {
+		t.Errorf("expected the missing permission to be exported as 1, got %v", got)
+	}
+	if got := testutil.ToFloat64(rbacPermissionMissing.WithLabelValues("pods", "list", "")); got != 0 {
+		t.Errorf("expected a granted permission to be exported as 0, got %v", got)
+	}
+}
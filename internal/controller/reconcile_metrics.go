@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Values of the controller label of the reconcile metrics. Pods, secrets and
+// nodes share one controller-runtime controller, so its own metrics cannot
+// tell them apart.
+const (
+	reconcileControllerPod    = "pod"
+	reconcileControllerSecret = "secret"
+	reconcileControllerNode   = "node"
+	reconcileControllerCSR    = "csr"
+)
+
+var (
+	// 正在进行中的 reconcile 数量；长期等于 MaxConcurrentReconciles 说明 worker 不足
+	reconciliationsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_reconciliations_in_flight",
+			Help: "Number of reconciliations currently running, by the kind of object reconciled",
+		},
+		[]string{
+			"controller", // pod、secret、node 或 csr
+		},
+	)
+)
+
+func init() {
+	registerMetrics(reconciliationsInFlight)
+	// 预先创建序列，空闲时也导出 0
+	reconciliationsInFlight.WithLabelValues(reconcileControllerPod)
+	reconciliationsInFlight.WithLabelValues(reconcileControllerSecret)
+	reconciliationsInFlight.WithLabelValues(reconcileControllerNode)
+	reconciliationsInFlight.WithLabelValues(reconcileControllerCSR)
+}
+
+// trackInFlight counts a reconciliation as running until the returned
+// function is called.
+func trackInFlight(controller string) func() {
+	gauge := reconciliationsInFlight.WithLabelValues(controller)
+	gauge.Inc()
+	return gauge.Dec
+}
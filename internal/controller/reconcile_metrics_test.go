@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestReconciliationsInFlight(t *testing.T) {
+	const namespace = "in-flight-test"
+	ctx := context.Background()
+	inFlight := func() float64 {
+		return testutil.ToFloat64(reconciliationsInFlight.WithLabelValues(reconcileControllerPod))
+	}
+
+	// 在 reconcile 读取 Pod 时记录进行中的数量；broken 的读取失败
+	var during []float64
+	pod := testsupport.NewPod(namespace, "web").Build()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+				opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Pod); ok {
+					during = append(during, inFlight())
+					if key.Name == "broken" {
+						return apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, key.Name, nil)
+					}
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, DisableSecretWatch: true,
+		DisablePodMonitorStatus: true}
+	web := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "web"}}
+	broken := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "broken"}}
+	defer func() {
+		_ = c.Delete(ctx, pod)
+		_, _ = r.Reconcile(ctx, web)
+		observeObjectResult(reconcileControllerPod, broken.NamespacedName, nil)
+	}()
+
+	if _, err := r.Reconcile(ctx, web); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, broken); err == nil {
+		t.Fatal("expected the failed Pod read to be returned")
+	}
+
+	if len(during) != 2 || during[0] != 1 || during[1] != 1 {
+		t.Errorf("expected one reconcile in flight while each ran, got %v", during)
+	}
+	// 成功与失败的 reconcile 返回后都不再计入
+	if got := inFlight(); got != 0 {
+		t.Errorf("expected no reconcile in flight after returning, got %v", got)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// exitCodeHistorySize is the number of recent exit codes kept per container.
+	exitCodeHistorySize = 8
+	// repeatedExitCodeMinRun is the run length from which the gauge is exported.
+	repeatedExitCodeMinRun = 3
+)
+
+var (
+	// 容器连续以同一非零退出码结束的次数；通常意味着确定性的 bug 而非偶发故障
+	containerRepeatedExitCode = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_repeated_exit_code",
+			Help: "Number of consecutive terminations of a container with the same non-zero exit code. " +
+				"Only exported from 3 in a row; removed when the streak is broken.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"exit_code", // 重复出现的退出码
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerRepeatedExitCode))
+}
+
+// exitCodeRing is a fixed-size ring of the most recent exit codes of a
+// container, oldest first once full.
+type exitCodeRing struct {
+	codes []int32
+	next  int
+}
+
+func (r *exitCodeRing) add(code int32) {
+	if len(r.codes) < exitCodeHistorySize {
+		r.codes = append(r.codes, code)
+		return
+	}
+	r.codes[r.next] = code
+	r.next = (r.next + 1) % exitCodeHistorySize
+}
+
+// latest returns the most recent exit code, or 0 for an empty ring.
+func (r *exitCodeRing) latest() int32 {
+	n := len(r.codes)
+	if n == 0 {
+		return 0
+	}
+	return r.codes[(r.next+n-1)%n]
+}
+
+// runLength returns how many of the most recent codes equal the latest one.
+// It saturates at the ring size.
+func (r *exitCodeRing) runLength() int {
+	n := len(r.codes)
+	if n == 0 {
+		return 0
+	}
+	latest := (r.next + n - 1) % n
+	run := 0
+	for i := 0; i < n; i++ {
+		if r.codes[(latest-i+n)%n] != r.codes[latest] {
+			break
+		}
+		run++
+	}
+	return run
+}
+
+// recordExitCode appends the exit code of a container termination and returns
+// the length of the current run of identical codes.
+func (s *restartStateStore) recordExitCode(key string, code int32) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ring, ok := s.exitCodes[key]
+	if !ok {
+		ring = &exitCodeRing{}
+		s.exitCodes[key] = ring
+	}
+	ring.add(code)
+	return ring.runLength()
+}
+
+// updateRepeatedExitCode records a termination and exports the run length of
+// its exit code once it reaches repeatedExitCodeMinRun. A different code or a
+// clean exit removes the series of the broken streak.
+func (r *PodMonitorReconciler) updateRepeatedExitCode(b *metricBatch, pod *corev1.Pod, container string,
+	exitCode int32) {
+	key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container)
+	run := stateStore.recordExitCode(key, exitCode)
+
+	// 每个容器最多一条序列：先删除旧的连续记录
+	b.deletePartial(containerRepeatedExitCode.MetricVec, prometheus.Labels{
+		"namespace": pod.Namespace,
+		"pod":       pod.Name,
+		"container": container,
+	})
+	if exitCode == 0 || run < repeatedExitCodeMinRun {
+		return
+	}
+	b.set(containerRepeatedExitCode, float64(run), pod.Namespace, pod.Name, container,
+		strconv.Itoa(int(exitCode)))
+
+	threshold := r.RepeatedExitCodeEventThreshold
+	if threshold > 0 && run == threshold {
+		r.warnPod(pod, EventReasonRepeatedExitCode,
+			"Container %s exited with code %d %d times in a row; this is likely a deterministic failure "+
+				"that restarts will not fix", container, exitCode, run)
+	}
+}
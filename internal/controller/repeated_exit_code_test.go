@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRepeatedExitCodeStreak(t *testing.T) {
+	const namespace = "repeated-exit-code-test"
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "app"}}
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Recorder: recorder, RepeatedExitCodeEventThreshold: 5}
+	defer func() {
+		stateStore.forgetPod(namespace, "app")
+		containerRepeatedExitCode.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+	}()
+
+	terminate := func(code int32) {
+		var batch metricBatch
+		r.updateRepeatedExitCode(&batch, pod, "main", code)
+		stateStore.commitMetrics(&batch)
+	}
+	series := func() int {
+		return testutil.CollectAndCount(containerRepeatedExitCode)
+	}
+
+	terminate(2)
+	terminate(2)
+	if n := series(); n != 0 {
+		t.Fatalf("expected no series below a run of 3, got %d", n)
+	}
+	terminate(2)
+	if got := testutil.ToFloat64(containerRepeatedExitCode.WithLabelValues(namespace, "app", "main", "2")); got != 3 {
+		t.Fatalf("expected a run length of 3, got %v", got)
+	}
+
+	terminate(2)
+	terminate(2)
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected one Warning event at the threshold, got %d", len(recorder.Events))
+	}
+
+	// 不同的退出码打断连续记录
+	terminate(1)
+	if n := series(); n != 0 {
+		t.Fatalf("expected the series to be removed when the streak breaks, got %d", n)
+	}
+
+	// 正常退出同样打断连续记录，且不导出退出码 0
+	terminate(1)
+	terminate(1)
+	terminate(0)
+	terminate(0)
+	terminate(0)
+	if n := series(); n != 0 {
+		t.Fatalf("expected no series for clean exits, got %d", n)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// reportTopWorkloads is the number of workloads listed in the report.
+	reportTopWorkloads = 10
+	// reportCertificateWindow lists certificates expiring within this period.
+	reportCertificateWindow = 30 * 24 * time.Hour
+)
+
+// workloadRestartSummary is the number of restarts of a workload in the
+// aggregation window.
+type workloadRestartSummary struct {
+	workloadRef
+	Restarts int `json:"restarts"`
+}
+
+// certificateSummary is a monitored certificate with its remaining lifetime.
+type certificateSummary struct {
+	certificateState
+	DaysUntilExpiration float64 `json:"daysUntilExpiration"`
+}
+
+// clusterReport is the cluster health summary served on /report.
+type clusterReport struct {
+	GeneratedAt          time.Time                `json:"generatedAt"`
+	TopWorkloads         []workloadRestartSummary `json:"topWorkloads"`
+	CrashLooping         []crashLoopState         `json:"crashLoopingContainers"`
+	ExpiringCertificates []certificateSummary     `json:"expiringCertificates"`
+	ExpiredCertificates  []certificateSummary     `json:"expiredCertificates"`
+}
+
+// report builds the cluster health summary from the aggregates already held
+// in the store; it never touches the informer cache.
+func (s *restartStateStore) report(now time.Time) clusterReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rep := clusterReport{
+		GeneratedAt:          now,
+		TopWorkloads:         []workloadRestartSummary{},
+		CrashLooping:         []crashLoopState{},
+		ExpiringCertificates: []certificateSummary{},
+		ExpiredCertificates:  []certificateSummary{},
+	}
+
+	since := now.Add(-restartAggregationWindow)
+	for _, h := range s.workloadRestarts {
+		if count := h.countSince(since); count > 0 {
+			rep.TopWorkloads = append(rep.TopWorkloads, workloadRestartSummary{workloadRef: h.workload, Restarts: count})
+		}
+	}
+	sort.Slice(rep.TopWorkloads, func(i, j int) bool {
+		if rep.TopWorkloads[i].Restarts != rep.TopWorkloads[j].Restarts {
+			return rep.TopWorkloads[i].Restarts > rep.TopWorkloads[j].Restarts
+		}
+		return rep.TopWorkloads[i].key() < rep.TopWorkloads[j].key()
+	})
+	if len(rep.TopWorkloads) > reportTopWorkloads {
+		rep.TopWorkloads = rep.TopWorkloads[:reportTopWorkloads]
+	}
+
+	for _, c := range s.crashLooping {
+		rep.CrashLooping = append(rep.CrashLooping, c)
+	}
+	sort.Slice(rep.CrashLooping, func(i, j int) bool {
+		a, b := rep.CrashLooping[i], rep.CrashLooping[j]
+		return a.Namespace+"/"+a.Pod+"/"+a.Container < b.Namespace+"/"+b.Pod+"/"+b.Container
+	})
+
+	for _, c := range s.certificates {
+		remaining := c.NotAfter.Sub(now)
+		summary := certificateSummary{certificateState: c, DaysUntilExpiration: remaining.Hours() / 24}
+		switch {
+		case remaining <= 0:
+			rep.ExpiredCertificates = append(rep.ExpiredCertificates, summary)
+		case remaining <= reportCertificateWindow:
+			rep.ExpiringCertificates = append(rep.ExpiringCertificates, summary)
+		}
+	}
+	byExpiry := func(certs []certificateSummary) func(i, j int) bool {
+		return func(i, j int) bool { return certs[i].NotAfter.Before(certs[j].NotAfter) }
+	}
+	sort.Slice(rep.ExpiringCertificates, byExpiry(rep.ExpiringCertificates))
+	sort.Slice(rep.ExpiredCertificates, byExpiry(rep.ExpiredCertificates))
+
+	return rep
+}
+
+// writeText renders the report as aligned plain-text tables.
+func (rep clusterReport) writeText(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Pod Monitor cluster report (generated %s)\n\n", rep.GeneratedAt.UTC().Format(time.RFC3339))
+
+	fmt.Fprintf(w, "Top workloads by restarts (last %dh):\n", int(restartAggregationWindow.Hours()))
+	if len(rep.TopWorkloads) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		fmt.Fprintln(w, "  NAMESPACE\tKIND\tNAME\tRESTARTS")
+		for _, wl := range rep.TopWorkloads {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%d\n", wl.Namespace, wl.Kind, wl.Name, wl.Restarts)
+		}
+	}
+
+	fmt.Fprintln(w, "\nCrashlooping containers:")
+	if len(rep.CrashLooping) == 0 {
+		fmt.Fprintln(w, "  none")
+	} else {
+		fmt.Fprintln(w, "  NAMESPACE\tPOD\tCONTAINER\tRESTARTS\tLAST REASON\tSINCE")
+		for _, c := range rep.CrashLooping {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%d\t%s\t%s\n", c.Namespace, c.Pod, c.Container, c.RestartCount,
+				c.LastReason, c.Since.UTC().Format(time.RFC3339))
+		}
+	}
+
+	writeCerts := func(title string, certs []certificateSummary) {
+		fmt.Fprintf(w, "\n%s:\n", title)
+		if len(certs) == 0 {
+			fmt.Fprintln(w, "  none")
+			return
+		}
+		fmt.Fprintln(w, "  NAMESPACE\tSECRET\tCERT TYPE\tNOT AFTER\tDAYS LEFT")
+		for _, c := range certs {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%.1f\n", c.Namespace, c.SecretName, c.CertType,
+				c.NotAfter.UTC().Format(time.RFC3339), c.DaysUntilExpiration)
+		}
+	}
+	writeCerts(fmt.Sprintf("Certificates expiring within %d days", int(reportCertificateWindow.Hours()/24)), rep.ExpiringCertificates)
+	writeCerts("Expired certificates", rep.ExpiredCertificates)
+
+	return w.Flush()
+}
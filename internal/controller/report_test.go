@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestReportDropsDeletedSecrets(t *testing.T) {
+	const namespace = "report-delete-test"
+	ctx := context.Background()
+	secret := testsupport.NewTLSSecret(namespace, "expiring", testsupport.CertificateExpiringIn(t, time.Now(), 10))
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secret)}
+	server := NewStateServer(":0")
+	defer forgetSecretCertificates(namespace, "expiring")
+
+	listed := func() bool {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report?format=json", nil))
+		var rep clusterReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &rep); err != nil {
+			t.Fatal(err)
+		}
+		for _, cert := range rep.ExpiringCertificates {
+			if cert.Namespace == namespace && cert.SecretName == "expiring" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if !listed() {
+		t.Fatal("expected the expiring certificate in the report")
+	}
+
+	// 删除 Secret 后报告中不再列出其证书
+	if err := c.Delete(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if listed() {
+		t.Error("expected the certificate of the deleted secret to leave the report")
+	}
+}
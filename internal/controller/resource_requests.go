@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// requestedResources are the values of the resource label of
+// pod_monitor_container_resource_request_missing.
+var requestedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+var (
+	// 未设置 request 的容器；没有 CPU request 的容器在节点压力下最先被节流或驱逐
+	containerResourceRequestMissing = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_resource_request_missing",
+			Help: "1 for every container that sets neither a request nor a limit for a resource.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"resource",  // cpu 或 memory
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerResourceRequestMissing))
+}
+
+// updateResourceRequestMissing exports a series for every container without
+// a CPU or memory request. A limit counts as a request, since the API server
+// defaults the request to it.
+func updateResourceRequestMissing(b *metricBatch, pod *corev1.Pod) {
+	for _, c := range pod.Spec.Containers {
+		for _, resource := range requestedResources {
+			if c.Resources.Requests.Name(resource, "").IsZero() && c.Resources.Limits.Name(resource, "").IsZero() {
+				b.set(containerResourceRequestMissing, 1, pod.Namespace, pod.Name, c.Name, string(resource))
+				continue
+			}
+			b.delete(containerResourceRequestMissing.MetricVec, pod.Namespace, pod.Name, c.Name, string(resource))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestResourceRequestMissing(t *testing.T) {
+	const namespace = "request-missing-test"
+	pod := testsupport.NewPod(namespace, "web").Build()
+	pod.Spec.Containers = []corev1.Container{
+		{Name: "best-effort"},
+		{Name: "requests", Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}}},
+		// 只设置 limit 时 request 默认等于 limit
+		{Name: "cpu-limit", Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("1"),
+		}}},
+	}
+	defer cleanupPod(namespace, "web")
+
+	var batch metricBatch
+	updateResourceRequestMissing(&batch, pod)
+	stateStore.commitMetrics(&batch)
+
+	labels := func(container, res string) testsupport.Labels {
+		return testsupport.Labels{"namespace": namespace, "pod": "web", "container": container, "resource": res}
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		labels("best-effort", "cpu"), 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		labels("best-effort", "memory"), 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		labels("cpu-limit", "memory"), 1)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		labels("cpu-limit", "cpu"))
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		testsupport.Labels{"namespace": namespace, "container": "requests"})
+
+	// 补上 request 后序列被删除
+	pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")}
+	updateResourceRequestMissing(&batch, pod)
+	stateStore.commitMetrics(&batch)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		labels("best-effort", "cpu"))
+
+	cleanupPod(namespace, "web")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_resource_request_missing",
+		testsupport.Labels{"namespace": namespace})
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=monitor.storehub.com,resources=podrestartbudgets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=monitor.storehub.com,resources=podrestartbudgets/status,verbs=get;update;patch
+
+const (
+	// defaultRestartBudgetWindow is used when a budget sets no window.
+	defaultRestartBudgetWindow = time.Hour
+	// restartBudgetResync is how often budgets are re-evaluated, so that
+	// restarts leaving the window are noticed without a change to the budget.
+	restartBudgetResync = time.Minute
+)
+
+var (
+	// Deployment 中窗口内发生过重启的 Pod 占比是否超过 PodRestartBudget 的上限
+	restartBudgetExceeded = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_restart_budget_exceeded",
+			Help: "Whether the share of a Deployment's pods that restarted within the window of its " +
+				"PodRestartBudget exceeds the budget (1) or not (0)",
+		},
+		[]string{
+			"namespace",  // Deployment 所在命名空间
+			"deployment", // Deployment 名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(restartBudgetExceeded)
+}
+
+// PodRestartBudgetReconciler evaluates PodRestartBudgets against the pods of
+// their target Deployment.
+type PodRestartBudgetReconciler struct {
+	client.Client
+
+	mu sync.Mutex
+	// 每个 PodRestartBudget 的目标与结果；多个 Budget 可指向同一 Deployment，
+	// 序列在最后一个 Budget 删除后才清理。key: Budget 的 namespace/name
+	exported map[types.NamespacedName]restartBudgetResult
+}
+
+// restartBudgetResult is the last evaluation of a budget.
+type restartBudgetResult struct {
+	target   restartBudgetTarget
+	exceeded bool
+}
+
+// restartBudgetTarget is the Deployment a budget applies to.
+type restartBudgetTarget struct {
+	Namespace  string
+	Deployment string
+}
+
+// restartBudgetUsage counts the pods of a Deployment and those with a
+// container that finished a run within the window, i.e. restarted in it.
+func restartBudgetUsage(pods []corev1.Pod, deployment string, since time.Time) (restarted, total int) {
+	for i := range pods {
+		pod := &pods[i]
+		if workload := resolveWorkload(pod); workload.Kind != "Deployment" || workload.Name != deployment {
+			continue
+		}
+		total++
+		for _, cs := range pod.Status.ContainerStatuses {
+			if last := cs.LastTerminationState.Terminated; cs.RestartCount > 0 && last != nil &&
+				!last.FinishedAt.Time.Before(since) {
+				restarted++
+				break
+			}
+		}
+	}
+	return restarted, total
+}
+
+// Reconcile re-evaluates a PodRestartBudget and requeues itself every
+// restartBudgetResync.
+func (r *PodRestartBudgetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var budget monitorv1alpha1.PodRestartBudget
+	if err := r.Get(ctx, req.NamespacedName, &budget); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		r.forget(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+	if err := r.reconcilePodRestartBudget(ctx, &budget, time.Now()); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: restartBudgetResync}, nil
+}
+
+// reconcilePodRestartBudget computes the share of restarted pods of the target
+// Deployment, exports pod_monitor_restart_budget_exceeded and records the
+// result in the status of the budget.
+func (r *PodRestartBudgetReconciler) reconcilePodRestartBudget(ctx context.Context,
+	budget *monitorv1alpha1.PodRestartBudget, now time.Time) error {
+	key := client.ObjectKeyFromObject(budget)
+	if budget.Spec.Namespace != "" && budget.Spec.Namespace != budget.Namespace {
+		// 只允许统计 Budget 所在命名空间的 Pod，不暴露其他租户的信息
+		r.forget(key)
+		return r.updateBudgetStatus(ctx, budget, 0, 0, metav1.Condition{
+			Type:               monitorv1alpha1.RestartBudgetExceededCondition,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: budget.Generation,
+			Reason:             "CrossNamespaceTarget",
+			Message: fmt.Sprintf("spec.namespace %q differs from the namespace of the budget; "+
+				"only Deployments in %q can be targeted", budget.Spec.Namespace, budget.Namespace),
+		})
+	}
+	target := restartBudgetTarget{Namespace: budget.Namespace, Deployment: budget.Spec.TargetDeployment}
+	window := budget.Spec.Window.Duration
+	if window <= 0 {
+		window = defaultRestartBudgetWindow
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(target.Namespace)); err != nil {
+		return fmt.Errorf("listing pods of deployment %s/%s: %w", target.Namespace, target.Deployment, err)
+	}
+	restarted, total := restartBudgetUsage(pods.Items, target.Deployment, now.Add(-window))
+
+	maxPercentage := budget.Spec.MaxRestartPercentage.AsApproximateFloat64()
+	percentage := 0.0
+	if total > 0 {
+		percentage = float64(restarted) / float64(total) * 100
+	}
+	exceeded := percentage > maxPercentage
+
+	r.export(key, target, exceeded)
+
+	condition := metav1.Condition{
+		Type:               monitorv1alpha1.RestartBudgetExceededCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: budget.Generation,
+		Reason:             "WithinBudget",
+		Message: fmt.Sprintf("%d of %d pods restarted within %s (%.1f%%, budget %.1f%%)",
+			restarted, total, window, percentage, maxPercentage),
+	}
+	if exceeded {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "BudgetExceeded"
+	}
+
+	if err := r.updateBudgetStatus(ctx, budget, restarted, total, condition); err != nil {
+		return err
+	}
+	logf.FromContext(ctx).V(1).Info("Evaluated restart budget", "deployment", target.Deployment,
+		"restarted", restarted, "pods", total, "exceeded", exceeded)
+	return nil
+}
+
+// updateBudgetStatus records the evaluation in the status of the budget,
+// skipping the update when nothing changed.
+func (r *PodRestartBudgetReconciler) updateBudgetStatus(ctx context.Context,
+	budget *monitorv1alpha1.PodRestartBudget, restarted, total int, condition metav1.Condition) error {
+	previous := budget.Status.DeepCopy()
+	budget.Status.Pods = int32(total)
+	budget.Status.RestartedPods = int32(restarted)
+	meta.SetStatusCondition(&budget.Status.Conditions, condition)
+	// 状态未变化时不写入，避免每分钟为每个 Budget 更新一次
+	if equality.Semantic.DeepEqual(*previous, budget.Status) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, budget); err != nil {
+		return fmt.Errorf("updating status of PodRestartBudget %s/%s: %w", budget.Namespace, budget.Name, err)
+	}
+	return nil
+}
+
+// export records the result of a budget and refreshes the gauge of its
+// target, and of its previous target when the budget was retargeted.
+func (r *PodRestartBudgetReconciler) export(key types.NamespacedName, target restartBudgetTarget, exceeded bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.exported == nil {
+		r.exported = make(map[types.NamespacedName]restartBudgetResult)
+	}
+	previous, ok := r.exported[key]
+	r.exported[key] = restartBudgetResult{target: target, exceeded: exceeded}
+	if ok && previous.target != target {
+		r.refreshLocked(previous.target)
+	}
+	r.refreshLocked(target)
+}
+
+// forget drops a deleted budget and refreshes the gauge of its target.
+func (r *PodRestartBudgetReconciler) forget(key types.NamespacedName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, ok := r.exported[key]; ok {
+		delete(r.exported, key)
+		r.refreshLocked(previous.target)
+	}
+}
+
+// refreshLocked sets the gauge of a Deployment to 1 when any budget targeting
+// it is exceeded, and removes the series once no budget targets it. r.mu must
+// be held.
+func (r *PodRestartBudgetReconciler) refreshLocked(target restartBudgetTarget) {
+	targeted, exceeded := false, false
+	for _, result := range r.exported {
+		if result.target == target {
+			targeted = true
+			exceeded = exceeded || result.exceeded
+		}
+	}
+	if !targeted {
+		restartBudgetExceeded.DeleteLabelValues(target.Namespace, target.Deployment)
+		return
+	}
+	value := 0.0
+	if exceeded {
+		value = 1
+	}
+	restartBudgetExceeded.WithLabelValues(target.Namespace, target.Deployment).Set(value)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PodRestartBudgetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// 状态更新不改变 generation，避免自身触发 reconcile；窗口滑动由定时 requeue 处理
+		For(&monitorv1alpha1.PodRestartBudget{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("podrestartbudget").
+		Complete(r)
+}
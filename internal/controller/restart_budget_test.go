@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+)
+
+func TestPodRestartBudget(t *testing.T) {
+	const namespace = "restart-budget-test"
+	now := time.Now()
+
+	// web 的 4 个 Pod 中 1 个在窗口内重启，1 个在窗口外重启；api 的 Pod 不计入
+	newPod := func(name, deployment string, finishedAgo time.Duration) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc12"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1", Kind: "ReplicaSet", Name: deployment + "-abc12", Controller: ptr.To(true),
+			}},
+		}}
+		if finishedAgo > 0 {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:         "app",
+				RestartCount: 1,
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+					FinishedAt: metav1.NewTime(now.Add(-finishedAgo)),
+				}},
+			}}
+		}
+		return pod
+	}
+	budget := &monitorv1alpha1.PodRestartBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"},
+		Spec: monitorv1alpha1.PodRestartBudgetSpec{
+			TargetDeployment:     "web",
+			MaxRestartPercentage: resource.MustParse("30"),
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = monitorv1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(budget, newPod("web-1", "web", 10*time.Minute), newPod("web-2", "web", 2*time.Hour),
+			newPod("web-3", "web", 0), newPod("web-4", "web", 0), newPod("api-1", "api", time.Minute)).
+		WithStatusSubresource(&monitorv1alpha1.PodRestartBudget{}).Build()
+	r := &PodRestartBudgetReconciler{Client: c}
+	defer restartBudgetExceeded.Reset()
+
+	evaluate := func() monitorv1alpha1.PodRestartBudget {
+		t.Helper()
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(budget)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.RequeueAfter != restartBudgetResync {
+			t.Errorf("expected the budget to be requeued after %v, got %v", restartBudgetResync, result.RequeueAfter)
+		}
+		var updated monitorv1alpha1.PodRestartBudget
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(budget), &updated); err != nil {
+			t.Fatal(err)
+		}
+		return updated
+	}
+
+	// 25% 未超过 30% 的预算
+	updated := evaluate()
+	if updated.Status.Pods != 4 || updated.Status.RestartedPods != 1 {
+		t.Fatalf("expected 1 of 4 pods restarted, got %d of %d", updated.Status.RestartedPods, updated.Status.Pods)
+	}
+	if !meta.IsStatusConditionFalse(updated.Status.Conditions, monitorv1alpha1.RestartBudgetExceededCondition) {
+		t.Errorf("expected the budget not to be exceeded")
+	}
+	if got := testutil.ToFloat64(restartBudgetExceeded.WithLabelValues(namespace, "web")); got != 0 {
+		t.Errorf("expected the gauge to be 0, got %v", got)
+	}
+
+	// 结果不变时不更新状态
+	if again := evaluate(); again.ResourceVersion != updated.ResourceVersion {
+		t.Errorf("expected an unchanged evaluation not to update the status")
+	}
+
+	// 预算收紧到 20% 后超出
+	updated.Spec.MaxRestartPercentage = resource.MustParse("20")
+	if err := c.Update(context.Background(), &updated); err != nil {
+		t.Fatal(err)
+	}
+	updated = evaluate()
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, monitorv1alpha1.RestartBudgetExceededCondition) {
+		t.Errorf("expected the budget to be exceeded: %v", updated.Status.Conditions)
+	}
+	if got := testutil.ToFloat64(restartBudgetExceeded.WithLabelValues(namespace, "web")); got != 1 {
+		t.Errorf("expected the gauge to be 1, got %v", got)
+	}
+
+	// 删除 Budget 后清理序列
+	if err := c.Delete(context.Background(), &updated); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(budget)}); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(restartBudgetExceeded); n != 0 {
+		t.Errorf("expected the series to be removed with the budget, %d left", n)
+	}
+}
+
+func TestPodRestartBudgetRejectsOtherNamespaces(t *testing.T) {
+	ctx := context.Background()
+	// 其他命名空间中的 Pod 不能被 Budget 统计
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "other-tenant", Name: "web-1",
+		Labels: map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "abc12"},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc12", Controller: ptr.To(true),
+		}},
+	}}
+	budget := &monitorv1alpha1.PodRestartBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "restart-budget-tenant", Name: "web"},
+		Spec: monitorv1alpha1.PodRestartBudgetSpec{
+			TargetDeployment:     "web",
+			Namespace:            "other-tenant",
+			MaxRestartPercentage: resource.MustParse("30"),
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = monitorv1alpha1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(budget, pod).
+		WithStatusSubresource(&monitorv1alpha1.PodRestartBudget{}).Build()
+	r := &PodRestartBudgetReconciler{Client: c}
+	defer restartBudgetExceeded.Reset()
+
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(budget)}); err != nil {
+		t.Fatal(err)
+	}
+	var updated monitorv1alpha1.PodRestartBudget
+	if err := c.Get(ctx, client.ObjectKeyFromObject(budget), &updated); err != nil {
+		t.Fatal(err)
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, monitorv1alpha1.RestartBudgetExceededCondition)
+	if condition == nil || condition.Status != metav1.ConditionUnknown || condition.Reason != "CrossNamespaceTarget" {
+		t.Errorf("expected the cross-namespace target to be rejected, got %v", updated.Status.Conditions)
+	}
+	if updated.Status.Pods != 0 {
+		t.Errorf("expected no pods of another namespace to be counted, got %d", updated.Status.Pods)
+	}
+	if n := testutil.CollectAndCount(restartBudgetExceeded); n != 0 {
+		t.Errorf("expected no series for a rejected budget, got %d", n)
+	}
+}
+
+func TestPodRestartBudgetSharedTarget(t *testing.T) {
+	const namespace = "restart-budget-shared-test"
+	r := &PodRestartBudgetReconciler{}
+	defer restartBudgetExceeded.Reset()
+	strict := types.NamespacedName{Namespace: namespace, Name: "strict"}
+	loose := types.NamespacedName{Namespace: namespace, Name: "loose"}
+	web := restartBudgetTarget{Namespace: namespace, Deployment: "web"}
+	gauge := func() float64 {
+		return testutil.ToFloat64(restartBudgetExceeded.WithLabelValues(namespace, "web"))
+	}
+
+	// 任一指向该 Deployment 的 Budget 超出时为 1
+	r.export(strict, web, true)
+	r.export(loose, web, false)
+	if got := gauge(); got != 1 {
+		t.Errorf("expected the exceeded budget to win, got %v", got)
+	}
+
+	// 删除或改指其他 Deployment 的 Budget 不影响其余 Budget 的序列
+	r.forget(strict)
+	if got := gauge(); got != 0 {
+		t.Errorf("expected the remaining budget's result, got %v", got)
+	}
+	r.export(loose, restartBudgetTarget{Namespace: namespace, Deployment: "api"}, false)
+	if n := testutil.CollectAndCount(restartBudgetExceeded); n != 1 {
+		t.Errorf("expected only the api series once no budget targets web, got %d series", n)
+	}
+	r.forget(loose)
+	if n := testutil.CollectAndCount(restartBudgetExceeded); n != 0 {
+		t.Errorf("expected no series without budgets, got %d", n)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// lastTermination is when a container last terminated, from its
+// lastState.terminated.finishedAt.
+type lastTermination struct {
+	Namespace  string
+	Pod        string
+	Container  string
+	FinishedAt time.Time
+}
+
+// setLastTermination records when a container last terminated, or clears it
+// when the container has no termination to report.
+func (s *restartStateStore) setLastTermination(key string, state *lastTermination) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state == nil {
+		delete(s.lastTerminations, key)
+		return
+	}
+	s.lastTerminations[key] = *state
+}
+
+// lastTerminationStates returns a snapshot of the last termination of every
+// container that has one.
+func (s *restartStateStore) lastTerminationStates() []lastTermination {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make([]lastTermination, 0, len(s.lastTerminations))
+	for _, state := range s.lastTerminations {
+		states = append(states, state)
+	}
+	return states
+}
+
+// updateRestartGap records the last termination of a container for
+// pod_monitor_container_restart_gap_seconds.
+func updateRestartGap(pod *corev1.Pod, containerKey string, cs corev1.ContainerStatus) {
+	terminated := cs.LastTerminationState.Terminated
+	if terminated == nil || terminated.FinishedAt.IsZero() {
+		stateStore.setLastTermination(containerKey, nil)
+		return
+	}
+	stateStore.setLastTermination(containerKey, &lastTermination{
+		Namespace:  pod.Namespace,
+		Pod:        pod.Name,
+		Container:  cs.Name,
+		FinishedAt: terminated.FinishedAt.Time,
+	})
+}
+
+// restartGapCollector exports the seconds since each container last
+// terminated, computed at scrape time so that it keeps growing between
+// reconciles and drops to near zero on a new termination.
+type restartGapCollector struct {
+	desc *prometheus.Desc
+}
+
+func newRestartGapCollector() *restartGapCollector {
+	return &restartGapCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_container_restart_gap_seconds",
+			"Seconds since the last termination of a container, from its lastState.terminated.finishedAt",
+			[]string{"namespace", "pod", "container"}, nil,
+		),
+	}
+}
+
+func (c *restartGapCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *restartGapCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, state := range stateStore.lastTerminationStates() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(state.FinishedAt).Seconds(),
+			state.Namespace, state.Pod, state.Container)
+	}
+}
+
+func init() {
+	registerMetrics(newRestartGapCollector())
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestRestartGap(t *testing.T) {
+	const namespace = "restart-gap-test"
+	finishedAt := time.Now().Add(-time.Hour)
+	pod := testsupport.NewPod(namespace, "web").
+		WithTerminatedContainer("app", 1, "Error", 1, finishedAt).
+		WithContainer("sidecar").
+		Build()
+	defer cleanupPod(namespace, "web")
+
+	gap := func(container string) []float64 {
+		t.Helper()
+		values, err := testsupport.Series(metrics.Registry, "pod_monitor_container_restart_gap_seconds",
+			testsupport.Labels{"namespace": namespace, "pod": "web", "container": container})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return values
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		updateRestartGap(pod, namespace+"/web/"+cs.Name, cs)
+	}
+	// 抓取时计算，至少为距终止的一小时
+	if values := gap("app"); len(values) != 1 || values[0] < time.Hour.Seconds() ||
+		values[0] > time.Hour.Seconds()+60 {
+		t.Errorf("restart gap of app = %v, want about 3600", values)
+	}
+	// 从未终止的容器没有序列
+	if values := gap("sidecar"); len(values) != 0 {
+		t.Errorf("restart gap of sidecar = %v, want none", values)
+	}
+
+	// 新的终止使间隔接近 0
+	cs := testsupport.TerminatedContainerStatus("app", 2, "Error", 1, time.Now())
+	updateRestartGap(pod, namespace+"/web/app", cs)
+	if values := gap("app"); len(values) != 1 || values[0] > 60 {
+		t.Errorf("restart gap of app after a new termination = %v, want near 0", values)
+	}
+
+	cleanupPod(namespace, "web")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_restart_gap_seconds",
+		testsupport.Labels{"namespace": namespace})
+}
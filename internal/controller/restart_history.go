@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultHistorySize is the number of termination records kept cluster-wide.
+	defaultHistorySize = 1000
+	// defaultHistoryPerContainer is the number of records kept per container.
+	defaultHistoryPerContainer = 50
+)
+
+// terminationRecord is a single container termination observed by reconcilePod.
+type terminationRecord struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Namespace string      `json:"namespace"`
+	Pod       string      `json:"pod"`
+	Container string      `json:"container"`
+	Reason    string      `json:"reason"`
+	ExitCode  int32       `json:"exitCode"`
+	Node      string      `json:"node,omitempty"`
+	Workload  workloadRef `json:"workload"`
+	// DuringRollout is "true", "false" or "unknown"
+	DuringRollout string `json:"duringRollout"`
+	// Severity is "critical" for restarts of critical pods
+	Severity string `json:"severity,omitempty"`
+	// EphemeralStorage is set for ephemeral storage evictions
+	EphemeralStorage *ephemeralStorageEviction `json:"ephemeralStorage,omitempty"`
+}
+
+func (t terminationRecord) containerKey() string {
+	return fmt.Sprintf("%s/%s/%s", t.Namespace, t.Pod, t.Container)
+}
+
+// historySlot is a ring slot; evicted slots were dropped by the per-container
+// cap and are skipped until the ring overwrites them.
+type historySlot struct {
+	record  terminationRecord
+	evicted bool
+}
+
+// restartHistory is a fixed-size ring buffer of termination records. Memory is
+// bounded by the ring size; eviction is always oldest-first, both cluster-wide
+// and within a single container.
+type restartHistory struct {
+	mu sync.RWMutex
+
+	size         int
+	perContainer int
+
+	slots []historySlot
+	next  int
+	// 每个容器在环中仍有效的记录数
+	containerCounts map[string]int
+}
+
+func newRestartHistory(size, perContainer int) *restartHistory {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	if perContainer <= 0 || perContainer > size {
+		perContainer = size
+	}
+	return &restartHistory{
+		size:            size,
+		perContainer:    perContainer,
+		slots:           make([]historySlot, 0, size),
+		containerCounts: make(map[string]int),
+	}
+}
+
+// append adds a record, evicting the oldest record of the same container when
+// the per-container cap is reached and the oldest record overall when full.
+func (h *restartHistory) append(rec terminationRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := rec.containerKey()
+	if h.containerCounts[key] >= h.perContainer {
+		h.evictOldestLocked(key)
+	}
+
+	slot := historySlot{record: rec}
+	if len(h.slots) < h.size {
+		h.slots = append(h.slots, slot)
+	} else {
+		old := h.slots[h.next]
+		if !old.evicted {
+			h.decrementLocked(old.record.containerKey())
+		}
+		h.slots[h.next] = slot
+		h.next = (h.next + 1) % h.size
+	}
+	h.containerCounts[key]++
+}
+
+// evictOldestLocked marks the oldest live record of a container as evicted.
+func (h *restartHistory) evictOldestLocked(key string) {
+	for i := 0; i < len(h.slots); i++ {
+		idx := (h.next + i) % len(h.slots)
+		slot := &h.slots[idx]
+		if !slot.evicted && slot.record.containerKey() == key {
+			slot.evicted = true
+			h.decrementLocked(key)
+			return
+		}
+	}
+}
+
+func (h *restartHistory) decrementLocked(key string) {
+	if h.containerCounts[key] <= 1 {
+		delete(h.containerCounts, key)
+		return
+	}
+	h.containerCounts[key]--
+}
+
+// list returns the records at or after since, oldest first, optionally
+// restricted to a namespace.
+func (h *restartHistory) list(since time.Time, namespace string) []terminationRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	records := make([]terminationRecord, 0)
+	for i := 0; i < len(h.slots); i++ {
+		slot := h.slots[(h.next+i)%len(h.slots)]
+		if slot.evicted || slot.record.Timestamp.Before(since) {
+			continue
+		}
+		if namespace != "" && slot.record.Namespace != namespace {
+			continue
+		}
+		records = append(records, slot.record)
+	}
+	return records
+}
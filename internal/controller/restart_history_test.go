@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRestartHistoryEviction(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		size         int
+		perContainer int
+		// 依次追加的记录所属的容器（Pod 名称）
+		appends []string
+		// 保留的记录，按追加顺序编号，从旧到新
+		want []string
+	}{
+		{"below the bounds", 4, 4, []string{"a", "b", "a"}, []string{"a0", "b1", "a2"}},
+		{"ring overwrites the oldest", 3, 3, []string{"a", "b", "c", "d", "e"}, []string{"c2", "d3", "e4"}},
+		{"per-container cap evicts the container's oldest", 5, 2, []string{"a", "b", "a", "a", "b", "a"},
+			[]string{"b1", "a3", "b4", "a5"}},
+		{"ring skips evicted slots", 3, 1, []string{"a", "a", "b", "a", "c"}, []string{"b2", "a3", "c4"}},
+		{"ring wraps after cap evictions", 3, 2, []string{"a", "a", "a", "b", "b", "b", "a"},
+			[]string{"b4", "b5", "a6"}},
+		{"per-container cap above size", 2, 10, []string{"a", "a", "a"}, []string{"a1", "a2"}},
+	}
+	for _, tt := range tests {
+		h := newRestartHistory(tt.size, tt.perContainer)
+		for i, pod := range tt.appends {
+			h.append(terminationRecord{Timestamp: base.Add(time.Duration(i) * time.Minute), Namespace: "default",
+				Pod: pod, Container: "app", Reason: fmt.Sprint(i)})
+		}
+		var got []string
+		counts := map[string]int{}
+		for _, rec := range h.list(time.Time{}, "") {
+			got = append(got, rec.Pod+rec.Reason)
+			counts[rec.containerKey()]++
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		// 内存有界：环的大小与每个容器的计数都不超过上限
+		if len(h.slots) > tt.size {
+			t.Errorf("%s: ring grew to %d slots, bound is %d", tt.name, len(h.slots), tt.size)
+		}
+		for key, n := range counts {
+			if n > h.perContainer || h.containerCounts[key] != n {
+				t.Errorf("%s: %s has %d records, counted %d, cap %d", tt.name, key, n, h.containerCounts[key],
+					h.perContainer)
+			}
+		}
+		if len(h.containerCounts) != len(counts) {
+			t.Errorf("%s: counts kept for %d containers, %d have records", tt.name, len(h.containerCounts),
+				len(counts))
+		}
+	}
+}
+
+func TestRestartHistoryListFilters(t *testing.T) {
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	h := newRestartHistory(10, 10)
+	for i, ns := range []string{"web", "batch", "web"} {
+		h.append(terminationRecord{Timestamp: base.Add(time.Duration(i) * time.Minute), Namespace: ns,
+			Pod: "app", Container: "app"})
+	}
+	if got := h.list(base.Add(time.Minute), ""); len(got) != 2 || got[0].Namespace != "batch" {
+		t.Errorf("expected the records since the first minute, got %v", got)
+	}
+	if got := h.list(time.Time{}, "web"); len(got) != 2 {
+		t.Errorf("expected the two web records, got %v", got)
+	}
+}
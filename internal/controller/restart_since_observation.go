@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 自 Operator 首次观察到该容器以来的重启次数，长期存活的 Pod 不再携带历史重启
+	// 注意：Operator 重启后从当前的 restartCount 重新计数
+	containerRestartSinceObservation = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_restart_since_observation",
+			Help: "Number of restarts of a container since the operator first observed it. " +
+				"Restarts from before the operator started tracking the pod are not counted.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerRestartSinceObservation))
+}
+
+// restartBaseline returns the restart count of a container when it was
+// first observed, recording count as the baseline on first observation. A
+// count below the baseline, from a recreated pod whose state was not
+// forgotten, starts a new baseline.
+func (s *restartStateStore) restartBaseline(containerKey string, count int32) int32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	baseline, ok := s.restartBaselines[containerKey]
+	if !ok || count < baseline {
+		s.restartBaselines[containerKey] = count
+		return count
+	}
+	return baseline
+}
+
+// updateRestartSinceObservation exports the restarts of a container since it
+// was first observed.
+func updateRestartSinceObservation(b *metricBatch, pod *corev1.Pod, containerKey string, cs corev1.ContainerStatus) {
+	baseline := stateStore.restartBaseline(containerKey, cs.RestartCount)
+	b.set(containerRestartSinceObservation, float64(cs.RestartCount-baseline), pod.Namespace, pod.Name, cs.Name)
+}
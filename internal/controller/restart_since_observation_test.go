@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestRestartSinceObservation(t *testing.T) {
+	const namespace = "restart-since-observation-test"
+	const key = namespace + "/web/app"
+	defer cleanupPod(namespace, "web")
+	labels := testsupport.Labels{"namespace": namespace, "pod": "web", "container": "app"}
+
+	observe := func(restartCount int32) {
+		t.Helper()
+		cs := testsupport.TerminatedContainerStatus("app", restartCount, "Error", 1, time.Now())
+		pod := testsupport.NewPod(namespace, "web").WithContainerStatus(cs).Build()
+		var batch metricBatch
+		updateRestartSinceObservation(&batch, pod, key, cs)
+		stateStore.commitMetrics(&batch)
+	}
+
+	// 首次观察时已累计的重启不计入
+	observe(7)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_since_observation", labels, 0)
+	observe(9)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_since_observation", labels, 2)
+
+	cleanupPod(namespace, "web")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_restart_since_observation",
+		testsupport.Labels{"namespace": namespace})
+
+	// 同名 Pod 重建后从新的基线开始
+	observe(1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_since_observation", labels, 0)
+	observe(3)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_since_observation", labels, 2)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultRestartVelocityAlpha is the EWMA smoothing factor.
+const defaultRestartVelocityAlpha = 0.2
+
+var (
+	// 每个容器的 EWMA 状态
+	// key: "namespace/podName/containerName"
+	restartVelocity = make(map[string]restartVelocityState)
+
+	// 保护 restartVelocity map 的互斥锁
+	restartVelocityMutex sync.Mutex
+)
+
+func init() {
+	registerMetrics(newRestartVelocityCollector(time.Now))
+}
+
+// restartVelocityState is the last EWMA value of a container together with
+// the restart count and time it was computed from.
+type restartVelocityState struct {
+	namespace, pod, container string
+
+	alpha        float64
+	ewma         float64
+	restartCount int32
+	updatedAt    time.Time
+}
+
+// weight returns the weight the EWMA keeps after elapsed: alpha is the weight
+// of a rate observed over one minute, so the previous value keeps
+// (1-alpha)^minutes.
+func (s restartVelocityState) weight(elapsed time.Duration) float64 {
+	return math.Pow(1-s.alpha, elapsed.Minutes())
+}
+
+// at returns the EWMA at a given time. Without a reconcile in between, no
+// restart was seen since updatedAt, so the value decays towards zero as if a
+// rate of zero had been observed.
+func (s restartVelocityState) at(now time.Time) float64 {
+	elapsed := now.Sub(s.updatedAt)
+	if elapsed <= 0 {
+		return s.ewma
+	}
+	return s.weight(elapsed) * s.ewma
+}
+
+// updateRestartVelocity folds the restarts since the previous reconcile into
+// the EWMA of every container: ewma = (1-w)*rate + w*ewma, where rate is the
+// restart count delta per minute since the last update and w = (1-alpha)^m
+// after m minutes. An update one minute after the previous one weighs the
+// rate by alpha.
+func (r *PodMonitorReconciler) updateRestartVelocity(pod *corev1.Pod, now time.Time) {
+	alpha := r.RestartVelocityAlpha
+	if alpha <= 0 || alpha > 1 {
+		alpha = defaultRestartVelocityAlpha
+	}
+
+	restartVelocityMutex.Lock()
+	defer restartVelocityMutex.Unlock()
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+		state, seen := restartVelocity[key]
+		state.alpha = alpha
+		if seen {
+			elapsed := now.Sub(state.updatedAt)
+			if elapsed <= 0 {
+				continue
+			}
+			delta := float64(cs.RestartCount - state.restartCount)
+			if delta < 0 {
+				// 容器状态被重置（例如 Pod 重建同名），重新开始计算
+				delta = 0
+			}
+			w := state.weight(elapsed)
+			state.ewma = (1-w)*delta/elapsed.Minutes() + w*state.ewma
+		} else {
+			state.namespace, state.pod, state.container = pod.Namespace, pod.Name, cs.Name
+		}
+		state.restartCount = cs.RestartCount
+		state.updatedAt = now
+		restartVelocity[key] = state
+	}
+}
+
+// forgetRestartVelocity drops the EWMA state of a deleted pod.
+func forgetRestartVelocity(namespace, podName string) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, podName)
+	restartVelocityMutex.Lock()
+	defer restartVelocityMutex.Unlock()
+	for key := range restartVelocity {
+		if strings.HasPrefix(key, prefix) {
+			delete(restartVelocity, key)
+		}
+	}
+}
+
+// restartVelocityCollector exports the restart velocity EWMA of every
+// container, decayed to scrape time.
+type restartVelocityCollector struct {
+	desc *prometheus.Desc
+	now  func() time.Time
+}
+
+func newRestartVelocityCollector(now func() time.Time) *restartVelocityCollector {
+	return &restartVelocityCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_container_restart_velocity",
+			"Exponentially weighted moving average of the container restart rate, in restarts per minute",
+			[]string{"namespace", "pod", "container"}, nil,
+		),
+		now: now,
+	}
+}
+
+func (c *restartVelocityCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *restartVelocityCollector) Collect(ch chan<- prometheus.Metric) {
+	restartVelocityMutex.Lock()
+	states := make([]restartVelocityState, 0, len(restartVelocity))
+	for _, state := range restartVelocity {
+		states = append(states, state)
+	}
+	restartVelocityMutex.Unlock()
+
+	now := c.now()
+	for _, state := range states {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, state.at(now),
+			state.namespace, state.pod, state.container)
+	}
+}
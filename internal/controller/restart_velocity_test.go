@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestRestartVelocityDecays(t *testing.T) {
+	const namespace = "restart-velocity-test"
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(now)
+	r := &PodMonitorReconciler{Clock: clock, RestartVelocityAlpha: 0.5}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newRestartVelocityCollector(clock.Now))
+	defer forgetRestartVelocity(namespace, "app")
+	observe := func(restarts int32) {
+		r.updateRestartVelocity(testsupport.NewPod(namespace, "app").
+			WithContainerStatus(corev1.ContainerStatus{Name: "app", RestartCount: restarts}).Build(), r.now())
+	}
+	velocity := func() float64 {
+		t.Helper()
+		values, err := testsupport.Series(registry, "pod_monitor_container_restart_velocity",
+			testsupport.Labels{"namespace": namespace, "pod": "app", "container": "app"})
+		if err != nil || len(values) != 1 {
+			t.Fatalf("expected one velocity series, got %v: %v", values, err)
+		}
+		return values[0]
+	}
+	assertVelocity := func(want float64) {
+		t.Helper()
+		if got := velocity(); math.Abs(got-want) > 1e-9 {
+			t.Errorf("at %s: expected velocity %v, got %v", clock.Now().Sub(now), want, got)
+		}
+	}
+
+	observe(0)
+	assertVelocity(0)
+
+	// 一分钟内重启 4 次：速率 4/分钟，权重 alpha
+	clock.SetTime(now.Add(time.Minute))
+	observe(4)
+	assertVelocity(2)
+
+	// 之后不再 reconcile，抓取时的值按每分钟 (1-alpha) 衰减
+	clock.SetTime(now.Add(3 * time.Minute))
+	assertVelocity(0.5)
+
+	// 没有新重启的 reconcile 与抓取时的衰减一致
+	observe(4)
+	assertVelocity(0.5)
+	clock.SetTime(now.Add(time.Hour))
+	if got := velocity(); got > 1e-9 {
+		t.Errorf("expected the velocity to decay to zero, got %v", got)
+	}
+}
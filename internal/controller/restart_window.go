@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// defaultRestartWindow is the length of the sliding restart window.
+	defaultRestartWindow = time.Hour
+	// restartWindowBuckets is the number of buckets the window is split into.
+	restartWindowBuckets = 60
+)
+
+// windowBucket counts the restarts of one bucket-width period.
+type windowBucket struct {
+	// epoch 为桶对应的时间段序号（Unix 时间 / 桶宽度）
+	epoch int64
+	count int
+}
+
+// windowKey identifies the restarts counted together in a window. Keys left
+// empty aggregate: the namespace window leaves the workload empty.
+type windowKey struct {
+	Namespace    string
+	WorkloadKind string
+	WorkloadName string
+	Reason       string
+}
+
+// windowCounts holds the buckets of one key.
+type windowCounts struct {
+	buckets [restartWindowBuckets]windowBucket
+}
+
+// restartWindow keeps sliding-window restart counts per key in fixed time
+// buckets. Expired buckets are dropped when the window is read, so counts
+// decay without new restarts.
+type restartWindow struct {
+	mu     sync.Mutex
+	window time.Duration
+	width  time.Duration
+	counts map[windowKey]*windowCounts
+}
+
+func newRestartWindow(window time.Duration) *restartWindow {
+	if window <= 0 {
+		window = defaultRestartWindow
+	}
+	width := window / restartWindowBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return &restartWindow{window: window, width: width, counts: make(map[windowKey]*windowCounts)}
+}
+
+func (w *restartWindow) epoch(t time.Time) int64 {
+	return t.UnixNano() / int64(w.width)
+}
+
+// add counts a restart that happened at the given time. Restarts outside the
+// window are ignored; restarts in the future count as happening now.
+func (w *restartWindow) add(key windowKey, at, now time.Time) {
+	if at.After(now) {
+		at = now
+	}
+	current := w.epoch(now)
+	epoch := w.epoch(at)
+	if epoch <= current-restartWindowBuckets {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	counts, ok := w.counts[key]
+	if !ok {
+		counts = &windowCounts{}
+		w.counts[key] = counts
+	}
+	bucket := &counts.buckets[epoch%restartWindowBuckets]
+	if bucket.epoch != epoch {
+		*bucket = windowBucket{epoch: epoch}
+	}
+	bucket.count++
+}
+
+// windowTotal is the restart count of one key in the window.
+type windowTotal struct {
+	windowKey
+	Count int
+}
+
+// advance drops the buckets that left the window and returns the remaining
+// totals. Keys without restarts in the window are removed.
+func (w *restartWindow) advance(now time.Time) []windowTotal {
+	current := w.epoch(now)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	totals := make([]windowTotal, 0, len(w.counts))
+	for key, counts := range w.counts {
+		total := 0
+		for i := range counts.buckets {
+			bucket := &counts.buckets[i]
+			if bucket.epoch <= current-restartWindowBuckets {
+				*bucket = windowBucket{}
+				continue
+			}
+			total += bucket.count
+		}
+		if total == 0 {
+			delete(w.counts, key)
+			continue
+		}
+		totals = append(totals, windowTotal{windowKey: key, Count: total})
+	}
+	return totals
+}
+
+// configureRestartWindow sets the length of the sliding restart window. It is
+// meant to be called once during setup and drops the counts collected so far.
+func (s *restartStateStore) configureRestartWindow(window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restartWindow = newRestartWindow(window)
+}
+
+// recordRestartInWindow counts a restart in the sliding window of its
+// namespace and, unless the container completed, in the failure reason
+// window of its workload.
+func (s *restartStateStore) recordRestartInWindow(workload workloadRef, reason string, at, now time.Time) {
+	s.mu.RLock()
+	window, failureReasons := s.restartWindow, s.failureReasonWindow
+	s.mu.RUnlock()
+	window.add(windowKey{Namespace: workload.Namespace, Reason: reason}, at, now)
+	if reason != "Completed" {
+		failureReasons.add(windowKey{
+			Namespace:    workload.Namespace,
+			WorkloadKind: workload.Kind,
+			WorkloadName: workload.Name,
+			Reason:       reason,
+		}, at, now)
+	}
+}
+
+// formatWindow renders a window length the way it is written in flags,
+// e.g. "1h" or "30m".
+func formatWindow(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
+
+// restartWindowCollector exports the sliding-window restart counts. Buckets
+// are advanced at scrape time so that counts decay even without new restarts.
+type restartWindowCollector struct {
+	desc *prometheus.Desc
+}
+
+func newRestartWindowCollector() *restartWindowCollector {
+	return &restartWindowCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_restarts_last_window",
+			"Number of container restarts within the sliding window, by namespace and termination reason",
+			[]string{"namespace", "reason", "window"}, nil,
+		),
+	}
+}
+
+func (c *restartWindowCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *restartWindowCollector) Collect(ch chan<- prometheus.Metric) {
+	stateStore.mu.RLock()
+	window := stateStore.restartWindow
+	stateStore.mu.RUnlock()
+
+	label := formatWindow(window.window)
+	for _, total := range window.advance(time.Now()) {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(total.Count),
+			total.Namespace, total.Reason, label)
+	}
+}
+
+func init() {
+	registerMetrics(newRestartWindowCollector())
+}
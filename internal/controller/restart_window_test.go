@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartWindowDecaysWithoutNewRestarts(t *testing.T) {
+	w := newRestartWindow(time.Hour)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	w.add(windowKey{Namespace: "default", Reason: "OOMKilled"}, now.Add(-50*time.Minute), now)
+	w.add(windowKey{Namespace: "default", Reason: "OOMKilled"}, now.Add(-10*time.Minute), now)
+	w.add(windowKey{Namespace: "default", Reason: "Error"}, now.Add(-5*time.Minute), now)
+	// 窗口之外的重启不计数
+	w.add(windowKey{Namespace: "default", Reason: "Error"}, now.Add(-2*time.Hour), now)
+
+	counts := func(at time.Time) map[string]int {
+		got := map[string]int{}
+		for _, total := range w.advance(at) {
+			got[total.Namespace+"/"+total.Reason] = total.Count
+		}
+		return got
+	}
+
+	if got := counts(now); got["default/OOMKilled"] != 2 || got["default/Error"] != 1 {
+		t.Fatalf("unexpected counts %v", got)
+	}
+	// 20 分钟后最早的一次重启已离开窗口
+	if got := counts(now.Add(20 * time.Minute)); got["default/OOMKilled"] != 1 || got["default/Error"] != 1 {
+		t.Fatalf("unexpected counts after 20 minutes %v", got)
+	}
+	// 一小时后全部过期，键被移除
+	if got := counts(now.Add(time.Hour)); len(got) != 0 {
+		t.Fatalf("expected all counts to expire, got %v", got)
+	}
+	if len(w.counts) != 0 {
+		t.Fatalf("expected expired keys to be removed, %d left", len(w.counts))
+	}
+}
+
+func TestFormatWindow(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		time.Hour:        "1h",
+		6 * time.Hour:    "6h",
+		30 * time.Minute: "30m",
+		90 * time.Second: "1m30s",
+	} {
+		if got := formatWindow(d); got != want {
+			t.Errorf("formatWindow(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// restartedAtAnnotation is set by kubectl rollout restart on the pod
+	// template, and by some operators on pods they restart in place.
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	// causeOperatorInitiated is the cause label of restarts requested
+	// through restartedAtAnnotation.
+	causeOperatorInitiated = "operator_initiated"
+)
+
+// parseRestartedAt returns the time of a restartedAt annotation, or the zero
+// time when it is missing or malformed.
+func parseRestartedAt(annotations map[string]string) time.Time {
+	value, ok := annotations[restartedAtAnnotation]
+	if !ok {
+		return time.Time{}
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return at
+}
+
+// latestRestartedAt returns the newest restartedAt of a pod and the pod
+// template of its owning workload, which may be nil.
+func latestRestartedAt(pod *corev1.Pod, owner client.Object) time.Time {
+	latest := parseRestartedAt(pod.Annotations)
+	var template map[string]string
+	switch w := owner.(type) {
+	case *appsv1.Deployment:
+		template = w.Spec.Template.Annotations
+	case *appsv1.StatefulSet:
+		template = w.Spec.Template.Annotations
+	}
+	if at := parseRestartedAt(template); at.After(latest) {
+		latest = at
+	}
+	return latest
+}
+
+// observeRestartedAt reports whether restartedAt is newer than the value seen
+// at the previous restart of the container, or than the pod's creation when
+// no restart was seen yet: a pod created by a rollout restart carries the
+// annotation from the start. The new value is remembered.
+func (s *restartStateStore) observeRestartedAt(pod *corev1.Pod, container string, restartedAt time.Time) bool {
+	if restartedAt.IsZero() {
+		return false
+	}
+	key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, container)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.restartedAt[key]
+	if !ok {
+		previous = pod.CreationTimestamp.Time
+	}
+	if !restartedAt.After(previous) {
+		return false
+	}
+	s.restartedAt[key] = restartedAt
+	return true
+}
+
+// restartCause returns the cause label of a restart: operator_initiated when
+// it follows a new restartedAt annotation on the pod or its owner, else "".
+func restartCause(pod *corev1.Pod, container string, owner client.Object) string {
+	if stateStore.observeRestartedAt(pod, container, latestRestartedAt(pod, owner)) {
+		return causeOperatorInitiated
+	}
+	return ""
+}
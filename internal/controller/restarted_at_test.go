@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestRestartCauseFromRestartedAt(t *testing.T) {
+	const namespace = "restarted-at-test"
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         namespace,
+		Name:              "web",
+		CreationTimestamp: metav1.NewTime(created),
+		// rollout restart 创建的 Pod 从一开始就带有注解
+		Annotations: map[string]string{restartedAtAnnotation: created.Add(-time.Second).Format(time.RFC3339)},
+	}}
+	defer stateStore.forgetPod(namespace, "web")
+
+	if cause := restartCause(pod, "app", nil); cause != "" {
+		t.Fatalf("expected an annotation older than the pod to be ignored, got %q", cause)
+	}
+
+	pod.Annotations[restartedAtAnnotation] = created.Add(time.Hour).Format(time.RFC3339)
+	if cause := restartCause(pod, "app", nil); cause != causeOperatorInitiated {
+		t.Fatalf("expected a new restartedAt to mark the restart, got %q", cause)
+	}
+	// 同一个注解值只归因一次重启，但每个容器各自归因
+	if cause := restartCause(pod, "app", nil); cause != "" {
+		t.Fatalf("expected a later restart to be unexpected again, got %q", cause)
+	}
+	if cause := restartCause(pod, "sidecar", nil); cause != causeOperatorInitiated {
+		t.Fatalf("expected the other container to be marked too, got %q", cause)
+	}
+
+	// 工作负载 Pod 模板上的注解同样生效
+	owner := &appsv1.Deployment{}
+	owner.Spec.Template.Annotations = map[string]string{
+		restartedAtAnnotation: created.Add(2 * time.Hour).Format(time.RFC3339),
+	}
+	if cause := restartCause(pod, "app", owner); cause != causeOperatorInitiated {
+		t.Fatalf("expected the owner's restartedAt to mark the restart, got %q", cause)
+	}
+}
+
+func TestOperatorInitiatedRestartSkipsWarning(t *testing.T) {
+	const namespace = "restarted-at-warning-test"
+	pod := testsupport.NewPod(namespace, "web").
+		WithCreationTimestamp(time.Now().Add(-time.Hour)).
+		WithAnnotation(restartedAtAnnotation, time.Now().Format(time.RFC3339)).
+		Build()
+	cs := testsupport.TerminatedContainerStatus("app", 1, "Completed", 0, time.Now())
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Recorder: recorder}
+	defer func() {
+		stateStore.forgetPod(namespace, "web")
+		labels := prometheus.Labels{"namespace": namespace}
+		podRestartTotal.DeletePartialMatch(labels)
+		podLastTerminationInfo.DeletePartialMatch(labels)
+		containerTerminationReasonTotal.DeletePartialMatch(labels)
+		podRestartEvents.DeletePartialMatch(labels)
+	}()
+
+	restart := func() {
+		var batch metricBatch
+		r.recordContainerRestart(context.Background(), &batch, pod, cs, resolveWorkload(pod), "")
+		stateStore.commitMetrics(&batch)
+	}
+	restart()
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected no Warning event for an operator-initiated restart, got %q", <-recorder.Events)
+	}
+	if got := testutil.ToFloat64(podRestartTotal.WithLabelValues(namespace, "web", "app", "Completed", "false",
+		"false", nodeReadyAtRestartUnknown, "", causeOperatorInitiated, derivedReasonCompleted, "")); got != 1 {
+		t.Fatal("expected the restart to be counted with cause=operator_initiated")
+	}
+
+	// 下一次重启没有新的注解，照常告警
+	cs.RestartCount = 2
+	restart()
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected a Warning event for the following restart, got %d", len(recorder.Events))
+	}
+}
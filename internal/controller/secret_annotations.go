@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Annotations written on monitored secrets when AnnotateSecrets is enabled.
+// last-checked has day precision so that an unchanged certificate causes at
+// most one write per secret per day.
+const (
+	lastCheckedAnnotation   = "pod-monitor.deraiven.io/last-checked"
+	notAfterAnnotation      = "pod-monitor.deraiven.io/not-after"
+	daysRemainingAnnotation = "pod-monitor.deraiven.io/days-remaining"
+)
+
+// managedSecretAnnotations are the annotations owned by the operator. The
+// pod-monitor.io names written by earlier versions are stripped on the next
+// sync.
+var managedSecretAnnotations = []string{
+	lastCheckedAnnotation,
+	notAfterAnnotation,
+	daysRemainingAnnotation,
+	"pod-monitor.io/last-checked",
+	"pod-monitor.io/not-after",
+	"pod-monitor.io/days-remaining",
+}
+
+// syncSecretAnnotations writes the earliest certificate expiry found in the
+// secret to its annotations, or strips them when the secret no longer holds a
+// monitored certificate. Secrets are never touched while AnnotateSecrets is
+// off, and the patch is skipped when nothing changed.
+func (r *PodMonitorReconciler) syncSecretAnnotations(ctx context.Context, secret *corev1.Secret, now time.Time) error {
+	if !r.AnnotateSecrets {
+		return nil
+	}
+
+	desired := map[string]string{}
+	if notAfter, ok := stateStore.earliestCertificate(secret.Namespace, secret.Name); ok {
+		desired[lastCheckedAnnotation] = now.UTC().Format(time.DateOnly)
+		desired[notAfterAnnotation] = notAfter.UTC().Format(time.RFC3339)
+		desired[daysRemainingAnnotation] = strconv.Itoa(int(math.Floor(notAfter.Sub(now).Hours() / 24)))
+	}
+
+	changed := false
+	for _, key := range managedSecretAnnotations {
+		current, exists := secret.Annotations[key]
+		want, wanted := desired[key]
+		if exists != wanted || current != want {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	for _, key := range managedSecretAnnotations {
+		if want, ok := desired[key]; ok {
+			secret.Annotations[key] = want
+		} else {
+			delete(secret.Annotations, key)
+		}
+	}
+	err := r.Patch(ctx, secret, patch)
+	if len(desired) == 0 && apierrors.IsForbidden(err) {
+		// 仅清理注解时缺少权限不影响监控，不让 reconcile 反复失败
+		logf.FromContext(ctx).Info("Not allowed to remove the operator's annotations from the secret",
+			"error", err.Error())
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestSyncSecretAnnotations(t *testing.T) {
+	const namespace = "secret-annotations-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := testsupport.NewSecret(namespace, "tls", nil)
+	// 旧版本写入的注解在下次同步时移除
+	secret.Annotations = map[string]string{"pod-monitor.io/days-remaining": "3", "team": "web"}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	r := &PodMonitorReconciler{Client: c, AnnotateSecrets: true}
+	stateStore.recordCertificate(namespace, "tls", "tls.crt", now.Add(30*24*time.Hour+time.Hour))
+	defer stateStore.forgetSecret(namespace, "tls")
+
+	if err := r.syncSecretAnnotations(ctx, secret, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"team":                                   "web",
+		"pod-monitor.deraiven.io/last-checked":   "2025-06-01",
+		"pod-monitor.deraiven.io/not-after":      "2025-07-01T13:00:00Z",
+		"pod-monitor.deraiven.io/days-remaining": "30",
+	}
+	if !maps.Equal(secret.Annotations, want) {
+		t.Errorf("expected annotations %v, got %v", want, secret.Annotations)
+	}
+
+	// 同一天内的重复检查不写入
+	version := secret.ResourceVersion
+	if err := r.syncSecretAnnotations(ctx, secret, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.ResourceVersion != version {
+		t.Error("expected an unchanged check not to patch the secret")
+	}
+}
+
+func TestSyncSecretAnnotationsLeavesSecretsAlone(t *testing.T) {
+	const namespace = "secret-annotations-off-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	secret := testsupport.NewSecret(namespace, "tls", nil)
+	secret.Annotations = map[string]string{lastCheckedAnnotation: "2025-05-01", "pod-monitor.io/not-after": "x"}
+	var patches int
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch,
+				_ ...client.PatchOption) error {
+				patches++
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, obj.GetName(), nil)
+			},
+		}).Build()
+
+	// 关闭注解功能时不修改 Secret，即使留有旧注解
+	r := &PodMonitorReconciler{Client: c}
+	if err := r.syncSecretAnnotations(ctx, secret, now); err != nil {
+		t.Fatal(err)
+	}
+	if patches != 0 {
+		t.Errorf("expected no patch while annotating is disabled, got %d", patches)
+	}
+
+	// 没有 patch 权限时清理失败不让 reconcile 失败
+	r.AnnotateSecrets = true
+	if err := r.syncSecretAnnotations(ctx, secret, now); err != nil {
+		t.Errorf("expected a forbidden cleanup to be ignored, got %v", err)
+	}
+	if patches != 1 {
+		t.Errorf("expected one cleanup attempt, got %d", patches)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/certparse"
+)
+
+var (
+	// Secret 中可解析的证书总数；突变通常意味着证书包轮换
+	secretCertCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_secret_cert_count",
+			Help: "Number of certificates successfully parsed across all data keys of the secret",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(secretCertCount)
+}
+
+// countSecretCertificates counts the certificates in every data key of the
+// secret: all CERTIFICATE blocks of PEM data and all certificates of JKS files.
+// Keys above the size limit are skipped and counted in
+// pod_monitor_secret_keys_skipped_total.
+func (r *PodMonitorReconciler) countSecretCertificates(secret *corev1.Secret) int {
+	count := 0
+	for key, data := range secret.Data {
+		if int64(len(data)) > r.maxSecretKeySize() {
+			secretKeysSkippedTotal.With(prometheus.Labels{
+				"namespace":   secret.Namespace,
+				"secret_name": secret.Name,
+			}).Inc()
+			continue
+		}
+		if strings.HasSuffix(key, ".jks") {
+			certs, err := parseCertificatesFromJKS(data, []byte(secret.Annotations[jksPasswordAnnotation]))
+			if err == nil {
+				count += len(certs)
+			}
+			continue
+		}
+		count += len(certparse.ParseBundle(data, r.maxPEMBlocksPerKey()))
+	}
+	return count
+}
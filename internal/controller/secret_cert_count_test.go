@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestSecretCertCount(t *testing.T) {
+	const namespace = "secret-cert-count-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := func(days int) *testsupport.Certificate {
+		return testsupport.CertificateExpiringIn(t, now, days)
+	}
+
+	secrets := map[string]map[string][]byte{
+		// 一个键中的证书包按块计数
+		"bundle": {"ca.crt": testsupport.PEMBundle(cert(100), cert(200), cert(300))},
+		// 证书键与非证书键混合时只计证书
+		"mixed": {
+			"tls.crt":     cert(100).CertPEM(),
+			"tls.key":     cert(100).KeyPEM(),
+			"ca.crt":      cert(200).CertPEM(),
+			"config.yaml": []byte("replicas: 3"),
+		},
+		// 无法解析的二进制数据计为 0
+		"garbage": {"tls.crt": {0x00, 0xff, 0x8a, 0x01, 0x02}},
+	}
+	want := map[string]float64{"bundle": 3, "mixed": 2, "garbage": 0}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+	for name, data := range secrets {
+		builder = builder.WithObjects(testsupport.NewSecret(namespace, name, data))
+	}
+	c := builder.Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+	defer func() {
+		for name := range secrets {
+			forgetSecretCertificates(namespace, name)
+		}
+	}()
+
+	for name := range secrets {
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		if _, err := r.reconcileSecret(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_secret_cert_count",
+			testsupport.Labels{"namespace": namespace, "secret_name": name}, want[name])
+	}
+
+	// Secret 删除后移除序列
+	if err := c.Delete(ctx, testsupport.NewSecret(namespace, "bundle", nil)); err != nil {
+		t.Fatal(err)
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "bundle"}}
+	if _, err := r.reconcileSecret(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_secret_cert_count",
+		testsupport.Labels{"namespace": namespace, "secret_name": "bundle"})
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_secret_cert_count",
+		testsupport.Labels{"namespace": namespace, "secret_name": "mixed"}, 2)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// certKeysAnnotation lists the comma-separated data keys holding PEM
+// certificates, for secrets of any type. When set, only these keys are
+// checked, replacing the default key allowlist for the secret.
+const certKeysAnnotation = "pod-monitor.deraiven.io/cert-keys"
+
+// legacyCertKeysAnnotation is the former name of certKeysAnnotation, still
+// honoured when certKeysAnnotation is not set.
+const legacyCertKeysAnnotation = "pod-monitor.io/cert-keys"
+
+var (
+	// Secret 注解中声明但数据中不存在的证书键，值恒为 1
+	secretMissingKey = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_secret_missing_key",
+			Help: "Set to 1 for every certificate key listed on a secret that is missing from its data.",
+		},
+		[]string{
+			"namespace",   // Secret 所在命名空间
+			"secret_name", // Secret 名称
+			"key",         // 缺失的键
+		},
+	)
+)
+
+func init() {
+	registerMetrics(secretMissingKey)
+}
+
+// annotatedCertificateKeys returns the keys listed in the cert-keys annotation.
+func annotatedCertificateKeys(secret *corev1.Secret) ([]string, bool) {
+	raw, ok := secret.Annotations[certKeysAnnotation]
+	if !ok {
+		raw, ok = secret.Annotations[legacyCertKeysAnnotation]
+	}
+	if !ok {
+		return nil, false
+	}
+	var keys []string
+	for _, key := range strings.Split(raw, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, true
+}
+
+// checkAnnotatedCertificates checks every key listed in the cert-keys
+// annotation and reports the listed keys that are missing from the data.
+func (r *PodMonitorReconciler) checkAnnotatedCertificates(ctx context.Context, secret *corev1.Secret, keys []string) {
+	log := logf.FromContext(ctx)
+
+	for _, key := range keys {
+		data, exists := secret.Data[key]
+		if !exists {
+			secretMissingKey.With(prometheus.Labels{
+				"namespace":   secret.Namespace,
+				"secret_name": secret.Name,
+				"key":         key,
+			}).Set(1)
+			continue
+		}
+		if err := r.checkCertificateExpiration(ctx, secret.Namespace, secret.Name, key, data); err != nil {
+			log.Error(err, "Failed to check certificate expiration", "key", key)
+		}
+	}
+}
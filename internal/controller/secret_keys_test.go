@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestAnnotatedCertificateKeys(t *testing.T) {
+	const namespace = "cert-keys-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	expiringIn := func(days int) []byte {
+		return testsupport.CertificateExpiringIn(t, now, days, "custom.example.com").CertPEM()
+	}
+
+	tests := []struct {
+		name       string
+		annotation string
+		data       map[string][]byte
+		want       map[string]float64
+		missing    []string
+	}{
+		{
+			// 自定义键名不在默认列表中，没有注解时会被忽略
+			name:       "custom key names",
+			annotation: certKeysAnnotation,
+			data: map[string][]byte{
+				"my-cert.pem": expiringIn(10),
+				"another.crt": expiringIn(20),
+			},
+			want: map[string]float64{"my-cert.pem": 10, "another.crt": 20},
+		},
+		{
+			// 注解替换默认键列表，未列出的 ca.crt 不再检查
+			name:       "only listed keys",
+			annotation: certKeysAnnotation,
+			data: map[string][]byte{
+				"my-cert.pem": expiringIn(10),
+				"another.crt": expiringIn(20),
+				"ca.crt":      expiringIn(30),
+			},
+			want: map[string]float64{"my-cert.pem": 10, "another.crt": 20},
+		},
+		{
+			name:       "listed key missing from the data",
+			annotation: certKeysAnnotation,
+			data:       map[string][]byte{"my-cert.pem": expiringIn(10)},
+			want:       map[string]float64{"my-cert.pem": 10},
+			missing:    []string{"another.crt"},
+		},
+		{
+			name:       "legacy annotation",
+			annotation: legacyCertKeysAnnotation,
+			data:       map[string][]byte{"my-cert.pem": expiringIn(10), "another.crt": expiringIn(20)},
+			want:       map[string]float64{"my-cert.pem": 10, "another.crt": 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := testsupport.NewSecret(namespace, "custom", tt.data)
+			secret.Annotations = map[string]string{tt.annotation: "my-cert.pem, another.crt"}
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+			r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "custom"}}
+			defer func() {
+				_ = c.Delete(ctx, secret)
+				_, _ = r.reconcileSecret(ctx, req)
+			}()
+
+			if _, err := r.reconcileSecret(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			secretLabels := testsupport.Labels{"namespace": namespace, "secret_name": "custom"}
+			if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+				secretLabels); n != len(tt.want) {
+				t.Errorf("expected %d monitored keys, got %d", len(tt.want), n)
+			}
+			for key, days := range tt.want {
+				testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+					testsupport.Labels{"namespace": namespace, "secret_name": "custom", "cert_type": key}, days)
+			}
+			if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_secret_missing_key",
+				secretLabels); n != len(tt.missing) {
+				t.Errorf("expected %d missing keys, got %d", len(tt.missing), n)
+			}
+			for _, key := range tt.missing {
+				testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_secret_missing_key",
+					testsupport.Labels{"namespace": namespace, "secret_name": "custom", "key": key}, 1)
+			}
+		})
+	}
+}
+
+func TestCertificateKeysAnnotationUpdate(t *testing.T) {
+	const namespace = "cert-keys-update-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	certPEM := testsupport.CertificateExpiringIn(t, now, 10, "custom.example.com").CertPEM()
+	secret := testsupport.NewSecret(namespace, "custom", map[string][]byte{"server-cert.pem": certPEM})
+	secret.Annotations = map[string]string{certKeysAnnotation: "server-cert.pem,bundle_2024.crt"}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "custom"}}
+	defer forgetSecretCertificates(namespace, "custom")
+	missing := func(key string) testsupport.Labels {
+		return testsupport.Labels{"namespace": namespace, "secret_name": "custom", "key": key}
+	}
+	// update 更新 Secret，经过更新谓词后重新检查
+	update := func(mutate func(*corev1.Secret)) {
+		t.Helper()
+		old := secret.DeepCopy()
+		mutate(secret)
+		if !secretUpdatePredicate().Update(event.UpdateEvent{ObjectOld: old, ObjectNew: secret}) {
+			t.Fatal("expected the update to trigger a reconcile")
+		}
+		if err := c.Update(ctx, secret); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reconcileSecret(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := r.reconcileSecret(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_secret_missing_key", missing("bundle_2024.crt"), 1)
+
+	// 补上缺失的键后不再上报
+	update(func(s *corev1.Secret) { s.Data["bundle_2024.crt"] = certPEM })
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_secret_missing_key", missing("bundle_2024.crt"))
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_certificate_days_until_expiration",
+		testsupport.Labels{"namespace": namespace, "secret_name": "custom", "cert_type": "bundle_2024.crt"}, 10)
+
+	// 只修改注解同样触发检查
+	update(func(s *corev1.Secret) { s.Annotations[certKeysAnnotation] = "server-cert.pem,renamed.crt" })
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_secret_missing_key", missing("renamed.crt"), 1)
+
+	// 删除注解后恢复默认键列表，不再上报缺失的键
+	update(func(s *corev1.Secret) { delete(s.Annotations, certKeysAnnotation) })
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_secret_missing_key",
+		testsupport.Labels{"namespace": namespace, "secret_name": "custom"}); n != 0 {
+		t.Errorf("expected no missing keys without the annotation, got %d", n)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 类型要求包含证书但证书数据缺失或为空的 Secret，值恒为 1；
+	// 通常意味着 cert-manager 续期失败，证书从未写入，区别于解析错误
+	secretMissingCertKey = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_secret_missing_cert_key",
+			Help: "Set to 1 when a secret whose type requires a certificate has that key missing or empty.",
+		},
+		[]string{
+			"namespace",    // Secret 所在命名空间
+			"secret_name",  // Secret 名称
+			"expected_key", // 缺失或为空的证书键
+		},
+	)
+)
+
+func init() {
+	registerMetrics(secretMissingCertKey)
+}
+
+// expectedCertificateKeys returns the data keys that must hold a certificate
+// for the type of the secret.
+func expectedCertificateKeys(secret *corev1.Secret) []string {
+	if secret.Type == corev1.SecretTypeTLS {
+		return []string{corev1.TLSCertKey}
+	}
+	return nil
+}
+
+// updateSecretMissingCertKey reports the expected certificate keys of a
+// secret that are absent or empty, and clears the keys that are now present.
+func updateSecretMissingCertKey(secret *corev1.Secret) {
+	secretMissingCertKey.DeletePartialMatch(prometheus.Labels{
+		"namespace":   secret.Namespace,
+		"secret_name": secret.Name,
+	})
+	for _, key := range expectedCertificateKeys(secret) {
+		if len(secret.Data[key]) > 0 {
+			continue
+		}
+		secretMissingCertKey.With(prometheus.Labels{
+			"namespace":    secret.Namespace,
+			"secret_name":  secret.Name,
+			"expected_key": key,
+		}).Set(1)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestSecretMissingCertKey(t *testing.T) {
+	const namespace = "missing-cert-key-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cert := testsupport.CertificateExpiringIn(t, now, 30, "tls.example.com")
+
+	tests := []struct {
+		name    string
+		secret  *corev1.Secret
+		missing bool
+	}{
+		{"tls.crt present", testsupport.NewTLSSecret(namespace, "tls", cert), false},
+		{"tls.crt absent", func() *corev1.Secret {
+			secret := testsupport.NewTLSSecret(namespace, "tls", cert)
+			delete(secret.Data, corev1.TLSCertKey)
+			return secret
+		}(), true},
+		{"tls.crt empty", func() *corev1.Secret {
+			secret := testsupport.NewTLSSecret(namespace, "tls", cert)
+			secret.Data[corev1.TLSCertKey] = []byte{}
+			return secret
+		}(), true},
+		// 只有 kubernetes.io/tls 类型要求证书键
+		{"opaque secret", testsupport.NewSecret(namespace, "tls", map[string][]byte{"password": []byte("x")}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tt.secret).Build()
+			r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clocktesting.NewFakePassiveClock(now)}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "tls"}}
+			labels := testsupport.Labels{"namespace": namespace, "secret_name": "tls", "expected_key": corev1.TLSCertKey}
+
+			if _, err := r.reconcileSecret(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			if tt.missing {
+				testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_secret_missing_cert_key", labels, 1)
+			} else {
+				testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_secret_missing_cert_key", labels)
+			}
+
+			if err := c.Delete(ctx, tt.secret); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.reconcileSecret(ctx, req); err != nil {
+				t.Fatal(err)
+			}
+			testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_secret_missing_cert_key", labels)
+		})
+	}
+}
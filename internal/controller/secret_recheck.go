@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// secretRecheckInterval is how often certificates are rechecked without
+	// any change to their secret.
+	secretRecheckInterval = time.Hour
+	// secretRecheckCoalesceWindow is how close a scheduled recheck may follow
+	// a check before it is skipped as redundant.
+	secretRecheckCoalesceWindow = 5 * time.Minute
+)
+
+// secretRecheck is the recheck schedule of one secret.
+type secretRecheck struct {
+	// 下一次定期检查的时间
+	next time.Time
+	// 上一次检查时 Secret 的 resourceVersion
+	resourceVersion string
+	// 被上一次检查取代、仍在队列中的定期检查的时间
+	coalesced time.Time
+}
+
+// secretRecheckScheduler spreads the periodic certificate rechecks of many
+// secrets over time. Without it every secret seen at startup is rechecked in
+// the same instant each hour.
+type secretRecheckScheduler struct {
+	mu       sync.Mutex
+	interval time.Duration
+	jitter   float64
+	rand     func() float64
+	secrets  map[types.NamespacedName]*secretRecheck
+}
+
+func newSecretRecheckScheduler(interval time.Duration, jitter float64, rand func() float64) *secretRecheckScheduler {
+	if jitter < 0 {
+		jitter = 0
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	return &secretRecheckScheduler{
+		interval: interval,
+		jitter:   jitter,
+		rand:     rand,
+		secrets:  make(map[types.NamespacedName]*secretRecheck),
+	}
+}
+
+// skip reports whether a reconcile is a scheduled recheck made redundant by
+// a check shortly before it, and if so the delay until the next recheck. The
+// workqueue keeps the earliest of several delayed requeues of a secret, so
+// after an update the earlier recheck still fires and has to be skipped here.
+func (s *secretRecheckScheduler) skip(key types.NamespacedName, resourceVersion string,
+	now time.Time) (time.Duration, bool) {
+	if s == nil {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.secrets[key]
+	if !ok || e.coalesced.IsZero() || now.Before(e.coalesced) {
+		return 0, false
+	}
+	e.coalesced = time.Time{}
+	if e.resourceVersion != resourceVersion || !now.Before(e.next) {
+		return 0, false
+	}
+	return e.next.Sub(now), true
+}
+
+// schedule records a check of a secret and returns the delay until its next
+// recheck, jittered around the interval. The first checks after startup are
+// spread by secretEventHandler, so rechecks stay spread out.
+func (s *secretRecheckScheduler) schedule(key types.NamespacedName, resourceVersion string,
+	now time.Time) time.Duration {
+	if s == nil {
+		return secretRecheckInterval
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.secrets[key]
+	if !ok {
+		e = &secretRecheck{}
+		s.secrets[key] = e
+	} else if e.next.After(now) && e.next.Sub(now) <= secretRecheckCoalesceWindow {
+		// 原定的检查很快就会到来，本次检查已覆盖它
+		e.coalesced = e.next
+	}
+	delay := time.Duration(float64(s.interval) * (1 + s.jitter*(2*s.rand()-1)))
+	e.next = now.Add(delay)
+	e.resourceVersion = resourceVersion
+	return delay
+}
+
+// forget drops the schedule of a deleted secret.
+func (s *secretRecheckScheduler) forget(key types.NamespacedName) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.secrets, key)
+}
+
+// randFloat64 returns Rand, or the default random source if unset.
+func (r *PodMonitorReconciler) randFloat64() func() float64 {
+	if r.Rand != nil {
+		return r.Rand
+	}
+	return rand.Float64
+}
+
+// secretEventHandler enqueues secrets like EnqueueRequestForObject, except
+// that secrets created before startedAt, i.e. listed when the informer
+// starts, are enqueued at a random point within spread. Without it every
+// existing secret is checked at once when the operator starts.
+func (r *PodMonitorReconciler) secretEventHandler(startedAt time.Time, spread time.Duration) handler.EventHandler {
+	enqueue := &handler.EnqueueRequestForObject{}
+	random := r.randFloat64()
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			if spread <= 0 || e.Object == nil || !e.Object.GetCreationTimestamp().Time.Before(startedAt) {
+				enqueue.Create(ctx, e, q)
+				return
+			}
+			q.AddAfter(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(e.Object)},
+				time.Duration(random()*float64(spread)))
+		},
+		UpdateFunc:  enqueue.Update,
+		DeleteFunc:  enqueue.Delete,
+		GenericFunc: enqueue.Generic,
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+// fixedRand returns the given values in turn, repeating the last one.
+func fixedRand(values ...float64) func() float64 {
+	return func() float64 {
+		v := values[0]
+		if len(values) > 1 {
+			values = values[1:]
+		}
+		return v
+	}
+}
+
+func TestSecretRecheckScheduler(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	key := types.NamespacedName{Namespace: "recheck-test", Name: "tls"}
+	s := newSecretRecheckScheduler(time.Hour, 0.1, fixedRand(0, 0.999999, 0.5))
+
+	// 定期检查在 ±10% 内抖动
+	if got := s.schedule(key, "1", now); got != 54*time.Minute {
+		t.Errorf("recheck after %v, want 54m", got)
+	}
+	now = now.Add(54 * time.Minute)
+	if got := s.schedule(key, "1", now); got < 65*time.Minute || got > 66*time.Minute {
+		t.Errorf("recheck after %v, want about 66m", got)
+	}
+	now = now.Add(66 * time.Minute)
+	if got := s.schedule(key, "1", now); got != time.Hour {
+		t.Errorf("recheck after %v, want 1h", got)
+	}
+	if _, ok := s.skip(key, "1", now); ok {
+		t.Error("skipped a recheck that was not coalesced")
+	}
+}
+
+func TestSecretEventHandlerSpreadsStartup(t *testing.T) {
+	startedAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	r := &PodMonitorReconciler{Rand: fixedRand(0.5)}
+	h := r.secretEventHandler(startedAt, time.Hour)
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	existing := testsupport.NewSecret("recheck-startup-test", "existing", nil)
+	existing.CreationTimestamp = metav1.NewTime(startedAt.Add(-24 * time.Hour))
+	created := testsupport.NewSecret("recheck-startup-test", "created", nil)
+	created.CreationTimestamp = metav1.NewTime(startedAt.Add(time.Minute))
+
+	// 启动前已存在的 Secret 延迟入队，之后创建的立即入队
+	h.Create(context.Background(), event.CreateEvent{Object: existing}, q)
+	h.Create(context.Background(), event.CreateEvent{Object: created}, q)
+	if q.Len() != 1 {
+		t.Fatalf("expected only the created secret to be queued, got %d requests", q.Len())
+	}
+	if req, _ := q.Get(); req.Name != "created" {
+		t.Errorf("expected the created secret to be queued, got %s", req.Name)
+	}
+
+	// 更新事件不延迟
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: existing, ObjectNew: existing}, q)
+	if q.Len() != 1 {
+		t.Errorf("expected an update to be queued immediately, got %d requests", q.Len())
+	}
+}
+
+func TestSecretRecheckCoalescing(t *testing.T) {
+	const namespace = "recheck-coalesce-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(now)
+	secret := testsupport.NewTLSSecret(namespace, "tls", testsupport.CertificateExpiringIn(t, now, 30))
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clock}
+	r.secretRechecks = newSecretRecheckScheduler(time.Hour, 0, fixedRand(0.5))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "tls"}}
+	defer forgetSecretCertificates(namespace, "tls")
+
+	reconcile := func() time.Duration {
+		t.Helper()
+		result, err := r.reconcileSecret(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.RequeueAfter
+	}
+
+	if got := reconcile(); got != time.Hour {
+		t.Fatalf("first recheck after %v, want 1h", got)
+	}
+
+	// 定期检查前 2 分钟 Secret 被更新，随后到来的定期检查被跳过
+	clock.SetTime(now.Add(58 * time.Minute))
+	secret.Data["ca.crt"] = []byte("updated")
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if got := reconcile(); got != time.Hour {
+		t.Fatalf("recheck after update in %v, want 1h", got)
+	}
+	clock.SetTime(now.Add(60 * time.Minute))
+	if got := reconcile(); got != 58*time.Minute {
+		t.Errorf("coalesced recheck requeued after %v, want 58m", got)
+	}
+	if _, ok := r.secretRechecks.skip(req.NamespacedName, secret.ResourceVersion, r.now()); ok {
+		t.Error("a coalesced recheck was skipped twice")
+	}
+
+	// 到期的定期检查照常执行
+	clock.SetTime(now.Add(118 * time.Minute))
+	if got := reconcile(); got != time.Hour {
+		t.Errorf("scheduled recheck requeued after %v, want 1h", got)
+	}
+
+	// Secret 删除后调度被清理；删除的请求经 Reconcile 分派
+	if err := c.Delete(ctx, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.secretRechecks.secrets[req.NamespacedName]; ok {
+		t.Error("schedule of a deleted secret was kept")
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Patching secrets is optional, see FeatureForceRefresh and FeatureAnnotateSecrets.
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=patch
+
+// forceRefreshAnnotation triggers an immediate re-check of a secret when its
+// value changes. The operator removes it once the secret was reconciled,
+// unless it lacks the permission to patch secrets.
+const forceRefreshAnnotation = "pod-monitor.deraiven.io/force-refresh"
+
+// secretUpdatePredicate passes secret updates that change the data, the
+// annotations read by the operator, or set a new force-refresh value. Writes
+// of the operator itself (removing force-refresh, the managed annotations) are
+// ignored.
+func secretUpdatePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return true
+			}
+			newSecret, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return true
+			}
+			if forceRefreshRequested(oldSecret, newSecret) {
+				return true
+			}
+			if oldSecret.Type != newSecret.Type || !reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+				return true
+			}
+			return !maps.Equal(userAnnotations(oldSecret.Annotations), userAnnotations(newSecret.Annotations))
+		},
+	}
+}
+
+// forceRefreshRequested reports whether the force-refresh annotation was set
+// to a new, non-empty value.
+func forceRefreshRequested(oldSecret, newSecret *corev1.Secret) bool {
+	value := newSecret.Annotations[forceRefreshAnnotation]
+	return value != "" && value != oldSecret.Annotations[forceRefreshAnnotation]
+}
+
+// userAnnotations returns the annotations without those written by the operator.
+func userAnnotations(annotations map[string]string) map[string]string {
+	out := maps.Clone(annotations)
+	delete(out, forceRefreshAnnotation)
+	for _, key := range managedSecretAnnotations {
+		delete(out, key)
+	}
+	return out
+}
+
+// acknowledgeForceRefresh removes the force-refresh annotation from a secret
+// that has just been reconciled.
+func (r *PodMonitorReconciler) acknowledgeForceRefresh(ctx context.Context, secret *corev1.Secret) error {
+	if _, ok := secret.Annotations[forceRefreshAnnotation]; !ok || r.disableForceRefreshAck {
+		return nil
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+	delete(secret.Annotations, forceRefreshAnnotation)
+	return r.Patch(ctx, secret, patch)
+}
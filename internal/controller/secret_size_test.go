@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestSecretSizeWarningOnThresholdCrossing(t *testing.T) {
+	const namespace = "secret-size-test"
+	ctx := context.Background()
+	secret := testsupport.NewSecret(namespace, "bundle", map[string][]byte{"data": make([]byte, 200)})
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Recorder: recorder, SecretSizeWarnThreshold: 100}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "bundle"}}
+	defer forgetSecretCertificates(namespace, "bundle")
+	check := func(size int, wantEvents int) {
+		t.Helper()
+		secret.Data["data"] = make([]byte, size)
+		if err := c.Update(ctx, secret); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reconcileSecret(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(recorder.Events); got != wantEvents {
+			t.Fatalf("size %d: expected %d SecretSizeLarge events, got %d", size, wantEvents, got)
+		}
+		for len(recorder.Events) > 0 {
+			<-recorder.Events
+		}
+	}
+
+	// 首次超过阈值时发出事件，之后的检查不再重复
+	check(200, 1)
+	check(200, 0)
+	check(300, 0)
+	// 降回阈值以下后再次超过时重新发出
+	check(50, 0)
+	check(200, 1)
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+)
+
+// secretWatchConfigReloadInterval is how often --secret-watch-config is
+// reread. Mounted ConfigMaps are updated by the kubelet within about a minute.
+const secretWatchConfigReloadInterval = 30 * time.Second
+
+// secretWatchConfig is the format of the --secret-watch-config file.
+type secretWatchConfig struct {
+	// Secrets are monitored by namespace and name.
+	Secrets []struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"secrets,omitempty"`
+	// Selectors monitor the secrets matching a label selector, in one
+	// namespace or, without one, in all namespaces.
+	Selectors []struct {
+		Namespace     string `json:"namespace,omitempty"`
+		LabelSelector string `json:"labelSelector"`
+	} `json:"selectors,omitempty"`
+}
+
+// secretSelector is a parsed selector of the configuration.
+type secretSelector struct {
+	namespace string
+	selector  labels.Selector
+}
+
+// secretWatchSet is the resolved --secret-watch-config: the secrets the
+// Secret watch reconciles besides the ones named by other flags.
+type secretWatchSet struct {
+	secrets   map[types.NamespacedName]struct{}
+	selectors []secretSelector
+}
+
+// parseSecretWatchConfig resolves the content of a --secret-watch-config
+// file. Unknown fields and invalid selectors are errors.
+func parseSecretWatchConfig(data []byte) (*secretWatchSet, error) {
+	var config secretWatchConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing secret watch configuration: %w", err)
+	}
+	set := &secretWatchSet{secrets: make(map[types.NamespacedName]struct{})}
+	for _, secret := range config.Secrets {
+		if secret.Namespace == "" || secret.Name == "" {
+			return nil, fmt.Errorf("secret watch configuration: secret %s/%s needs a namespace and a name",
+				secret.Namespace, secret.Name)
+		}
+		set.secrets[types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}] = struct{}{}
+	}
+	for _, sel := range config.Selectors {
+		selector, err := labels.Parse(sel.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("secret watch configuration: invalid label selector %q: %w", sel.LabelSelector, err)
+		}
+		if selector.Empty() {
+			return nil, fmt.Errorf("secret watch configuration: empty label selector, list the namespace's " +
+				"secrets or disable --secret-watch-config to monitor all secrets")
+		}
+		set.selectors = append(set.selectors, secretSelector{namespace: sel.Namespace, selector: selector})
+	}
+	return set, nil
+}
+
+// matches reports whether the configuration selects a secret.
+func (s *secretWatchSet) matches(obj client.Object) bool {
+	if _, ok := s.secrets[client.ObjectKeyFromObject(obj)]; ok {
+		return true
+	}
+	for _, sel := range s.selectors {
+		if (sel.namespace == "" || sel.namespace == obj.GetNamespace()) &&
+			sel.selector.Matches(labels.Set(obj.GetLabels())) {
+			return true
+		}
+	}
+	return false
+}
+
+// empty reports whether the configuration selects no secret at all.
+func (s *secretWatchSet) empty() bool {
+	return len(s.secrets) == 0 && len(s.selectors) == 0
+}
+
+// describe lists the configured secrets and selectors for logging.
+func (s *secretWatchSet) describe() []string {
+	var out []string
+	for key := range s.secrets {
+		out = append(out, key.String())
+	}
+	slices.Sort(out)
+	for _, sel := range s.selectors {
+		namespace := sel.namespace
+		if namespace == "" {
+			namespace = "*"
+		}
+		out = append(out, fmt.Sprintf("%s[%s]", namespace, sel.selector))
+	}
+	return out
+}
+
+// isNamedSecret reports whether a secret is named by the etcd, Linkerd or
+// kubeadm flags; those are watched whatever --secret-watch-config says.
+func (r *PodMonitorReconciler) isNamedSecret(namespace, name string) bool {
+	return r.isEtcdSecret(namespace, name) || r.isLinkerdIssuer(namespace, name) ||
+		(r.KubeadmMode && namespace == kubeadmCertsSecret.Namespace && name == kubeadmCertsSecret.Name)
+}
+
+// namedSecrets returns the secrets named by the etcd, Linkerd and kubeadm
+// flags.
+func (r *PodMonitorReconciler) namedSecrets() []string {
+	var named []string
+	if r.WatchEtcdCerts {
+		for _, name := range r.EtcdSecretNames {
+			named = append(named, etcdSecretNamespace+"/"+name)
+		}
+	}
+	if r.LinkerdMode {
+		named = append(named, r.linkerdNamespace()+"/"+linkerdIssuerSecret)
+	}
+	if r.KubeadmMode {
+		named = append(named, kubeadmCertsSecret.Namespace+"/"+kubeadmCertsSecret.Name)
+	}
+	return named
+}
+
+// watchedBy reports whether a secret is reconciled under a configuration;
+// a nil configuration watches every secret.
+func (r *PodMonitorReconciler) watchedBy(set *secretWatchSet, obj client.Object) bool {
+	return set == nil || r.isNamedSecret(obj.GetNamespace(), obj.GetName()) || set.matches(obj)
+}
+
+// secretWatched reports whether a secret is reconciled under the current
+// configuration.
+func (r *PodMonitorReconciler) secretWatched(obj client.Object) bool {
+	return r.watchedBy(r.secretWatch.Load(), obj)
+}
+
+// secretWatchEmpty reports whether the configuration selects no secret, in
+// which case the Secret watch is not registered.
+func (r *PodMonitorReconciler) secretWatchEmpty() bool {
+	set := r.secretWatch.Load()
+	return set != nil && set.empty() && len(r.namedSecrets()) == 0
+}
+
+// loadSecretWatchConfig reads and applies --secret-watch-config at setup.
+func (r *PodMonitorReconciler) loadSecretWatchConfig() ([]byte, error) {
+	data, err := os.ReadFile(r.SecretWatchConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading secret watch configuration: %w", err)
+	}
+	set, err := parseSecretWatchConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	r.secretWatch.Store(set)
+	return data, nil
+}
+
+// secretWatchSource returns the Secret watch: the secrets allowed by the
+// WatchFilter and the configuration, reconciled on creation, deletion and
+// relevant updates.
+func (r *PodMonitorReconciler) secretWatchSource(c cache.Cache, filter WatchFilter) source.Source {
+	// 启动时已存在的 Secret 在 SecretStartupSpread 内随机错开首次检查
+	return source.Kind[client.Object](c, &corev1.Secret{}, r.secretEventHandler(r.now(), r.SecretStartupSpread),
+		watchFilterPredicate(filter.AllowSecret),
+		// 谓词每次读取当前配置，配置重新加载后立即生效
+		predicate.NewPredicateFuncs(r.secretWatched),
+		predicate.Or(secretUpdatePredicate(), r.etcdSecretPredicate()))
+}
+
+// setupSecretWatch adds the Secret watch to the builder, unless the
+// configuration selects no secret. With --secret-watch-config it also adds
+// the channel through which reloads enqueue the secrets whose monitoring
+// changed, and the reloader.
+func (r *PodMonitorReconciler) setupSecretWatch(mgr manager.Manager, b *builder.Builder,
+	filter WatchFilter) (*builder.Builder, error) {
+	if r.SecretWatchConfigFile != "" {
+		data, err := r.loadSecretWatchConfig()
+		if err != nil {
+			return nil, err
+		}
+		r.secretWatchEvents = make(chan event.GenericEvent, secretWatchEventBuffer)
+		b = b.WatchesRawSource(source.Channel(r.secretWatchEvents, &handler.EnqueueRequestForObject{}))
+		if err := mgr.Add(&secretWatchReloader{r: r, interval: secretWatchConfigReloadInterval,
+			last: data}); err != nil {
+			return nil, err
+		}
+	}
+	r.watchCache = mgr.GetCache()
+	r.watchFilterForSecrets = filter
+	if r.secretWatchEmpty() {
+		// 配置未选中任何 Secret：不注册监听，也不缓存 Secret；重新加载后再注册
+		r.skipSecretWatch.Store(true)
+		return b, nil
+	}
+	return b.WatchesRawSource(r.secretWatchSource(mgr.GetCache(), filter)), nil
+}
+
+// secretWatchEventBuffer bounds the secrets enqueued by one reload.
+const secretWatchEventBuffer = 1024
+
+// applySecretWatchConfig switches to a reloaded configuration. It registers
+// the Secret watch if it was skipped and enqueues the cached secrets whose
+// monitoring changed: newly selected secrets are checked, deselected ones
+// forgotten.
+func (r *PodMonitorReconciler) applySecretWatchConfig(ctx context.Context, data []byte) error {
+	log := logf.FromContext(ctx)
+	set, err := parseSecretWatchConfig(data)
+	if err != nil {
+		return err
+	}
+	previous := r.secretWatch.Swap(set)
+	log.Info("Secret watch configuration reloaded", r.secretWatchKeysAndValues(r.watchFilter())...)
+
+	if r.skipSecretWatch.Load() {
+		if r.secretWatchEmpty() || r.secretController == nil {
+			return nil
+		}
+		// 新注册的监听在初始 list 时会 reconcile 所有选中的 Secret
+		if err := r.secretController.Watch(r.secretWatchSource(r.watchCache, r.watchFilterForSecrets)); err != nil {
+			return fmt.Errorf("registering the Secret watch: %w", err)
+		}
+		r.skipSecretWatch.Store(false)
+		return nil
+	}
+
+	var secrets corev1.SecretList
+	if err := r.List(ctx, &secrets); err != nil {
+		return fmt.Errorf("listing secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if r.watchedBy(previous, secret) == r.watchedBy(set, secret) {
+			continue
+		}
+		select {
+		case r.secretWatchEvents <- event.GenericEvent{Object: secret}:
+		default:
+			log.Info("Too many secrets changed by the reload, some are only rechecked on their next update",
+				"namespace", secret.Namespace, "secret", secret.Name)
+			return nil
+		}
+	}
+	return nil
+}
+
+// secretWatchReloader rereads --secret-watch-config periodically and applies
+// it when its content changed. It runs on every replica so that a new
+// leader starts with the current configuration.
+type secretWatchReloader struct {
+	r        *PodMonitorReconciler
+	interval time.Duration
+	last     []byte
+}
+
+func (l *secretWatchReloader) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("secret-watch-config")
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			data, err := os.ReadFile(l.r.SecretWatchConfigFile)
+			if err != nil {
+				log.Error(err, "Unable to read the secret watch configuration, keeping the current one")
+				continue
+			}
+			if bytes.Equal(data, l.last) {
+				continue
+			}
+			if err := l.r.applySecretWatchConfig(logf.IntoContext(ctx, log), data); err != nil {
+				log.Error(err, "Unable to apply the secret watch configuration, keeping the current one")
+				continue
+			}
+			l.last = data
+		}
+	}
+}
+
+// NeedLeaderElection returns false: every replica keeps its configuration
+// current.
+func (l *secretWatchReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// logSecretWatch logs at startup which secrets the Secret watch reconciles:
+// those allowed by the WatchFilter and --secret-watch-config, plus the etcd,
+// Linkerd issuer and kubeadm secrets named by the configuration.
+func (r *PodMonitorReconciler) logSecretWatch(log logr.Logger, filter WatchFilter) {
+	if r.DisableSecretWatch {
+		log.Info("Secret watch disabled, certificates are not monitored")
+		return
+	}
+	if r.skipSecretWatch.Load() {
+		log.Info("No secrets configured, the Secret watch is not registered until the configuration selects one",
+			"secretWatchConfig", r.SecretWatchConfigFile)
+		return
+	}
+	log.Info("Watching secrets", r.secretWatchKeysAndValues(filter)...)
+}
+
+// secretWatchKeysAndValues describes the secrets reconciled by the Secret
+// watch as logging key/value pairs.
+func (r *PodMonitorReconciler) secretWatchKeysAndValues(filter WatchFilter) []any {
+	// 未配置自动发现命名空间时表示所有命名空间
+	discover := "disabled"
+	if r.AutoDiscoverCerts {
+		discover = "all namespaces"
+		if len(r.AutoDiscoverNamespaces) > 0 {
+			discover = fmt.Sprint(r.AutoDiscoverNamespaces)
+		}
+	}
+	kv := []any{
+		"watchFilter", fmt.Sprintf("%T", filter),
+		"namedSecrets", r.namedSecrets(),
+		"autoDiscover", discover,
+	}
+	if set := r.secretWatch.Load(); set != nil {
+		kv = append(kv, "configuredSecrets", set.describe())
+	}
+	return kv
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestParseSecretWatchConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    []string
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"secrets and selectors", `
+secrets:
+- {namespace: web, name: tls}
+selectors:
+- labelSelector: cert-monitor=true
+- {namespace: batch, labelSelector: "team in (a, b)"}
+`, []string{"web/tls", "*[cert-monitor=true]", "batch[team in (a,b)]"}, false},
+		{"missing name", "secrets: [{namespace: web}]", nil, true},
+		{"invalid selector", "selectors: [{labelSelector: '=='}]", nil, true},
+		{"empty selector", "selectors: [{namespace: web}]", nil, true},
+		{"unknown field", "namespaces: [web]", nil, true},
+	}
+	for _, tt := range tests {
+		set, err := parseSecretWatchConfig([]byte(tt.config))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := set.describe(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSecretWatched(t *testing.T) {
+	set, err := parseSecretWatchConfig([]byte(`
+secrets: [{namespace: web, name: tls}]
+selectors: [{namespace: batch, labelSelector: cert-monitor=true}]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &PodMonitorReconciler{LinkerdMode: true}
+	labeled := testsupport.NewSecret("batch", "labeled", nil)
+	labeled.Labels = map[string]string{"cert-monitor": "true"}
+	tests := []struct {
+		name   string
+		secret client.Object
+		want   bool
+	}{
+		{"named", testsupport.NewSecret("web", "tls", nil), true},
+		{"other name", testsupport.NewSecret("web", "other", nil), false},
+		{"labeled", labeled, true},
+		{"unlabeled", testsupport.NewSecret("batch", "unlabeled", nil), false},
+		{"linkerd issuer", testsupport.NewSecret("linkerd", linkerdIssuerSecret, nil), true},
+	}
+
+	// 未配置时监听所有 Secret
+	for _, tt := range tests {
+		if !r.secretWatched(tt.secret) {
+			t.Errorf("%s: expected every secret to be watched without a configuration", tt.name)
+		}
+	}
+	r.secretWatch.Store(set)
+	for _, tt := range tests {
+		if got := r.secretWatched(tt.secret); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSecretWatchEmpty(t *testing.T) {
+	empty, err := parseSecretWatchConfig([]byte("secrets: []"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &PodMonitorReconciler{}
+	if r.secretWatchEmpty() {
+		t.Error("expected no configuration to watch all secrets")
+	}
+	r.secretWatch.Store(empty)
+	if !r.secretWatchEmpty() {
+		t.Error("expected a configuration without secrets to skip the watch")
+	}
+
+	// 其他选项指定的 Secret 仍需监听
+	r.KubeadmMode = true
+	if r.secretWatchEmpty() {
+		t.Error("expected the kubeadm-certs secret to need the watch")
+	}
+}
+
+func TestSecretWatchReload(t *testing.T) {
+	const namespace = "secret-watch-reload-test"
+	ctx := context.Background()
+	now := time.Now()
+	first := testsupport.NewTLSSecret(namespace, "first", testsupport.CertificateExpiringIn(t, now, 30))
+	second := testsupport.NewTLSSecret(namespace, "second", testsupport.CertificateExpiringIn(t, now, 30))
+	second.Labels = map[string]string{"cert-monitor": "true"}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(first, second).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	r.secretWatchEvents = make(chan event.GenericEvent, 2)
+	set, err := parseSecretWatchConfig([]byte("secrets: [{namespace: " + namespace + ", name: first}]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.secretWatch.Store(set)
+	defer forgetSecretCertificates(namespace, "first")
+	defer forgetSecretCertificates(namespace, "second")
+	reconcile := func(name string) {
+		t.Helper()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	monitored := func(name string) int {
+		t.Helper()
+		return testsupport.CountSeries(t, metrics.Registry, "pod_monitor_certificate_expiration_timestamp_seconds",
+			testsupport.Labels{"namespace": namespace, "secret_name": name})
+	}
+
+	reconcile("first")
+	reconcile("second")
+	if monitored("first") != 1 || monitored("second") != 0 {
+		t.Fatalf("expected only the configured secret to be monitored, got first=%d second=%d",
+			monitored("first"), monitored("second"))
+	}
+
+	// 重新加载后选择器替换了原来的名称：两个 Secret 的监控状态都变化，均入队
+	reloaded := "selectors: [{namespace: " + namespace + ", labelSelector: cert-monitor=true}]"
+	if err := r.applySecretWatchConfig(ctx, []byte(reloaded)); err != nil {
+		t.Fatal(err)
+	}
+	var queued []string
+	for len(r.secretWatchEvents) > 0 {
+		queued = append(queued, (<-r.secretWatchEvents).Object.GetName())
+	}
+	slices.Sort(queued)
+	if !slices.Equal(queued, []string{"first", "second"}) {
+		t.Fatalf("expected both secrets to be enqueued, got %v", queued)
+	}
+	for _, name := range queued {
+		reconcile(name)
+	}
+	if monitored("first") != 0 || monitored("second") != 1 {
+		t.Errorf("expected the reloaded configuration to be monitored, got first=%d second=%d",
+			monitored("first"), monitored("second"))
+	}
+
+	// 无法解析的配置不替换当前配置
+	if err := r.applySecretWatchConfig(ctx, []byte("selectors: [{labelSelector: '=='}]")); err == nil {
+		t.Error("expected an invalid configuration to be rejected")
+	}
+	if !r.secretWatched(second) {
+		t.Error("expected the current configuration to be kept")
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// dangerousCapabilities are the added capabilities that give a container
+// control over the node's network stack or most of the kernel.
+var dangerousCapabilities = []corev1.Capability{"NET_ADMIN", "SYS_ADMIN"}
+
+var (
+	// 容器安全上下文清单，值恒为 1；安全团队可与重启指标关联告警
+	containerSecurityContextInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_security_context_info",
+			Help: "Security-relevant settings of each container's securityContext. The value is always 1.",
+		},
+		[]string{
+			"namespace",                  // Pod 所在命名空间
+			"pod",                        // Pod 名称
+			"container",                  // 容器名称（包括 init 容器）
+			"privileged",                 // securityContext.privileged
+			"allow_privilege_escalation", // securityContext.allowPrivilegeEscalation；未设置时 Linux 默认允许，记为 true
+			"has_dangerous_caps",         // capabilities.add 是否包含 NET_ADMIN 或 SYS_ADMIN
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerSecurityContextInfo))
+}
+
+// updateSecurityContextInfo exports the security context of every container
+// of the pod. Container security contexts are immutable, so the series never
+// needs to be replaced.
+func updateSecurityContextInfo(b *metricBatch, pod *corev1.Pod) {
+	containers := append(slices.Clone(pod.Spec.InitContainers), pod.Spec.Containers...)
+	for _, c := range containers {
+		privileged, escalation, dangerousCaps := false, true, false
+		if sc := c.SecurityContext; sc != nil {
+			privileged = sc.Privileged != nil && *sc.Privileged
+			escalation = sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation
+			if sc.Capabilities != nil {
+				dangerousCaps = slices.ContainsFunc(sc.Capabilities.Add, func(c corev1.Capability) bool {
+					return slices.Contains(dangerousCapabilities, c)
+				})
+			}
+		}
+		b.set(containerSecurityContextInfo, 1, pod.Namespace, pod.Name, c.Name, strconv.FormatBool(privileged),
+			strconv.FormatBool(escalation), strconv.FormatBool(dangerousCaps))
+	}
+}
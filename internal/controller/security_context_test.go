@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestSecurityContextInfo(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "security-context-test", Name: "web"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "setup", SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"CHOWN", "NET_ADMIN"}},
+			}}},
+			Containers: []corev1.Container{
+				{Name: "app", SecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: ptr.To(false)}},
+				{Name: "agent", SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)}},
+			},
+		},
+	}
+	defer containerSecurityContextInfo.Reset()
+
+	var batch metricBatch
+	updateSecurityContextInfo(&batch, pod)
+	stateStore.commitMetrics(&batch)
+
+	if n := testutil.CollectAndCount(containerSecurityContextInfo); n != 3 {
+		t.Fatalf("expected one series per container, got %d", n)
+	}
+	for _, labels := range [][]string{
+		{"setup", "false", "true", "true"},
+		{"app", "false", "false", "false"},
+		{"agent", "true", "true", "false"},
+	} {
+		lv := append([]string{pod.Namespace, pod.Name}, labels...)
+		if got := testutil.ToFloat64(containerSecurityContextInfo.WithLabelValues(lv...)); got != 1 {
+			t.Errorf("expected series %v to be exported, got %v", labels, got)
+		}
+	}
+}
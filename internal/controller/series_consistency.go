@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var (
+	// 注册表中的序列数与状态存储预期的序列数之差
+	seriesConsistencyDrift = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_series_consistency_drift",
+			Help: "Absolute difference between the number of series of a metric family in the registry and " +
+				"the number the state store expects. Non-zero values point to series deleted or left behind by mistake.",
+		},
+		[]string{
+			"metric", // 指标族名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(seriesConsistencyDrift)
+}
+
+// expectedSeriesCounts returns, per checked metric family, the number of
+// series the state store implies: one certificate series per checked
+// certificate, and one repeated exit code series per container with a
+// non-zero streak of at least repeatedExitCodeMinRun.
+func (s *restartStateStore) expectedSeriesCounts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	streaks := 0
+	for _, ring := range s.exitCodes {
+		if ring.latest() != 0 && ring.runLength() >= repeatedExitCodeMinRun {
+			streaks++
+		}
+	}
+	return map[string]int{
+		"pod_monitor_certificate_expiration_timestamp_seconds": len(s.certificates),
+		"pod_monitor_certificate_days_until_expiration":        len(s.certificates),
+		"pod_monitor_certificate_info":                         len(s.certificates),
+		"pod_monitor_container_repeated_exit_code":             streaks,
+	}
+}
+
+// SeriesConsistencyChecker periodically compares the series in the registry
+// with the series the state store expects and exports the difference as
+// pod_monitor_series_consistency_drift. Only the leader reconciles, so it
+// only runs on the leader.
+type SeriesConsistencyChecker struct {
+	gatherer prometheus.Gatherer
+	interval time.Duration
+}
+
+var _ manager.Runnable = &SeriesConsistencyChecker{}
+var _ manager.LeaderElectionRunnable = &SeriesConsistencyChecker{}
+
+// NewSeriesConsistencyChecker creates a checker that gathers the given
+// registry every interval.
+func NewSeriesConsistencyChecker(gatherer prometheus.Gatherer, interval time.Duration) *SeriesConsistencyChecker {
+	return &SeriesConsistencyChecker{gatherer: gatherer, interval: interval}
+}
+
+// Start runs the check every interval until the context is cancelled.
+func (c *SeriesConsistencyChecker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns true so the check only runs on the leader.
+func (c *SeriesConsistencyChecker) NeedLeaderElection() bool {
+	return true
+}
+
+// check gathers the registry once and updates the drift of every checked
+// family. The expectation is read before and after gathering, and the smaller
+// difference is reported, so a reconcile running concurrently is not
+// mistaken for drift.
+func (c *SeriesConsistencyChecker) check(ctx context.Context) {
+	log := logf.FromContext(ctx).WithName("series-consistency")
+
+	before := stateStore.expectedSeriesCounts()
+	families, err := c.gatherer.Gather()
+	if err != nil {
+		log.Error(err, "Failed to gather metrics")
+		return
+	}
+	after := stateStore.expectedSeriesCounts()
+
+	actual := make(map[string]int, len(families))
+	for _, family := range families {
+		actual[family.GetName()] = len(family.GetMetric())
+	}
+	for name := range before {
+		drift := min(absDiff(actual[name], before[name]), absDiff(actual[name], after[name]))
+		seriesConsistencyDrift.WithLabelValues(name).Set(float64(drift))
+		if drift != 0 {
+			log.Info("Metric series do not match the state store", "metric", name,
+				"series", actual[name], "expected", after[name])
+		}
+	}
+}
+
+func absDiff(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
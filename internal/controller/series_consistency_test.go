@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSeriesConsistencyDrift(t *testing.T) {
+	const namespace = "series-consistency-test"
+	const family = "pod_monitor_certificate_expiration_timestamp_seconds"
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(certificateExpirationTime)
+	checker := NewSeriesConsistencyChecker(registry, time.Minute)
+	drift := func() float64 {
+		checker.check(context.Background())
+		return testutil.ToFloat64(seriesConsistencyDrift.WithLabelValues(family))
+	}
+	labels := prometheus.Labels{"namespace": namespace}
+	defer func() {
+		certificateExpirationTime.DeletePartialMatch(labels)
+		stateStore.forgetSecret(namespace, "a")
+		stateStore.forgetSecret(namespace, "b")
+	}()
+	baseline := drift()
+
+	// 指标与状态同时写入时不产生偏差
+	for _, name := range []string{"a", "b"} {
+		stateStore.recordCertificate(namespace, name, "tls.crt", time.Now())
+		certificateExpirationTime.WithLabelValues(namespace, name, "tls.crt", certificateSourceSecret, "").Set(1)
+	}
+	if got := drift(); got != baseline {
+		t.Fatalf("expected no new drift, got %v (baseline %v)", got, baseline)
+	}
+
+	// 过宽的 DeletePartialMatch 删除了仍被跟踪的序列
+	certificateExpirationTime.DeletePartialMatch(labels)
+	if got := drift(); got != baseline+2 {
+		t.Fatalf("expected a drift of 2 after deleting tracked series, got %v (baseline %v)", got, baseline)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;create;update
+//+kubebuilder:rbac:groups="",resources=services,verbs=get
+
+// serviceAccountNamespaceFile holds the namespace the operator runs in.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// ServiceMonitorInstaller creates or updates a Prometheus Operator
+// ServiceMonitor for the operator's own metrics Service once the replica
+// becomes leader. Nothing is done when the ServiceMonitor CRD is not
+// installed.
+type ServiceMonitorInstaller struct {
+	client client.Client
+	reader client.Reader
+	mapper meta.RESTMapper
+	scheme *runtime.Scheme
+
+	namespace   string
+	serviceName string
+	portName    string
+	secure      bool
+}
+
+var _ manager.Runnable = &ServiceMonitorInstaller{}
+var _ manager.LeaderElectionRunnable = &ServiceMonitorInstaller{}
+
+// NewServiceMonitorInstaller creates an installer for the metrics Service
+// serviceName in namespace, scraped through the named port over HTTPS when
+// secure is set. An empty namespace means the operator's own namespace.
+func NewServiceMonitorInstaller(mgr manager.Manager, namespace, serviceName, portName string,
+	secure bool) *ServiceMonitorInstaller {
+	return &ServiceMonitorInstaller{
+		client:      mgr.GetClient(),
+		reader:      mgr.GetAPIReader(),
+		mapper:      mgr.GetRESTMapper(),
+		scheme:      mgr.GetScheme(),
+		namespace:   namespace,
+		serviceName: serviceName,
+		portName:    portName,
+		secure:      secure,
+	}
+}
+
+// Start installs the ServiceMonitor once. Failures are logged rather than
+// returned so that they never stop the manager.
+func (s *ServiceMonitorInstaller) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("service-monitor")
+
+	installed, err := s.crdInstalled()
+	if err != nil {
+		log.Error(err, "Unable to check for the ServiceMonitor CRD")
+		return nil
+	}
+	if !installed {
+		log.Info("ServiceMonitor CRD not installed, skipping ServiceMonitor creation")
+		return nil
+	}
+
+	if err := s.install(ctx); err != nil {
+		log.Error(err, "Failed to create or update ServiceMonitor", "service", s.serviceName)
+		return nil
+	}
+	log.Info("ServiceMonitor is up to date", "namespace", s.namespace, "name", s.serviceName)
+	return nil
+}
+
+// NeedLeaderElection returns true so only one replica writes the ServiceMonitor.
+func (s *ServiceMonitorInstaller) NeedLeaderElection() bool {
+	return true
+}
+
+// crdInstalled reports whether the API server serves monitoring.coreos.com/v1
+// ServiceMonitors.
+func (s *ServiceMonitorInstaller) crdInstalled() (bool, error) {
+	gk := monitoringv1.SchemeGroupVersion.WithKind(monitoringv1.ServiceMonitorsKind).GroupKind()
+	_, err := s.mapper.RESTMapping(gk, monitoringv1.SchemeGroupVersion.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *ServiceMonitorInstaller) install(ctx context.Context) error {
+	if s.namespace == "" {
+		data, err := os.ReadFile(serviceAccountNamespaceFile)
+		if err != nil {
+			return fmt.Errorf("determining the operator namespace: %w", err)
+		}
+		s.namespace = strings.TrimSpace(string(data))
+	}
+
+	// 由 Service 自身决定 selector，并作为 owner，Service 删除时 ServiceMonitor 随之回收
+	var service corev1.Service
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: s.serviceName}, &service); err != nil {
+		return fmt.Errorf("getting metrics service: %w", err)
+	}
+
+	desired, err := s.serviceMonitorFor(&service)
+	if err != nil {
+		return err
+	}
+
+	var existing monitoringv1.ServiceMonitor
+	err = s.reader.Get(ctx, client.ObjectKeyFromObject(desired), &existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		return s.client.Create(ctx, desired)
+	case err != nil:
+		return err
+	}
+
+	// 保留用户添加的标签（例如 Prometheus 的 serviceMonitorSelector 所需的 release 标签）
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		existing.Labels[k] = v
+	}
+	existing.OwnerReferences = desired.OwnerReferences
+	existing.Spec = desired.Spec
+	return s.client.Update(ctx, &existing)
+}
+
+// serviceMonitorFor builds the ServiceMonitor scraping /metrics of the given
+// Service.
+func (s *ServiceMonitorInstaller) serviceMonitorFor(service *corev1.Service) (*monitoringv1.ServiceMonitor, error) {
+	if len(service.Labels) == 0 {
+		// 空 selector 会匹配命名空间内的所有 Service
+		return nil, fmt.Errorf("metrics service %s/%s has no labels to select it by", service.Namespace, service.Name)
+	}
+
+	endpoint := monitoringv1.Endpoint{
+		Port:   s.portName,
+		Path:   "/metrics",
+		Scheme: "http",
+	}
+	if s.secure {
+		// 与 config/prometheus/monitor.yaml 一致：使用 ServiceAccount token 认证，
+		// metrics server 默认使用自签名证书
+		endpoint.Scheme = "https"
+		endpoint.BearerTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+		endpoint.TLSConfig = &monitoringv1.TLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{InsecureSkipVerify: ptr.To(true)},
+		}
+	}
+
+	sm := &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: service.Namespace,
+			Name:      service.Name,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "pod-monitor-operator",
+			},
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Endpoints: []monitoringv1.Endpoint{endpoint},
+			Selector:  metav1.LabelSelector{MatchLabels: service.Labels},
+			NamespaceSelector: monitoringv1.NamespaceSelector{
+				MatchNames: []string{service.Namespace},
+			},
+		},
+	}
+	if err := controllerutil.SetOwnerReference(service, sm, s.scheme); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestServiceMonitorInstallerKeepsUserLabels(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitoringv1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "system",
+		Name:      "metrics",
+		Labels:    map[string]string{"control-plane": "controller-manager"},
+	}}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(service).Build()
+	installer := &ServiceMonitorInstaller{
+		client: c, reader: c, scheme: s,
+		namespace: "system", serviceName: "metrics", portName: "https", secure: true,
+	}
+	ctx := context.Background()
+	key := types.NamespacedName{Namespace: "system", Name: "metrics"}
+
+	if err := installer.install(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var sm monitoringv1.ServiceMonitor
+	if err := c.Get(ctx, key, &sm); err != nil {
+		t.Fatal(err)
+	}
+	if got := sm.Spec.Selector.MatchLabels["control-plane"]; got != "controller-manager" {
+		t.Errorf("expected the selector to match the service labels, got %v", sm.Spec.Selector.MatchLabels)
+	}
+	if ep := sm.Spec.Endpoints[0]; ep.Port != "https" || ep.Scheme != "https" {
+		t.Errorf("expected an https endpoint on port https, got %+v", ep)
+	}
+	if len(sm.OwnerReferences) != 1 || sm.OwnerReferences[0].Name != "metrics" {
+		t.Errorf("expected the service as owner, got %v", sm.OwnerReferences)
+	}
+
+	// 用户添加的标签在更新时保留
+	sm.Labels["release"] = "prometheus"
+	if err := c.Update(ctx, &sm); err != nil {
+		t.Fatal(err)
+	}
+	installer.secure = false
+	if err := installer.install(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, key, &sm); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Labels["release"] != "prometheus" {
+		t.Errorf("expected user labels to be kept, got %v", sm.Labels)
+	}
+	if sm.Spec.Endpoints[0].Scheme != "http" {
+		t.Errorf("expected the spec to be updated, got %+v", sm.Spec.Endpoints[0])
+	}
+}
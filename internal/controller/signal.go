@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// 以信号终止的容器次数（退出码 128+N）
+	containerTerminatedBySignalTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_container_terminated_by_signal_total",
+			Help: "Total number of container terminations by a signal, from exit codes 129 to 165",
+		},
+		[]string{
+			"namespace",   // Pod 所在命名空间
+			"pod",         // Pod 名称
+			"container",   // 容器名称
+			"signal_name", // 信号名称 (e.g., SIGKILL)
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerTerminatedBySignalTotal))
+}
+
+// Exit codes of processes killed by a signal, as reported by shells and
+// container runtimes: 128 plus the signal number.
+const (
+	exitCodeSignalBase = 128
+	exitCodeSignalMax  = exitCodeSignalBase + 37
+)
+
+// signalNames are the Linux signal names by number.
+var signalNames = map[int32]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	4:  "SIGILL",
+	5:  "SIGTRAP",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	9:  "SIGKILL",
+	10: "SIGUSR1",
+	11: "SIGSEGV",
+	12: "SIGUSR2",
+	13: "SIGPIPE",
+	14: "SIGALRM",
+	15: "SIGTERM",
+	16: "SIGSTKFLT",
+	17: "SIGCHLD",
+	18: "SIGCONT",
+	19: "SIGSTOP",
+	20: "SIGTSTP",
+	21: "SIGTTIN",
+	22: "SIGTTOU",
+	23: "SIGURG",
+	24: "SIGXCPU",
+	25: "SIGXFSZ",
+	26: "SIGVTALRM",
+	27: "SIGPROF",
+	28: "SIGWINCH",
+	29: "SIGIO",
+	30: "SIGPWR",
+	31: "SIGSYS",
+}
+
+// signalName returns the name of a signal; real-time signals are named
+// relative to SIGRTMIN as glibc numbers them, others by number.
+func signalName(signal int32) string {
+	if name, ok := signalNames[signal]; ok {
+		return name
+	}
+	// glibc 保留 32、33，SIGRTMIN 为 34
+	if signal >= 34 {
+		return fmt.Sprintf("SIGRTMIN+%d", signal-34)
+	}
+	return fmt.Sprintf("SIG%d", signal)
+}
+
+// terminationSignal returns the name of the signal that killed a container
+// according to its exit code, and false when the exit code does not denote
+// a signal. Exit code 128 is not a signal (signal 0 cannot be delivered) but
+// an invalid argument to exit.
+func terminationSignal(exitCode int32) (string, bool) {
+	if exitCode <= exitCodeSignalBase || exitCode > exitCodeSignalMax {
+		return "", false
+	}
+	return signalName(exitCode - exitCodeSignalBase), true
+}
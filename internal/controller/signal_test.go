@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestTerminationSignal(t *testing.T) {
+	tests := []struct {
+		exitCode int32
+		want     string
+	}{
+		{exitCode: 0},
+		{exitCode: 1},
+		{exitCode: 127},
+		// 128 不是信号
+		{exitCode: 128},
+		{exitCode: 129, want: "SIGHUP"},
+		{exitCode: 134, want: "SIGABRT"},
+		{exitCode: 137, want: "SIGKILL"},
+		{exitCode: 139, want: "SIGSEGV"},
+		{exitCode: 143, want: "SIGTERM"},
+		{exitCode: 160, want: "SIG32"},
+		{exitCode: 162, want: "SIGRTMIN+0"},
+		{exitCode: 165, want: "SIGRTMIN+3"},
+		{exitCode: 166},
+		{exitCode: 255},
+	}
+	for _, tt := range tests {
+		got, ok := terminationSignal(tt.exitCode)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("exit code %d: expected %q, got %q (signal: %v)", tt.exitCode, tt.want, got, ok)
+		}
+	}
+}
+
+func TestTerminatedBySignalTotal(t *testing.T) {
+	const namespace = "signal-test"
+	pod := testsupport.NewPod(namespace, "web").Build()
+	r := &PodMonitorReconciler{}
+	defer func() {
+		stateStore.forgetPod(namespace, "web")
+		labels := prometheus.Labels{"namespace": namespace}
+		podRestartTotal.DeletePartialMatch(labels)
+		podLastTerminationInfo.DeletePartialMatch(labels)
+		podLastTerminationStartedAt.DeletePartialMatch(labels)
+		containerTerminationReasonTotal.DeletePartialMatch(labels)
+		containerTerminatedBySignalTotal.DeletePartialMatch(labels)
+	}()
+
+	for i, exitCode := range []int32{143, 1, 137, 143} {
+		cs := testsupport.TerminatedContainerStatus("app", int32(i+1), "Error", exitCode, time.Now())
+		var batch metricBatch
+		r.recordContainerRestart(context.Background(), &batch, pod, cs, resolveWorkload(pod), "")
+		stateStore.commitMetrics(&batch)
+	}
+
+	container := testsupport.Labels{"namespace": namespace, "pod": "web", "container": "app"}
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_container_terminated_by_signal_total",
+		container); n != 2 {
+		t.Fatalf("expected a series for SIGTERM and SIGKILL only, got %d", n)
+	}
+	container["signal_name"] = "SIGTERM"
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_terminated_by_signal_total", container, 2)
+	container["signal_name"] = "SIGKILL"
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_terminated_by_signal_total", container, 1)
+}
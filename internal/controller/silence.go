@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+)
+
+// silenceRegistry holds the PodMonitorPolicies as of their last listing, for
+// their silence windows. Whether a namespace is silenced is decided when a
+// notification is emitted, against the current time, so windows start and
+// end on time without a reconcile of the namespace.
+type silenceRegistry struct {
+	mu       sync.RWMutex
+	policies []monitorv1alpha1.PodMonitorPolicy
+}
+
+// silences are the silence windows of the policies, updated by policyFor.
+var silences = &silenceRegistry{}
+
+// update replaces the known policies.
+func (s *silenceRegistry) update(policies []monitorv1alpha1.PodMonitorPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies = policies
+}
+
+// silenced reports whether notifications for the namespace are suppressed at
+// now by a silence window of its effective policy.
+func (s *silenceRegistry) silenced(namespace string, now time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, window := range resolvePolicy(s.policies, namespace).Silences {
+		if silenceActive(window, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// silenceActive reports whether now is within the window; End is exclusive.
+func silenceActive(window monitorv1alpha1.SilenceWindow, now time.Time) bool {
+	return !now.Before(window.Start.Time) && now.Before(window.End.Time)
+}
+
+// notificationsSilenced reports whether Kubernetes Events and event stream
+// notifications for the namespace are currently suppressed.
+func (r *PodMonitorReconciler) notificationsSilenced(namespace string) bool {
+	return silences.silenced(namespace, r.now())
+}
+
+// eventf emits a Kubernetes Event on obj unless the operator has no recorder
+// or the namespace of obj is silenced.
+func (r *PodMonitorReconciler) eventf(obj runtime.Object, eventType, reason, messageFmt string,
+	args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	if accessor, err := meta.Accessor(obj); err == nil && r.notificationsSilenced(accessor.GetNamespace()) {
+		return
+	}
+	r.Recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// activeSilencesCollector exports the number of silence windows of each
+// policy that are active, computed at scrape time.
+type activeSilencesCollector struct {
+	desc *prometheus.Desc
+}
+
+func newActiveSilencesCollector() *activeSilencesCollector {
+	return &activeSilencesCollector{
+		desc: prometheus.NewDesc(
+			"pod_monitor_active_silences",
+			"Number of active silence windows of a PodMonitorPolicy, during which no events are emitted",
+			[]string{"policy"}, nil,
+		),
+	}
+}
+
+func (c *activeSilencesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *activeSilencesCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	silences.mu.RLock()
+	defer silences.mu.RUnlock()
+	for _, policy := range silences.policies {
+		if len(policy.Spec.Silences) == 0 {
+			continue
+		}
+		active := 0
+		for _, window := range policy.Spec.Silences {
+			if silenceActive(window, now) {
+				active++
+			}
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(active), policy.Name)
+	}
+}
+
+func init() {
+	registerMetrics(newActiveSilencesCollector())
+}
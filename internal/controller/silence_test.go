@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestSilenceSuppressesEventsUntilItEnds(t *testing.T) {
+	const namespace = "silence-test"
+	ctx := context.Background()
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitorv1alpha1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	pod := testsupport.NewPod(namespace, "web").
+		WithTerminatedContainer("app", 1, "Error", 1, start.Add(-time.Minute)).
+		Build()
+	policy := &monitorv1alpha1.PodMonitorPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "silence-test"},
+		Spec: monitorv1alpha1.PodMonitorPolicySpec{
+			Namespaces: []string{namespace},
+			Silences: []monitorv1alpha1.SilenceWindow{{
+				Start:  metav1.NewTime(start.Add(-time.Hour)),
+				End:    metav1.NewTime(start.Add(time.Hour)),
+				Reason: "maintenance",
+			}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(pod, policy).Build()
+	clock := clocktesting.NewFakePassiveClock(start)
+	recorder := record.NewFakeRecorder(10)
+	r := &PodMonitorReconciler{Client: c, Scheme: s, Recorder: recorder, Clock: clock}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "web"}}
+	defer func() {
+		_ = c.Delete(ctx, pod)
+		_, _ = r.reconcilePod(ctx, req)
+		silences.update(nil)
+	}()
+
+	restart := func(t *testing.T, count int32) {
+		t.Helper()
+		var current corev1.Pod
+		if err := c.Get(ctx, req.NamespacedName, &current); err != nil {
+			t.Fatal(err)
+		}
+		current.Status.ContainerStatuses[0] = testsupport.TerminatedContainerStatus("app", count, "Error", 1,
+			clock.Now().Add(-time.Minute))
+		if err := c.Status().Update(ctx, &current); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reconcilePod(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	restartLabels := testsupport.Labels{"namespace": namespace, "pod": "web", "container": "app", "reason": "Error"}
+
+	// 静默期间照常记录指标，但不发出事件
+	restart(t, 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total", restartLabels, 1)
+	if len(recorder.Events) != 0 {
+		t.Fatalf("expected no events during the silence, got %q", <-recorder.Events)
+	}
+
+	// 静默到期后无需修改策略即恢复事件
+	clock.SetTime(start.Add(time.Hour))
+	restart(t, 2)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total", restartLabels, 2)
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected an event once the silence ended, got %d", len(recorder.Events))
+	}
+}
+
+func TestActiveSilences(t *testing.T) {
+	now := time.Now()
+	window := func(from, to time.Duration) monitorv1alpha1.SilenceWindow {
+		return monitorv1alpha1.SilenceWindow{Start: metav1.NewTime(now.Add(from)), End: metav1.NewTime(now.Add(to))}
+	}
+	silences.update([]monitorv1alpha1.PodMonitorPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "maintenance"},
+			Spec: monitorv1alpha1.PodMonitorPolicySpec{
+				Namespaces: []string{"payments"},
+				Silences: []monitorv1alpha1.SilenceWindow{
+					window(-time.Hour, time.Hour),
+					window(-2*time.Hour, -time.Hour),
+					window(time.Hour, 2*time.Hour),
+				},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "unsilenced"}, Spec: monitorv1alpha1.PodMonitorPolicySpec{
+			Namespaces: []string{"web"},
+		}},
+	})
+	defer silences.update(nil)
+
+	if !silences.silenced("payments", now) {
+		t.Error("expected payments to be silenced now")
+	}
+	if !silences.silenced("payments", now.Add(90*time.Minute)) {
+		t.Error("expected the later window to silence payments")
+	}
+	if silences.silenced("payments", now.Add(3*time.Hour)) {
+		t.Error("expected payments not to be silenced after all windows ended")
+	}
+	if silences.silenced("web", now) {
+		t.Error("expected a namespace without silences not to be silenced")
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_active_silences",
+		testsupport.Labels{"policy": "maintenance"}, 1)
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_active_silences",
+		testsupport.Labels{"policy": "unsilenced"})
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// simulatedNamespace is the namespace label of all simulated series.
+	simulatedNamespace = "pod-monitor-simulation"
+	// simulatedPodCount bounds the number of fake pods, and thus series.
+	simulatedPodCount  = 5
+	simulatedContainer = "app"
+)
+
+// simulatedTerminationReasons are cycled through in order by the simulator.
+var simulatedTerminationReasons = []string{
+	"OOMKilled",
+	"Error",
+	"Completed",
+	"ContainerCannotRun",
+	"DeadlineExceeded",
+	"Unknown",
+}
+
+// RestartSimulator periodically injects synthetic container terminations into
+// the metrics so dashboards can be built without real failures. All series it
+// writes carry simulated="true" and a sim-pod-* pod name.
+type RestartSimulator struct {
+	interval time.Duration
+	next     int
+}
+
+var _ manager.Runnable = &RestartSimulator{}
+
+// NewRestartSimulator creates a simulator emitting ratePerMinute terminations
+// per minute. Non-positive rates fall back to one per minute.
+func NewRestartSimulator(ratePerMinute float64) *RestartSimulator {
+	if ratePerMinute <= 0 {
+		ratePerMinute = 1
+	}
+	return &RestartSimulator{interval: time.Duration(float64(time.Minute) / ratePerMinute)}
+}
+
+// Start emits simulated terminations until the context is cancelled.
+func (s *RestartSimulator) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("restart-simulator")
+	log.Info("Starting restart simulation", "interval", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			s.emit(now)
+		}
+	}
+}
+
+// emit records one synthetic termination, cycling through the known reasons.
+func (s *RestartSimulator) emit(now time.Time) {
+	reason := simulatedTerminationReasons[s.next%len(simulatedTerminationReasons)]
+	podName := fmt.Sprintf("sim-pod-%d", s.next%simulatedPodCount)
+	s.next++
+
+	// 每个模拟容器只保留最后一次终止，避免序列数量增长
+	var batch metricBatch
+	batch.deletePartial(podLastTerminationInfo.MetricVec, prometheus.Labels{
+		"namespace": simulatedNamespace,
+		"pod":       podName,
+		"container": simulatedContainer,
+	})
+	batch.set(podLastTerminationInfo, float64(now.Unix()), simulatedNamespace, podName, simulatedContainer,
+		reason, strconv.Itoa(simulatedExitCode(reason)), "true", "")
+
+	if reason == "OOMKilled" {
+		batch.inc(containerOOMKilledTotal, simulatedNamespace, podName, simulatedContainer, "true", "")
+	}
+	stateStore.commitMetrics(&batch)
+}
+
+// simulatedExitCode returns a plausible exit code for a termination reason.
+func simulatedExitCode(reason string) int {
+	switch reason {
+	case "OOMKilled":
+		return 137
+	case "Completed":
+		return 0
+	default:
+		return 1 + rand.IntN(255)
+	}
+}
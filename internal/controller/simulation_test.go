@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestRestartSimulator(t *testing.T) {
+	defer podLastTerminationInfo.DeletePartialMatch(prometheus.Labels{"namespace": simulatedNamespace})
+	defer containerOOMKilledTotal.DeletePartialMatch(prometheus.Labels{"namespace": simulatedNamespace})
+
+	if got := NewRestartSimulator(4).interval; got != 15*time.Second {
+		t.Errorf("expected 4 events per minute every 15s, got %v", got)
+	}
+	if got := NewRestartSimulator(0).interval; got != time.Minute {
+		t.Errorf("expected the default rate of one event per minute, got %v", got)
+	}
+
+	s := NewRestartSimulator(1)
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cycles := 2 * len(simulatedTerminationReasons)
+	for i := 0; i < cycles; i++ {
+		s.emit(now.Add(time.Duration(i) * time.Minute))
+	}
+
+	// 每个模拟容器只保留最后一次终止，序列数量有界
+	all := testsupport.Labels{"namespace": simulatedNamespace}
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_container_last_termination_info",
+		all); n != simulatedPodCount {
+		t.Errorf("expected one series per simulated pod, got %d", n)
+	}
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_container_last_termination_info",
+		testsupport.Labels{"namespace": simulatedNamespace, "simulated": "true"}); n != simulatedPodCount {
+		t.Errorf("expected every simulated series to carry simulated=\"true\", got %d of %d", n, simulatedPodCount)
+	}
+
+	// 按顺序循环所有终止原因：最后一次终止属于 sim-pod-1，原因为最后一个
+	last := cycles - 1
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_last_termination_info",
+		testsupport.Labels{"namespace": simulatedNamespace, "pod": "sim-pod-1",
+			"reason": simulatedTerminationReasons[last%len(simulatedTerminationReasons)]},
+		float64(now.Add(time.Duration(last)*time.Minute).Unix()))
+
+	// 每轮循环一次 OOMKilled
+	values, err := testsupport.Series(metrics.Registry, "pod_monitor_container_oom_killed_total", all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ooms float64
+	for _, v := range values {
+		ooms += v
+	}
+	if ooms != 2 {
+		t.Errorf("expected 2 simulated OOM kills, got %v", ooms)
+	}
+}
+
+func TestSimulatedExitCode(t *testing.T) {
+	if got := simulatedExitCode("OOMKilled"); got != 137 {
+		t.Errorf("expected 137 for OOMKilled, got %d", got)
+	}
+	if got := simulatedExitCode("Completed"); got != 0 {
+		t.Errorf("expected 0 for Completed, got %d", got)
+	}
+	for i := 0; i < 100; i++ {
+		if got := simulatedExitCode("Error"); got < 1 || got > 255 {
+			t.Fatalf("expected a failing exit code in [1, 255], got %d", got)
+		}
+	}
+}
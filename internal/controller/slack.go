@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// slackNotifyInterval is the minimum time between two Slack notifications
+	// about the same certificate.
+	slackNotifyInterval = 4 * time.Hour
+	// slackRequestTimeout bounds a request to the Slack webhook.
+	slackRequestTimeout = 10 * time.Second
+	// slackQueueSize is the number of notifications waiting to be sent
+	// beyond which new ones are dropped.
+	slackQueueSize = 100
+)
+
+var (
+	// 发送队列已满而丢弃的 Slack 通知数
+	slackNotificationsDroppedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_slack_notifications_dropped_total",
+			Help: "Number of Slack notifications dropped because the send queue was full",
+		},
+	)
+)
+
+func init() {
+	registerMetrics(slackNotificationsDroppedTotal)
+}
+
+// slackMessage is a notification waiting to be sent.
+type slackMessage struct {
+	// key: "namespace/secretName/certType"，发送失败时据此允许重试
+	key  string
+	text string
+}
+
+// SlackNotifier posts messages to a Slack Incoming Webhook. Reconciles only
+// queue notifications; they are sent by the notifier, which is added to the
+// manager as a Runnable, so a slow webhook never blocks a reconcile worker.
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+
+	queue chan slackMessage
+	mu    sync.Mutex
+	// key: "namespace/secretName/certType"，上一次发送通知的时间
+	lastNotified map[string]time.Time
+}
+
+// NewSlackNotifier returns a notifier posting to the given webhook URL.
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		WebhookURL:   webhookURL,
+		Client:       &http.Client{Timeout: slackRequestTimeout},
+		queue:        make(chan slackMessage, slackQueueSize),
+		lastNotified: make(map[string]time.Time),
+	}
+}
+
+var _ manager.Runnable = &SlackNotifier{}
+var _ manager.LeaderElectionRunnable = &SlackNotifier{}
+
+// Start sends queued notifications until the context is cancelled.
+func (n *SlackNotifier) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("slack")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-n.queue:
+			if err := n.Notify(ctx, msg.text); err != nil {
+				// 发送失败时允许下一次 reconcile 重试
+				n.forget(msg.key)
+				log.Error(err, "Failed to send Slack notification", "certificate", msg.key)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection returns true: notifications are only queued by the
+// reconciles of the leader.
+func (n *SlackNotifier) NeedLeaderElection() bool {
+	return true
+}
+
+// enqueue queues a notification without blocking. When the queue is full
+// the notification is dropped, counted and may be retried by a later
+// reconcile.
+func (n *SlackNotifier) enqueue(key, text string) bool {
+	select {
+	case n.queue <- slackMessage{key: key, text: text}:
+		return true
+	default:
+		n.forget(key)
+		slackNotificationsDroppedTotal.Inc()
+		return false
+	}
+}
+
+// Notify posts message to the webhook.
+func (n *SlackNotifier) Notify(ctx context.Context, message string) error {
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// shouldNotify reports whether no notification was sent for key in the
+// last slackNotifyInterval, and if so records one at now.
+func (n *SlackNotifier) shouldNotify(key string, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if last, ok := n.lastNotified[key]; ok && now.Sub(last) < slackNotifyInterval {
+		return false
+	}
+	n.lastNotified[key] = now
+	return true
+}
+
+// forget allows a new notification for key, after a failed attempt.
+func (n *SlackNotifier) forget(key string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.lastNotified, key)
+}
+
+// forgetSecret drops the notification times of the certificates of a
+// deleted secret.
+func (n *SlackNotifier) forgetSecret(namespace, secretName string) {
+	if n == nil {
+		return
+	}
+	prefix := fmt.Sprintf("%s/%s/", namespace, secretName)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for key := range n.lastNotified {
+		if strings.HasPrefix(key, prefix) {
+			delete(n.lastNotified, key)
+		}
+	}
+}
+
+// notifyCertificateCritical notifies Slack of a certificate expiring within
+// the critical window of its namespace policy, at most once every four hours
+// per certificate. The notification is only queued; failures are logged and
+// never fail the reconcile.
+func (r *PodMonitorReconciler) notifyCertificateCritical(ctx context.Context, namespace, secretName, certType string,
+	notAfter time.Time, daysUntilExpiration float64) {
+	if r.SlackNotifier == nil || r.notificationsSilenced(namespace) {
+		return
+	}
+	if daysUntilExpiration >= float64(r.policyFor(ctx, namespace).CertCriticalDays) {
+		return
+	}
+	key := fmt.Sprintf("%s/%s/%s", namespace, secretName, certType)
+	if !r.SlackNotifier.shouldNotify(key, r.now()) {
+		return
+	}
+	message := fmt.Sprintf("Certificate %s in secret %s/%s expires on %s (in %.1f days)",
+		certType, namespace, secretName, notAfter.UTC().Format(time.RFC3339), daysUntilExpiration)
+	if !r.SlackNotifier.enqueue(key, message) {
+		logf.FromContext(ctx).V(1).Info("Slack notification queue full, dropping notification",
+			"namespace", namespace, "secret", secretName, "certType", certType)
+	}
+}
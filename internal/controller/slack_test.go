@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestSlackNotifierCertificateCritical(t *testing.T) {
+	const namespace = "slack-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	var messages []string
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Errorf("invalid Slack payload: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, body.Text)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+	sent := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), messages...)
+	}
+
+	// 等待后台发送；until 为 nil 时只等待 n 条消息
+	waitFor := func(n int, until func()) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for len(sent()) < n && time.Now().Before(deadline) {
+			if until != nil {
+				until()
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if len(sent()) != n {
+			t.Fatalf("expected %d Slack messages, got %d", n, len(sent()))
+		}
+	}
+
+	clock := clocktesting.NewFakePassiveClock(now)
+	notifier := NewSlackNotifier(server.URL)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() { _ = notifier.Start(ctx) }()
+	r := &PodMonitorReconciler{Clock: clock, DisablePolicies: true, SlackNotifier: notifier}
+	notAfter := now.Add(3 * 24 * time.Hour)
+	notify := func() {
+		r.notifyCertificateCritical(ctx, namespace, "web-tls", "tls.crt", notAfter,
+			notAfter.Sub(clock.Now()).Hours()/24)
+	}
+
+	notify()
+	waitFor(1, nil)
+	for _, want := range []string{namespace + "/web-tls", "tls.crt", "2025-06-04T12:00:00Z"} {
+		if !strings.Contains(sent()[0], want) {
+			t.Errorf("Slack message %q does not contain %q", sent()[0], want)
+		}
+	}
+
+	// 4 小时内不重复通知
+	clock.SetTime(now.Add(3 * time.Hour))
+	notify()
+	if len(sent()) != 1 {
+		t.Errorf("notified again within 4 hours: %v", sent())
+	}
+	clock.SetTime(now.Add(4 * time.Hour))
+	notify()
+	waitFor(2, nil)
+
+	// 发送失败只记录日志，之后的检查重试
+	status.Store(http.StatusInternalServerError)
+	r.notifyCertificateCritical(ctx, namespace, "db-tls", "tls.crt", notAfter, 3)
+	waitFor(3, nil)
+	status.Store(http.StatusOK)
+	waitFor(4, func() { r.notifyCertificateCritical(ctx, namespace, "db-tls", "tls.crt", notAfter, 3) })
+
+	// critical 窗口之外的证书不通知
+	r.notifyCertificateCritical(ctx, namespace, "api-tls", "tls.crt", now.Add(60*24*time.Hour), 60)
+	time.Sleep(50 * time.Millisecond)
+	if len(sent()) != 4 {
+		t.Errorf("notified for a certificate outside the critical window: %v", sent())
+	}
+}
+
+func TestSlackNotifierDropsWhenQueueFull(t *testing.T) {
+	const namespace = "slack-queue-test"
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	// 未启动的通知器，队列只容纳一条消息
+	notifier := NewSlackNotifier("http://127.0.0.1:0")
+	notifier.queue = make(chan slackMessage, 1)
+	r := &PodMonitorReconciler{Clock: clocktesting.NewFakePassiveClock(now), DisablePolicies: true,
+		SlackNotifier: notifier}
+	notAfter := now.Add(3 * 24 * time.Hour)
+
+	before := testutil.ToFloat64(slackNotificationsDroppedTotal)
+	r.notifyCertificateCritical(context.Background(), namespace, "web-tls", "tls.crt", notAfter, 3)
+	r.notifyCertificateCritical(context.Background(), namespace, "db-tls", "tls.crt", notAfter, 3)
+	if got := testutil.ToFloat64(slackNotificationsDroppedTotal) - before; got != 1 {
+		t.Errorf("expected 1 dropped notification, got %v", got)
+	}
+	// 丢弃的通知可在下一次检查时重新排队
+	if !notifier.shouldNotify(namespace+"/db-tls/tls.crt", now) {
+		t.Error("expected a dropped notification to be retried")
+	}
+
+	// 删除 Secret 后清理其通知时间
+	notifier.forgetSecret(namespace, "web-tls")
+	if !notifier.shouldNotify(namespace+"/web-tls/tls.crt", now) {
+		t.Error("expected the notification time of a deleted secret to be forgotten")
+	}
+}
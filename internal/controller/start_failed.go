@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 首次启动即失败、从未进入 Running 的容器（如错误的 entrypoint、缺失的挂载）
+	containerStartFailedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_container_start_failed_total",
+			Help: "Total number of containers that terminated before ever reaching the Running state",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+			"reason",    // 终止原因
+		},
+	)
+
+	// 已上报的启动失败，防止重复计数
+	// key: "namespace/podName/containerName"
+	reportedStartFailures = make(map[string]struct{})
+	startFailuresMutex    sync.Mutex
+)
+
+func init() {
+	registerMetrics(batched(containerStartFailedTotal))
+}
+
+// reportStartFailure counts a container whose first start failed: it has a
+// terminated last state but was never restarted and is not running. Restarts
+// of containers that ran before are counted by the restart detection. Each
+// container is reported once.
+func reportStartFailure(b *metricBatch, pod *corev1.Pod, cs corev1.ContainerStatus) {
+	terminated := cs.LastTerminationState.Terminated
+	if cs.State.Running != nil || terminated == nil || cs.RestartCount != 0 {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+	startFailuresMutex.Lock()
+	_, reported := reportedStartFailures[key]
+	reportedStartFailures[key] = struct{}{}
+	startFailuresMutex.Unlock()
+	if reported {
+		return
+	}
+
+	reason := terminated.Reason
+	if reason == "" {
+		reason = "Unknown"
+	}
+	b.inc(containerStartFailedTotal, pod.Namespace, pod.Name, cs.Name, reason)
+}
+
+// forgetStartFailures drops the reported start failures of a deleted pod.
+func forgetStartFailures(namespace, podName string) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, podName)
+
+	startFailuresMutex.Lock()
+	defer startFailuresMutex.Unlock()
+	for key := range reportedStartFailures {
+		if strings.HasPrefix(key, prefix) {
+			delete(reportedStartFailures, key)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestStartFailureReportedOnce(t *testing.T) {
+	const namespace = "start-failed-test"
+	pod := testsupport.NewPod(namespace, "app").Build()
+	defer func() {
+		forgetStartFailures(namespace, "app")
+		containerStartFailedTotal.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+	}()
+
+	report := func(cs corev1.ContainerStatus) {
+		var batch metricBatch
+		reportStartFailure(&batch, pod, cs)
+		stateStore.commitMetrics(&batch)
+	}
+	failed := corev1.ContainerStatus{
+		Name:  "main",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "RunContainerError"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason: "StartError", ExitCode: 128,
+		}},
+	}
+	report(failed)
+	report(failed)
+	got := testutil.ToFloat64(containerStartFailedTotal.WithLabelValues(namespace, "app", "main", "StartError"))
+	if got != 1 {
+		t.Fatalf("expected the start failure to be counted once, got %v", got)
+	}
+
+	// 已经重启过的容器属于重启循环，不计入
+	restarted := failed
+	restarted.Name = "sidecar"
+	restarted.RestartCount = 2
+	report(restarted)
+	if n := testutil.CollectAndCount(containerStartFailedTotal); n != 1 {
+		t.Fatalf("expected restarted containers not to be counted, got %d series", n)
+	}
+}
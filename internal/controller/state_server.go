@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// StateServer serves read-only views of the operator's in-memory state over
+// HTTP. It is added to the manager as a Runnable and runs on every replica.
+type StateServer struct {
+	addr string
+	mux  *http.ServeMux
+}
+
+var _ manager.Runnable = &StateServer{}
+var _ manager.LeaderElectionRunnable = &StateServer{}
+
+// NewStateServer creates a state server listening on addr.
+func NewStateServer(addr string) *StateServer {
+	s := &StateServer{addr: addr, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /report", s.handleReport)
+	s.mux.HandleFunc("GET /api/v1/restarts/history", s.handleRestartHistory)
+	s.mux.HandleFunc("GET /version", s.handleVersion)
+	s.mux.HandleFunc("GET /dashboards/pod-monitor.json", s.handleDashboard)
+	return s
+}
+
+// Start runs the HTTP server until the context is cancelled.
+func (s *StateServer) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("state-server")
+
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Info("Starting state server", "addr", s.addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		return err
+	}
+}
+
+// NeedLeaderElection returns false so standby replicas serve their state too.
+func (s *StateServer) NeedLeaderElection() bool {
+	return false
+}
+
+// handleReport renders the cluster health summary, as plain text by default
+// or as JSON with ?format=json.
+func (s *StateServer) handleReport(w http.ResponseWriter, req *http.Request) {
+	rep := stateStore.report(time.Now())
+
+	if req.URL.Query().Get("format") == "json" {
+		writeJSON(w, rep)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_ = rep.writeText(w)
+}
+
+// restartHistoryList is the response of /api/v1/restarts/history.
+type restartHistoryList struct {
+	Items []terminationRecord `json:"items"`
+}
+
+// handleRestartHistory lists recent terminations, oldest first. The optional
+// since parameter accepts an RFC3339 timestamp or a duration such as 30m, and
+// namespace restricts the result to one namespace.
+func (s *StateServer) handleRestartHistory(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	var since time.Time
+	if raw := query.Get("since"); raw != "" {
+		parsed, err := parseSince(raw, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	writeJSON(w, restartHistoryList{Items: stateStore.terminations(since, query.Get("namespace"))})
+}
+
+// handleVersion returns the build information and enabled features.
+func (s *StateServer) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, getBuildInfo())
+}
+
+// parseSince parses an RFC3339 timestamp or a duration relative to now.
+func parseSince(raw string, now time.Time) (time.Time, error) {
+	if ts, err := time.Parse(time.RFC3339, raw); err == nil {
+		return ts, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: expected RFC3339 timestamp or duration", raw)
+	}
+	return now.Add(-d), nil
+}
+
+// writeJSON encodes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
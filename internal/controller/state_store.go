@@ -0,0 +1,395 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// workloadRestartHistorySize bounds the restart timestamps kept per workload.
+	workloadRestartHistorySize = 256
+	// restartAggregationWindow is how far back per-workload restarts are kept.
+	restartAggregationWindow = 24 * time.Hour
+)
+
+// workloadRestartHistory is a fixed-size ring of restart timestamps.
+type workloadRestartHistory struct {
+	workload   workloadRef
+	timestamps []time.Time
+	next       int
+	latest     time.Time
+}
+
+func (h *workloadRestartHistory) add(at time.Time) {
+	if len(h.timestamps) < workloadRestartHistorySize {
+		h.timestamps = append(h.timestamps, at)
+	} else {
+		h.timestamps[h.next] = at
+		h.next = (h.next + 1) % workloadRestartHistorySize
+	}
+	if at.After(h.latest) {
+		h.latest = at
+	}
+}
+
+func (h *workloadRestartHistory) countSince(since time.Time) int {
+	count := 0
+	for _, ts := range h.timestamps {
+		if !ts.Before(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// crashLoopState describes a container currently in CrashLoopBackOff.
+type crashLoopState struct {
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container"`
+	RestartCount int32     `json:"restartCount"`
+	LastReason   string    `json:"lastReason,omitempty"`
+	Since        time.Time `json:"since"`
+}
+
+// certificateState is the last known expiry of a monitored certificate.
+type certificateState struct {
+	Namespace  string    `json:"namespace"`
+	SecretName string    `json:"secretName"`
+	CertType   string    `json:"certType"`
+	NotAfter   time.Time `json:"notAfter"`
+}
+
+// restartStateStore holds the in-memory aggregates shared between the
+// reconcilers and the state API. All maps are bounded by the number of live
+// objects (containers, secrets) or by the aggregation window (workloads).
+type restartStateStore struct {
+	mu sync.RWMutex
+
+	// 已经观察到的容器重启次数，防止重复处理
+	// 注意：Operator 重启后该状态会丢失
+	// key: "namespace/podName/containerName"
+	observedRestarts map[string]int32
+	// 首次观察到容器时的重启次数
+	// key: "namespace/podName/containerName"
+	restartBaselines map[string]int32
+	// key: workloadRef.key()
+	workloadRestarts map[string]*workloadRestartHistory
+	// key: "namespace/podName/containerName"
+	crashLooping map[string]crashLoopState
+	// key: "namespace/secretName/certType"
+	certificates map[string]certificateState
+	// key: "namespace/podName/containerName"
+	imagePullStuck map[string]imagePullState
+	// key: "namespace/podName/containerName"
+	exitCodes map[string]*exitCodeRing
+	// key: "namespace/podName"，仅包含设置了调优注解的 Pod
+	overrides map[string]podOverrides
+	// key: "namespace/podName"，状态所属 Pod 的 UID，仅在 --include-pod-uid 时记录
+	podUIDs map[string]types.UID
+	// key: "namespace/podName/containerName"，上一次重启时看到的 restartedAt 注解
+	restartedAt map[string]time.Time
+	// key: "namespace/secretName"，reconcile 过的 Secret，删除后据此清理
+	secrets map[string]struct{}
+	// key: "namespace/secretName"，自动发现模式下正在监控的 Secret
+	autoDiscovered map[string]struct{}
+	// key: "namespace/secretName"，数据大小超过告警阈值的 Secret
+	largeSecrets map[string]struct{}
+	// key: "namespace/podName/containerName"，容器上一次终止的时间
+	lastTerminations map[string]lastTermination
+	// key: "namespace/podName"，正在终止（已设置 deletionTimestamp）的 Pod
+	terminating map[string]terminatingPod
+	// key: "namespace/podName/containerName"，已上报的 Job 容器失败，防止重复计数
+	jobFailures map[string]struct{}
+
+	// 最近的容器终止记录（有界环形缓冲区）
+	history *restartHistory
+	// 按命名空间和终止原因统计的滑动窗口重启次数
+	restartWindow *restartWindow
+	// 按工作负载和终止原因统计的滑动窗口重启次数，用于统计不同失败原因的数量
+	failureReasonWindow *restartWindow
+	// 最近一次检测到 Linkerd issuer 证书轮换的时间，由 Secret reconcile 写入、Pod reconcile 读取
+	issuerRotatedAt time.Time
+
+	// 批量提交指标与抓取之间的锁，保证每个指标收集器看到的一次 reconcile 的更新是完整的
+	metricsMu sync.RWMutex
+}
+
+func newRestartStateStore() *restartStateStore {
+	return &restartStateStore{
+		observedRestarts:    make(map[string]int32),
+		restartBaselines:    make(map[string]int32),
+		workloadRestarts:    make(map[string]*workloadRestartHistory),
+		crashLooping:        make(map[string]crashLoopState),
+		certificates:        make(map[string]certificateState),
+		imagePullStuck:      make(map[string]imagePullState),
+		exitCodes:           make(map[string]*exitCodeRing),
+		overrides:           make(map[string]podOverrides),
+		podUIDs:             make(map[string]types.UID),
+		restartedAt:         make(map[string]time.Time),
+		secrets:             make(map[string]struct{}),
+		autoDiscovered:      make(map[string]struct{}),
+		largeSecrets:        make(map[string]struct{}),
+		lastTerminations:    make(map[string]lastTermination),
+		terminating:         make(map[string]terminatingPod),
+		jobFailures:         make(map[string]struct{}),
+		history:             newRestartHistory(defaultHistorySize, defaultHistoryPerContainer),
+		restartWindow:       newRestartWindow(defaultRestartWindow),
+		failureReasonWindow: newRestartWindow(defaultFailureReasonWindow),
+	}
+}
+
+// stateStore 是 reconciler 与状态 API 共享的内存状态
+var stateStore = newRestartStateStore()
+
+// configureHistory resizes the termination history. It is meant to be called
+// once during setup and drops any records collected so far.
+func (s *restartStateStore) configureHistory(size, perContainer int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = newRestartHistory(size, perContainer)
+}
+
+// recordTermination appends a termination to the history buffer.
+func (s *restartStateStore) recordTermination(rec terminationRecord) {
+	s.mu.RLock()
+	history := s.history
+	s.mu.RUnlock()
+	history.append(rec)
+}
+
+// terminations returns recorded terminations at or after since, oldest first.
+func (s *restartStateStore) terminations(since time.Time, namespace string) []terminationRecord {
+	s.mu.RLock()
+	history := s.history
+	s.mu.RUnlock()
+	return history.list(since, namespace)
+}
+
+// recordWorkloadRestart appends a restart to the workload's history and drops
+// workloads whose last restart fell out of the aggregation window.
+func (s *restartStateStore) recordWorkloadRestart(workload workloadRef, at, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := workload.key()
+	h, ok := s.workloadRestarts[key]
+	if !ok {
+		h = &workloadRestartHistory{workload: workload}
+		s.workloadRestarts[key] = h
+	}
+	h.add(at)
+
+	cutoff := now.Add(-restartAggregationWindow)
+	for k, other := range s.workloadRestarts {
+		if other.latest.Before(cutoff) {
+			delete(s.workloadRestarts, k)
+		}
+	}
+}
+
+// setCrashLooping records or clears the CrashLoopBackOff state of a container.
+func (s *restartStateStore) setCrashLooping(key string, state *crashLoopState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state == nil {
+		delete(s.crashLooping, key)
+		return
+	}
+	if existing, ok := s.crashLooping[key]; ok {
+		// 保留首次进入 CrashLoopBackOff 的时间
+		state.Since = existing.Since
+	}
+	s.crashLooping[key] = *state
+}
+
+// observedRestartCount returns the restart count of a container as of its
+// last processed restart.
+func (s *restartStateStore) observedRestartCount(containerKey string) int32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.observedRestarts[containerKey]
+}
+
+// setObservedRestartCount records the restart count of a container once its
+// restarts have been processed.
+func (s *restartStateStore) setObservedRestartCount(containerKey string, count int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observedRestarts[containerKey] = count
+}
+
+// forgetPod drops all container state of a deleted pod.
+func (s *restartStateStore) forgetPod(namespace, podName string) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, podName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.observedRestarts {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.observedRestarts, key)
+		}
+	}
+	for key := range s.restartBaselines {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.restartBaselines, key)
+		}
+	}
+	for key := range s.crashLooping {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.crashLooping, key)
+		}
+	}
+	for key := range s.imagePullStuck {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.imagePullStuck, key)
+		}
+	}
+	for key := range s.exitCodes {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.exitCodes, key)
+		}
+	}
+	for key := range s.restartedAt {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.restartedAt, key)
+		}
+	}
+	for key := range s.lastTerminations {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.lastTerminations, key)
+		}
+	}
+	for key := range s.jobFailures {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.jobFailures, key)
+		}
+	}
+	delete(s.overrides, fmt.Sprintf("%s/%s", namespace, podName))
+	delete(s.podUIDs, fmt.Sprintf("%s/%s", namespace, podName))
+	delete(s.terminating, fmt.Sprintf("%s/%s", namespace, podName))
+}
+
+// recordCertificate stores the expiry of a certificate found in a secret and
+// returns the previously stored expiry, if any.
+func (s *restartStateStore) recordCertificate(namespace, secretName, certType string,
+	notAfter time.Time) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s", namespace, secretName, certType)
+	previous, ok := s.certificates[key]
+	s.certificates[key] = certificateState{
+		Namespace:  namespace,
+		SecretName: secretName,
+		CertType:   certType,
+		NotAfter:   notAfter,
+	}
+	return previous.NotAfter, ok
+}
+
+// earliestCertificate returns the earliest expiry among the certificates
+// recorded for a secret.
+func (s *restartStateStore) earliestCertificate(namespace, secretName string) (time.Time, bool) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, secretName)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var earliest time.Time
+	found := false
+	for key, cert := range s.certificates {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if !found || cert.NotAfter.Before(earliest) {
+			earliest = cert.NotAfter
+			found = true
+		}
+	}
+	return earliest, found
+}
+
+// namespaceCertificates returns the certificates recorded for the secrets
+// of a namespace.
+func (s *restartStateStore) namespaceCertificates(namespace string) []certificateState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var certs []certificateState
+	for _, cert := range s.certificates {
+		if cert.Namespace == namespace {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+// trackSecret records that a secret was reconciled, so that its state is
+// cleaned up once it is deleted.
+func (s *restartStateStore) trackSecret(namespace, secretName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.secrets[fmt.Sprintf("%s/%s", namespace, secretName)] = struct{}{}
+}
+
+// isKnownSecret reports whether a secret was reconciled and not forgotten
+// since. A request for a deleted secret cannot be told apart from one for a
+// pod otherwise.
+func (s *restartStateStore) isKnownSecret(namespace, secretName string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.secrets[fmt.Sprintf("%s/%s", namespace, secretName)]
+	return ok
+}
+
+// setSecretLarge records whether the data of a secret is above the size
+// warning threshold and reports whether it just crossed it.
+func (s *restartStateStore) setSecretLarge(namespace, secretName string, large bool) bool {
+	key := fmt.Sprintf("%s/%s", namespace, secretName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, wasLarge := s.largeSecrets[key]
+	if !large {
+		delete(s.largeSecrets, key)
+		return false
+	}
+	s.largeSecrets[key] = struct{}{}
+	return !wasLarge
+}
+
+// forgetSecret drops all certificates of a deleted secret.
+func (s *restartStateStore) forgetSecret(namespace, secretName string) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, secretName)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.certificates {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.certificates, key)
+		}
+	}
+	delete(s.secrets, fmt.Sprintf("%s/%s", namespace, secretName))
+	delete(s.autoDiscovered, fmt.Sprintf("%s/%s", namespace, secretName))
+	delete(s.largeSecrets, fmt.Sprintf("%s/%s", namespace, secretName))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// workloadKindStaticPod is the workload kind of the mirror pods of static
+// pods (kube-apiserver, etcd, ... on kubeadm control planes).
+const workloadKindStaticPod = "StaticPod"
+
+// isMirrorPod reports whether pod is the API server mirror of a static pod
+// run by the kubelet from a manifest. Mirror pods have no controller; their
+// only owner reference, if any, is the Node.
+func isMirrorPod(pod *corev1.Pod) bool {
+	_, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]
+	return ok
+}
+
+// staticPodWorkload returns the workload of a mirror pod: the static pod name
+// without the "-<node>" suffix the kubelet appends, so that the series of
+// kube-apiserver-cp-1 and kube-apiserver-cp-2 aggregate, and survive the
+// replacement of a control plane node.
+func staticPodWorkload(pod *corev1.Pod) workloadRef {
+	name := pod.Name
+	if trimmed, ok := strings.CutSuffix(name, "-"+pod.Spec.NodeName); ok && pod.Spec.NodeName != "" && trimmed != "" {
+		name = trimmed
+	}
+	return workloadRef{Namespace: pod.Namespace, Kind: workloadKindStaticPod, Name: name}
+}
+
+// seedObservedRestartCounts records the current restart counts of a pod as
+// already processed. The kubelet recreates the mirror pod of a static pod
+// with a new UID, e.g. after it was deleted through the API, while the
+// containers keep running: the restarts in its status happened before and
+// must not be counted again. When the static pod itself was recreated, the
+// counts start over at zero and the next restart is detected as usual.
+func seedObservedRestartCounts(pod *corev1.Pod) {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, cs := range statuses {
+			containerKey := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+			stateStore.setObservedRestartCount(containerKey, cs.RestartCount)
+		}
+	}
+}
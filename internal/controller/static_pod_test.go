@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestResolveWorkloadStaticPod(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want workloadRef
+	}{
+		{
+			name: "mirror pod owned by its node",
+			pod: testsupport.NewPod("kube-system", "kube-apiserver-cp-1").WithNode("cp-1").
+				WithOwner("Node", "cp-1").WithAnnotation(corev1.MirrorPodAnnotationKey, "hash").Build(),
+			want: workloadRef{Namespace: "kube-system", Kind: workloadKindStaticPod, Name: "kube-apiserver"},
+		},
+		{
+			name: "mirror pod without owner references",
+			pod: testsupport.NewPod("kube-system", "etcd-cp-2").WithNode("cp-2").
+				WithAnnotation(corev1.MirrorPodAnnotationKey, "hash").Build(),
+			want: workloadRef{Namespace: "kube-system", Kind: workloadKindStaticPod, Name: "etcd"},
+		},
+		{
+			// 名称不以节点名结尾时保留原名
+			name: "mirror pod named without node suffix",
+			pod: testsupport.NewPod("kube-system", "haproxy").WithNode("cp-1").
+				WithAnnotation(corev1.MirrorPodAnnotationKey, "hash").Build(),
+			want: workloadRef{Namespace: "kube-system", Kind: workloadKindStaticPod, Name: "haproxy"},
+		},
+		{
+			name: "pod owned by a node without the mirror annotation",
+			pod:  testsupport.NewPod("kube-system", "agent-cp-1").WithNode("cp-1").WithOwner("Node", "cp-1").Build(),
+			want: workloadRef{Namespace: "kube-system", Kind: "Node", Name: "cp-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveWorkload(tt.pod); got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecreatedMirrorPodRestarts(t *testing.T) {
+	const namespace = "static-pod-test"
+	const name = "kube-scheduler-cp-1"
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+
+	// 模拟 kubelet 以新的 UID 重建镜像 Pod
+	var current *corev1.Pod
+	recreate := func(t *testing.T, uid string, restartCount int32) {
+		t.Helper()
+		if current != nil {
+			if err := c.Delete(ctx, current); err != nil {
+				t.Fatal(err)
+			}
+		}
+		current = testsupport.NewPod(namespace, name).WithUID(uid).WithNode("cp-1").
+			WithOwner("Node", "cp-1").WithAnnotation(corev1.MirrorPodAnnotationKey, "hash").
+			WithTerminatedContainer("kube-scheduler", restartCount, "Error", 1, time.Now()).
+			Build()
+		if err := c.Create(ctx, current); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reconcilePod(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		_ = c.Delete(ctx, current)
+		_, _ = r.reconcilePod(ctx, req)
+	}()
+	assertRestarts := func(t *testing.T, want float64) {
+		t.Helper()
+		testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total",
+			testsupport.Labels{"namespace": namespace, "pod": name, "container": "kube-scheduler", "reason": "Error"}, want)
+	}
+
+	recreate(t, "uid-a", 2)
+	assertRestarts(t, 1)
+
+	// 容器未重启，仅镜像 Pod 被重建：状态中的重启次数不应再次计数
+	recreate(t, "uid-b", 2)
+	assertRestarts(t, 1)
+
+	// 静态 Pod 本身被重建，重启次数从零开始，之后的重启照常检测
+	recreate(t, "uid-c", 0)
+	assertRestarts(t, 1)
+	current.Status.ContainerStatuses[0] = testsupport.TerminatedContainerStatus("kube-scheduler", 1, "Error", 1,
+		time.Now())
+	if err := c.Status().Update(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcilePod(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	assertRestarts(t, 2)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestTerminationReasonTotalIsMonotonic(t *testing.T) {
+	const namespace = "termination-reason-test"
+	pod := testsupport.NewPod(namespace, "app").WithContainer("main").Build()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pod).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "app"}}
+	defer func() {
+		// 删除 Pod 并 reconcile，清理内存状态
+		_ = c.Delete(context.Background(), pod)
+		_, _ = r.reconcilePod(context.Background(), req)
+	}()
+
+	assertCount := func(t *testing.T, reason string, want float64) {
+		t.Helper()
+		labels := testsupport.Labels{"namespace": namespace, "pod": "app", "container": "main", "reason": reason}
+		if want == 0 {
+			testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_container_last_termination_reason_total",
+				labels)
+			return
+		}
+		testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_last_termination_reason_total",
+			labels, want)
+	}
+
+	steps := []struct {
+		restartCount int32
+		reason       string
+		wantOOM      float64
+		wantError    float64
+	}{
+		{restartCount: 1, reason: "OOMKilled", wantOOM: 1, wantError: 0},
+		{restartCount: 2, reason: "Error", wantOOM: 1, wantError: 1},
+		// 重启次数未变化时不应重复计数
+		{restartCount: 2, reason: "Error", wantOOM: 1, wantError: 1},
+		{restartCount: 3, reason: "OOMKilled", wantOOM: 2, wantError: 1},
+	}
+	for i, step := range steps {
+		var current corev1.Pod
+		if err := c.Get(context.Background(), req.NamespacedName, &current); err != nil {
+			t.Fatal(err)
+		}
+		current.Status.ContainerStatuses[0] = testsupport.TerminatedContainerStatus("main", step.restartCount,
+			step.reason, 1, time.Now())
+		if err := c.Status().Update(context.Background(), &current); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := r.reconcilePod(context.Background(), req); err != nil {
+			t.Fatalf("step %d: reconcile failed: %v", i, err)
+		}
+		t.Run(fmt.Sprintf("step %d", i), func(t *testing.T) {
+			assertCount(t, "OOMKilled", step.wantOOM)
+			assertCount(t, "Error", step.wantError)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var (
+	// reconcile 时 Pod 已被删除的次数；tombstone 为 false 时 Pod 的最终状态不可用，
+	// 其中未被观察到的终止已丢失
+	podGoneReconcilesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_pod_gone_reconciles_total",
+			Help: "Total number of pod reconciles that found the pod already deleted, by whether its final " +
+				"state was available from the delete event. Terminations of pods without one are lost.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"tombstone", // 删除事件是否携带了 Pod 的最终状态
+		},
+	)
+
+	// 从已删除 Pod 的最终状态中补记的容器终止次数
+	tombstoneTerminationsRecoveredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_monitor_tombstone_terminations_recovered_total",
+			Help: "Total number of container terminations recorded from the final state of pods that were " +
+				"deleted before they could be reconciled",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+		},
+	)
+
+	// 删除事件携带的 Pod 最终状态，由对应的 reconcile 取走
+	// key: "namespace/podName"
+	podTombstones      = make(map[string]*corev1.Pod)
+	podTombstonesMutex sync.Mutex
+)
+
+func init() {
+	registerMetrics(podGoneReconcilesTotal, tombstoneTerminationsRecoveredTotal)
+}
+
+// podTombstonePredicate keeps the final state of deleted pods until their
+// delete is reconciled. A pod that crashes and is garbage collected between
+// the event and the reconcile can no longer be read, and its last
+// terminations would otherwise never be recorded. The state of a missed
+// delete (DeleteStateUnknown) is the last one seen and is kept as well.
+func podTombstonePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			if pod, ok := e.Object.(*corev1.Pod); ok {
+				podTombstonesMutex.Lock()
+				podTombstones[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = pod
+				podTombstonesMutex.Unlock()
+			}
+			return true
+		},
+	}
+}
+
+// takePodTombstone returns and drops the final state of a deleted pod, or nil
+// when its delete event was not seen.
+func takePodTombstone(namespace, podName string) *corev1.Pod {
+	key := fmt.Sprintf("%s/%s", namespace, podName)
+	podTombstonesMutex.Lock()
+	defer podTombstonesMutex.Unlock()
+	pod := podTombstones[key]
+	delete(podTombstones, key)
+	return pod
+}
+
+// recordFinalTerminations records the terminations in the final state of a
+// deleted pod that were not observed while it existed: restarts beyond the
+// observed restart count and failed containers of Job pods. It returns the
+// number of terminations recorded.
+func (r *PodMonitorReconciler) recordFinalTerminations(ctx context.Context, pod *corev1.Pod) int {
+	var batch metricBatch
+	defer stateStore.commitMetrics(&batch)
+
+	policy := r.policyFor(ctx, pod.Namespace)
+	workload := resolveWorkload(pod)
+	if r.isCritical(ctx, pod, workload) {
+		policy = policy.escalate()
+	}
+	recovered := r.reportJobFailures(pod, workload)
+	for _, cs := range policy.containerStatuses(pod) {
+		if policy.isExcluded(cs.Name) {
+			continue
+		}
+		containerKey := fmt.Sprintf("%s/%s/%s", pod.Namespace, pod.Name, cs.Name)
+		observedCount := stateStore.observedRestartCount(containerKey)
+		terminated := cs.LastTerminationState.Terminated
+		if cs.RestartCount <= observedCount || terminated == nil || isCompletedJobContainer(workload, terminated) {
+			continue
+		}
+		r.recordContainerRestart(ctx, &batch, pod, cs, workload, policy.severity())
+		r.checkRestartThreshold(pod, cs, observedCount, policy)
+		stateStore.setObservedRestartCount(containerKey, cs.RestartCount)
+		recovered++
+	}
+	return recovered
+}
+
+// reconcileDeletedPod records what can still be learned from the final state
+// of a pod that was deleted before it was reconciled, then drops its metrics
+// and in-memory state.
+func (r *PodMonitorReconciler) reconcileDeletedPod(ctx context.Context, namespace, podName string) {
+	log := logf.FromContext(ctx)
+
+	tombstone := takePodTombstone(namespace, podName)
+	podGoneReconcilesTotal.WithLabelValues(namespace, strconv.FormatBool(tombstone != nil)).Inc()
+	if tombstone != nil {
+		if n := r.recordFinalTerminations(ctx, tombstone); n > 0 {
+			log.Info("Recorded terminations from the final state of a deleted pod", "namespace", namespace,
+				"pod", podName, "terminations", n)
+			tombstoneTerminationsRecoveredTotal.WithLabelValues(namespace).Add(float64(n))
+		}
+	}
+	cleanupPod(namespace, podName)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestDeletedPodTerminationsRecoveredFromTombstone(t *testing.T) {
+	const namespace = "tombstone-test"
+	ctx := context.Background()
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	reconcile := func(t *testing.T, name string) {
+		t.Helper()
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+		if _, err := r.reconcilePod(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 在 reconcile 之前 Pod 已被删除，只有删除事件携带了最终状态
+	deleteWithTombstone := func(t *testing.T, pod *corev1.Pod) {
+		t.Helper()
+		if err := c.Delete(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+		podTombstonePredicate().Delete(event.DeleteEvent{Object: pod})
+	}
+
+	web := testsupport.NewPod(namespace, "web-abc").WithOwner("ReplicaSet", "web-5d8f").
+		WithTerminatedContainer("app", 1, "Error", 1, time.Now().Add(-time.Minute)).Build()
+	job := testsupport.NewPod(namespace, "migrate-xyz").WithOwner("Job", "migrate").WithContainer("migrate").Build()
+	for _, pod := range []*corev1.Pod{web, job} {
+		if err := c.Create(ctx, pod); err != nil {
+			t.Fatal(err)
+		}
+		reconcile(t, pod.Name)
+	}
+	restarts := testsupport.Labels{"namespace": namespace, "pod": "web-abc", "container": "app", "reason": "Error"}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total", restarts, 1)
+
+	// 崩溃后立即被删除：最后一次重启与 Job 容器的失败只存在于最终状态中
+	web.Status.ContainerStatuses[0] = testsupport.TerminatedContainerStatus("app", 2, "OOMKilled", 137, time.Now())
+	deleteWithTombstone(t, web)
+	reconcile(t, "web-abc")
+	job.Status.ContainerStatuses[0] = corev1.ContainerStatus{Name: "migrate", State: corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 2},
+	}}
+	deleteWithTombstone(t, job)
+	reconcile(t, "migrate-xyz")
+
+	restarts["reason"] = "OOMKilled"
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total", restarts, 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_job_container_failures_total",
+		testsupport.Labels{"namespace": namespace, "job": "migrate", "container": "migrate", "exit_code": "2"}, 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_tombstone_terminations_recovered_total",
+		testsupport.Labels{"namespace": namespace}, 2)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_gone_reconciles_total",
+		testsupport.Labels{"namespace": namespace, "tombstone": "true"}, 2)
+
+	// 未收到删除事件的 Pod：最终状态不可用，计为丢失；tombstone 只使用一次
+	reconcile(t, "web-abc")
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_gone_reconciles_total",
+		testsupport.Labels{"namespace": namespace, "tombstone": "false"}, 1)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_tombstone_terminations_recovered_total",
+		testsupport.Labels{"namespace": namespace}, 2)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodeTopologyTTL is how long a node's zone/region labels are cached.
+// nodeTopologyLookupTimeout bounds how long a pod reconcile waits for the
+// node, e.g. while the Node cache is still syncing.
+const (
+	nodeTopologyTTL           = 5 * time.Minute
+	nodeTopologyLookupTimeout = 2 * time.Second
+)
+
+var (
+	// Pod 所在节点的可用区与区域信息，值恒为 1
+	podTopologyInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_pod_topology_info",
+			Help: "Zone and region of the node a pod is scheduled on. The value is always 1.",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"node",      // 节点名称
+			"zone",      // topology.kubernetes.io/zone
+			"region",    // topology.kubernetes.io/region
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(podTopologyInfo))
+}
+
+// nodeTopology is the cached zone/region of a node.
+type nodeTopology struct {
+	zone      string
+	region    string
+	fetchedAt time.Time
+}
+
+// nodeTopologyCache caches node topology labels per node name so that pod
+// reconciles do not look up the node every time.
+type nodeTopologyCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	nodes map[string]nodeTopology
+}
+
+func newNodeTopologyCache(ttl time.Duration) *nodeTopologyCache {
+	return &nodeTopologyCache{ttl: ttl, nodes: make(map[string]nodeTopology)}
+}
+
+// lookup returns the topology of the node, fetching it through the client
+// when the cached entry is missing or older than the TTL.
+func (c *nodeTopologyCache) lookup(ctx context.Context, reader client.Reader, nodeName string, now time.Time) (nodeTopology, error) {
+	c.mu.Lock()
+	cached, ok := c.nodes[nodeName]
+	c.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < c.ttl {
+		return cached, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, nodeTopologyLookupTimeout)
+	defer cancel()
+	var node corev1.Node
+	if err := reader.Get(lookupCtx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		return nodeTopology{}, err
+	}
+	topo := nodeTopology{
+		zone:      node.Labels[corev1.LabelTopologyZone],
+		region:    node.Labels[corev1.LabelTopologyRegion],
+		fetchedAt: now,
+	}
+
+	c.mu.Lock()
+	c.nodes[nodeName] = topo
+	// 清理过期条目，防止已删除节点的记录一直留在内存中
+	for name, entry := range c.nodes {
+		if now.Sub(entry.fetchedAt) >= c.ttl {
+			delete(c.nodes, name)
+		}
+	}
+	c.mu.Unlock()
+
+	return topo, nil
+}
+
+// updatePodTopology exports pod_monitor_pod_topology_info for a scheduled pod,
+// replacing the pod's previous series when the node's labels changed.
+func (r *PodMonitorReconciler) updatePodTopology(ctx context.Context, b *metricBatch, pod *corev1.Pod) error {
+	if pod.Spec.NodeName == "" || r.topologyCache == nil {
+		return nil
+	}
+
+	topo, err := r.topologyCache.lookup(ctx, r.Client, pod.Spec.NodeName, r.now())
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	// 节点的可用区/区域标签变化时，移除旧序列
+	b.deletePartial(podTopologyInfo.MetricVec, prometheus.Labels{"namespace": pod.Namespace, "pod": pod.Name})
+	b.set(podTopologyInfo, 1, pod.Namespace, pod.Name, pod.Spec.NodeName, topo.zone, topo.region)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestPodTopologyInfo(t *testing.T) {
+	const namespace = "topology-test"
+	ctx := context.Background()
+	defer podTopologyInfo.Reset()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{
+		corev1.LabelTopologyZone: "eu-west-1a", corev1.LabelTopologyRegion: "eu-west-1"}}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(node).Build()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(now)
+	r := &PodMonitorReconciler{Client: c, Clock: clock, topologyCache: newNodeTopologyCache(nodeTopologyTTL)}
+
+	update := func(t *testing.T, pod *corev1.Pod) {
+		t.Helper()
+		var b metricBatch
+		if err := r.updatePodTopology(ctx, &b, pod); err != nil {
+			t.Fatal(err)
+		}
+		stateStore.commitMetrics(&b)
+	}
+	web := testsupport.NewPod(namespace, "web").WithNode("node-a").Build()
+	webLabels := testsupport.Labels{"namespace": namespace, "pod": "web"}
+
+	update(t, web)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_topology_info",
+		testsupport.Labels{"namespace": namespace, "pod": "web", "node": "node-a",
+			"zone": "eu-west-1a", "region": "eu-west-1"}, 1)
+
+	// 未调度的 Pod 与节点已不存在的 Pod 不导出序列
+	update(t, testsupport.NewPod(namespace, "pending").Build())
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_pod_topology_info",
+		testsupport.Labels{"namespace": namespace, "pod": "pending"})
+	update(t, testsupport.NewPod(namespace, "orphan").WithNode("node-gone").Build())
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_pod_topology_info",
+		testsupport.Labels{"namespace": namespace, "pod": "orphan"})
+
+	// 节点标签变化在缓存过期前不可见
+	node.Labels[corev1.LabelTopologyZone] = "eu-west-1b"
+	if err := c.Update(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	clock.SetTime(now.Add(time.Minute))
+	update(t, web)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_topology_info",
+		testsupport.Labels{"namespace": namespace, "pod": "web", "zone": "eu-west-1a"}, 1)
+
+	// 缓存过期后读取新标签，并移除旧序列
+	clock.SetTime(now.Add(nodeTopologyTTL + time.Minute))
+	update(t, web)
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_pod_topology_info",
+		testsupport.Labels{"namespace": namespace, "pod": "web", "zone": "eu-west-1b"}, 1)
+	if n := testsupport.CountSeries(t, metrics.Registry, "pod_monitor_pod_topology_info", webLabels); n != 1 {
+		t.Errorf("expected the previous zone's series to be removed, got %d series", n)
+	}
+
+	// Pod 删除后清理
+	cleanupPod(namespace, "web")
+	testsupport.AssertNoMetric(t, metrics.Registry, "pod_monitor_pod_topology_info", webLabels)
+}
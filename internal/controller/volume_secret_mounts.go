@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	// 容器挂载的 Secret 卷数量；挂载越多，Secret 轮换过程中重启的机会越大
+	containerVolumeMountSecretCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pod_monitor_container_volume_mount_secret_count",
+			Help: "Number of secret volumes mounted by a container",
+		},
+		[]string{
+			"namespace", // Pod 所在命名空间
+			"pod",       // Pod 名称
+			"container", // 容器名称
+		},
+	)
+)
+
+func init() {
+	registerMetrics(batched(containerVolumeMountSecretCount))
+}
+
+// secretVolumeNames returns the names of the volumes of a pod backed by a
+// secret.
+func secretVolumeNames(pod *corev1.Pod) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, v := range pod.Spec.Volumes {
+		if v.Secret != nil {
+			names[v.Name] = struct{}{}
+		}
+	}
+	return names
+}
+
+// volumeMountSecretCount counts the distinct secret volumes a container
+// mounts; mounting several keys of one volume through subPath counts once.
+func volumeMountSecretCount(c *corev1.Container, secretVolumes map[string]struct{}) int {
+	mounted := make(map[string]struct{})
+	for _, m := range c.VolumeMounts {
+		if _, ok := secretVolumes[m.Name]; ok {
+			mounted[m.Name] = struct{}{}
+		}
+	}
+	return len(mounted)
+}
+
+// updateVolumeMountSecretCount exports the secret volumes mounted by every
+// container of a pod.
+func updateVolumeMountSecretCount(b *metricBatch, pod *corev1.Pod) {
+	secretVolumes := secretVolumeNames(pod)
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		b.set(containerVolumeMountSecretCount, float64(volumeMountSecretCount(c, secretVolumes)),
+			pod.Namespace, pod.Name, c.Name)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestVolumeMountSecretCount(t *testing.T) {
+	const namespace = "volume-mount-secrets-test"
+	pod := testsupport.NewPod(namespace, "api").WithContainer("app").WithContainer("sidecar").Build()
+	pod.Spec.Volumes = []corev1.Volume{
+		{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "api-tls"}}},
+		{Name: "db", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db"}}},
+		{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+	}
+	pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{
+		{Name: "tls", MountPath: "/tls/tls.crt", SubPath: "tls.crt"},
+		{Name: "tls", MountPath: "/tls/tls.key", SubPath: "tls.key"},
+		{Name: "db", MountPath: "/db"},
+		{Name: "config", MountPath: "/config"},
+	}
+	defer cleanupPod(namespace, "api")
+
+	var batch metricBatch
+	updateVolumeMountSecretCount(&batch, pod)
+	stateStore.commitMetrics(&batch)
+
+	const name = "pod_monitor_container_volume_mount_secret_count"
+	testsupport.AssertMetricValue(t, metrics.Registry, name,
+		testsupport.Labels{"namespace": namespace, "pod": "api", "container": "app"}, 2)
+	testsupport.AssertMetricValue(t, metrics.Registry, name,
+		testsupport.Labels{"namespace": namespace, "pod": "api", "container": "sidecar"}, 0)
+
+	cleanupPod(namespace, "api")
+	testsupport.AssertNoMetric(t, metrics.Registry, name, testsupport.Labels{"namespace": namespace})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// WatchFilter decides which pods and secrets are reconciled. It is consulted
+// for every create, update, delete and generic event before the
+// event-specific predicates; update events pass the new object.
+type WatchFilter interface {
+	AllowPod(e event.GenericEvent) bool
+	AllowSecret(e event.GenericEvent) bool
+}
+
+// DefaultWatchFilter is the WatchFilter used when none is configured. It
+// allows every pod and every secret: which secret updates are reconciled is
+// decided by the update and etcd predicates, and which keys are checked by
+// reconcileSecret.
+type DefaultWatchFilter struct{}
+
+var _ WatchFilter = DefaultWatchFilter{}
+
+// AllowPod implements WatchFilter.
+func (DefaultWatchFilter) AllowPod(event.GenericEvent) bool { return true }
+
+// AllowSecret implements WatchFilter.
+func (DefaultWatchFilter) AllowSecret(event.GenericEvent) bool { return true }
+
+// watchFilter returns the configured WatchFilter or the default one.
+func (r *PodMonitorReconciler) watchFilter() WatchFilter {
+	if r.WatchFilter != nil {
+		return r.WatchFilter
+	}
+	return DefaultWatchFilter{}
+}
+
+// watchFilterPredicate adapts a WatchFilter method to a predicate that is
+// applied to all event types.
+func watchFilterPredicate(allow func(event.GenericEvent) bool) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return allow(event.GenericEvent{Object: e.Object})
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return allow(event.GenericEvent{Object: e.ObjectNew})
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return allow(event.GenericEvent{Object: e.Object})
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return allow(e)
+		},
+	}
+}
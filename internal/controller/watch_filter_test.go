@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// MockWatchFilter returns the given decisions in order and records the
+// objects it was asked about. It allows everything once the decisions run out.
+type MockWatchFilter struct {
+	Decisions []bool
+	Pods      []string
+	Secrets   []string
+}
+
+func (m *MockWatchFilter) next() bool {
+	if len(m.Decisions) == 0 {
+		return true
+	}
+	allow := m.Decisions[0]
+	m.Decisions = m.Decisions[1:]
+	return allow
+}
+
+func (m *MockWatchFilter) AllowPod(e event.GenericEvent) bool {
+	m.Pods = append(m.Pods, e.Object.GetName())
+	return m.next()
+}
+
+func (m *MockWatchFilter) AllowSecret(e event.GenericEvent) bool {
+	m.Secrets = append(m.Secrets, e.Object.GetName())
+	return m.next()
+}
+
+func TestWatchFilterPredicate(t *testing.T) {
+	filter := &MockWatchFilter{Decisions: []bool{true, false, true, false}}
+	r := &PodMonitorReconciler{WatchFilter: filter}
+	p := watchFilterPredicate(r.watchFilter().AllowSecret)
+
+	oldSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "old"}}
+	newSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "new"}}
+	got := []bool{
+		p.Create(event.CreateEvent{Object: newSecret}),
+		p.Update(event.UpdateEvent{ObjectOld: oldSecret, ObjectNew: newSecret}),
+		p.Delete(event.DeleteEvent{Object: oldSecret}),
+		p.Generic(event.GenericEvent{Object: newSecret}),
+	}
+	want := []bool{true, false, true, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+	// 更新事件按新对象判断
+	if filter.Secrets[1] != "new" {
+		t.Errorf("expected the update to be filtered on the new object, got %q", filter.Secrets[1])
+	}
+	if len(filter.Pods) != 0 {
+		t.Errorf("expected the pod filter not to be consulted, got %v", filter.Pods)
+	}
+}
+
+func TestDefaultWatchFilter(t *testing.T) {
+	r := &PodMonitorReconciler{}
+	filter := r.watchFilter()
+	pod := event.GenericEvent{Object: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "a"}}}
+	secret := event.GenericEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "b"}}}
+	if !filter.AllowPod(pod) || !filter.AllowSecret(secret) {
+		t.Fatal("expected the default filter to allow every pod and secret")
+	}
+}
+
+func TestSecretWatchKeysAndValues(t *testing.T) {
+	r := &PodMonitorReconciler{
+		WatchEtcdCerts:         true,
+		EtcdSecretNames:        []string{"etcd-certs"},
+		LinkerdMode:            true,
+		LinkerdNamespace:       "linkerd-system",
+		AutoDiscoverCerts:      true,
+		AutoDiscoverNamespaces: []string{"web", "batch"},
+	}
+	got := fmt.Sprint(r.secretWatchKeysAndValues(r.watchFilter()))
+	want := "[watchFilter controller.DefaultWatchFilter namedSecrets [kube-system/etcd-certs " +
+		"linkerd-system/linkerd-identity-issuer] autoDiscover [web batch]]"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	// 未启用的来源不列出，Linkerd 命名空间回退到默认值
+	r = &PodMonitorReconciler{LinkerdMode: true}
+	got = fmt.Sprint(r.secretWatchKeysAndValues(r.watchFilter()))
+	want = "[watchFilter controller.DefaultWatchFilter namedSecrets [linkerd/linkerd-identity-issuer] autoDiscover disabled]"
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
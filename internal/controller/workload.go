@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch
+
+// workloadRef identifies the top-level workload a pod belongs to.
+type workloadRef struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
+// key returns a stable map key for the workload.
+func (w workloadRef) key() string {
+	return fmt.Sprintf("%s/%s/%s", w.Namespace, w.Kind, w.Name)
+}
+
+// resolveWorkload derives the owning workload of a pod from its owner
+// references alone, without extra API calls. Pods owned by a ReplicaSet are
+// attributed to the Deployment when the ReplicaSet name carries the pod's
+// pod-template-hash suffix; mirror pods are attributed to their static pod
+// and other pods without a controller are their own workload.
+func resolveWorkload(pod *corev1.Pod) workloadRef {
+	if isMirrorPod(pod) {
+		return staticPodWorkload(pod)
+	}
+
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return workloadRef{Namespace: pod.Namespace, Kind: "Pod", Name: pod.Name}
+	}
+
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+			if name, ok := strings.CutSuffix(owner.Name, "-"+hash); ok && name != "" {
+				return workloadRef{Namespace: pod.Namespace, Kind: "Deployment", Name: name}
+			}
+		}
+	}
+
+	return workloadRef{Namespace: pod.Namespace, Kind: owner.Kind, Name: owner.Name}
+}
+
+// rolloutLookupTimeout bounds how long a restart waits for the owning
+// workload's status; rolloutLookupBackoff is how long lookups are skipped
+// after a failure (e.g. missing RBAC) so reconciles are not slowed down.
+const (
+	rolloutLookupTimeout = 2 * time.Second
+	rolloutLookupBackoff = 10 * time.Minute
+)
+
+// rolloutStateUnknown is reported when the workload status cannot be read.
+const rolloutStateUnknown = "unknown"
+
+// lookupWorkload reads the owning Deployment or StatefulSet and returns it
+// with its rollout state. The object is nil when the pod has no such owner or
+// it cannot be read.
+func (r *PodMonitorReconciler) lookupWorkload(ctx context.Context, workload workloadRef) (client.Object, string) {
+	obj := newWorkloadObject(workload.Kind)
+	if obj == nil {
+		return nil, "false"
+	}
+
+	if until, ok := r.rolloutLookupDisabledUntil.Load().(time.Time); ok && r.now().Before(until) {
+		return nil, rolloutStateUnknown
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, rolloutLookupTimeout)
+	defer cancel()
+	if err := r.Get(lookupCtx, types.NamespacedName{Namespace: workload.Namespace, Name: workload.Name}, obj); err != nil {
+		if !apierrors.IsNotFound(err) {
+			// 权限不足或缓存无法同步时，暂停查询一段时间
+			logf.FromContext(ctx).Info("Unable to read workload status, reporting rollout state as unknown",
+				"kind", workload.Kind, "name", workload.Name, "error", err.Error())
+			r.rolloutLookupDisabledUntil.Store(r.now().Add(rolloutLookupBackoff))
+		}
+		return nil, rolloutStateUnknown
+	}
+
+	return obj, strconv.FormatBool(isRollingOut(obj))
+}
+
+// newWorkloadObject returns an empty object of a workload kind the operator
+// can read, or nil for other kinds.
+func newWorkloadObject(kind string) client.Object {
+	switch kind {
+	case "Deployment":
+		return &appsv1.Deployment{}
+	case "StatefulSet":
+		return &appsv1.StatefulSet{}
+	}
+	return nil
+}
+
+// isRollingOut reports whether a Deployment or StatefulSet has not finished
+// rolling out its latest template.
+func isRollingOut(obj client.Object) bool {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		if w.Status.ObservedGeneration < w.Generation || w.Status.UpdatedReplicas < replicas {
+			return true
+		}
+		for _, cond := range w.Status.Conditions {
+			if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionTrue &&
+				cond.Reason != "NewReplicaSetAvailable" {
+				return true
+			}
+		}
+	case *appsv1.StatefulSet:
+		replicas := int32(1)
+		if w.Spec.Replicas != nil {
+			replicas = *w.Spec.Replicas
+		}
+		if w.Status.ObservedGeneration < w.Generation || w.Status.UpdatedReplicas < replicas {
+			return true
+		}
+		if w.Status.UpdateRevision != "" && w.Status.UpdateRevision != w.Status.CurrentRevision {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments/status;statefulsets/status,verbs=get;update;patch
+
+const (
+	// workloadConditionCrashLooping is the condition set on a Deployment or
+	// StatefulSet whose pods cross the restart alert threshold.
+	workloadConditionCrashLooping = "PodMonitorCrashLooping"
+
+	// Reasons of the PodMonitorCrashLooping condition.
+	workloadConditionReasonBurst    = "RestartThresholdExceeded"
+	workloadConditionReasonSubsided = "RestartsSubsided"
+
+	// defaultWorkloadConditionCoolDown is how long a marked workload must go
+	// without a restart above the threshold before its condition is cleared.
+	defaultWorkloadConditionCoolDown = 30 * time.Minute
+	// workloadConditionCheckInterval is how often marked workloads are
+	// checked for a subsided burst.
+	workloadConditionCheckInterval = time.Minute
+)
+
+// markedWorkload is a workload the operator set PodMonitorCrashLooping=True
+// on, with the time of its latest restart above the threshold.
+type markedWorkload struct {
+	ref       workloadRef
+	lastBurst time.Time
+}
+
+// workloadConditionTracker remembers the workloads the operator marked, so
+// that it only ever clears conditions it set itself.
+type workloadConditionTracker struct {
+	mu sync.Mutex
+	// key: workloadRef.key()
+	marked map[string]*markedWorkload
+}
+
+func newWorkloadConditionTracker() *workloadConditionTracker {
+	return &workloadConditionTracker{marked: make(map[string]*markedWorkload)}
+}
+
+// burst records a restart above the threshold and reports whether the
+// workload still has to be marked.
+func (t *workloadConditionTracker) burst(ref workloadRef, at time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if m, ok := t.marked[ref.key()]; ok {
+		if at.After(m.lastBurst) {
+			m.lastBurst = at
+		}
+		return false
+	}
+	return true
+}
+
+// mark records that PodMonitorCrashLooping=True was set on a workload.
+func (t *workloadConditionTracker) mark(ref workloadRef, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.marked[ref.key()] = &markedWorkload{ref: ref, lastBurst: at}
+}
+
+// subsided returns the marked workloads without a burst for coolDown.
+func (t *workloadConditionTracker) subsided(now time.Time, coolDown time.Duration) []workloadRef {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var refs []workloadRef
+	for _, m := range t.marked {
+		if now.Sub(m.lastBurst) >= coolDown {
+			refs = append(refs, m.ref)
+		}
+	}
+	return refs
+}
+
+// forget drops a workload whose condition was cleared or that was deleted,
+// unless it burst again since before.
+func (t *workloadConditionTracker) forget(ref workloadRef, before time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if m, ok := t.marked[ref.key()]; ok && !m.lastBurst.After(before) {
+		delete(t.marked, ref.key())
+	}
+}
+
+// setCrashLoopingCondition sets the PodMonitorCrashLooping condition on a
+// Deployment or StatefulSet through its status subresource, retrying on
+// conflicts with the workload controller. It reports whether the workload
+// exists.
+func (r *PodMonitorReconciler) setCrashLoopingCondition(ctx context.Context, ref workloadRef,
+	status corev1.ConditionStatus, reason, message string) (bool, error) {
+	found := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj := newWorkloadObject(ref.Kind)
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				found = false
+				return nil
+			}
+			return err
+		}
+		if !setWorkloadCondition(obj, status, reason, message, metav1.NewTime(r.now())) {
+			return nil
+		}
+		return r.Status().Update(ctx, obj)
+	})
+	return found, err
+}
+
+// setWorkloadCondition sets the PodMonitorCrashLooping condition of a
+// Deployment or StatefulSet and reports whether it changed.
+func setWorkloadCondition(obj client.Object, status corev1.ConditionStatus, reason, message string,
+	now metav1.Time) bool {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		for i := range w.Status.Conditions {
+			cond := &w.Status.Conditions[i]
+			if cond.Type != workloadConditionCrashLooping {
+				continue
+			}
+			if cond.Status == status && cond.Reason == reason && cond.Message == message {
+				return false
+			}
+			if cond.Status != status {
+				cond.LastTransitionTime = now
+			}
+			cond.Status, cond.Reason, cond.Message, cond.LastUpdateTime = status, reason, message, now
+			return true
+		}
+		w.Status.Conditions = append(w.Status.Conditions, appsv1.DeploymentCondition{
+			Type: workloadConditionCrashLooping, Status: status, Reason: reason, Message: message,
+			LastUpdateTime: now, LastTransitionTime: now,
+		})
+		return true
+	case *appsv1.StatefulSet:
+		for i := range w.Status.Conditions {
+			cond := &w.Status.Conditions[i]
+			if cond.Type != workloadConditionCrashLooping {
+				continue
+			}
+			if cond.Status == status && cond.Reason == reason && cond.Message == message {
+				return false
+			}
+			if cond.Status != status {
+				cond.LastTransitionTime = now
+			}
+			cond.Status, cond.Reason, cond.Message = status, reason, message
+			return true
+		}
+		w.Status.Conditions = append(w.Status.Conditions, appsv1.StatefulSetCondition{
+			Type: workloadConditionCrashLooping, Status: status, Reason: reason, Message: message,
+			LastTransitionTime: now,
+		})
+		return true
+	}
+	return false
+}
+
+// crashLoopingConditionTrue reports whether a Deployment or StatefulSet has
+// PodMonitorCrashLooping=True.
+func crashLoopingConditionTrue(obj client.Object) bool {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		for _, cond := range w.Status.Conditions {
+			if cond.Type == workloadConditionCrashLooping {
+				return cond.Status == corev1.ConditionTrue
+			}
+		}
+	case *appsv1.StatefulSet:
+		for _, cond := range w.Status.Conditions {
+			if cond.Type == workloadConditionCrashLooping {
+				return cond.Status == corev1.ConditionTrue
+			}
+		}
+	}
+	return false
+}
+
+// markWorkloadCrashLooping sets PodMonitorCrashLooping=True on the owning
+// Deployment or StatefulSet of a pod whose container reached the restart
+// alert threshold.
+func (r *PodMonitorReconciler) markWorkloadCrashLooping(ctx context.Context, workload workloadRef, pod *corev1.Pod,
+	cs corev1.ContainerStatus, threshold int32) {
+	if !r.PatchWorkloadConditions || newWorkloadObject(workload.Kind) == nil {
+		return
+	}
+	now := r.now()
+	if !r.workloadConditions.burst(workload, now) {
+		return
+	}
+	message := fmt.Sprintf("Container %s of pod %s restarted %d times, reaching the threshold of %d",
+		cs.Name, pod.Name, cs.RestartCount, threshold)
+	found, err := r.setCrashLoopingCondition(ctx, workload, corev1.ConditionTrue, workloadConditionReasonBurst, message)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to set workload condition", "kind", workload.Kind,
+			"name", workload.Name, "condition", workloadConditionCrashLooping)
+		return
+	}
+	if found {
+		r.workloadConditions.mark(workload, now)
+	}
+}
+
+// clearSubsidedWorkloads sets PodMonitorCrashLooping=False on the marked
+// workloads without a restart above the threshold for the cool-down period.
+func (r *PodMonitorReconciler) clearSubsidedWorkloads(ctx context.Context) {
+	coolDown := r.WorkloadConditionCoolDown
+	if coolDown <= 0 {
+		coolDown = defaultWorkloadConditionCoolDown
+	}
+	now := r.now()
+	message := fmt.Sprintf("No container restarted past the threshold for %s", coolDown)
+	for _, ref := range r.workloadConditions.subsided(now, coolDown) {
+		if _, err := r.setCrashLoopingCondition(ctx, ref, corev1.ConditionFalse, workloadConditionReasonSubsided,
+			message); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to clear workload condition", "kind", ref.Kind,
+				"name", ref.Name, "condition", workloadConditionCrashLooping)
+			continue
+		}
+		r.workloadConditions.forget(ref, now.Add(-coolDown))
+	}
+}
+
+// adoptMarkedWorkloads tracks the workloads left with
+// PodMonitorCrashLooping=True by a previous run of the operator, so that
+// their condition is cleared after the cool-down period too.
+func (r *PodMonitorReconciler) adoptMarkedWorkloads(ctx context.Context) error {
+	lists := map[string]client.ObjectList{"Deployment": &appsv1.DeploymentList{}, "StatefulSet": &appsv1.StatefulSetList{}}
+	now := r.now()
+	for kind, list := range lists {
+		if err := r.List(ctx, list); err != nil {
+			return err
+		}
+		var objects []client.Object
+		switch l := list.(type) {
+		case *appsv1.DeploymentList:
+			for i := range l.Items {
+				objects = append(objects, &l.Items[i])
+			}
+		case *appsv1.StatefulSetList:
+			for i := range l.Items {
+				objects = append(objects, &l.Items[i])
+			}
+		}
+		for _, obj := range objects {
+			if crashLoopingConditionTrue(obj) {
+				r.workloadConditions.mark(workloadRef{Namespace: obj.GetNamespace(), Kind: kind,
+					Name: obj.GetName()}, now)
+			}
+		}
+	}
+	return nil
+}
+
+// workloadConditionClearer clears the conditions of workloads whose restart
+// burst subsided. Only the leader reconciles pods and marks workloads, so it
+// runs on the leader only.
+type workloadConditionClearer struct {
+	r        *PodMonitorReconciler
+	interval time.Duration
+}
+
+var _ manager.Runnable = &workloadConditionClearer{}
+var _ manager.LeaderElectionRunnable = &workloadConditionClearer{}
+
+func newWorkloadConditionClearer(r *PodMonitorReconciler, interval time.Duration) *workloadConditionClearer {
+	return &workloadConditionClearer{r: r, interval: interval}
+}
+
+// Start adopts the workloads marked by a previous run, then clears subsided
+// bursts every interval until the context is cancelled.
+func (c *workloadConditionClearer) Start(ctx context.Context) error {
+	ctx = logf.IntoContext(ctx, logf.FromContext(ctx).WithName("workload-conditions"))
+	if err := c.r.adoptMarkedWorkloads(ctx); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list workloads marked by a previous run")
+	}
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.r.clearSubsidedWorkloads(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection returns true: only the leader marks workloads.
+func (c *workloadConditionClearer) NeedLeaderElection() bool {
+	return true
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestWorkloadConditions(t *testing.T) {
+	const namespace = "workload-conditions-test"
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	web := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"}}
+	db := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "db"}}
+	// 其他控制器设置的同名条件，Operator 从未标记过
+	other := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "other"},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: workloadConditionCrashLooping, Status: corev1.ConditionFalse, Reason: "Manual"}}}}
+
+	// 第一次更新状态时返回冲突，验证乐观重试
+	conflicted := false
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(web, db, other).
+		WithStatusSubresource(web, db, other).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object,
+				opts ...client.SubResourceUpdateOption) error {
+				if !conflicted {
+					conflicted = true
+					return apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"},
+						obj.GetName(), nil)
+				}
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).Build()
+	clock := clocktesting.NewFakePassiveClock(now)
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme, Clock: clock, PatchWorkloadConditions: true,
+		WorkloadConditionCoolDown: 10 * time.Minute, workloadConditions: newWorkloadConditionTracker()}
+
+	condition := func(obj client.Object) (corev1.ConditionStatus, string) {
+		t.Helper()
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: obj.GetName()}, obj); err != nil {
+			t.Fatal(err)
+		}
+		switch w := obj.(type) {
+		case *appsv1.Deployment:
+			for _, cond := range w.Status.Conditions {
+				if cond.Type == workloadConditionCrashLooping {
+					return cond.Status, cond.Reason
+				}
+			}
+		case *appsv1.StatefulSet:
+			for _, cond := range w.Status.Conditions {
+				if cond.Type == workloadConditionCrashLooping {
+					return cond.Status, cond.Reason
+				}
+			}
+		}
+		return "", ""
+	}
+
+	pod := testsupport.NewPod(namespace, "web-abc").
+		WithTerminatedContainer("app", 5, "Error", 1, now).Build()
+	cs := pod.Status.ContainerStatuses[0]
+	r.markWorkloadCrashLooping(ctx, workloadRef{Namespace: namespace, Kind: "Deployment", Name: "web"}, pod, cs, 5)
+	r.markWorkloadCrashLooping(ctx, workloadRef{Namespace: namespace, Kind: "StatefulSet", Name: "db"}, pod, cs, 5)
+	// 不支持的工作负载类型不做任何处理
+	r.markWorkloadCrashLooping(ctx, workloadRef{Namespace: namespace, Kind: "Pod", Name: "web-abc"}, pod, cs, 5)
+
+	if !conflicted {
+		t.Error("status update conflict not exercised")
+	}
+	if status, reason := condition(&appsv1.Deployment{ObjectMeta: web.ObjectMeta}); status != corev1.ConditionTrue ||
+		reason != workloadConditionReasonBurst {
+		t.Errorf("Deployment condition = %s/%s, want True/%s", status, reason, workloadConditionReasonBurst)
+	}
+	if status, _ := condition(&appsv1.StatefulSet{ObjectMeta: db.ObjectMeta}); status != corev1.ConditionTrue {
+		t.Errorf("StatefulSet condition = %s, want True", status)
+	}
+
+	// 冷却期内又一次超过阈值，推迟清除
+	clock.SetTime(now.Add(8 * time.Minute))
+	r.markWorkloadCrashLooping(ctx, workloadRef{Namespace: namespace, Kind: "Deployment", Name: "web"}, pod, cs, 5)
+	clock.SetTime(now.Add(12 * time.Minute))
+	r.clearSubsidedWorkloads(ctx)
+	if status, _ := condition(&appsv1.Deployment{ObjectMeta: web.ObjectMeta}); status != corev1.ConditionTrue {
+		t.Errorf("Deployment condition = %s during cool-down, want True", status)
+	}
+	if status, reason := condition(&appsv1.StatefulSet{ObjectMeta: db.ObjectMeta}); status != corev1.ConditionFalse ||
+		reason != workloadConditionReasonSubsided {
+		t.Errorf("StatefulSet condition = %s/%s, want False/%s", status, reason, workloadConditionReasonSubsided)
+	}
+
+	clock.SetTime(now.Add(20 * time.Minute))
+	r.clearSubsidedWorkloads(ctx)
+	if status, _ := condition(&appsv1.Deployment{ObjectMeta: web.ObjectMeta}); status != corev1.ConditionFalse {
+		t.Errorf("Deployment condition = %s after cool-down, want False", status)
+	}
+	if status, reason := condition(&appsv1.Deployment{ObjectMeta: other.ObjectMeta}); status != corev1.ConditionFalse ||
+		reason != "Manual" {
+		t.Errorf("unmarked Deployment condition changed to %s/%s", status, reason)
+	}
+	if n := len(r.workloadConditions.marked); n != 0 {
+		t.Errorf("%d workloads still tracked after clearing", n)
+	}
+}
+
+func TestAdoptMarkedWorkloads(t *testing.T) {
+	const namespace = "workload-conditions-adopt-test"
+	marked := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "marked"},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: workloadConditionCrashLooping, Status: corev1.ConditionTrue}}}}
+	cleared := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "cleared"},
+		Status: appsv1.StatefulSetStatus{Conditions: []appsv1.StatefulSetCondition{
+			{Type: workloadConditionCrashLooping, Status: corev1.ConditionFalse}}}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(marked, cleared).Build()
+	r := &PodMonitorReconciler{Client: c, Clock: clocktesting.NewFakePassiveClock(time.Now()),
+		workloadConditions: newWorkloadConditionTracker()}
+
+	if err := r.adoptMarkedWorkloads(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.workloadConditions.marked[workloadRef{Namespace: namespace, Kind: "Deployment",
+		Name: "marked"}.key()]; !ok || len(r.workloadConditions.marked) != 1 {
+		t.Errorf("adopted workloads = %v, want only the marked Deployment", r.workloadConditions.marked)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func TestIsRollingOut(t *testing.T) {
+	deployment := func(generation, observed int64, replicas, updated int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: observed, UpdatedReplicas: updated},
+		}
+	}
+	statefulSet := func(generation, observed int64, replicas, updated int32) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(replicas)},
+			Status:     appsv1.StatefulSetStatus{ObservedGeneration: observed, UpdatedReplicas: updated},
+		}
+	}
+	newRevision := statefulSet(2, 2, 3, 3)
+	newRevision.Status.CurrentRevision, newRevision.Status.UpdateRevision = "web-1", "web-2"
+
+	tests := []struct {
+		name string
+		obj  client.Object
+		want bool
+	}{
+		{"deployment complete", deployment(2, 2, 3, 3), false},
+		{"deployment updating replicas", deployment(2, 2, 3, 1), true},
+		{"deployment spec not observed", deployment(3, 2, 3, 3), true},
+		{"statefulset complete", statefulSet(2, 2, 3, 3), false},
+		{"statefulset updating replicas", statefulSet(2, 2, 3, 2), true},
+		{"statefulset spec not observed", statefulSet(3, 2, 3, 3), true},
+		{"statefulset revision pending", newRevision, true},
+	}
+	for _, tt := range tests {
+		if got := isRollingOut(tt.obj); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestLookupWorkloadRolloutState(t *testing.T) {
+	const namespace = "rollout-state-test"
+	ctx := context.Background()
+	rolling := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "rolling", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1},
+	}
+	stable := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "stable", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 3},
+	}
+	// 模拟缺少 statefulsets 的读取权限
+	var gets int
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(rolling, stable).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+				opts ...client.GetOption) error {
+				gets++
+				if _, ok := obj.(*appsv1.StatefulSet); ok {
+					return apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "statefulsets"},
+						key.Name, nil)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(now)
+	r := &PodMonitorReconciler{Client: c, Clock: clock}
+	state := func(kind, name string) string {
+		_, got := r.lookupWorkload(ctx, workloadRef{Namespace: namespace, Kind: kind, Name: name})
+		return got
+	}
+
+	if got := state("Deployment", "rolling"); got != "true" {
+		t.Errorf("expected a rolling Deployment to report true, got %q", got)
+	}
+	if got := state("Deployment", "stable"); got != "false" {
+		t.Errorf("expected a rolled out Deployment to report false, got %q", got)
+	}
+	// 没有 Deployment / StatefulSet 所有者的 Pod 不查询
+	if got := state("DaemonSet", "agent"); got != "false" {
+		t.Errorf("expected other kinds to report false, got %q", got)
+	}
+	// 已删除的工作负载为 unknown，但不暂停查询
+	if got := state("Deployment", "deleted"); got != rolloutStateUnknown {
+		t.Errorf("expected a missing Deployment to report unknown, got %q", got)
+	}
+
+	// 读取失败后在退避期间不再查询
+	if got := state("StatefulSet", "db"); got != rolloutStateUnknown {
+		t.Errorf("expected a forbidden lookup to report unknown, got %q", got)
+	}
+	before := gets
+	if got := state("Deployment", "rolling"); got != rolloutStateUnknown {
+		t.Errorf("expected lookups to report unknown while backing off, got %q", got)
+	}
+	if gets != before {
+		t.Errorf("expected no lookups while backing off, got %d", gets-before)
+	}
+	clock.SetTime(now.Add(rolloutLookupBackoff))
+	if got := state("Deployment", "rolling"); got != "true" {
+		t.Errorf("expected lookups to resume after the backoff, got %q", got)
+	}
+}
+
+func TestRestartDuringRolloutLabel(t *testing.T) {
+	const namespace = "during-rollout-test"
+	ctx := context.Background()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web", Generation: 3},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 2},
+	}
+	pod := testsupport.NewPod(namespace, "web-7c9d-x2").WithOwner("ReplicaSet", "web-7c9d").
+		WithTerminatedContainer("app", 1, "Error", 1, time.Now()).Build()
+	pod.Labels = map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "7c9d"}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(deployment, pod).Build()
+	r := &PodMonitorReconciler{Client: c, Scheme: scheme.Scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: pod.Name}}
+	defer func() {
+		_ = c.Delete(ctx, pod)
+		_, _ = r.reconcilePod(ctx, req)
+		podRestartTotal.DeletePartialMatch(prometheus.Labels{"namespace": namespace})
+	}()
+
+	if _, err := r.reconcilePod(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total",
+		testsupport.Labels{"namespace": namespace, "pod": pod.Name, "container": "app", "during_rollout": "true"}, 1)
+
+	// Deployment 滚动更新完成后的重启标记为 false
+	deployment.Status.ObservedGeneration = 3
+	if err := c.Status().Update(ctx, deployment); err != nil {
+		t.Fatal(err)
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		testsupport.TerminatedContainerStatus("app", 2, "Error", 1, time.Now()),
+	}
+	if err := c.Status().Update(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.reconcilePod(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	testsupport.AssertMetricValue(t, metrics.Registry, "pod_monitor_container_restart_total",
+		testsupport.Labels{"namespace": namespace, "pod": pod.Name, "container": "app", "during_rollout": "false"}, 1)
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version holds the build metadata of the operator. The variables are
+// set at build time with -ldflags "-X ..." (see the build target of the
+// Makefile) and default to "dev" for local builds.
+package version
+
+var (
+	// Version is the released version of the operator.
+	Version = "dev"
+	// GitCommit is the commit the binary was built from.
+	GitCommit = "dev"
+	// BuildDate is the RFC3339 time the binary was built at.
+	BuildDate = "dev"
+)
@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certparse parses the certificates stored in secret values, as PEM
+// bundles or raw DER, and summarizes them. It is shared by the controller and
+// the inspect subcommand so both exercise the same code path.
+package certparse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultMaxBlocks bounds the PEM blocks decoded by ParseAll.
+const DefaultMaxBlocks = 100
+
+// ErrNotCertificateData is returned for data that is neither PEM nor DER.
+var ErrNotCertificateData = errors.New("data is neither PEM nor DER encoded")
+
+// Format is the encoding of certificate data guessed from its content.
+type Format int
+
+const (
+	FormatUnknown Format = iota
+	FormatPEM
+	FormatDER
+)
+
+var pemBeginMarker = []byte("-----BEGIN ")
+
+// Classify cheaply tells PEM and DER data apart from anything else, so binary
+// garbage is rejected before any parsing is attempted.
+func Classify(data []byte) Format {
+	if bytes.Contains(data, pemBeginMarker) {
+		return FormatPEM
+	}
+	// DER 编码的证书总是以 ASN.1 SEQUENCE 开头
+	if len(data) > 1 && data[0] == 0x30 {
+		return FormatDER
+	}
+	return FormatUnknown
+}
+
+// ParseBundle returns every parseable certificate in the PEM data, skipping
+// blocks of other types and malformed certificates. At most maxBlocks blocks
+// are decoded.
+func ParseBundle(data []byte, maxBlocks int) []*x509.Certificate {
+	if Classify(data) != FormatPEM {
+		return nil
+	}
+
+	var certs []*x509.Certificate
+	for i := 0; i < maxBlocks; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+// ParseAll returns every certificate of PEM or DER data, in order, decoding
+// at most DefaultMaxBlocks PEM blocks. See ParseChain.
+func ParseAll(data []byte) ([]*x509.Certificate, error) {
+	return ParseChain(data, DefaultMaxBlocks)
+}
+
+// ParseChain returns every certificate of PEM or DER data, in order, decoding
+// at most maxBlocks PEM blocks. Blocks of other types, such as a private key
+// written into the same value as its certificate, are skipped wherever they
+// appear. Unlike ParseBundle it fails on a malformed certificate block, and
+// when the data holds no certificate at all.
+func ParseChain(data []byte, maxBlocks int) ([]*x509.Certificate, error) {
+	switch Classify(data) {
+	case FormatDER:
+		cert, err := x509.ParseCertificate(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		return []*x509.Certificate{cert}, nil
+	case FormatPEM:
+	default:
+		return nil, ErrNotCertificateData
+	}
+
+	var certs []*x509.Certificate
+	for i := 0; i < maxBlocks; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certs, fmt.Errorf("failed to parse certificate %d: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no CERTIFICATE block found")
+	}
+	return certs, nil
+}
+
+// Leaf returns the certificate a chain was issued for: the first one that is
+// not a CA or, when all are CAs (e.g. a CA bundle), the first one.
+func Leaf(certs []*x509.Certificate) *x509.Certificate {
+	for _, cert := range certs {
+		if !cert.IsCA {
+			return cert
+		}
+	}
+	if len(certs) == 0 {
+		return nil
+	}
+	return certs[0]
+}
+
+// CertSummary is the part of a certificate useful when triaging an expiry or
+// rotation problem.
+type CertSummary struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+	IsCA      bool      `json:"isCA"`
+	// SANs are the DNS names, IP addresses, email addresses and URIs.
+	SANs []string `json:"sans,omitempty"`
+	// Fingerprint is the SHA-256 fingerprint of the certificate, see
+	// Fingerprint.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Fingerprint returns the SHA-256 fingerprint of a DER encoded certificate,
+// formatted as openssl x509 -fingerprint does: uppercase hex bytes separated
+// by colons.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+	return strings.Join(parts, ":")
+}
+
+// Summarize returns the summary of a certificate.
+func Summarize(cert *x509.Certificate) CertSummary {
+	sans := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+	return CertSummary{
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		IsCA:        cert.IsCA,
+		SANs:        sans,
+		Fingerprint: Fingerprint(cert.Raw),
+	}
+}
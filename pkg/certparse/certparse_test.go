@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certparse
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/Deraiven/pod-monitor-operator/pkg/testsupport"
+)
+
+func newTestCertificate(t testing.TB, cn string, dnsNames ...string) []byte {
+	t.Helper()
+	return testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{
+		CommonName:  cn,
+		DNSNames:    dnsNames,
+		IPAddresses: []net.IP{net.ParseIP("10.0.0.1")},
+	}).DER()
+}
+
+// FuzzParseChain checks that arbitrary secret values never panic and that a
+// nil error always comes with at least one certificate. Run with
+// go test ./pkg/certparse -run '^$' -fuzz FuzzParseChain
+func FuzzParseChain(f *testing.F) {
+	der := newTestCertificate(f, "fuzz.example.com")
+	valid := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	// 截断的 PEM
+	f.Add(valid[:len(valid)/2])
+	f.Add(valid[:len(valid)-len("-----END CERTIFICATE-----\n")])
+	// 嵌套的 PEM：证书块内部又包含一个 PEM 块
+	f.Add(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: valid}))
+	// 证书前有其他类型的块
+	f.Add(append(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1, 2, 3}}), valid...))
+	// 随机字节与 DER
+	f.Add([]byte{0x30, 0x82, 0xff, 0xff, 0x00})
+	f.Add([]byte("\x00\xfe\x13not a certificate"))
+	f.Add(der)
+	f.Add(valid)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		certs, err := ParseChain(data, DefaultMaxBlocks)
+		if err == nil && len(certs) == 0 {
+			t.Fatal("got neither a certificate nor an error")
+		}
+		if slices.Contains(certs, nil) {
+			t.Fatal("got a nil certificate")
+		}
+		// PEM 数据中 ParseBundle 跳过格式错误的块，解析出的证书不少于 ParseChain
+		if bundle := ParseBundle(data, DefaultMaxBlocks); Classify(data) == FormatPEM && len(bundle) < len(certs) {
+			t.Fatalf("ParseBundle found %d certificates, ParseChain %d", len(bundle), len(certs))
+		}
+	})
+}
+
+func TestParseChainBlockLimit(t *testing.T) {
+	der := newTestCertificate(t, "limit.example.com")
+	var data bytes.Buffer
+	for i := 0; i < 3; i++ {
+		_ = pem.Encode(&data, &pem.Block{Type: "PRIVATE KEY", Bytes: []byte{byte(i)}})
+	}
+	_ = pem.Encode(&data, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	if _, err := ParseChain(data.Bytes(), 3); err == nil {
+		t.Fatal("expected the certificate after the block limit to be ignored")
+	}
+	if certs, err := ParseChain(data.Bytes(), 4); err != nil || len(certs) != 1 {
+		t.Fatalf("expected the certificate within the block limit to be parsed, got %d: %v", len(certs), err)
+	}
+	if got := len(ParseBundle(data.Bytes(), 3)); got != 0 {
+		t.Fatalf("expected no certificates within 3 blocks, got %d", got)
+	}
+	if got := len(ParseBundle(data.Bytes(), 4)); got != 1 {
+		t.Fatalf("expected one certificate within 4 blocks, got %d", got)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	leaf := newTestCertificate(t, "leaf.example.com")
+	ca := newTestCertificate(t, "ca.example.com")
+	var bundle bytes.Buffer
+	_ = pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: leaf})
+	_ = pem.Encode(&bundle, &pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})
+	_ = pem.Encode(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: ca})
+
+	certs, err := ParseAll(bundle.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 || certs[0].Subject.CommonName != "leaf.example.com" ||
+		certs[1].Subject.CommonName != "ca.example.com" {
+		t.Fatalf("expected the leaf and the CA in order, got %d certificates", len(certs))
+	}
+
+	if certs, err := ParseAll(leaf); err != nil || len(certs) != 1 {
+		t.Errorf("expected a DER certificate to be parsed, got %d certificates and %v", len(certs), err)
+	}
+	if _, err := ParseAll([]byte("not a certificate")); !errors.Is(err, ErrNotCertificateData) {
+		t.Errorf("expected ErrNotCertificateData, got %v", err)
+	}
+	keyOnly := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{1}})
+	if _, err := ParseAll(keyOnly); err == nil {
+		t.Errorf("expected an error for PEM data without certificates")
+	}
+	malformed := append(bytes.Clone(bundle.Bytes()), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE",
+		Bytes: []byte{0x30, 0x00}})...)
+	if _, err := ParseAll(malformed); err == nil {
+		t.Errorf("expected an error for a malformed certificate block")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	der := newTestCertificate(t, "web.example.com")
+
+	fingerprint := Fingerprint(der)
+	if !regexp.MustCompile(`^[0-9A-F]{2}(:[0-9A-F]{2}){31}$`).MatchString(fingerprint) {
+		t.Fatalf("expected 32 colon-separated uppercase hex bytes, got %q", fingerprint)
+	}
+	// 相同字节总是得到相同指纹
+	if got := Fingerprint(bytes.Clone(der)); got != fingerprint {
+		t.Errorf("expected a stable fingerprint, got %q then %q", fingerprint, got)
+	}
+	// 任意一个字节变化都会改变指纹
+	for _, i := range []int{0, len(der) / 2, len(der) - 1} {
+		mutated := bytes.Clone(der)
+		mutated[i] ^= 0x01
+		if Fingerprint(mutated) == fingerprint {
+			t.Errorf("expected mutating byte %d to change the fingerprint", i)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	der := newTestCertificate(t, "web.example.com", "web.example.com", "www.example.com")
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := Summarize(cert)
+
+	if summary.Subject != "CN=web.example.com" || summary.Issuer != "CN=web.example.com" {
+		t.Errorf("unexpected subject %q or issuer %q", summary.Subject, summary.Issuer)
+	}
+	if !slices.Equal(summary.SANs, []string{"web.example.com", "www.example.com", "10.0.0.1"}) {
+		t.Errorf("unexpected SANs %v", summary.SANs)
+	}
+	// 与控制器导出的指纹格式一致
+	if summary.Fingerprint != Fingerprint(der) {
+		t.Errorf("unexpected fingerprint %s", summary.Fingerprint)
+	}
+	if !summary.NotAfter.Equal(cert.NotAfter) || summary.IsCA {
+		t.Errorf("unexpected validity or CA flag: %+v", summary)
+	}
+}
+
+func TestParseChainWithPrivateKey(t *testing.T) {
+	chain := testsupport.GenerateChain(t, testsupport.CertificateOptions{CommonName: "web.example.com"})
+	key := chain.Leaf.KeyPEM()
+	// 同一个值中包含叶子证书、CA 证书与私钥，两种顺序都应选出叶子证书
+	for name, data := range map[string][]byte{
+		"cert first": append(testsupport.PEMBundle(chain.Leaf, chain.Intermediate), key...),
+		"key first":  append(bytes.Clone(key), testsupport.PEMBundle(chain.Intermediate, chain.Leaf)...),
+	} {
+		certs, err := ParseChain(data, DefaultMaxBlocks)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(certs) != 2 {
+			t.Fatalf("%s: expected the leaf and the CA, got %d certificates", name, len(certs))
+		}
+		if leaf := Leaf(certs); leaf.Subject.CommonName != "web.example.com" {
+			t.Errorf("%s: expected the leaf to be selected, got %s", name, leaf.Subject)
+		}
+	}
+
+	ca := chain.Root.Cert
+	if leaf := Leaf([]*x509.Certificate{ca}); leaf != ca {
+		t.Errorf("expected the first certificate of a CA bundle to be selected")
+	}
+	if Leaf(nil) != nil {
+		t.Errorf("expected no leaf for an empty chain")
+	}
+}
+
+func TestParseChainKeyTypes(t *testing.T) {
+	for name, keyType := range map[string]testsupport.KeyType{
+		"ecdsa p256": testsupport.KeyECDSAP256,
+		"ecdsa p384": testsupport.KeyECDSAP384,
+		"rsa":        testsupport.KeyRSA2048,
+		"ed25519":    testsupport.KeyEd25519,
+	} {
+		cert := testsupport.MustGenerateCertificate(t, testsupport.CertificateOptions{
+			CommonName: name, KeyType: keyType,
+		})
+		certs, err := ParseChain(append(cert.KeyPEM(), cert.CertPEM()...), DefaultMaxBlocks)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(certs) != 1 || certs[0].Subject.CommonName != name {
+			t.Errorf("%s: expected the certificate after the key to be parsed, got %d certificates", name, len(certs))
+		}
+	}
+}
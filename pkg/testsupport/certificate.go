@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsupport
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// KeyType is the algorithm of a generated key.
+type KeyType int
+
+const (
+	// KeyECDSAP256 is the default, and what cert-manager and Linkerd issue.
+	KeyECDSAP256 KeyType = iota
+	KeyECDSAP384
+	KeyRSA2048
+	KeyEd25519
+)
+
+func (k KeyType) generate() (crypto.Signer, error) {
+	switch k {
+	case KeyECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeyRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, fmt.Errorf("unknown key type %d", k)
+}
+
+// Certificate is a generated certificate and its private key.
+type Certificate struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// DER returns the DER encoding of the certificate.
+func (c *Certificate) DER() []byte {
+	return c.Cert.Raw
+}
+
+// CertPEM returns the PEM encoding of the certificate.
+func (c *Certificate) CertPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Cert.Raw})
+}
+
+// KeyPEM returns the PKCS #8 PEM encoding of the private key.
+func (c *Certificate) KeyPEM() []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(c.Key)
+	if err != nil {
+		// 由 GenerateCertificate 生成的密钥总能编码
+		panic(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// PEMBundle concatenates the PEM encodings of certs, in order, as in a
+// crt.pem or ca.crt holding a chain.
+func PEMBundle(certs ...*Certificate) []byte {
+	var buf bytes.Buffer
+	for _, c := range certs {
+		buf.Write(c.CertPEM())
+	}
+	return buf.Bytes()
+}
+
+// CertificateOptions describes a certificate to generate.
+type CertificateOptions struct {
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []net.IP
+	// NotBefore defaults to an hour ago, NotAfter to a day from now.
+	NotBefore time.Time
+	NotAfter  time.Time
+	IsCA      bool
+	KeyType   KeyType
+	// Issuer signs the certificate; it is self-signed when nil.
+	Issuer *Certificate
+}
+
+var serialNumber atomic.Int64
+
+// GenerateCertificate creates a certificate and its key.
+func GenerateCertificate(opts CertificateOptions) (*Certificate, error) {
+	key, err := opts.KeyType.generate()
+	if err != nil {
+		return nil, err
+	}
+	if opts.NotBefore.IsZero() {
+		opts.NotBefore = time.Now().Add(-time.Hour)
+	}
+	if opts.NotAfter.IsZero() {
+		opts.NotAfter = time.Now().Add(24 * time.Hour)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serialNumber.Add(1)),
+		Subject:               pkix.Name{CommonName: opts.CommonName},
+		DNSNames:              opts.DNSNames,
+		IPAddresses:           opts.IPAddresses,
+		NotBefore:             opts.NotBefore,
+		NotAfter:              opts.NotAfter,
+		IsCA:                  opts.IsCA,
+		BasicConstraintsValid: true,
+	}
+	if opts.IsCA {
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	} else {
+		tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	parent, parentKey := tmpl, key
+	if opts.Issuer != nil {
+		parent, parentKey = opts.Issuer.Cert, opts.Issuer.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{Cert: cert, Key: key}, nil
+}
+
+// MustGenerateCertificate is GenerateCertificate failing the test on error.
+func MustGenerateCertificate(t testing.TB, opts CertificateOptions) *Certificate {
+	t.Helper()
+	cert, err := GenerateCertificate(opts)
+	if err != nil {
+		t.Fatalf("generating certificate %q: %v", opts.CommonName, err)
+	}
+	return cert
+}
+
+// Chain is a root CA, an intermediate CA signed by it and a leaf signed by
+// the intermediate.
+type Chain struct {
+	Root         *Certificate
+	Intermediate *Certificate
+	Leaf         *Certificate
+}
+
+// GenerateChain creates a chain whose leaf is described by leaf; its Issuer
+// is replaced by the intermediate. The CAs are valid from five years before
+// the leaf until five years after it.
+func GenerateChain(t testing.TB, leaf CertificateOptions) *Chain {
+	t.Helper()
+	if leaf.NotAfter.IsZero() {
+		leaf.NotAfter = time.Now().Add(24 * time.Hour)
+	}
+	if leaf.NotBefore.IsZero() {
+		leaf.NotBefore = leaf.NotAfter.Add(-90 * 24 * time.Hour)
+	}
+	caValidity := CertificateOptions{
+		NotBefore: leaf.NotBefore.AddDate(-5, 0, 0),
+		NotAfter:  leaf.NotAfter.AddDate(5, 0, 0),
+		IsCA:      true,
+	}
+	rootOpts, intermediateOpts := caValidity, caValidity
+	rootOpts.CommonName = "root.test"
+	intermediateOpts.CommonName = "intermediate.test"
+	root := MustGenerateCertificate(t, rootOpts)
+	intermediateOpts.Issuer = root
+	intermediate := MustGenerateCertificate(t, intermediateOpts)
+	leaf.Issuer = intermediate
+	return &Chain{Root: root, Intermediate: intermediate, Leaf: MustGenerateCertificate(t, leaf)}
+}
+
+// CertificateExpiringIn generates a self-signed leaf certificate for dnsNames
+// that expires the given number of days after now. Negative days give an
+// expired certificate.
+func CertificateExpiringIn(t testing.TB, now time.Time, days int, dnsNames ...string) *Certificate {
+	t.Helper()
+	cn := "test.example.com"
+	if len(dnsNames) > 0 {
+		cn = dnsNames[0]
+	}
+	notAfter := now.Add(time.Duration(days) * 24 * time.Hour)
+	notBefore := now.Add(-30 * 24 * time.Hour)
+	if notAfter.Before(notBefore) {
+		notBefore = notAfter.Add(-30 * 24 * time.Hour)
+	}
+	return MustGenerateCertificate(t, CertificateOptions{
+		CommonName: cn,
+		DNSNames:   dnsNames,
+		NotBefore:  notBefore,
+		NotAfter:   notAfter,
+	})
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsupport
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Labels selects series by a subset of their labels; labels not listed match
+// any value.
+type Labels map[string]string
+
+// Series returns the values of the series of the metric family name whose
+// labels include labels. Counters, gauges and untyped metrics yield their
+// value, histograms and summaries their sample count.
+func Series(g prometheus.Gatherer, name string, labels Labels) ([]float64, error) {
+	families, err := g.Gather()
+	if err != nil {
+		return nil, err
+	}
+	var values []float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if matches(m, labels) {
+				values = append(values, value(m))
+			}
+		}
+	}
+	return values, nil
+}
+
+// CountSeries returns how many series of the metric family name include
+// labels, failing the test if gathering fails.
+func CountSeries(t testing.TB, g prometheus.Gatherer, name string, labels Labels) int {
+	t.Helper()
+	values, err := Series(g, name, labels)
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+	return len(values)
+}
+
+// AssertMetricValue checks that exactly one series of the metric family name
+// includes labels, and that its value is want.
+func AssertMetricValue(t testing.TB, g prometheus.Gatherer, name string, labels Labels, want float64) {
+	t.Helper()
+	values, err := Series(g, name, labels)
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+	switch {
+	case len(values) == 0:
+		t.Errorf("%s%v: no such series", name, labels)
+	case len(values) > 1:
+		t.Errorf("%s%v: expected one series, got %d", name, labels, len(values))
+	case values[0] != want:
+		t.Errorf("%s%v = %v, want %v", name, labels, values[0], want)
+	}
+}
+
+// AssertNoMetric checks that no series of the metric family name includes
+// labels.
+func AssertNoMetric(t testing.TB, g prometheus.Gatherer, name string, labels Labels) {
+	t.Helper()
+	if n := CountSeries(t, g, name, labels); n != 0 {
+		t.Errorf("%s%v: expected no series, got %d", name, labels, n)
+	}
+}
+
+func matches(m *dto.Metric, labels Labels) bool {
+	for _, pair := range m.GetLabel() {
+		if want, ok := labels[pair.GetName()]; ok && pair.GetValue() != want {
+			return false
+		}
+	}
+	// 未出现在序列中的标签只匹配空值
+	for name, want := range labels {
+		if want != "" && !hasLabel(m, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasLabel(m *dto.Metric, name string) bool {
+	for _, pair := range m.GetLabel() {
+		if pair.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func value(m *dto.Metric) float64 {
+	switch {
+	case m.Counter != nil:
+		return m.GetCounter().GetValue()
+	case m.Gauge != nil:
+		return m.GetGauge().GetValue()
+	case m.Histogram != nil:
+		return float64(m.GetHistogram().GetSampleCount())
+	case m.Summary != nil:
+		return float64(m.GetSummary().GetSampleCount())
+	}
+	return m.GetUntyped().GetValue()
+}
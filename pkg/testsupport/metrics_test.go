@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsupport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSeriesMatchesLabelSubset(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_gauge"}, []string{"pod", "reason"})
+	reg.MustRegister(vec)
+	vec.WithLabelValues("a", "OOMKilled").Set(1)
+	vec.WithLabelValues("a", "").Set(2)
+	vec.WithLabelValues("b", "Error").Set(3)
+
+	if n := CountSeries(t, reg, "test_gauge", Labels{"pod": "a"}); n != 2 {
+		t.Fatalf("expected 2 series of pod a, got %d", n)
+	}
+	AssertMetricValue(t, reg, "test_gauge", Labels{"pod": "a", "reason": ""}, 2)
+	AssertMetricValue(t, reg, "test_gauge", Labels{"reason": "Error"}, 3)
+	AssertNoMetric(t, reg, "test_gauge", Labels{"pod": "c"})
+	AssertNoMetric(t, reg, "test_gauge", Labels{"node": "n1"})
+}
+
+func TestCertificateExpiringIn(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, days := range []int{10, -3} {
+		cert := CertificateExpiringIn(t, now, days, "web.example.com")
+		if got := cert.Cert.NotAfter.Sub(now); got != time.Duration(days)*24*time.Hour {
+			t.Errorf("%d days: expected NotAfter %d days after now, got %v", days, days, got)
+		}
+		if !cert.Cert.NotBefore.Before(cert.Cert.NotAfter) {
+			t.Errorf("%d days: NotBefore %v is not before NotAfter", days, cert.Cert.NotBefore)
+		}
+		if len(cert.Cert.DNSNames) != 1 || cert.Cert.Subject.CommonName != "web.example.com" {
+			t.Errorf("%d days: unexpected names %v / %q", days, cert.Cert.DNSNames, cert.Cert.Subject.CommonName)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testsupport provides builders for the objects the operator watches,
+// certificate generation and assertions over gathered metrics, for the tests
+// of the operator and of binaries that embed its reconciler. See the
+// podmonitortest subpackage for a reconciler wired to an isolated registry.
+package testsupport
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// PodBuilder builds a pod with a container status per container.
+type PodBuilder struct {
+	pod corev1.Pod
+}
+
+// NewPod starts a Running pod without containers.
+func NewPod(namespace, name string) *PodBuilder {
+	return &PodBuilder{pod: corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}}
+}
+
+// WithUID sets the UID of the pod.
+func (b *PodBuilder) WithUID(uid string) *PodBuilder {
+	b.pod.UID = types.UID(uid)
+	return b
+}
+
+// WithCreationTimestamp sets the creation time of the pod.
+func (b *PodBuilder) WithCreationTimestamp(t time.Time) *PodBuilder {
+	b.pod.CreationTimestamp = metav1.NewTime(t)
+	return b
+}
+
+// WithNode sets the node the pod is scheduled on.
+func (b *PodBuilder) WithNode(node string) *PodBuilder {
+	b.pod.Spec.NodeName = node
+	return b
+}
+
+// WithAnnotation adds an annotation to the pod.
+func (b *PodBuilder) WithAnnotation(key, value string) *PodBuilder {
+	if b.pod.Annotations == nil {
+		b.pod.Annotations = map[string]string{}
+	}
+	b.pod.Annotations[key] = value
+	return b
+}
+
+// WithOwner sets the controller owner reference of the pod, e.g. a ReplicaSet
+// or a Job.
+func (b *PodBuilder) WithOwner(kind, name string) *PodBuilder {
+	controller := true
+	b.pod.OwnerReferences = append(b.pod.OwnerReferences, metav1.OwnerReference{
+		APIVersion: ownerAPIVersion(kind),
+		Kind:       kind,
+		Name:       name,
+		UID:        types.UID(kind + "-" + name),
+		Controller: &controller,
+	})
+	return b
+}
+
+// WithContainer adds a running container that has never restarted.
+func (b *PodBuilder) WithContainer(name string) *PodBuilder {
+	return b.WithContainerStatus(corev1.ContainerStatus{
+		Name:  name,
+		Ready: true,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	})
+}
+
+// WithTerminatedContainer adds a running container whose last termination had
+// the given reason and exit code, after restartCount restarts.
+func (b *PodBuilder) WithTerminatedContainer(name string, restartCount int32, reason string, exitCode int32,
+	finishedAt time.Time) *PodBuilder {
+	return b.WithContainerStatus(TerminatedContainerStatus(name, restartCount, reason, exitCode, finishedAt))
+}
+
+// WithContainerStatus adds a container with the given status.
+func (b *PodBuilder) WithContainerStatus(cs corev1.ContainerStatus) *PodBuilder {
+	b.pod.Spec.Containers = append(b.pod.Spec.Containers, corev1.Container{Name: cs.Name, Image: cs.Name})
+	b.pod.Status.ContainerStatuses = append(b.pod.Status.ContainerStatuses, cs)
+	return b
+}
+
+// Build returns a copy of the pod built so far.
+func (b *PodBuilder) Build() *corev1.Pod {
+	return b.pod.DeepCopy()
+}
+
+// TerminatedContainerStatus returns the status of a running container whose
+// last termination had the given reason and exit code.
+func TerminatedContainerStatus(name string, restartCount int32, reason string, exitCode int32,
+	finishedAt time.Time) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:         name,
+		Ready:        true,
+		RestartCount: restartCount,
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{
+			StartedAt: metav1.NewTime(finishedAt.Add(time.Second)),
+		}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:     reason,
+			ExitCode:   exitCode,
+			StartedAt:  metav1.NewTime(finishedAt.Add(-time.Minute)),
+			FinishedAt: metav1.NewTime(finishedAt),
+		}},
+	}
+}
+
+func ownerAPIVersion(kind string) string {
+	switch kind {
+	case "ReplicaSet", "StatefulSet", "DaemonSet", "Deployment":
+		return "apps/v1"
+	case "Job":
+		return "batch/v1"
+	}
+	return "v1"
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package podmonitortest builds a PodMonitorReconciler for tests, with a fake
+// clock, a fake event recorder and its metrics registered with a registry of
+// the test. It is separate from testsupport so that the tests of the
+// controller package itself can use the builders without an import cycle.
+//
+// The metric values and the restart state of the reconciler are global to
+// the process: reconcilers built here share them with each other and with
+// controller-runtime's registry. Tests should use distinct namespaces and
+// delete the objects they create, reconciling the deletions, to clean up.
+package podmonitortest
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitorv1alpha1 "github.com/Deraiven/pod-monitor-operator/api/v1alpha1"
+	"github.com/Deraiven/pod-monitor-operator/internal/controller"
+)
+
+// StartTime is the initial time of the fake clock of test reconcilers.
+var StartTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// eventBufferSize is the number of events the fake recorder buffers. Recording
+// blocks once it is full, so tests emitting more must drain Events.
+const eventBufferSize = 100
+
+// Reconciler is a PodMonitorReconciler with handles on its test doubles.
+type Reconciler struct {
+	*controller.PodMonitorReconciler
+	Clock    *clocktesting.FakeClock
+	Events   *record.FakeRecorder
+	Registry *prometheus.Registry
+}
+
+// NewScheme returns a scheme with the built-in types and the types of the
+// operator, for fake clients.
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(monitorv1alpha1.AddToScheme(scheme))
+	return scheme
+}
+
+// NewFakeClient returns a fake client using NewScheme that holds objs.
+func NewFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(NewScheme()).WithObjects(objs...).Build()
+}
+
+// NewTestReconciler returns a reconciler reading through c whose metrics are
+// registered with registry, and whose clock starts at StartTime. A nil
+// registry gets a new one. Adjust the exported fields of the reconciler
+// before the first reconcile.
+func NewTestReconciler(c client.Client, registry *prometheus.Registry) (*Reconciler, error) {
+	if registry == nil {
+		registry = prometheus.NewRegistry()
+	}
+	if err := controller.RegisterMetrics(registry); err != nil {
+		return nil, err
+	}
+	clock := clocktesting.NewFakeClock(StartTime)
+	events := record.NewFakeRecorder(eventBufferSize)
+	return &Reconciler{
+		PodMonitorReconciler: &controller.PodMonitorReconciler{
+			Client:   c,
+			Scheme:   c.Scheme(),
+			Recorder: events,
+			Clock:    clock,
+		},
+		Clock:    clock,
+		Events:   events,
+		Registry: registry,
+	}, nil
+}
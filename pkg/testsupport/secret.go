@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testsupport
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewSecret returns an Opaque secret with the given data.
+func NewSecret(namespace, name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       data,
+	}
+}
+
+// NewTLSSecret returns a kubernetes.io/tls secret holding the certificate and
+// key of cert.
+func NewTLSSecret(namespace, name string, cert *Certificate) *corev1.Secret {
+	secret := NewSecret(namespace, name, map[string][]byte{
+		corev1.TLSCertKey:       cert.CertPEM(),
+		corev1.TLSPrivateKeyKey: cert.KeyPEM(),
+	})
+	secret.Type = corev1.SecretTypeTLS
+	return secret
+}